@@ -0,0 +1,26 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS blog_revisions (
+  id          SERIAL NOT NULL PRIMARY KEY,
+  blog_id     INT NOT NULL REFERENCES blogs(id) ON DELETE CASCADE,
+  revision    INT NOT NULL,
+  editor_id   INT NOT NULL,
+  title       TEXT NOT NULL,
+  content     TEXT NOT NULL,
+  description TEXT NOT NULL,
+  thumbnail_image_file_name TEXT NOT NULL DEFAULT '',
+  created BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM CURRENT_TIMESTAMP),
+  UNIQUE(blog_id, revision)
+);
+
+-- 既存の記事は、最初の編集で元の内容が失われないよう現在の内容をリビジョン1とする
+INSERT INTO blog_revisions
+  (blog_id, revision, editor_id, title, content, description, thumbnail_image_file_name, created)
+SELECT
+  id, 1, author_id, title, content, description, COALESCE(thumbnail_image_file_name, ''), modified
+FROM blogs
+WHERE NOT EXISTS (
+  SELECT 1 FROM blog_revisions WHERE blog_revisions.blog_id = blogs.id
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS blog_revisions;
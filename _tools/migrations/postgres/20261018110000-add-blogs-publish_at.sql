@@ -0,0 +1,9 @@
+-- +migrate Up
+-- 0は即時公開を表す
+ALTER TABLE blogs ADD COLUMN IF NOT EXISTS publish_at BIGINT NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS blogs_is_public_publish_at_idx ON blogs (is_public, publish_at);
+
+-- +migrate Down
+DROP INDEX IF EXISTS blogs_is_public_publish_at_idx;
+ALTER TABLE blogs DROP COLUMN IF EXISTS publish_at;
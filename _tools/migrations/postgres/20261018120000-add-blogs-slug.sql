@@ -0,0 +1,20 @@
+-- +migrate Up
+ALTER TABLE blogs ADD COLUMN IF NOT EXISTS slug VARCHAR(255) NOT NULL DEFAULT '';
+
+-- 既存の記事はIDをスラッグとする
+UPDATE blogs SET slug = id::TEXT WHERE slug = '';
+
+CREATE UNIQUE INDEX IF NOT EXISTS blogs_slug_key ON blogs (slug) WHERE slug <> '';
+
+-- 変更前のスラッグから現在の記事へリダイレクトするための履歴
+CREATE TABLE IF NOT EXISTS blog_slug_histories (
+  id          SERIAL NOT NULL PRIMARY KEY,
+  blog_id     INT NOT NULL REFERENCES blogs(id) ON DELETE CASCADE,
+  slug        VARCHAR(255) NOT NULL UNIQUE,
+  created BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM CURRENT_TIMESTAMP)
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS blog_slug_histories;
+DROP INDEX IF EXISTS blogs_slug_key;
+ALTER TABLE blogs DROP COLUMN IF EXISTS slug;
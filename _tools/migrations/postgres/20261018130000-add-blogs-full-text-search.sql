@@ -0,0 +1,70 @@
+-- +migrate Up
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE blogs ADD COLUMN IF NOT EXISTS search_vector TSVECTOR;
+ALTER TABLE blogs ADD COLUMN IF NOT EXISTS search_text TEXT NOT NULL DEFAULT '';
+
+-- 単語検索用の全文検索インデックス
+CREATE INDEX IF NOT EXISTS blogs_search_vector_idx ON blogs USING GIN (search_vector);
+-- 日本語など単語区切りのない文字列の部分一致検索用のトライグラムインデックス
+CREATE INDEX IF NOT EXISTS blogs_search_text_trgm_idx ON blogs USING GIN (search_text gin_trgm_ops);
+
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION update_blogs_search_columns()
+RETURNS TRIGGER AS $$
+DECLARE
+    tag_names TEXT;
+BEGIN
+    SELECT COALESCE(string_agg(tags.name, ' '), '') INTO tag_names
+    FROM blogs_tags
+    JOIN tags ON blogs_tags.tag_id = tags.id
+    WHERE blogs_tags.blog_id = NEW.id;
+
+    NEW.search_vector :=
+        setweight(to_tsvector('simple', COALESCE(NEW.title, '')), 'A') ||
+        setweight(to_tsvector('simple', COALESCE(NEW.description, '')), 'B') ||
+        setweight(to_tsvector('simple', tag_names), 'B') ||
+        setweight(to_tsvector('simple', COALESCE(NEW.content, '')), 'C');
+    NEW.search_text := lower(concat_ws(' ', NEW.title, NEW.description, tag_names, NEW.content));
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER update_blogs_search_columns_trigger
+BEFORE INSERT OR UPDATE ON blogs
+FOR EACH ROW
+EXECUTE FUNCTION update_blogs_search_columns();
+
+-- タグの付け替え時に検索用カラムを再計算する
+CREATE OR REPLACE FUNCTION update_blogs_search_columns_by_tags()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        UPDATE blogs SET search_vector = NULL WHERE id = OLD.blog_id;
+        RETURN OLD;
+    END IF;
+    UPDATE blogs SET search_vector = NULL WHERE id = NEW.blog_id;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER update_blogs_search_columns_by_tags_trigger
+AFTER INSERT OR DELETE ON blogs_tags
+FOR EACH ROW
+EXECUTE FUNCTION update_blogs_search_columns_by_tags();
+-- +migrate StatementEnd
+
+-- 既存の記事の検索用カラムを更新する(更新日時は変更しない)
+ALTER TABLE blogs DISABLE TRIGGER update_blogs_trigger_mod;
+UPDATE blogs SET search_vector = NULL;
+ALTER TABLE blogs ENABLE TRIGGER update_blogs_trigger_mod;
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS update_blogs_search_columns_by_tags_trigger ON blogs_tags;
+DROP TRIGGER IF EXISTS update_blogs_search_columns_trigger ON blogs;
+DROP FUNCTION IF EXISTS update_blogs_search_columns_by_tags();
+DROP FUNCTION IF EXISTS update_blogs_search_columns();
+DROP INDEX IF EXISTS blogs_search_text_trgm_idx;
+DROP INDEX IF EXISTS blogs_search_vector_idx;
+ALTER TABLE blogs DROP COLUMN IF EXISTS search_text;
+ALTER TABLE blogs DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,15 @@
+-- +migrate Up
+-- 本文のレンダリング結果のキャッシュ
+-- 既存の記事は cli の render-blogs コマンドで生成する
+ALTER TABLE blogs ADD COLUMN IF NOT EXISTS content_html TEXT NOT NULL DEFAULT '';
+ALTER TABLE blogs ADD COLUMN IF NOT EXISTS toc JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE blogs ADD COLUMN IF NOT EXISTS word_count INT NOT NULL DEFAULT 0;
+ALTER TABLE blogs ADD COLUMN IF NOT EXISTS char_count INT NOT NULL DEFAULT 0;
+ALTER TABLE blogs ADD COLUMN IF NOT EXISTS reading_time INT NOT NULL DEFAULT 0;
+
+-- +migrate Down
+ALTER TABLE blogs DROP COLUMN IF EXISTS reading_time;
+ALTER TABLE blogs DROP COLUMN IF EXISTS char_count;
+ALTER TABLE blogs DROP COLUMN IF EXISTS word_count;
+ALTER TABLE blogs DROP COLUMN IF EXISTS toc;
+ALTER TABLE blogs DROP COLUMN IF EXISTS content_html;
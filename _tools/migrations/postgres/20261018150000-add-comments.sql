@@ -0,0 +1,28 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS comments (
+  id           SERIAL NOT NULL PRIMARY KEY,
+  blog_id      INT NOT NULL REFERENCES blogs(id) ON DELETE CASCADE,
+  -- 返信先のコメント。返信は1階層のみ
+  parent_id    INT REFERENCES comments(id) ON DELETE CASCADE,
+  author_name  VARCHAR(255) NOT NULL,
+  author_email VARCHAR(255) NOT NULL DEFAULT '',
+  body         TEXT NOT NULL,
+  status       VARCHAR(16) NOT NULL DEFAULT 'pending'
+               CHECK (status IN ('pending', 'approved', 'rejected', 'spam')),
+  ip_address   VARCHAR(64) NOT NULL DEFAULT '',
+  user_agent   TEXT NOT NULL DEFAULT '',
+  created BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM CURRENT_TIMESTAMP),
+  modified BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM CURRENT_TIMESTAMP)
+);
+
+CREATE INDEX IF NOT EXISTS comments_blog_id_status_idx ON comments (blog_id, status);
+CREATE INDEX IF NOT EXISTS comments_status_idx ON comments (status);
+
+CREATE TRIGGER update_comments_trigger_mod
+BEFORE UPDATE ON comments
+FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS update_comments_trigger_mod ON comments;
+DROP TABLE IF EXISTS comments;
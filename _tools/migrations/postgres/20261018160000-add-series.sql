@@ -0,0 +1,28 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS series (
+  id          SERIAL NOT NULL PRIMARY KEY,
+  title       VARCHAR(255) NOT NULL,
+  description TEXT NOT NULL DEFAULT '',
+  created BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM CURRENT_TIMESTAMP),
+  modified BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM CURRENT_TIMESTAMP)
+);
+
+-- ブログは1つのシリーズにのみ所属できる
+CREATE TABLE IF NOT EXISTS series_blogs (
+  series_id INT NOT NULL REFERENCES series(id) ON DELETE CASCADE,
+  blog_id   INT NOT NULL UNIQUE REFERENCES blogs(id) ON DELETE CASCADE,
+  position  INT NOT NULL,
+  PRIMARY KEY (series_id, blog_id)
+);
+
+CREATE INDEX IF NOT EXISTS series_blogs_series_id_position_idx ON series_blogs (series_id, position);
+
+CREATE TRIGGER update_series_trigger_mod
+BEFORE UPDATE ON series
+FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+-- +migrate Down
+DROP TABLE IF EXISTS series_blogs;
+DROP TRIGGER IF EXISTS update_series_trigger_mod ON series;
+DROP TABLE IF EXISTS series;
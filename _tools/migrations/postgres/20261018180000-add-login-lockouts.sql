@@ -0,0 +1,17 @@
+-- +migrate Up
+-- ログイン試行の失敗によるロックアウトの監査記録
+CREATE TABLE IF NOT EXISTS login_lockouts (
+  id           SERIAL NOT NULL PRIMARY KEY,
+  target_type  VARCHAR(16) NOT NULL,
+  target       VARCHAR(255) NOT NULL,
+  failures     INT NOT NULL,
+  locked_until BIGINT NOT NULL,
+  ip_address   VARCHAR(64) NOT NULL DEFAULT '',
+  user_agent   TEXT NOT NULL DEFAULT '',
+  created BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM CURRENT_TIMESTAMP)
+);
+
+CREATE INDEX IF NOT EXISTS login_lockouts_target_idx ON login_lockouts (target_type, target);
+
+-- +migrate Down
+DROP TABLE IF EXISTS login_lockouts;
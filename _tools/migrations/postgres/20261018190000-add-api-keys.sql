@@ -0,0 +1,25 @@
+-- +migrate Up
+-- キー全体はハッシュのみを保存し、prefixで識別する
+CREATE TABLE IF NOT EXISTS api_keys (
+  id           SERIAL NOT NULL PRIMARY KEY,
+  user_id      INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name         VARCHAR(255) NOT NULL,
+  prefix       VARCHAR(16) NOT NULL UNIQUE,
+  key_hash     VARCHAR(64) NOT NULL,
+  scopes       VARCHAR(255) NOT NULL DEFAULT '',
+  last_used_at BIGINT,
+  revoked_at   BIGINT,
+  created BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM CURRENT_TIMESTAMP),
+  modified BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM CURRENT_TIMESTAMP)
+);
+
+CREATE INDEX IF NOT EXISTS api_keys_user_id_idx ON api_keys (user_id);
+
+CREATE TRIGGER update_api_keys_trigger_mod
+BEFORE UPDATE ON api_keys
+FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS update_api_keys_trigger_mod ON api_keys;
+DROP TABLE IF EXISTS api_keys;
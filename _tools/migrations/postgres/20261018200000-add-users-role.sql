@@ -0,0 +1,7 @@
+-- +migrate Up
+-- 既存のユーザーはこれまで全ての操作が可能だったため管理者として扱う
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(16) NOT NULL DEFAULT 'admin';
+ALTER TABLE users ALTER COLUMN role SET DEFAULT 'viewer';
+
+-- +migrate Down
+ALTER TABLE users DROP COLUMN IF EXISTS role;
@@ -0,0 +1,6 @@
+-- +migrate Up
+-- 無効化されたユーザーはログインできない。無効化の日時を保持し、NULLの場合は有効とする
+ALTER TABLE users ADD COLUMN IF NOT EXISTS disabled_at BIGINT;
+
+-- +migrate Down
+ALTER TABLE users DROP COLUMN IF EXISTS disabled_at;
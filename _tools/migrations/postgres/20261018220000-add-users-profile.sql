@@ -0,0 +1,12 @@
+-- +migrate Up
+-- 著者として公開するプロフィール。social_linksは{service, url}の配列をJSONとして保存する
+ALTER TABLE users ADD COLUMN IF NOT EXISTS display_name VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS bio TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS avatar_url TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS social_links TEXT NOT NULL DEFAULT '[]';
+
+-- +migrate Down
+ALTER TABLE users DROP COLUMN IF EXISTS social_links;
+ALTER TABLE users DROP COLUMN IF EXISTS avatar_url;
+ALTER TABLE users DROP COLUMN IF EXISTS bio;
+ALTER TABLE users DROP COLUMN IF EXISTS display_name;
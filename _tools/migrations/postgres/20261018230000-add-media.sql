@@ -0,0 +1,39 @@
+-- +migrate Up
+-- アップロード用の署名付きURLの発行時にpendingで登録し、アップロードの完了通知でuploadedとする
+-- media_keyはサーバーで生成したUUIDで、派生画像を含めて本文・サムネイルからの参照の判定に使用する
+CREATE TABLE IF NOT EXISTS media (
+  id           SERIAL NOT NULL PRIMARY KEY,
+  media_key    VARCHAR(36) NOT NULL UNIQUE,
+  kind         VARCHAR(16) NOT NULL,
+  file_name    VARCHAR(255) NOT NULL,
+  object_url   TEXT NOT NULL,
+  content_type VARCHAR(64) NOT NULL,
+  size         BIGINT NOT NULL,
+  status       VARCHAR(16) NOT NULL DEFAULT 'pending',
+  uploaded_by  INT REFERENCES users(id) ON DELETE SET NULL,
+  uploaded_at  BIGINT,
+  created BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM CURRENT_TIMESTAMP),
+  modified BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM CURRENT_TIMESTAMP)
+);
+
+CREATE INDEX IF NOT EXISTS media_kind_status_idx ON media (kind, status);
+
+CREATE TRIGGER update_media_trigger_mod
+BEFORE UPDATE ON media
+FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+-- ブログの本文・サムネイルから参照されているメディア
+-- ブログの保存時に作り直す
+CREATE TABLE IF NOT EXISTS media_references (
+  media_id INT NOT NULL REFERENCES media(id) ON DELETE CASCADE,
+  blog_id  INT NOT NULL REFERENCES blogs(id) ON DELETE CASCADE,
+  PRIMARY KEY (media_id, blog_id)
+);
+
+CREATE INDEX IF NOT EXISTS media_references_blog_id_idx ON media_references (blog_id);
+
+-- +migrate Down
+DROP TABLE IF EXISTS media_references;
+DROP TRIGGER IF EXISTS update_media_trigger_mod ON media;
+DROP TABLE IF EXISTS media;
@@ -0,0 +1,6 @@
+-- +migrate Up
+-- 同じ認証コードの再利用を防ぐため、最後に受け付けたTOTPのタイムステップを保持する
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_last_step BIGINT;
+
+-- +migrate Down
+ALTER TABLE users DROP COLUMN IF EXISTS totp_last_step;
@@ -0,0 +1,6 @@
+-- +migrate Up
+-- サムネイルの派生画像を生成した日時。NULLの場合は派生画像が存在しないため、URLを返さない
+ALTER TABLE media ADD COLUMN IF NOT EXISTS variants_processed_at BIGINT;
+
+-- +migrate Down
+ALTER TABLE media DROP COLUMN IF EXISTS variants_processed_at;
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shoet/blog/internal/clocker"
+	"github.com/shoet/blog/internal/config"
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/infrastracture/repository"
+	"github.com/shoet/blog/internal/usecase/create_api_key"
+	"github.com/shoet/blog/internal/usecase/get_api_keys"
+	"github.com/shoet/blog/internal/usecase/revoke_api_key"
+	"github.com/spf13/cobra"
+)
+
+var apiKeyCmd = &cobra.Command{
+	Use:   "api-key",
+	Short: "Manage personal api keys",
+}
+
+// apiKeyUser は、--emailで指定したユーザーとDBの接続を返す
+func apiKeyUser(cmd *cobra.Command) (*sqlx.DB, *models.User) {
+	ctx := cmd.Context()
+	email, _ := cmd.Flags().GetString("email")
+	if email == "" {
+		fmt.Println("--email is required")
+		os.Exit(1)
+	}
+	cfg, err := config.NewConfig()
+	if err != nil {
+		log.Fatalf("failed to create config: %v", err)
+	}
+	db, err := infrastracture.NewDBPostgres(ctx, cfg)
+	if err != nil {
+		fmt.Printf("failed to create db: %v", err)
+		os.Exit(1)
+	}
+	c := clocker.RealClocker{}
+	userRepo, err := repository.NewUserRepository(&c)
+	if err != nil {
+		fmt.Printf("failed to create user repository: %v", err)
+		os.Exit(1)
+	}
+	user, err := userRepo.GetByEmail(ctx, db, email)
+	if err != nil {
+		fmt.Printf("failed to get user: %v", err)
+		os.Exit(1)
+	}
+	return db, user
+}
+
+var apiKeyCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create api key",
+	Run: func(cmd *cobra.Command, args []string) {
+		name, _ := cmd.Flags().GetString("name")
+		scopes, _ := cmd.Flags().GetStringSlice("scope")
+		db, user := apiKeyUser(cmd)
+		c := clocker.RealClocker{}
+		usecase := create_api_key.NewUsecase(db, repository.NewAPIKeyRepository(&c))
+		keyScopes := models.APIKeyScopes{}
+		for _, s := range scopes {
+			keyScopes = append(keyScopes, models.APIKeyScope(s))
+		}
+		result, err := usecase.Run(cmd.Context(), user.Id, name, keyScopes)
+		if err != nil {
+			fmt.Printf("failed to create api key: %v", err)
+			os.Exit(1)
+		}
+		fmt.Printf("id: %d\n", result.APIKey.Id)
+		fmt.Printf("key: %s\n", result.Key)
+		fmt.Println("store the key now; it cannot be shown again")
+	},
+}
+
+var apiKeyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List api keys",
+	Run: func(cmd *cobra.Command, args []string) {
+		db, user := apiKeyUser(cmd)
+		c := clocker.RealClocker{}
+		usecase := get_api_keys.NewUsecase(db, repository.NewAPIKeyRepository(&c))
+		keys, err := usecase.Run(cmd.Context(), user.Id)
+		if err != nil {
+			fmt.Printf("failed to list api keys: %v", err)
+			os.Exit(1)
+		}
+		for _, k := range keys {
+			scopes, _ := k.Scopes.Value()
+			status := "active"
+			if k.RevokedAt != nil {
+				status = "revoked"
+			}
+			fmt.Printf("%d\t%s\tblog_%s_...\t%s\t%s\tlast used: %s\n",
+				k.Id, k.Name, k.Prefix, scopes, status, formatUnix(k.LastUsedAt))
+		}
+	},
+}
+
+var apiKeyRevokeCmd = &cobra.Command{
+	Use:   "revoke",
+	Short: "Revoke api key",
+	Run: func(cmd *cobra.Command, args []string) {
+		id, _ := cmd.Flags().GetInt64("id")
+		db, user := apiKeyUser(cmd)
+		c := clocker.RealClocker{}
+		usecase := revoke_api_key.NewUsecase(db, repository.NewAPIKeyRepository(&c))
+		if err := usecase.Run(cmd.Context(), user.Id, models.APIKeyId(id)); err != nil {
+			fmt.Printf("failed to revoke api key: %v", err)
+			os.Exit(1)
+		}
+		fmt.Printf("revoked %d\n", id)
+	},
+}
+
+func formatUnix(t *uint) string {
+	if t == nil {
+		return "never"
+	}
+	return time.Unix(int64(*t), 0).Format(time.RFC3339)
+}
+
+func init() {
+	apiKeyCmd.PersistentFlags().String("email", "", "email of the key owner")
+
+	apiKeyCreateCmd.Flags().String("name", "", "name of the key")
+	apiKeyCreateCmd.Flags().StringSlice("scope", nil,
+		fmt.Sprintf("scope of the key (%s)", strings.Join([]string{
+			string(models.APIKeyScopeBlogsWrite), string(models.APIKeyScopeFilesWrite),
+		}, ", ")))
+	apiKeyRevokeCmd.Flags().Int64("id", 0, "id of the key")
+
+	apiKeyCmd.AddCommand(apiKeyCreateCmd, apiKeyListCmd, apiKeyRevokeCmd)
+	rootCmd.AddCommand(apiKeyCmd)
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/shoet/blog/internal/clocker"
+	"github.com/shoet/blog/internal/config"
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/adapter"
+	"github.com/shoet/blog/internal/infrastracture/repository"
+	"github.com/shoet/blog/internal/infrastracture/services/media_gc_service"
+	"github.com/shoet/blog/internal/usecase/backfill_media_references"
+	"github.com/shoet/blog/internal/usecase/gc_media"
+	"github.com/spf13/cobra"
+)
+
+var mediaCmd = &cobra.Command{
+	Use:   "media",
+	Short: "Manage uploaded media",
+}
+
+var mediaGCCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Delete images not referenced by any blog",
+	Run: func(cmd *cobra.Command, args []string) {
+		// 誤って削除しないよう、--deleteを指定した場合のみ削除する
+		deleteObjects, _ := cmd.Flags().GetBool("delete")
+		dryRun := !deleteObjects
+		gracePeriod, _ := cmd.Flags().GetDuration("grace-period")
+		if gracePeriod < 0 {
+			fmt.Println("--grace-period must not be negative")
+			os.Exit(1)
+		}
+		ctx := cmd.Context()
+		cfg, err := config.NewConfig()
+		if err != nil {
+			log.Fatalf("failed to create config: %v", err)
+		}
+		db, err := infrastracture.NewDBPostgres(ctx, cfg)
+		if err != nil {
+			fmt.Printf("failed to create db: %v", err)
+			os.Exit(1)
+		}
+		c := clocker.RealClocker{}
+		storage, err := adapter.NewStorageAdapter(cfg, &c)
+		if err != nil {
+			fmt.Printf("failed to create storage adapter: %v", err)
+			os.Exit(1)
+		}
+		gcService := media_gc_service.NewMediaGCService(
+			storage, &c, cfg.AWSS3ThumbnailDirectory, cfg.AWSSS3ContentImageDirectory)
+		userRepo, err := repository.NewUserRepository(&c)
+		if err != nil {
+			fmt.Printf("failed to create user repository: %v", err)
+			os.Exit(1)
+		}
+		usecase := gc_media.NewUsecase(
+			db, repository.NewBlogRepository(&c), userRepo, repository.NewMediaRepository(&c), gcService)
+		objects, err := usecase.Run(ctx, gracePeriod, dryRun)
+		var size int64
+		for _, o := range objects {
+			size += o.Size
+			fmt.Printf("%s\t%d\t%s\n", o.Key, o.Size, o.LastModified.Format(time.RFC3339))
+		}
+		if dryRun {
+			if err != nil {
+				fmt.Printf("failed to gc media: %v", err)
+				os.Exit(1)
+			}
+			fmt.Printf("found %d unreferenced objects (%d bytes), run with --delete to delete them\n", len(objects), size)
+			return
+		}
+		fmt.Printf("deleted %d unreferenced objects (%d bytes)\n", len(objects), size)
+		if err != nil {
+			fmt.Printf("failed to gc media: %v", err)
+			os.Exit(1)
+		}
+	},
+}
+
+var mediaBackfillReferencesCmd = &cobra.Command{
+	Use:   "backfill-references",
+	Short: "Rebuild media references of all blogs",
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		cfg, err := config.NewConfig()
+		if err != nil {
+			log.Fatalf("failed to create config: %v", err)
+		}
+		db, err := infrastracture.NewDBPostgres(ctx, cfg)
+		if err != nil {
+			fmt.Printf("failed to create db: %v", err)
+			os.Exit(1)
+		}
+		c := clocker.RealClocker{}
+		usecase := backfill_media_references.NewUsecase(
+			db, repository.NewBlogRepository(&c), repository.NewMediaRepository(&c))
+		count, err := usecase.Run(ctx)
+		if err != nil {
+			fmt.Printf("failed to backfill media references: %v", err)
+			os.Exit(1)
+		}
+		fmt.Printf("rebuilt media references of %d blogs\n", count)
+	},
+}
+
+func init() {
+	mediaGCCmd.Flags().Bool("delete", false, "delete unreferenced objects instead of only reporting them")
+	mediaGCCmd.Flags().Duration("grace-period", 7*24*time.Hour, "keep objects modified within this period")
+
+	mediaCmd.AddCommand(mediaGCCmd)
+	mediaCmd.AddCommand(mediaBackfillReferencesCmd)
+	rootCmd.AddCommand(mediaCmd)
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/shoet/blog/internal/clocker"
+	"github.com/shoet/blog/internal/config"
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/repository"
+	"github.com/shoet/blog/internal/usecase/render_blogs"
+	"github.com/spf13/cobra"
+)
+
+var renderBlogsCmd = &cobra.Command{
+	Use:   "render-blogs",
+	Short: "Re-render markdown of all blogs and update the cache",
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		cfg, err := config.NewConfig()
+		if err != nil {
+			log.Fatalf("failed to create config: %v", err)
+		}
+		db, err := infrastracture.NewDBPostgres(ctx, cfg)
+		if err != nil {
+			fmt.Printf("failed to create db: %v", err)
+			os.Exit(1)
+		}
+		c := clocker.RealClocker{}
+		usecase := render_blogs.NewUsecase(db, repository.NewBlogRepository(&c))
+		count, err := usecase.Run(ctx)
+		if err != nil {
+			fmt.Printf("failed to render blogs: %v", err)
+			os.Exit(1)
+		}
+		fmt.Printf("rendered %d blogs\n", count)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(renderBlogsCmd)
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/shoet/blog/internal/clocker"
+	"github.com/shoet/blog/internal/config"
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/infrastracture/repository"
+	"github.com/shoet/blog/internal/infrastracture/services/login_guard_service"
+	"github.com/spf13/cobra"
+)
+
+var unlockLoginCmd = &cobra.Command{
+	Use:   "unlock-login",
+	Short: "Unlock login locked out by repeated failures",
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		email, _ := cmd.Flags().GetString("email")
+		ipAddress, _ := cmd.Flags().GetString("ip")
+		if email == "" && ipAddress == "" {
+			fmt.Println("either --email or --ip is required")
+			os.Exit(1)
+		}
+		cfg, err := config.NewConfig()
+		if err != nil {
+			log.Fatalf("failed to create config: %v", err)
+		}
+		db, err := infrastracture.NewDBPostgres(ctx, cfg)
+		if err != nil {
+			fmt.Printf("failed to create db: %v", err)
+			os.Exit(1)
+		}
+		kvs, err := infrastracture.NewRedisKVS(
+			ctx, cfg.KVSHost, cfg.KVSPort, cfg.KVSUser, cfg.KVSPass, cfg.JWTExpiresInSec, cfg.KVSTlsEnabled)
+		if err != nil {
+			fmt.Printf("failed to create redis kvs: %v", err)
+			os.Exit(1)
+		}
+		c := clocker.RealClocker{}
+		lockoutRepo := repository.NewLoginLockoutRepository(&c)
+		loginGuard := login_guard_service.NewLoginGuardService(
+			db, kvs, lockoutRepo, &c, login_guard_service.NewLimits(cfg))
+
+		if err := loginGuard.Reset(ctx, email, ipAddress); err != nil {
+			fmt.Printf("failed to unlock login: %v", err)
+			os.Exit(1)
+		}
+		if email != "" {
+			lockouts, err := lockoutRepo.ListByTarget(ctx, db, models.LoginLockoutTargetEmail, email)
+			if err != nil {
+				fmt.Printf("failed to list login lockouts: %v", err)
+				os.Exit(1)
+			}
+			for _, l := range lockouts {
+				fmt.Printf("lockout: failures=%d locked_until=%s ip=%s\n",
+					l.Failures, time.Unix(int64(l.LockedUntil), 0).Format(time.RFC3339), l.IpAddress)
+			}
+		}
+		fmt.Println("unlocked")
+	},
+}
+
+func init() {
+	unlockLoginCmd.Flags().String("email", "", "email address to unlock")
+	unlockLoginCmd.Flags().String("ip", "", "ip address to unlock")
+	rootCmd.AddCommand(unlockLoginCmd)
+}
@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shoet/blog/internal/clocker"
+	"github.com/shoet/blog/internal/config"
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/infrastracture/repository"
+	"github.com/shoet/blog/internal/infrastracture/services/auth_service"
+	"github.com/shoet/blog/internal/infrastracture/services/jwt_service"
+	"github.com/shoet/blog/internal/usecase/create_user"
+	"github.com/shoet/blog/internal/usecase/get_user"
+	"github.com/shoet/blog/internal/usecase/get_users"
+	"github.com/shoet/blog/internal/usecase/put_user"
+	"github.com/shoet/blog/internal/usecase/reset_user_password"
+	"github.com/spf13/cobra"
+)
+
+var userCmd = &cobra.Command{
+	Use:   "user",
+	Short: "Manage users",
+}
+
+// userDependencies は、ユーザー管理のコマンドで使用する依存関係
+type userDependencies struct {
+	db          *sqlx.DB
+	userRepo    *repository.UserRepository
+	authService *auth_service.AuthService
+}
+
+// newUserDependencies は、DBとセッションの失効に使用するKVSに接続する
+func newUserDependencies(cmd *cobra.Command) *userDependencies {
+	ctx := cmd.Context()
+	cfg, err := config.NewConfig()
+	if err != nil {
+		log.Fatalf("failed to create config: %v", err)
+	}
+	db, err := infrastracture.NewDBPostgres(ctx, cfg)
+	if err != nil {
+		fmt.Printf("failed to create db: %v", err)
+		os.Exit(1)
+	}
+	kvs, err := infrastracture.NewRedisKVS(
+		ctx, cfg.KVSHost, cfg.KVSPort, cfg.KVSUser, cfg.KVSPass, cfg.JWTExpiresInSec, cfg.KVSTlsEnabled)
+	if err != nil {
+		fmt.Printf("failed to create redis kvs: %v", err)
+		os.Exit(1)
+	}
+	c := clocker.RealClocker{}
+	jwtService := jwt_service.NewJWTService(
+		kvs, &c, []byte(cfg.JWTSecret), cfg.JWTExpiresInSec, cfg.RefreshTokenExpiresInSec)
+	userRepo, err := repository.NewUserRepository(&c)
+	if err != nil {
+		fmt.Printf("failed to create user repository: %v", err)
+		os.Exit(1)
+	}
+	authService, err := auth_service.NewAuthService(
+		db, userRepo, repository.NewAPIKeyRepository(&c), jwtService, &c)
+	if err != nil {
+		fmt.Printf("failed to create auth service: %v", err)
+		os.Exit(1)
+	}
+	return &userDependencies{
+		db:          db,
+		userRepo:    userRepo,
+		authService: authService,
+	}
+}
+
+// getUserByEmail は、--emailで指定したユーザーを取得する
+func (d *userDependencies) getUserByEmail(cmd *cobra.Command) *models.User {
+	email, _ := cmd.Flags().GetString("email")
+	if email == "" {
+		fmt.Println("--email is required")
+		os.Exit(1)
+	}
+	user, err := get_user.NewUsecase(d.db, d.userRepo).RunByEmail(cmd.Context(), email)
+	if err != nil {
+		fmt.Printf("failed to get user: %v", err)
+		os.Exit(1)
+	}
+	return user
+}
+
+// putUser は、ユーザーの現在の値にupdateで変更を加えて更新する
+func (d *userDependencies) putUser(cmd *cobra.Command, user *models.User, update func(input *put_user.Input)) {
+	input := &put_user.Input{
+		Name:     user.Name,
+		Email:    user.Email,
+		Role:     user.Role,
+		Disabled: user.IsDisabled(),
+	}
+	update(input)
+	c := clocker.RealClocker{}
+	usecase := put_user.NewUsecase(d.db, d.userRepo, &c, d.authService)
+	if _, err := usecase.Run(cmd.Context(), user.Id, input); err != nil {
+		fmt.Printf("failed to update user: %v", err)
+		os.Exit(1)
+	}
+}
+
+var userListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List users",
+	Run: func(cmd *cobra.Command, args []string) {
+		deps := newUserDependencies(cmd)
+		users, err := get_users.NewUsecase(deps.db, deps.userRepo).Run(cmd.Context())
+		if err != nil {
+			fmt.Printf("failed to list users: %v", err)
+			os.Exit(1)
+		}
+		for _, u := range users {
+			status := "active"
+			if u.IsDisabled() {
+				status = "disabled"
+			}
+			fmt.Printf("%d\t%s\t%s\t%s\t%s\n", u.Id, u.Email, u.Name, u.Role, status)
+		}
+	},
+}
+
+var userAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add user",
+	Run: func(cmd *cobra.Command, args []string) {
+		email, _ := cmd.Flags().GetString("email")
+		name, _ := cmd.Flags().GetString("name")
+		password, _ := cmd.Flags().GetString("password")
+		role, _ := cmd.Flags().GetString("role")
+		if email == "" || name == "" || password == "" {
+			fmt.Println("--email, --name and --password are required")
+			os.Exit(1)
+		}
+		deps := newUserDependencies(cmd)
+		user, err := create_user.NewUsecase(deps.db, deps.userRepo).Run(cmd.Context(), &create_user.Input{
+			Name:     name,
+			Email:    email,
+			Password: password,
+			Role:     models.Role(role),
+		})
+		if err != nil {
+			fmt.Printf("failed to add user: %v", err)
+			os.Exit(1)
+		}
+		fmt.Printf("added %d\n", user.Id)
+	},
+}
+
+var userDisableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Disable user and revoke all sessions",
+	Run: func(cmd *cobra.Command, args []string) {
+		enable, _ := cmd.Flags().GetBool("enable")
+		deps := newUserDependencies(cmd)
+		user := deps.getUserByEmail(cmd)
+		deps.putUser(cmd, user, func(input *put_user.Input) {
+			input.Disabled = !enable
+		})
+		if enable {
+			fmt.Printf("enabled %s\n", user.Email)
+			return
+		}
+		fmt.Printf("disabled %s\n", user.Email)
+	},
+}
+
+var userSetRoleCmd = &cobra.Command{
+	Use:   "set-role",
+	Short: "Set role of user",
+	Run: func(cmd *cobra.Command, args []string) {
+		role, _ := cmd.Flags().GetString("role")
+		deps := newUserDependencies(cmd)
+		user := deps.getUserByEmail(cmd)
+		deps.putUser(cmd, user, func(input *put_user.Input) {
+			input.Role = models.Role(role)
+		})
+		fmt.Printf("set role of %s to %s\n", user.Email, role)
+	},
+}
+
+var userResetPasswordCmd = &cobra.Command{
+	Use:   "reset-password",
+	Short: "Reset password of user and revoke all sessions",
+	Run: func(cmd *cobra.Command, args []string) {
+		password, _ := cmd.Flags().GetString("password")
+		if password == "" {
+			fmt.Println("--password is required")
+			os.Exit(1)
+		}
+		deps := newUserDependencies(cmd)
+		user := deps.getUserByEmail(cmd)
+		usecase := reset_user_password.NewUsecase(deps.db, deps.userRepo, deps.authService)
+		if err := usecase.Run(cmd.Context(), user.Id, password); err != nil {
+			fmt.Printf("failed to reset password: %v", err)
+			os.Exit(1)
+		}
+		fmt.Printf("reset password of %s\n", user.Email)
+	},
+}
+
+func roleFlagUsage() string {
+	return fmt.Sprintf("role of the user (%s)", strings.Join([]string{
+		string(models.RoleAdmin), string(models.RoleEditor), string(models.RoleAuthor), string(models.RoleViewer),
+	}, ", "))
+}
+
+func init() {
+	userAddCmd.Flags().String("email", "", "email of the user")
+	userAddCmd.Flags().String("name", "", "name of the user")
+	userAddCmd.Flags().String("password", "", "password of the user")
+	userAddCmd.Flags().String("role", string(models.RoleAuthor), roleFlagUsage())
+
+	userDisableCmd.Flags().String("email", "", "email of the user")
+	userDisableCmd.Flags().Bool("enable", false, "enable the disabled user instead")
+
+	userSetRoleCmd.Flags().String("email", "", "email of the user")
+	userSetRoleCmd.Flags().String("role", "", roleFlagUsage())
+
+	userResetPasswordCmd.Flags().String("email", "", "email of the user")
+	userResetPasswordCmd.Flags().String("password", "", "new password of the user")
+
+	userCmd.AddCommand(userListCmd, userAddCmd, userDisableCmd, userSetRoleCmd, userResetPasswordCmd)
+	rootCmd.AddCommand(userCmd)
+}
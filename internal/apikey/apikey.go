@@ -0,0 +1,70 @@
+// Package apikey は、自動化クライアント向けのAPIキーの生成と解析を行う
+// キーは blog_<prefix>_<secret> の形式で、prefixは識別用に平文で保存し、キー全体はハッシュ化して保存する
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+const (
+	keyPrefix    = "blog_"
+	prefixLength = 8
+)
+
+// prefixAlphabet は、識別用のprefixに使用する文字
+const prefixAlphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+// Generate は、新しいAPIキーと識別用のprefix、保存用のハッシュを返す
+func Generate() (key string, prefix string, hash string, err error) {
+	b := make([]byte, prefixLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", "", fmt.Errorf("failed to read random: %w", err)
+	}
+	for i := range b {
+		b[i] = prefixAlphabet[int(b[i])%len(prefixAlphabet)]
+	}
+	prefix = string(b)
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", "", fmt.Errorf("failed to read random: %w", err)
+	}
+	key = keyPrefix + prefix + "_" + base64.RawURLEncoding.EncodeToString(secret)
+	return key, prefix, Hash(key), nil
+}
+
+// IsAPIKey は、トークンがAPIキーの形式かを判定する
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, keyPrefix)
+}
+
+// Parse は、APIキーから識別用のprefixを取り出す
+func Parse(key string) (prefix string, ok bool) {
+	if !IsAPIKey(key) {
+		return "", false
+	}
+	rest := strings.TrimPrefix(key, keyPrefix)
+	prefix, secret, found := strings.Cut(rest, "_")
+	if !found || len(prefix) != prefixLength || secret == "" {
+		return "", false
+	}
+	return prefix, true
+}
+
+// Hash は、APIキーの保存用のハッシュを返す
+// キーは十分なエントロピーを持つため、bcryptではなくSHA-256で照合する
+func Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Verify は、APIキーと保存済みのハッシュが一致するかを判定する
+func Verify(key string, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(Hash(key)), []byte(hash)) == 1
+}
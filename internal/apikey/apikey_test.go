@@ -0,0 +1,52 @@
+package apikey_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shoet/blog/internal/apikey"
+)
+
+func Test_Generate(t *testing.T) {
+	key, prefix, hash, err := apikey.Generate()
+	if err != nil {
+		t.Fatalf("failed to generate api key: %v", err)
+	}
+	if !strings.HasPrefix(key, "blog_"+prefix+"_") {
+		t.Errorf("key %s does not contain prefix %s", key, prefix)
+	}
+	got, ok := apikey.Parse(key)
+	if !ok || got != prefix {
+		t.Errorf("want prefix %s, but got %s, %v", prefix, got, ok)
+	}
+	if !apikey.Verify(key, hash) {
+		t.Errorf("failed to verify generated key")
+	}
+	if apikey.Verify(key+"x", hash) {
+		t.Errorf("want not verified for other key")
+	}
+}
+
+func Test_Parse(t *testing.T) {
+	tests := []struct {
+		name   string
+		key    string
+		want   string
+		wantOk bool
+	}{
+		{name: "valid", key: "blog_abcd1234_secret", want: "abcd1234", wantOk: true},
+		{name: "jwt", key: "eyJhbGciOiJIUzI1NiJ9.e30.sig", wantOk: false},
+		{name: "short prefix", key: "blog_abc_secret", wantOk: false},
+		{name: "no secret", key: "blog_abcd1234_", wantOk: false},
+		{name: "no separator", key: "blog_abcd1234", wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := apikey.Parse(tt.key)
+			if ok != tt.wantOk || got != tt.want {
+				t.Errorf("want %s, %v, but got %s, %v", tt.want, tt.wantOk, got, ok)
+			}
+		})
+	}
+}
@@ -21,18 +21,42 @@ type Config struct {
 	KVSPass                     string `env:"BLOG_KVS_PASS,required"`
 	KVSTlsEnabled               bool   `env:"BLOG_KVS_TLS_ENABLED" envDefault:"false"`
 	AWSS3Region                 string `env:"AWS_DEFAULT_REGION"`
-	AWSS3Bucket                 string `env:"BLOG_AWS_S3_BUCKET,required"`
+	AWSS3Bucket                 string `env:"BLOG_AWS_S3_BUCKET"`
 	AWSS3ThumbnailDirectory     string `env:"BLOG_AWS_S3_THUMBNAIL_DIRECTORY,required"`
 	AWSSS3ContentImageDirectory string `env:"BLOG_AWS_S3_CONTENT_IMAGE_DIRECTORY,required"`
 	AWSS3PresignPutExpiresSec   int64  `env:"BLOG_AWS_S3_PRESIGN_PUT_EXPIRES_SEC" envDefault:"300"`
+	Storage                     string `env:"STORAGE" envDefault:"s3"`
+	StorageLocalDir             string `env:"STORAGE_LOCAL_DIR" envDefault:"./tmp/storage"`
+	StorageLocalBaseURL         string `env:"STORAGE_LOCAL_BASE_URL"`
+	StorageLocalSecret          string `env:"STORAGE_LOCAL_SECRET"`
+	UploadMaxSize               int64  `env:"UPLOAD_MAX_SIZE" envDefault:"10485760"`
 	AdminName                   string `env:"ADMIN_NAME,required"`
 	AdminEmail                  string `env:"ADMIN_EMAIL,required"`
 	AdminPassword               string `env:"ADMIN_PASSWORD,required"`
 	JWTSecret                   string `env:"JWT_SECRET,required"`
-	JWTExpiresInSec             int    `env:"JWT_EXPIRES_IN_SEC" envDefault:"86400"`
+	JWTExpiresInSec             int    `env:"JWT_EXPIRES_IN_SEC" envDefault:"900"`
+	RefreshTokenExpiresInSec    int    `env:"REFRESH_TOKEN_EXPIRES_IN_SEC" envDefault:"2592000"`
+	LoginMaxFailuresPerEmail    int64  `env:"LOGIN_MAX_FAILURES_PER_EMAIL" envDefault:"5"`
+	LoginMaxFailuresPerIp       int64  `env:"LOGIN_MAX_FAILURES_PER_IP" envDefault:"20"`
+	LoginFailureWindowSec       int    `env:"LOGIN_FAILURE_WINDOW_SEC" envDefault:"3600"`
+	LoginLockoutBaseSec         int    `env:"LOGIN_LOCKOUT_BASE_SEC" envDefault:"60"`
+	LoginLockoutMaxSec          int    `env:"LOGIN_LOCKOUT_MAX_SEC" envDefault:"3600"`
+	PasswordResetURL            string `env:"PASSWORD_RESET_URL"`
+	PasswordResetExpiresInSec   int    `env:"PASSWORD_RESET_EXPIRES_IN_SEC" envDefault:"3600"`
+	PasswordResetRateLimit      int    `env:"PASSWORD_RESET_RATE_LIMIT" envDefault:"5"`
+	Mailer                      string `env:"MAILER"`
+	MailerFileDir               string `env:"MAILER_FILE_DIR" envDefault:"./tmp/mail"`
+	MailFrom                    string `env:"MAIL_FROM" envDefault:"noreply@localhost"`
+	TOTPIssuer                  string `env:"TOTP_ISSUER" envDefault:"blog"`
 	CORSWhiteList               string `env:"CORS_WHITE_LIST"`
+	TrustedProxies              string `env:"TRUSTED_PROXIES"`
 	SiteDomain                  string `env:"SITE_DOMAIN"`
 	CdnDomain                   string `env:"CDN_DOMAIN"`
+	FeedTitle                   string `env:"FEED_TITLE" envDefault:"blog"`
+	FeedDescription             string `env:"FEED_DESCRIPTION"`
+	RobotsDisallow              string `env:"ROBOTS_DISALLOW" envDefault:"/admin,/auth,/files"`
+	CommentRateLimit            int    `env:"COMMENT_RATE_LIMIT" envDefault:"5"`
+	CommentRateLimitWindowSec   int    `env:"COMMENT_RATE_LIMIT_WINDOW_SEC" envDefault:"600"`
 	GitHubPersonalAccessToken   string `env:"GITHUB_PERSONAL_ACCESS_TOKEN"`
 }
 
@@ -0,0 +1,258 @@
+package feed
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"mime"
+	"path"
+	"time"
+)
+
+// Feed は、RSS, Atom, JSON Feedの共通のフィード定義
+type Feed struct {
+	Title       string
+	Description string
+	Author      string
+	// SiteURLはサイトのトップページのURL
+	SiteURL string
+	// FeedURLはフィード自身のURL
+	FeedURL string
+	// Generatedはフィードを生成した日時で、記事がない場合の最終更新日時とする
+	Generated time.Time
+	Items     []*Item
+}
+
+// Item は、フィードに含める記事
+type Item struct {
+	Id        string
+	Title     string
+	Link      string
+	Summary   string
+	ImageURL  string
+	Tags      []string
+	Published time.Time
+	Updated   time.Time
+}
+
+// Updated は、フィードの最終更新日時として記事の更新日時の最大値を返す
+// 記事がない場合はGeneratedを返す
+func (f *Feed) Updated() time.Time {
+	if len(f.Items) == 0 {
+		return f.Generated
+	}
+	var updated time.Time
+	for _, item := range f.Items {
+		if item.Updated.After(updated) {
+			updated = item.Updated
+		}
+	}
+	return updated
+}
+
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string     `xml:"title"`
+	Link          string     `xml:"link"`
+	Description   string     `xml:"description"`
+	AtomLink      atomLink   `xml:"atom:link"`
+	LastBuildDate string     `xml:"lastBuildDate,omitempty"`
+	Items         []*rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link"`
+	Guid        rssGuid       `xml:"guid"`
+	Description string        `xml:"description"`
+	PubDate     string        `xml:"pubDate"`
+	Categories  []string      `xml:"category"`
+	Enclosure   *rssEnclosure `xml:"enclosure"`
+}
+
+type rssGuid struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Type   string `xml:"type,attr"`
+	Length int    `xml:"length,attr"`
+}
+
+// RSS は、RSS 2.0形式のフィードを生成する
+func RSS(f *Feed) ([]byte, error) {
+	channel := rssChannel{
+		Title:       f.Title,
+		Link:        f.SiteURL,
+		Description: f.Description,
+		AtomLink:    atomLink{Href: f.FeedURL, Rel: "self", Type: "application/rss+xml"},
+	}
+	if updated := f.Updated(); !updated.IsZero() {
+		channel.LastBuildDate = updated.UTC().Format(time.RFC1123Z)
+	}
+	for _, item := range f.Items {
+		i := &rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			Guid:        rssGuid{IsPermaLink: item.Id == item.Link, Value: item.Id},
+			Description: item.Summary,
+			PubDate:     item.Published.UTC().Format(time.RFC1123Z),
+			Categories:  item.Tags,
+		}
+		if item.ImageURL != "" {
+			// 画像サイズは不明のため0とする
+			i.Enclosure = &rssEnclosure{URL: item.ImageURL, Type: imageType(item.ImageURL)}
+		}
+		channel.Items = append(channel.Items, i)
+	}
+	return marshalXML(&rss{Version: "2.0", AtomNS: "http://www.w3.org/2005/Atom", Channel: channel})
+}
+
+type atomFeed struct {
+	XMLName xml.Name     `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string       `xml:"title"`
+	Id      string       `xml:"id"`
+	Links   []atomLink   `xml:"link"`
+	Updated string       `xml:"updated"`
+	Author  atomAuthor   `xml:"author"`
+	Entries []*atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	Title      string         `xml:"title"`
+	Id         string         `xml:"id"`
+	Links      []atomLink     `xml:"link"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Summary    string         `xml:"summary"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// Atom は、Atom 1.0形式のフィードを生成する
+func Atom(f *Feed) ([]byte, error) {
+	feed := &atomFeed{
+		Title: f.Title,
+		Id:    f.FeedURL,
+		Links: []atomLink{
+			{Href: f.SiteURL, Rel: "alternate", Type: "text/html"},
+			{Href: f.FeedURL, Rel: "self", Type: "application/atom+xml"},
+		},
+		Updated: f.Updated().UTC().Format(time.RFC3339),
+		Author:  atomAuthor{Name: f.Author},
+	}
+	for _, item := range f.Items {
+		entry := &atomEntry{
+			Title:     item.Title,
+			Id:        item.Id,
+			Links:     []atomLink{{Href: item.Link, Rel: "alternate", Type: "text/html"}},
+			Published: item.Published.UTC().Format(time.RFC3339),
+			Updated:   item.Updated.UTC().Format(time.RFC3339),
+			Summary:   item.Summary,
+		}
+		if item.ImageURL != "" {
+			entry.Links = append(entry.Links, atomLink{Href: item.ImageURL, Rel: "enclosure", Type: imageType(item.ImageURL)})
+		}
+		for _, tag := range item.Tags {
+			entry.Categories = append(entry.Categories, atomCategory{Term: tag})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	return marshalXML(feed)
+}
+
+type jsonFeed struct {
+	Version     string          `json:"version"`
+	Title       string          `json:"title"`
+	HomePageURL string          `json:"home_page_url"`
+	FeedURL     string          `json:"feed_url"`
+	Description string          `json:"description,omitempty"`
+	Authors     []jsonFeedActor `json:"authors,omitempty"`
+	Items       []*jsonFeedItem `json:"items"`
+}
+
+type jsonFeedActor struct {
+	Name string `json:"name"`
+}
+
+type jsonFeedItem struct {
+	Id            string   `json:"id"`
+	URL           string   `json:"url"`
+	Title         string   `json:"title"`
+	Summary       string   `json:"summary,omitempty"`
+	ContentText   string   `json:"content_text"`
+	Image         string   `json:"image,omitempty"`
+	DatePublished string   `json:"date_published"`
+	DateModified  string   `json:"date_modified"`
+	Tags          []string `json:"tags,omitempty"`
+}
+
+// JSON は、JSON Feed 1.1形式のフィードを生成する
+func JSON(f *Feed) ([]byte, error) {
+	feed := &jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       f.Title,
+		HomePageURL: f.SiteURL,
+		FeedURL:     f.FeedURL,
+		Description: f.Description,
+		Items:       []*jsonFeedItem{},
+	}
+	if f.Author != "" {
+		feed.Authors = []jsonFeedActor{{Name: f.Author}}
+	}
+	for _, item := range f.Items {
+		feed.Items = append(feed.Items, &jsonFeedItem{
+			Id:            item.Id,
+			URL:           item.Link,
+			Title:         item.Title,
+			Summary:       item.Summary,
+			ContentText:   item.Summary,
+			Image:         item.ImageURL,
+			DatePublished: item.Published.UTC().Format(time.RFC3339),
+			DateModified:  item.Updated.UTC().Format(time.RFC3339),
+			Tags:          item.Tags,
+		})
+	}
+	b, err := json.Marshal(feed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal json feed: %w", err)
+	}
+	return b, nil
+}
+
+func marshalXML(v interface{}) ([]byte, error) {
+	b, err := xml.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal xml feed: %w", err)
+	}
+	return append([]byte(xml.Header), b...), nil
+}
+
+// imageType は、画像URLの拡張子からMIMEタイプを推測する
+func imageType(url string) string {
+	if t := mime.TypeByExtension(path.Ext(url)); t != "" {
+		return t
+	}
+	return "application/octet-stream"
+}
@@ -0,0 +1,125 @@
+package feed_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shoet/blog/internal/feed"
+)
+
+func newTestFeed() *feed.Feed {
+	return &feed.Feed{
+		Title:       "blog",
+		Description: "description",
+		Author:      "author",
+		SiteURL:     "https://example.com",
+		FeedURL:     "https://example.com/feed.xml",
+		Items: []*feed.Item{
+			{
+				Id:        "https://example.com/blogs/2",
+				Title:     "title2 & <b>",
+				Link:      "https://example.com/blogs/2",
+				Summary:   "summary2",
+				ImageURL:  "https://cdn.example.com/thumbnail/2.png",
+				Tags:      []string{"go"},
+				Published: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+				Updated:   time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+			},
+			{
+				Id:        "https://example.com/blogs/1",
+				Title:     "title1",
+				Link:      "https://example.com/blogs/1",
+				Summary:   "summary1",
+				Published: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				Updated:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+}
+
+func Test_Feed_Updated(t *testing.T) {
+	f := newTestFeed()
+	want := time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)
+	if got := f.Updated(); !got.Equal(want) {
+		t.Errorf("Updated() = %v, want %v", got, want)
+	}
+	generated := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	if got := (&feed.Feed{Generated: generated}).Updated(); !got.Equal(generated) {
+		t.Errorf("Updated() = %v, want %v", got, generated)
+	}
+	// 生成日時は記事がない場合のみ使用する
+	f.Generated = generated
+	if got := f.Updated(); !got.Equal(want) {
+		t.Errorf("Updated() = %v, want %v", got, want)
+	}
+}
+
+func Test_RSS(t *testing.T) {
+	got, err := feed.RSS(newTestFeed())
+	if err != nil {
+		t.Fatalf("failed to build rss: %v", err)
+	}
+	assertContains(t, string(got), []string{
+		`<?xml version="1.0" encoding="UTF-8"?>`,
+		`<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom">`,
+		`<atom:link href="https://example.com/feed.xml" rel="self" type="application/rss+xml"></atom:link>`,
+		`<lastBuildDate>Wed, 03 Jan 2024 00:00:00 +0000</lastBuildDate>`,
+		`<title>title2 &amp; &lt;b&gt;</title>`,
+		`<guid isPermaLink="true">https://example.com/blogs/2</guid>`,
+		`<pubDate>Tue, 02 Jan 2024 00:00:00 +0000</pubDate>`,
+		`<category>go</category>`,
+		`<enclosure url="https://cdn.example.com/thumbnail/2.png" type="image/png" length="0"></enclosure>`,
+	})
+}
+
+func Test_Atom(t *testing.T) {
+	got, err := feed.Atom(newTestFeed())
+	if err != nil {
+		t.Fatalf("failed to build atom: %v", err)
+	}
+	assertContains(t, string(got), []string{
+		`<feed xmlns="http://www.w3.org/2005/Atom">`,
+		`<id>https://example.com/feed.xml</id>`,
+		`<updated>2024-01-03T00:00:00Z</updated>`,
+		`<author><name>author</name></author>`,
+		`<published>2024-01-02T00:00:00Z</published>`,
+		`<link href="https://cdn.example.com/thumbnail/2.png" rel="enclosure" type="image/png"></link>`,
+		`<category term="go"></category>`,
+	})
+
+	empty, err := feed.Atom(&feed.Feed{Generated: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatalf("failed to build atom: %v", err)
+	}
+	assertContains(t, string(empty), []string{`<updated>2024-02-01T00:00:00Z</updated>`})
+}
+
+func Test_JSON(t *testing.T) {
+	got, err := feed.JSON(newTestFeed())
+	if err != nil {
+		t.Fatalf("failed to build json feed: %v", err)
+	}
+	assertContains(t, string(got), []string{
+		`"version":"https://jsonfeed.org/version/1.1"`,
+		`"feed_url":"https://example.com/feed.xml"`,
+		`"image":"https://cdn.example.com/thumbnail/2.png"`,
+		`"date_modified":"2024-01-03T00:00:00Z"`,
+		`"tags":["go"]`,
+	})
+
+	empty, err := feed.JSON(&feed.Feed{})
+	if err != nil {
+		t.Fatalf("failed to build json feed: %v", err)
+	}
+	assertContains(t, string(empty), []string{`"items":[]`})
+}
+
+func assertContains(t *testing.T, got string, wants []string) {
+	t.Helper()
+	for _, want := range wants {
+		if !strings.Contains(got, want) {
+			t.Errorf("%q is not contained in:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package highlight
+
+import (
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DefaultLength は、スニペットの既定の文字数
+const DefaultLength = 120
+
+const (
+	markOpen  = "<mark>"
+	markClose = "</mark>"
+	ellipsis  = "…"
+)
+
+// Terms は、検索キーワードからハイライト対象の語句を取り出す
+// websearch_to_tsquery の構文に合わせ、"-" で始まる除外語と OR は対象外とする
+func Terms(keyword string) []string {
+	var terms []string
+	for _, f := range strings.Fields(keyword) {
+		if strings.HasPrefix(f, "-") || strings.EqualFold(f, "or") {
+			continue
+		}
+		f = strings.Trim(f, `"`)
+		if f == "" {
+			continue
+		}
+		terms = append(terms, strings.ToLower(f))
+	}
+	// 長い語句を優先して一致させる
+	sort.SliceStable(terms, func(i, j int) bool {
+		return len([]rune(terms[i])) > len([]rune(terms[j]))
+	})
+	return terms
+}
+
+// Snippet は、textsのうち最初にキーワードを含むテキストから、
+// 一致箇所の周辺maxRunes文字を切り出し、一致箇所を<mark>で囲んだHTMLを生成する
+// いずれのテキストにも一致しない場合は、最初の空でないテキストの先頭を返す
+func Snippet(keyword string, maxRunes int, texts ...string) string {
+	terms := Terms(keyword)
+	for _, text := range texts {
+		runes := []rune(collapseSpaces(text))
+		lower := toLowerRunes(runes)
+		pos := firstMatch(lower, terms)
+		if pos < 0 {
+			continue
+		}
+		// 一致箇所が先頭寄りに来るように切り出す
+		start := pos - maxRunes/4
+		if start < 0 {
+			start = 0
+		}
+		end := start + maxRunes
+		if end > len(runes) {
+			end = len(runes)
+		}
+		return render(runes, lower, terms, start, end)
+	}
+	for _, text := range texts {
+		runes := []rune(collapseSpaces(text))
+		if len(runes) == 0 {
+			continue
+		}
+		end := maxRunes
+		if end > len(runes) {
+			end = len(runes)
+		}
+		return render(runes, nil, nil, 0, end)
+	}
+	return ""
+}
+
+func render(runes []rune, lower []rune, terms []string, start int, end int) string {
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString(ellipsis)
+	}
+	for i := start; i < end; {
+		n := 0
+		if lower != nil {
+			n = matchAt(lower[:end], i, terms)
+		}
+		if n > 0 {
+			b.WriteString(markOpen)
+			b.WriteString(html.EscapeString(string(runes[i : i+n])))
+			b.WriteString(markClose)
+			i += n
+			continue
+		}
+		b.WriteString(html.EscapeString(string(runes[i])))
+		i++
+	}
+	if end < len(runes) {
+		b.WriteString(ellipsis)
+	}
+	return b.String()
+}
+
+// firstMatch は、いずれかの語句に一致する最初の位置を返す
+func firstMatch(lower []rune, terms []string) int {
+	for i := range lower {
+		if matchAt(lower, i, terms) > 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// matchAt は、位置iから一致する語句の文字数を返す
+func matchAt(lower []rune, i int, terms []string) int {
+	for _, term := range terms {
+		t := []rune(term)
+		if i+len(t) > len(lower) {
+			continue
+		}
+		if string(lower[i:i+len(t)]) == term {
+			return len(t)
+		}
+	}
+	return 0
+}
+
+// toLowerRunes は、文字数を変えずに小文字へ変換する
+func toLowerRunes(runes []rune) []rune {
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+	return lower
+}
+
+// collapseSpaces は、改行を含む連続した空白を1つの空白にまとめる
+func collapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
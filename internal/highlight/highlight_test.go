@@ -0,0 +1,81 @@
+package highlight_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/shoet/blog/internal/highlight"
+)
+
+func Test_Terms(t *testing.T) {
+	tests := []struct {
+		name    string
+		keyword string
+		want    []string
+	}{
+		{name: "単語", keyword: "Go", want: []string{"go"}},
+		{name: "複数語は長い順", keyword: "Go ブログ開発", want: []string{"ブログ開発", "go"}},
+		{name: "除外語とORは対象外", keyword: `"chi router" or -echo`, want: []string{"router", "chi"}},
+		{name: "空", keyword: " ", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := highlight.Terms(tt.keyword)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Terms() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_Snippet(t *testing.T) {
+	type args struct {
+		keyword  string
+		maxRunes int
+		texts    []string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "一致箇所を囲む",
+			args: args{keyword: "go", maxRunes: 40, texts: []string{"Goで\nブログを書く。go!"}},
+			want: "<mark>Go</mark>で ブログを書く。<mark>go</mark>!",
+		},
+		{
+			name: "一致箇所の周辺を切り出す",
+			args: args{keyword: "検索", maxRunes: 8, texts: []string{"あいうえおかきくけこ検索さしすせそたちつてと"}},
+			want: "…けこ<mark>検索</mark>さしすせ…",
+		},
+		{
+			name: "HTMLをエスケープする",
+			args: args{keyword: "tag", maxRunes: 40, texts: []string{"<script>tag</script>"}},
+			want: "&lt;script&gt;<mark>tag</mark>&lt;/script&gt;",
+		},
+		{
+			name: "後続のテキストで一致",
+			args: args{keyword: "本文", maxRunes: 40, texts: []string{"概要", "本文です"}},
+			want: "<mark>本文</mark>です",
+		},
+		{
+			name: "一致しない場合は先頭を返す",
+			args: args{keyword: "なし", maxRunes: 3, texts: []string{"", "概要です"}},
+			want: "概要で…",
+		},
+		{
+			name: "テキストが空",
+			args: args{keyword: "なし", maxRunes: 3, texts: []string{""}},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := highlight.Snippet(tt.args.keyword, tt.args.maxRunes, tt.args.texts...)
+			if got != tt.want {
+				t.Errorf("Snippet() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+// Package imaging は、アップロードされた画像の検証・リサイズ・再エンコードを行う
+// 再エンコードした画像にはEXIFなどのメタデータは含まれない
+// WebPのエンコードはcgoを必要とするため、CGO_ENABLED=0でビルドした場合はWebPSupportedがfalseとなり、
+// WebPへのエンコードはErrUnsupportedImageを返す
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+
+	"golang.org/x/image/draw"
+)
+
+const (
+	ContentTypeJPEG = "image/jpeg"
+	ContentTypePNG  = "image/png"
+	ContentTypeGIF  = "image/gif"
+	ContentTypeWebP = "image/webp"
+)
+
+// MaxPixels は、デコードを許可する画像の最大の画素数
+// 巨大な画像の展開によるメモリの枯渇を防ぐ
+const MaxPixels = 40_000_000
+
+var (
+	ErrUnsupportedImage = errors.New("unsupported image type")
+	ErrImageTooLarge    = errors.New("image is too large")
+)
+
+var supportedContentTypes = []string{ContentTypeJPEG, ContentTypePNG, ContentTypeGIF, ContentTypeWebP}
+
+// Extension は、Content-Typeに対応するファイルの拡張子を返す
+func Extension(contentType string) string {
+	switch contentType {
+	case ContentTypeJPEG:
+		return ".jpg"
+	case ContentTypePNG:
+		return ".png"
+	case ContentTypeGIF:
+		return ".gif"
+	case ContentTypeWebP:
+		return ".webp"
+	default:
+		return ""
+	}
+}
+
+// DetectContentType は、ファイル名や申告されたContent-Typeではなく内容から画像の種類を判定する
+// 対応していない種類の場合はErrUnsupportedImageを返す
+func DetectContentType(b []byte) (string, error) {
+	contentType := http.DetectContentType(b)
+	for _, t := range supportedContentTypes {
+		if contentType == t {
+			return contentType, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrUnsupportedImage, contentType)
+}
+
+// Decode は、画像を検証してデコードする
+// JPEGのEXIFに回転の指定がある場合は、メタデータを除去しても正しい向きになるよう画素に適用する
+func Decode(b []byte) (image.Image, string, error) {
+	contentType, err := DetectContentType(b)
+	if err != nil {
+		return nil, "", err
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(b))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode image config: %w", err)
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > MaxPixels {
+		return nil, "", fmt.Errorf("%w: %dx%d", ErrImageTooLarge, config.Width, config.Height)
+	}
+	img, _, err := image.Decode(bytes.NewReader(b))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode image: %w", err)
+	}
+	if contentType == ContentTypeJPEG {
+		img = applyOrientation(img, jpegOrientation(b))
+	}
+	return img, contentType, nil
+}
+
+// Resize は、縦横比を保ったまま画像を指定した幅に縮小する
+// 元の画像の幅が指定した幅以下の場合は拡大しない
+func Resize(img image.Image, width int) image.Image {
+	bounds := img.Bounds()
+	if width <= 0 || bounds.Dx() <= width {
+		return img
+	}
+	height := (bounds.Dy()*width + bounds.Dx()/2) / bounds.Dx()
+	if height < 1 {
+		height = 1
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+	return dst
+}
+
+// Encode は、画像をContent-Typeの形式でエンコードする
+// JPEGは透過に対応していないため、白の背景に合成する
+func Encode(w io.Writer, img image.Image, contentType string) error {
+	switch contentType {
+	case ContentTypeJPEG:
+		bounds := img.Bounds()
+		dst := image.NewRGBA(bounds)
+		draw.Draw(dst, bounds, image.NewUniform(color.White), image.Point{}, draw.Src)
+		draw.Draw(dst, bounds, img, bounds.Min, draw.Over)
+		if err := jpeg.Encode(w, dst, &jpeg.Options{Quality: 85}); err != nil {
+			return fmt.Errorf("failed to encode jpeg: %w", err)
+		}
+	case ContentTypePNG:
+		if err := png.Encode(w, img); err != nil {
+			return fmt.Errorf("failed to encode png: %w", err)
+		}
+	case ContentTypeGIF:
+		if err := gif.Encode(w, img, nil); err != nil {
+			return fmt.Errorf("failed to encode gif: %w", err)
+		}
+	case ContentTypeWebP:
+		if err := encodeWebP(w, img); err != nil {
+			return fmt.Errorf("failed to encode webp: %w", err)
+		}
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedImage, contentType)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package imaging_test
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+
+	"github.com/shoet/blog/internal/imaging"
+)
+
+// newJPEG は、幅w・高さhのJPEGを生成する
+// exifを指定した場合はAPP1セグメントとしてSOIの直後に挿入する
+func newJPEG(t *testing.T, w, h int, exif []byte) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for x := 0; x < w; x++ {
+		img.Set(x, 0, color.RGBA{R: 255, A: 255})
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatalf("failed to encode jpeg: %v", err)
+	}
+	b := buf.Bytes()
+	if exif == nil {
+		return b
+	}
+	size := len(exif) + 2
+	segment := append([]byte{0xFF, 0xE1, byte(size >> 8), byte(size)}, exif...)
+	return append(append(append([]byte{}, b[:2]...), segment...), b[2:]...)
+}
+
+// exifWithOrientation は、Orientationタグのみを含むEXIF(ビッグエンディアン)を生成する
+func exifWithOrientation(o byte) []byte {
+	return []byte{
+		'E', 'x', 'i', 'f', 0, 0,
+		'M', 'M', 0, 42, 0, 0, 0, 8,
+		0, 1,
+		0x01, 0x12, 0, 3, 0, 0, 0, 1, 0, o, 0, 0,
+		0, 0, 0, 0,
+	}
+}
+
+func Test_DetectContentType(t *testing.T) {
+	if got, err := imaging.DetectContentType(newJPEG(t, 2, 2, nil)); err != nil || got != imaging.ContentTypeJPEG {
+		t.Errorf("want %s, but got %s, %v", imaging.ContentTypeJPEG, got, err)
+	}
+	if _, err := imaging.DetectContentType([]byte("<svg></svg>")); !errors.Is(err, imaging.ErrUnsupportedImage) {
+		t.Errorf("want %v, but got %v", imaging.ErrUnsupportedImage, err)
+	}
+}
+
+func Test_Decode_Orientation(t *testing.T) {
+	tests := []struct {
+		name        string
+		orientation byte
+		wantWidth   int
+		wantHeight  int
+	}{
+		{name: "no rotation", orientation: 1, wantWidth: 40, wantHeight: 20},
+		{name: "rotate 180", orientation: 3, wantWidth: 40, wantHeight: 20},
+		{name: "rotate 90", orientation: 6, wantWidth: 20, wantHeight: 40},
+		{name: "rotate 270", orientation: 8, wantWidth: 20, wantHeight: 40},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, contentType, err := imaging.Decode(newJPEG(t, 40, 20, exifWithOrientation(tt.orientation)))
+			if err != nil {
+				t.Fatalf("failed to decode: %v", err)
+			}
+			if contentType != imaging.ContentTypeJPEG {
+				t.Errorf("want %s, but got %s", imaging.ContentTypeJPEG, contentType)
+			}
+			if b := img.Bounds(); b.Dx() != tt.wantWidth || b.Dy() != tt.wantHeight {
+				t.Errorf("want %dx%d, but got %dx%d", tt.wantWidth, tt.wantHeight, b.Dx(), b.Dy())
+			}
+		})
+	}
+}
+
+func Test_Resize(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 1000, 500))
+	if b := imaging.Resize(img, 320).Bounds(); b.Dx() != 320 || b.Dy() != 160 {
+		t.Errorf("want 320x160, but got %dx%d", b.Dx(), b.Dy())
+	}
+	// 拡大はしない
+	if b := imaging.Resize(img, 1280).Bounds(); b.Dx() != 1000 || b.Dy() != 500 {
+		t.Errorf("want 1000x500, but got %dx%d", b.Dx(), b.Dy())
+	}
+}
+
+func Test_Encode_StripsExif(t *testing.T) {
+	img, _, err := imaging.Decode(newJPEG(t, 40, 20, exifWithOrientation(1)))
+	if err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	contentTypes := []string{imaging.ContentTypeJPEG}
+	if imaging.WebPSupported {
+		contentTypes = append(contentTypes, imaging.ContentTypeWebP)
+	}
+	for _, contentType := range contentTypes {
+		var buf bytes.Buffer
+		if err := imaging.Encode(&buf, img, contentType); err != nil {
+			t.Fatalf("failed to encode %s: %v", contentType, err)
+		}
+		if bytes.Contains(buf.Bytes(), []byte("Exif")) {
+			t.Errorf("%s contains exif", contentType)
+		}
+		got, err := imaging.DetectContentType(buf.Bytes())
+		if err != nil || got != contentType {
+			t.Errorf("want %s, but got %s, %v", contentType, got, err)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+//go:build cgo
+
+package imaging
+
+import (
+	"image"
+	"io"
+
+	"github.com/chai2010/webp"
+)
+
+// WebPSupported は、WebPへのエンコードに対応しているか
+const WebPSupported = true
+
+func encodeWebP(w io.Writer, img image.Image) error {
+	return webp.Encode(w, img, &webp.Options{Quality: 80})
+}
@@ -0,0 +1,19 @@
+//go:build !cgo
+
+package imaging
+
+import (
+	"fmt"
+	"image"
+	"io"
+
+	// デコードのみ対応する
+	_ "golang.org/x/image/webp"
+)
+
+// WebPSupported は、WebPへのエンコードに対応しているか
+const WebPSupported = false
+
+func encodeWebP(w io.Writer, img image.Image) error {
+	return fmt.Errorf("%w: %s requires cgo", ErrUnsupportedImage, ContentTypeWebP)
+}
@@ -0,0 +1,268 @@
+package adapter
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shoet/blog/internal/clocker"
+	"github.com/shoet/blog/internal/config"
+)
+
+var (
+	ErrInvalidObjectKey = errors.New("invalid object key")
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrSignatureExpired = errors.New("signature expired")
+	ErrObjectNotFound   = errors.New("object not found")
+)
+
+// LocalStoragePathPrefix は、ローカルストレージのオブジェクトを配信するパス
+const LocalStoragePathPrefix = "/files/local/"
+
+// LocalStorageAdapter は、S3の代わりにローカルのディレクトリへ画像を保存する
+// 署名付きURLはS3と同様にPUTでアップロードできる形式で発行し、/files/local/で受け付ける
+// ローカル開発・テスト用
+type LocalStorageAdapter struct {
+	dir     string
+	baseURL string
+	secret  []byte
+	expires time.Duration
+	clocker clocker.Clocker
+}
+
+func NewLocalStorageAdapter(cfg *config.Config, clocker clocker.Clocker) (*LocalStorageAdapter, error) {
+	if cfg.StorageLocalDir == "" {
+		return nil, fmt.Errorf("local storage directory is required")
+	}
+	baseURL := cfg.StorageLocalBaseURL
+	if baseURL == "" {
+		baseURL = fmt.Sprintf("http://localhost:%d", cfg.AppPort)
+	}
+	// 署名の鍵が設定されていない場合はJWTの鍵から導出する
+	secret := []byte(cfg.StorageLocalSecret)
+	if len(secret) == 0 {
+		mac := hmac.New(sha256.New, []byte(cfg.JWTSecret))
+		mac.Write([]byte("local-storage"))
+		secret = mac.Sum(nil)
+	}
+	return &LocalStorageAdapter{
+		dir:     cfg.StorageLocalDir,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		secret:  secret,
+		expires: time.Duration(cfg.AWSS3PresignPutExpiresSec) * time.Second,
+		clocker: clocker,
+	}, nil
+}
+
+// GeneratePreSignedURL は、S3と同様にContent-TypeとContent-Lengthを署名に含めたURLを生成する
+func (s *LocalStorageAdapter) GeneratePreSignedURL(
+	destinationPath string, fileName string, contentType string, contentLength int64,
+) (presignedUrl, objectUrl string, err error) {
+	key, err := cleanObjectKey(path.Join(destinationPath, fileName))
+	if err != nil {
+		return "", "", err
+	}
+	expires := strconv.FormatInt(s.clocker.Now().Add(s.expires).Unix(), 10)
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", s.sign(key, contentType, contentLength, expires))
+	objectURL := s.baseURL + LocalStoragePathPrefix + key
+	return objectURL + "?" + query.Encode(), objectURL, nil
+}
+
+// Verify は、署名付きURLのクエリパラメータがオブジェクトキーとリクエストのヘッダーに対して有効かを検証する
+func (s *LocalStorageAdapter) Verify(
+	key string, contentType string, contentLength int64, expires string, signature string,
+) error {
+	key, err := cleanObjectKey(key)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal([]byte(signature), []byte(s.sign(key, contentType, contentLength, expires))) {
+		return ErrInvalidSignature
+	}
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if s.clocker.Now().Unix() > expiresAt {
+		return ErrSignatureExpired
+	}
+	return nil
+}
+
+// Save は、オブジェクトを保存する
+// 書き込み途中のファイルが配信されないよう、一時ファイルに書き込んでから置き換える
+func (s *LocalStorageAdapter) Save(key string, body io.Reader) error {
+	p, err := s.objectPath(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write object: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temp file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), p); err != nil {
+		return fmt.Errorf("failed to rename temp file: %w", err)
+	}
+	return nil
+}
+
+// Open は、オブジェクトを読み込み用に開く
+func (s *LocalStorageAdapter) Open(key string) (*os.File, error) {
+	p, err := s.objectPath(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, fmt.Errorf("failed to open object: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to stat object: %w", err)
+	}
+	if info.IsDir() {
+		f.Close()
+		return nil, ErrObjectNotFound
+	}
+	return f, nil
+}
+
+// GetObject は、StorageAdapterとしてオブジェクトを読み込む
+func (s *LocalStorageAdapter) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	return s.Open(key)
+}
+
+// PutObject は、StorageAdapterとしてオブジェクトを保存する
+// Content-Typeは配信時に拡張子から判定するため保存しない
+func (s *LocalStorageAdapter) PutObject(ctx context.Context, key string, contentType string, body io.Reader) error {
+	return s.Save(key, body)
+}
+
+// HeadObject は、StorageAdapterとしてオブジェクトのメタデータを取得する
+func (s *LocalStorageAdapter) HeadObject(ctx context.Context, key string) (*ObjectInfo, error) {
+	f, err := s.Open(key)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat object: %w", err)
+	}
+	return &ObjectInfo{
+		Key:          key,
+		Size:         info.Size(),
+		LastModified: info.ModTime(),
+	}, nil
+}
+
+// ListObjects は、StorageAdapterとしてキーがprefixで始まるオブジェクトを列挙する
+// 書き込み途中の一時ファイルは含めない
+func (s *LocalStorageAdapter) ListObjects(ctx context.Context, prefix string) ([]*ObjectInfo, error) {
+	objects := []*ObjectInfo{}
+	err := filepath.WalkDir(s.dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".") {
+			return nil
+		}
+		rel, err := filepath.Rel(s.dir, p)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, &ObjectInfo{
+			Key:          key,
+			Size:         info.Size(),
+			LastModified: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list objects: %w", err)
+	}
+	return objects, nil
+}
+
+// DeleteObject は、StorageAdapterとしてオブジェクトを削除する
+// S3と同様に存在しないオブジェクトの削除はエラーとしない
+func (s *LocalStorageAdapter) DeleteObject(ctx context.Context, key string) error {
+	p, err := s.objectPath(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete object: %w", err)
+	}
+	return nil
+}
+
+func (s *LocalStorageAdapter) sign(key string, contentType string, contentLength int64, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(strings.Join([]string{
+		"PUT", key, contentType, strconv.FormatInt(contentLength, 10), expires,
+	}, "\n")))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *LocalStorageAdapter) objectPath(key string) (string, error) {
+	key, err := cleanObjectKey(key)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
+
+// cleanObjectKey は、保存先のディレクトリの外を指すキーを拒否する
+func cleanObjectKey(key string) (string, error) {
+	key = strings.TrimPrefix(key, "/")
+	if key == "" || strings.Contains(key, "\\") {
+		return "", ErrInvalidObjectKey
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." || strings.HasPrefix(segment, ".") {
+			return "", ErrInvalidObjectKey
+		}
+	}
+	return key, nil
+}
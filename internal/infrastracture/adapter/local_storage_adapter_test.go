@@ -0,0 +1,92 @@
+package adapter_test
+
+import (
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/shoet/blog/internal/clocker"
+	"github.com/shoet/blog/internal/config"
+	"github.com/shoet/blog/internal/infrastracture/adapter"
+)
+
+func Test_LocalStorageAdapter(t *testing.T) {
+	cfg := &config.Config{
+		StorageLocalDir:           t.TempDir(),
+		StorageLocalBaseURL:       "http://localhost:3000",
+		StorageLocalSecret:        "secret",
+		AWSS3PresignPutExpiresSec: 300,
+	}
+	sut, err := adapter.NewLocalStorageAdapter(cfg, clocker.NewFixedClocker())
+	if err != nil {
+		t.Fatalf("failed to create local storage adapter: %v", err)
+	}
+
+	signedUrl, objectUrl, err := sut.GeneratePreSignedURL("thumbnail", "test.jpg", "image/jpeg", 5)
+	if err != nil {
+		t.Fatalf("failed to generate url: %v", err)
+	}
+	if objectUrl != "http://localhost:3000/files/local/thumbnail/test.jpg" {
+		t.Errorf("object url is not expected: %v", objectUrl)
+	}
+	u, err := url.Parse(signedUrl)
+	if err != nil {
+		t.Fatalf("failed to parse signed url: %v", err)
+	}
+	key := strings.TrimPrefix(u.Path, adapter.LocalStoragePathPrefix)
+	expires, signature := u.Query().Get("expires"), u.Query().Get("signature")
+
+	t.Run("verify", func(t *testing.T) {
+		if err := sut.Verify(key, "image/jpeg", 5, expires, signature); err != nil {
+			t.Errorf("want no error, but got %v", err)
+		}
+		tests := []struct {
+			name          string
+			key           string
+			contentType   string
+			contentLength int64
+			expires       string
+			wantErr       error
+		}{
+			{name: "other key", key: "thumbnail/other.jpg", contentType: "image/jpeg", contentLength: 5, expires: expires, wantErr: adapter.ErrInvalidSignature},
+			{name: "other content type", key: key, contentType: "text/html", contentLength: 5, expires: expires, wantErr: adapter.ErrInvalidSignature},
+			{name: "other content length", key: key, contentType: "image/jpeg", contentLength: 1 << 30, expires: expires, wantErr: adapter.ErrInvalidSignature},
+			{name: "extended expires", key: key, contentType: "image/jpeg", contentLength: 5, expires: "4102444800", wantErr: adapter.ErrInvalidSignature},
+			{name: "outside directory", key: "thumbnail/../../etc/passwd", contentType: "image/jpeg", contentLength: 5, expires: expires, wantErr: adapter.ErrInvalidObjectKey},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				err := sut.Verify(tt.key, tt.contentType, tt.contentLength, tt.expires, signature)
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("want %v, but got %v", tt.wantErr, err)
+				}
+			})
+		}
+	})
+
+	t.Run("save and open", func(t *testing.T) {
+		if err := sut.Save(key, strings.NewReader("image")); err != nil {
+			t.Fatalf("failed to save: %v", err)
+		}
+		f, err := sut.Open(key)
+		if err != nil {
+			t.Fatalf("failed to open: %v", err)
+		}
+		defer f.Close()
+		b, err := io.ReadAll(f)
+		if err != nil {
+			t.Fatalf("failed to read: %v", err)
+		}
+		if string(b) != "image" {
+			t.Errorf("want %q, but got %q", "image", string(b))
+		}
+		if _, err := sut.Open("thumbnail/notfound.jpg"); !errors.Is(err, adapter.ErrObjectNotFound) {
+			t.Errorf("want %v, but got %v", adapter.ErrObjectNotFound, err)
+		}
+		if _, err := sut.Open("thumbnail"); !errors.Is(err, adapter.ErrObjectNotFound) {
+			t.Errorf("want %v, but got %v", adapter.ErrObjectNotFound, err)
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/shoet/blog/internal/config"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/logging"
+)
+
+// Mailer は、メールの送信先の実装を差し替えるためのインターフェース
+type Mailer interface {
+	Send(ctx context.Context, mail *models.Mail) error
+}
+
+// NewMailer は、設定に応じたMailerを返す
+// 未指定の場合はprod以外の環境でのみLogMailerを使用する
+// prodではLogMailerを指定してもトークンを含む本文はログに出力しない
+func NewMailer(cfg *config.Config) (Mailer, error) {
+	switch cfg.Mailer {
+	case "":
+		if cfg.Env == "prod" {
+			return nil, fmt.Errorf("MAILER is required in %s", cfg.Env)
+		}
+		return NewLogMailer(false), nil
+	case "log":
+		return NewLogMailer(cfg.Env == "prod"), nil
+	case "file":
+		return NewFileMailer(cfg.MailerFileDir), nil
+	default:
+		return nil, fmt.Errorf("unknown mailer: %s", cfg.Mailer)
+	}
+}
+
+// LogMailer は、メールを送信せずにロガーへ出力する
+// ローカル開発用
+type LogMailer struct {
+	redactBody bool
+}
+
+// NewLogMailer は、LogMailerを返す
+// redactBodyがtrueの場合は、本文をログに出力しない
+func NewLogMailer(redactBody bool) *LogMailer {
+	return &LogMailer{redactBody: redactBody}
+}
+
+func (m *LogMailer) Send(ctx context.Context, mail *models.Mail) error {
+	logger := logging.GetLogger(ctx)
+	if m.redactBody {
+		logger.Info(fmt.Sprintf("mail: to=%s subject=%s body=[redacted]", mail.To, mail.Subject))
+		return nil
+	}
+	logger.Info(fmt.Sprintf("mail: to=%s subject=%s body=%q", mail.To, mail.Subject, mail.Body))
+	return nil
+}
+
+// FileMailer は、メールを送信せずにディレクトリへ1通ずつファイルとして書き出す
+// ローカル開発・テスト用
+type FileMailer struct {
+	dir string
+}
+
+func NewFileMailer(dir string) *FileMailer {
+	return &FileMailer{dir: dir}
+}
+
+var unsafeFileNameChars = regexp.MustCompile(`[^a-zA-Z0-9@._-]`)
+
+func (m *FileMailer) Send(ctx context.Context, mail *models.Mail) error {
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create mail directory: %w", err)
+	}
+	name := fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), unsafeFileNameChars.ReplaceAllString(mail.To, "_"))
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "From: %s\r\n", mail.From)
+	fmt.Fprintf(&sb, "To: %s\r\n", mail.To)
+	fmt.Fprintf(&sb, "Subject: %s\r\n", mail.Subject)
+	sb.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	sb.WriteString(mail.Body)
+	if err := os.WriteFile(filepath.Join(m.dir, name), []byte(sb.String()), 0o600); err != nil {
+		return fmt.Errorf("failed to write mail: %w", err)
+	}
+	return nil
+}
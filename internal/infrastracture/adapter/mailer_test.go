@@ -0,0 +1,69 @@
+package adapter_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shoet/blog/internal/config"
+	"github.com/shoet/blog/internal/infrastracture/adapter"
+	"github.com/shoet/blog/internal/infrastracture/models"
+)
+
+func Test_FileMailer_Send(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	sut := adapter.NewFileMailer(dir)
+
+	mail := &models.Mail{
+		From:    "noreply@example.com",
+		To:      "admin@example.com",
+		Subject: "subject",
+		Body:    "body",
+	}
+	if err := sut.Send(context.Background(), mail); err != nil {
+		t.Fatalf("failed to send mail: %v", err)
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read dir: %v", err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("want 1 file, but got %d", len(files))
+	}
+	if !strings.HasSuffix(files[0].Name(), "-admin@example.com.eml") {
+		t.Errorf("unexpected file name: %s", files[0].Name())
+	}
+	b, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	want := "From: noreply@example.com\r\nTo: admin@example.com\r\nSubject: subject\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\nbody"
+	if string(b) != want {
+		t.Errorf("want %q, but got %q", want, string(b))
+	}
+}
+
+func Test_NewMailer(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     string
+		mailer  string
+		wantErr bool
+	}{
+		{name: "default in dev", env: "dev", mailer: "", wantErr: false},
+		{name: "default in prod", env: "prod", mailer: "", wantErr: true},
+		{name: "explicit log in prod", env: "prod", mailer: "log", wantErr: false},
+		{name: "unknown", env: "dev", mailer: "smtp", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := adapter.NewMailer(&config.Config{Env: tt.env, Mailer: tt.mailer})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("want error %v, but got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
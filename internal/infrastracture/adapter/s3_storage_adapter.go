@@ -2,7 +2,9 @@ package adapter
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"path/filepath"
 	"time"
 
@@ -10,6 +12,7 @@ import (
 	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
 	awsConfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/shoet/blog/internal/config"
 )
 
@@ -20,6 +23,9 @@ type AWSS3StorageAdapter struct {
 }
 
 func NewAWSS3StorageAdapter(cfg *config.Config) (*AWSS3StorageAdapter, error) {
+	if cfg.AWSS3Bucket == "" {
+		return nil, fmt.Errorf("s3 bucket is required")
+	}
 	sdkConfig, err := awsConfig.LoadDefaultConfig(context.TODO())
 	if err != nil {
 		return nil, fmt.Errorf("failed to load aws config: %w", err)
@@ -32,10 +38,12 @@ func NewAWSS3StorageAdapter(cfg *config.Config) (*AWSS3StorageAdapter, error) {
 	}, nil
 }
 
-func (s *AWSS3StorageAdapter) GeneratePreSignedURL(destinationPath string, fileName string) (presignedUrl, objectUrl string, err error) {
+func (s *AWSS3StorageAdapter) GeneratePreSignedURL(
+	destinationPath string, fileName string, contentType string, contentLength int64,
+) (presignedUrl, objectUrl string, err error) {
 	bucketName := s.config.AWSS3Bucket
 	objectKey := filepath.Join(destinationPath, fileName)
-	request, err := s.GenerateSignedURL(bucketName, objectKey)
+	request, err := s.GenerateSignedURL(bucketName, objectKey, contentType, contentLength)
 	if err != nil {
 		return "", "", fmt.Errorf("failed to generate signed url: %w", err)
 	}
@@ -48,14 +56,18 @@ func (s *AWSS3StorageAdapter) GeneratePreSignedURL(destinationPath string, fileN
 	return request.URL, objectURL, nil
 }
 
+// GenerateSignedURL は、PutObjectの署名付きURLを生成する
+// Content-TypeとContent-Lengthは署名に含まれるため、異なる値でのアップロードはS3に拒否される
 func (s *AWSS3StorageAdapter) GenerateSignedURL(
-	bucketName string, objectKey string,
+	bucketName string, objectKey string, contentType string, contentLength int64,
 ) (presignedRequest *v4.PresignedHTTPRequest, err error) {
 	request, err := s.PresignClient.PresignPutObject(
 		context.TODO(),
 		&s3.PutObjectInput{
-			Bucket: aws.String(bucketName),
-			Key:    aws.String(objectKey),
+			Bucket:        aws.String(bucketName),
+			Key:           aws.String(objectKey),
+			ContentType:   aws.String(contentType),
+			ContentLength: contentLength,
 		}, func(opts *s3.PresignOptions) {
 			opts.Expires = time.Duration(s.config.AWSS3PresignPutExpiresSec * int64(time.Second))
 		})
@@ -65,3 +77,90 @@ func (s *AWSS3StorageAdapter) GenerateSignedURL(
 	}
 	return request, err
 }
+
+// GetObject は、オブジェクトを読み込む
+// 呼び出し元でReadCloserを閉じる必要がある
+func (s *AWSS3StorageAdapter) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	output, err := s.S3Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.config.AWSS3Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var noSuchKey *types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, fmt.Errorf("failed to get object %s: %w", key, err)
+	}
+	return output.Body, nil
+}
+
+// PutObject は、オブジェクトを保存する
+func (s *AWSS3StorageAdapter) PutObject(ctx context.Context, key string, contentType string, body io.Reader) error {
+	_, err := s.S3Client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.config.AWSS3Bucket),
+		Key:         aws.String(key),
+		ContentType: aws.String(contentType),
+		Body:        body,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put object %s: %w", key, err)
+	}
+	return nil
+}
+
+// HeadObject は、オブジェクトのメタデータを取得する
+func (s *AWSS3StorageAdapter) HeadObject(ctx context.Context, key string) (*ObjectInfo, error) {
+	output, err := s.S3Client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.config.AWSS3Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var notFound *types.NotFound
+		if errors.As(err, &notFound) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, fmt.Errorf("failed to head object %s: %w", key, err)
+	}
+	return &ObjectInfo{
+		Key:          key,
+		Size:         output.ContentLength,
+		LastModified: aws.ToTime(output.LastModified),
+	}, nil
+}
+
+// ListObjects は、キーがprefixで始まるオブジェクトを列挙する
+func (s *AWSS3StorageAdapter) ListObjects(ctx context.Context, prefix string) ([]*ObjectInfo, error) {
+	paginator := s3.NewListObjectsV2Paginator(s.S3Client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.config.AWSS3Bucket),
+		Prefix: aws.String(prefix),
+	})
+	objects := []*ObjectInfo{}
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list objects %s: %w", prefix, err)
+		}
+		for _, o := range page.Contents {
+			objects = append(objects, &ObjectInfo{
+				Key:          aws.ToString(o.Key),
+				Size:         o.Size,
+				LastModified: aws.ToTime(o.LastModified),
+			})
+		}
+	}
+	return objects, nil
+}
+
+// DeleteObject は、オブジェクトを削除する
+// 存在しないオブジェクトの削除はエラーとしない
+func (s *AWSS3StorageAdapter) DeleteObject(ctx context.Context, key string) error {
+	_, err := s.S3Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.config.AWSS3Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete object %s: %w", key, err)
+	}
+	return nil
+}
@@ -23,7 +23,7 @@ func Test_AWSStorageService_GenerateThumbnailPutURL(t *testing.T) {
 	wantFileName := "test.jpg"
 	wantDirectoryName := "test"
 	wantPath := filepath.Join(wantDirectoryName, wantFileName)
-	signedUrl, objectUrl, err := s.GeneratePreSignedURL(wantDirectoryName, wantFileName)
+	signedUrl, objectUrl, err := s.GeneratePreSignedURL(wantDirectoryName, wantFileName, "image/jpeg", 1024)
 	if err != nil {
 		t.Fatalf("failed to generate url: %v", err)
 	}
@@ -0,0 +1,44 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/shoet/blog/internal/clocker"
+	"github.com/shoet/blog/internal/config"
+)
+
+// StorageAdapter は、画像の保存先の実装を差し替えるためのインターフェース
+// キーは保存先のディレクトリを含むオブジェクトのパス
+// 署名付きURLは指定したContent-TypeとContent-Lengthのアップロードのみを許可する
+type StorageAdapter interface {
+	GeneratePreSignedURL(
+		destinationPath string, fileName string, contentType string, contentLength int64,
+	) (presignedUrl, objectUrl string, err error)
+	GetObject(ctx context.Context, key string) (io.ReadCloser, error)
+	PutObject(ctx context.Context, key string, contentType string, body io.Reader) error
+	HeadObject(ctx context.Context, key string) (*ObjectInfo, error)
+	ListObjects(ctx context.Context, prefix string) ([]*ObjectInfo, error)
+	DeleteObject(ctx context.Context, key string) error
+}
+
+// ObjectInfo は、保存されたオブジェクトのメタデータ
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// NewStorageAdapter は、設定に応じたStorageAdapterを返す
+func NewStorageAdapter(cfg *config.Config, clocker clocker.Clocker) (StorageAdapter, error) {
+	switch cfg.Storage {
+	case "s3":
+		return NewAWSS3StorageAdapter(cfg)
+	case "local":
+		return NewLocalStorageAdapter(cfg, clocker)
+	default:
+		return nil, fmt.Errorf("unknown storage: %s", cfg.Storage)
+	}
+}
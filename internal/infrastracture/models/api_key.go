@@ -0,0 +1,89 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+type APIKeyId int64
+
+type APIKeyScope string
+
+const (
+	// APIKeyScopeBlogsWrite は、ブログの作成・更新・削除と管理用の一覧の取得を許可する
+	APIKeyScopeBlogsWrite APIKeyScope = "blogs:write"
+	// APIKeyScopeFilesWrite は、画像のアップロード用の署名付きURLの発行を許可する
+	APIKeyScopeFilesWrite APIKeyScope = "files:write"
+)
+
+var apiKeyScopes = []APIKeyScope{
+	APIKeyScopeBlogsWrite,
+	APIKeyScopeFilesWrite,
+}
+
+func (s APIKeyScope) IsValid() bool {
+	for _, v := range apiKeyScopes {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKeyScopes は、DBにはカンマ区切りの文字列として保存する
+type APIKeyScopes []APIKeyScope
+
+func (s APIKeyScopes) Has(scope APIKeyScope) bool {
+	for _, v := range s {
+		if v == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func (s APIKeyScopes) Value() (driver.Value, error) {
+	values := make([]string, 0, len(s))
+	for _, v := range s {
+		values = append(values, string(v))
+	}
+	return strings.Join(values, ","), nil
+}
+
+func (s *APIKeyScopes) Scan(src interface{}) error {
+	var v string
+	switch src := src.(type) {
+	case string:
+		v = src
+	case []byte:
+		v = string(src)
+	case nil:
+		v = ""
+	default:
+		return fmt.Errorf("failed to scan api key scopes: %T", src)
+	}
+	scopes := APIKeyScopes{}
+	for _, scope := range strings.Split(v, ",") {
+		if scope != "" {
+			scopes = append(scopes, APIKeyScope(scope))
+		}
+	}
+	*s = scopes
+	return nil
+}
+
+// APIKey は、自動化クライアントがJWTの代わりに使用するスコープ付きのキー
+// キーの平文は発行時にのみ返し、DBにはハッシュを保存する
+type APIKey struct {
+	Id         APIKeyId     `json:"id" db:"id"`
+	UserId     UserId       `json:"userId" db:"user_id"`
+	Name       string       `json:"name" db:"name"`
+	Prefix     string       `json:"prefix" db:"prefix"`
+	KeyHash    string       `json:"-" db:"key_hash"`
+	Scopes     APIKeyScopes `json:"scopes" db:"scopes"`
+	LastUsedAt *uint        `json:"lastUsedAt" db:"last_used_at"`
+	RevokedAt  *uint        `json:"revokedAt" db:"revoked_at"`
+	Created    uint         `json:"created" db:"created"`
+	Modified   uint         `json:"modified" db:"modified"`
+}
@@ -0,0 +1,14 @@
+package models
+
+// AuthToken は、ログイン・リフレッシュ時に発行するトークンの組
+// AccessTokenは短命なJWTで、期限切れ後はRefreshTokenで再発行する
+// ExpiresInはAccessTokenの有効期間(秒)
+// 2段階認証が有効なユーザーのログインでは、TwoFactorRequiredとChallengeTokenのみを返し、
+// ChallengeTokenと認証コードを交換してトークンの組を発行する
+type AuthToken struct {
+	AccessToken       string `json:"authToken,omitempty"`
+	RefreshToken      string `json:"refreshToken,omitempty"`
+	ExpiresIn         int    `json:"expiresIn,omitempty"`
+	TwoFactorRequired bool   `json:"twoFactorRequired,omitempty"`
+	ChallengeToken    string `json:"challengeToken,omitempty"`
+}
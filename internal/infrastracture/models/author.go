@@ -0,0 +1,93 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// SocialLink は、プロフィールに表示する外部サービスへのリンク
+type SocialLink struct {
+	Service string `json:"service"`
+	URL     string `json:"url"`
+}
+
+// SocialLinks は、DBにはJSONとして保存する
+type SocialLinks []*SocialLink
+
+func (s SocialLinks) Value() (driver.Value, error) {
+	if s == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal social links: %w", err)
+	}
+	return string(b), nil
+}
+
+func (s *SocialLinks) Scan(src interface{}) error {
+	var b []byte
+	switch v := src.(type) {
+	case nil:
+		*s = nil
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return fmt.Errorf("unsupported type for social links: %T", src)
+	}
+	var links SocialLinks
+	if err := json.Unmarshal(b, &links); err != nil {
+		return fmt.Errorf("failed to unmarshal social links: %w", err)
+	}
+	// リンクがない場合はnilとして扱う
+	if len(links) == 0 {
+		links = nil
+	}
+	*s = links
+	return nil
+}
+
+// Profile は、ユーザーが編集できる公開用のプロフィール
+type Profile struct {
+	DisplayName string      `json:"displayName" db:"display_name"`
+	Bio         string      `json:"bio" db:"bio"`
+	AvatarURL   string      `json:"avatarUrl" db:"avatar_url"`
+	SocialLinks SocialLinks `json:"socialLinks,omitempty" db:"social_links"`
+}
+
+// Author は、ブログの著者として公開するユーザーの情報
+// メールアドレスなどの非公開の情報は含めない
+// DisplayNameが設定されていない場合はユーザー名を表示名とする
+type Author struct {
+	Id UserId `json:"id" db:"id"`
+	Profile
+}
+
+// AuthorIds は、ブログの著者のIDを重複なく返す
+func (blogs Blogs) AuthorIds() []UserId {
+	ids := make([]UserId, 0, len(blogs))
+	seen := make(map[UserId]bool, len(blogs))
+	for _, blog := range blogs {
+		if seen[blog.AuthorId] {
+			continue
+		}
+		seen[blog.AuthorId] = true
+		ids = append(ids, blog.AuthorId)
+	}
+	return ids
+}
+
+// SetAuthors は、ブログに著者の情報を設定する
+func (blogs Blogs) SetAuthors(authors []*Author) {
+	authorMap := make(map[UserId]*Author, len(authors))
+	for _, a := range authors {
+		authorMap[a.Id] = a
+	}
+	for _, blog := range blogs {
+		blog.Author = authorMap[blog.AuthorId]
+	}
+}
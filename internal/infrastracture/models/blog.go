@@ -1,24 +1,60 @@
 package models
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"strings"
+	"time"
 
 	"golang.org/x/exp/slices"
 )
 
 type BlogId int64
 
+// Snippetはキーワード検索時の一致箇所をハイライトした本文の抜粋(HTML)
+// ContentHTML, Toc, WordCount, CharCount, ReadingTimeは本文のレンダリング結果で、
+// 保存時に算出してDBにキャッシュする。ReadingTimeは読了までの目安時間(分)
+// Seriesはブログが所属するシリーズで、詳細の取得時にのみ設定する
+// Authorは著者の公開用のプロフィールで、ユースケースでまとめて取得して設定する
+// ThumbnailImageVariantsはサムネイル画像の派生画像で、srcsetの指定に使用する
 type Blog struct {
-	Id                     BlogId   `json:"id" db:"id"`
-	Title                  string   `json:"title" db:"title"`
-	Description            string   `json:"description" db:"description"`
-	Content                string   `json:"content,omitempty" db:"content"`
-	AuthorId               UserId   `json:"authorId" db:"author_id"`
-	ThumbnailImageFileName string   `json:"thumbnailImageFileName" db:"thumbnail_image_file_name"`
-	IsPublic               bool     `json:"isPublic" db:"is_public"`
-	Tags                   []string `json:"tags,omitempty" db:"tags"`
-	Created                uint     `json:"created" db:"created"`
-	Modified               uint     `json:"modified" db:"modified"`
+	Id                     BlogId          `json:"id" db:"id"`
+	Title                  string          `json:"title" db:"title"`
+	Slug                   string          `json:"slug" db:"slug"`
+	Description            string          `json:"description" db:"description"`
+	Content                string          `json:"content,omitempty" db:"content"`
+	ContentHTML            string          `json:"contentHtml,omitempty" db:"content_html"`
+	Toc                    TableOfContents `json:"toc,omitempty" db:"toc"`
+	WordCount              int             `json:"wordCount,omitempty" db:"word_count"`
+	CharCount              int             `json:"charCount,omitempty" db:"char_count"`
+	ReadingTime            int             `json:"readingTime,omitempty" db:"reading_time"`
+	AuthorId               UserId          `json:"authorId" db:"author_id"`
+	ThumbnailImageFileName string          `json:"thumbnailImageFileName" db:"thumbnail_image_file_name"`
+	IsPublic               bool            `json:"isPublic" db:"is_public"`
+	PublishAt              uint            `json:"publishAt" db:"publish_at"`
+	Tags                   []string        `json:"tags,omitempty" db:"tags"`
+	Snippet                string          `json:"snippet,omitempty" db:"-"`
+	Series                 *BlogSeries     `json:"series,omitempty" db:"-"`
+	Author                 *Author         `json:"author,omitempty" db:"-"`
+	ThumbnailImageVariants []*ImageVariant `json:"thumbnailImageVariants,omitempty" db:"-"`
+	Created                uint            `json:"created" db:"created"`
+	Modified               uint            `json:"modified" db:"modified"`
+}
+
+// IsPublished は、指定した時刻においてブログが公開されているかを判定する
+// PublishAtが0の場合は即時公開として扱う
+func (blog *Blog) IsPublished(now time.Time) bool {
+	return blog.IsPublic && int64(blog.PublishAt) <= now.Unix()
+}
+
+// LastModified は、ブログの内容が読者から見て最後に変わった日時を返す
+// 予約投稿は更新日時より後の公開日時に公開されるため、更新日時と公開日時の遅い方とする
+func (blog *Blog) LastModified() uint {
+	if blog.PublishAt > blog.Modified {
+		return blog.PublishAt
+	}
+	return blog.Modified
 }
 
 func (blog *Blog) HavingTag(tag string) bool {
@@ -38,6 +74,54 @@ func (blog *Blog) HavingKeyword(keyword string) bool {
 	return strings.Contains(titleLower, keywordLower) || strings.Contains(descriptionLower, keywordLower)
 }
 
+// TocItem は、目次の見出し
+// Idは本文HTMLの見出しに付与したアンカーID
+type TocItem struct {
+	Level int    `json:"level"`
+	Text  string `json:"text"`
+	Id    string `json:"id"`
+}
+
+// TableOfContents は、本文の見出しから生成した目次
+// DBにはJSONとして保存する
+type TableOfContents []*TocItem
+
+func (t TableOfContents) Value() (driver.Value, error) {
+	if t == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(t)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal table of contents: %w", err)
+	}
+	return string(b), nil
+}
+
+func (t *TableOfContents) Scan(src interface{}) error {
+	var b []byte
+	switch v := src.(type) {
+	case nil:
+		*t = nil
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return fmt.Errorf("unsupported type for table of contents: %T", src)
+	}
+	var toc TableOfContents
+	if err := json.Unmarshal(b, &toc); err != nil {
+		return fmt.Errorf("failed to unmarshal table of contents: %w", err)
+	}
+	// 見出しがない場合はnilとして扱う
+	if len(toc) == 0 {
+		toc = nil
+	}
+	*t = toc
+	return nil
+}
+
 type Blogs []*Blog
 
 func (blogs Blogs) FilterByTag(tag string) Blogs {
@@ -75,8 +159,19 @@ type User struct {
 	Name     string `json:"name" db:"name"`
 	Email    string `json:"email,omitempty" db:"email"`
 	Password string `json:"password,omitempty" db:"password"`
-	Created  uint   `json:"created,omitempty" db:"created"`
-	Modified uint   `json:"modified,omitempty" db:"modified"`
+	Role     Role   `json:"role,omitempty" db:"role"`
+	Profile
+	// TOTPSecret は2段階認証の共有鍵。TOTPEnabledがtrueになるまでは登録途中として扱う
+	TOTPSecret  string `json:"-" db:"totp_secret"`
+	TOTPEnabled bool   `json:"totpEnabled" db:"totp_enabled"`
+	// DisabledAt は無効化された日時。nilの場合は有効なユーザーとして扱う
+	DisabledAt *uint `json:"disabledAt,omitempty" db:"disabled_at"`
+	Created    uint  `json:"created,omitempty" db:"created"`
+	Modified   uint  `json:"modified,omitempty" db:"modified"`
+}
+
+func (u *User) IsDisabled() bool {
+	return u.DisabledAt != nil
 }
 
 type TagId int64
@@ -88,6 +183,12 @@ type Tag struct {
 
 type Tags []*Tag
 
+// TagModified は、タグとそのタグが付いたブログの最終更新日時
+type TagModified struct {
+	Name     string `json:"name" db:"name"`
+	Modified uint   `json:"modified" db:"modified"`
+}
+
 type BlogsTags struct {
 	BlogId BlogId `json:"blogId" db:"blog_id"`
 	TagId  TagId  `json:"tagId" db:"tag_id"`
@@ -0,0 +1,28 @@
+package models
+
+type BlogRevisionId int64
+
+// BlogRevision は、ブログの作成・更新時点の内容を保持する履歴
+type BlogRevision struct {
+	Id                     BlogRevisionId `json:"id" db:"id"`
+	BlogId                 BlogId         `json:"blogId" db:"blog_id"`
+	Revision               int64          `json:"revision" db:"revision"`
+	EditorId               UserId         `json:"editorId" db:"editor_id"`
+	Title                  string         `json:"title" db:"title"`
+	Content                string         `json:"content,omitempty" db:"content"`
+	Description            string         `json:"description" db:"description"`
+	ThumbnailImageFileName string         `json:"thumbnailImageFileName" db:"thumbnail_image_file_name"`
+	Created                uint           `json:"created" db:"created"`
+}
+
+// NewBlogRevision は、ブログの現在の内容から履歴を生成する
+func NewBlogRevision(blog *Blog, editorId UserId) *BlogRevision {
+	return &BlogRevision{
+		BlogId:                 blog.Id,
+		EditorId:               editorId,
+		Title:                  blog.Title,
+		Content:                blog.Content,
+		Description:            blog.Description,
+		ThumbnailImageFileName: blog.ThumbnailImageFileName,
+	}
+}
@@ -0,0 +1,48 @@
+package models
+
+type CommentId int64
+
+type CommentStatus string
+
+const (
+	// CommentStatusPending は、承認待ちのコメント
+	CommentStatusPending CommentStatus = "pending"
+	// CommentStatusApproved は、承認済みで公開されるコメント
+	CommentStatusApproved CommentStatus = "approved"
+	// CommentStatusRejected は、却下されたコメント
+	CommentStatusRejected CommentStatus = "rejected"
+	// CommentStatusSpam は、スパムと判定されたコメント
+	CommentStatusSpam CommentStatus = "spam"
+)
+
+// Valid は、コメントの状態が定義済みの値かを判定する
+func (s CommentStatus) Valid() bool {
+	switch s {
+	case CommentStatusPending, CommentStatusApproved, CommentStatusRejected, CommentStatusSpam:
+		return true
+	}
+	return false
+}
+
+// Comment は、ブログへの読者のコメント
+// ParentIdは返信先のコメントで、返信は1階層のみとする
+// AuthorEmail, IpAddress, UserAgentは管理者向けの情報で、公開しない
+type Comment struct {
+	Id          CommentId     `json:"id" db:"id"`
+	BlogId      BlogId        `json:"blogId" db:"blog_id"`
+	ParentId    *CommentId    `json:"parentId,omitempty" db:"parent_id"`
+	AuthorName  string        `json:"authorName" db:"author_name"`
+	AuthorEmail string        `json:"authorEmail,omitempty" db:"author_email"`
+	Body        string        `json:"body" db:"body"`
+	Status      CommentStatus `json:"status" db:"status"`
+	IpAddress   string        `json:"ipAddress,omitempty" db:"ip_address"`
+	UserAgent   string        `json:"userAgent,omitempty" db:"user_agent"`
+	Replies     []*Comment    `json:"replies,omitempty" db:"-"`
+	Created     uint          `json:"created" db:"created"`
+	Modified    uint          `json:"modified" db:"modified"`
+}
+
+// IsReply は、コメントが返信かを判定する
+func (c *Comment) IsReply() bool {
+	return c.ParentId != nil
+}
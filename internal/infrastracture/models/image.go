@@ -0,0 +1,24 @@
+package models
+
+// ImageVariant は、サーバー側でリサイズ・再エンコードした画像
+// Widthはリサイズの上限の幅で、元画像の幅がこれより小さい場合は拡大しない
+type ImageVariant struct {
+	URL         string `json:"url"`
+	Width       int    `json:"width"`
+	ContentType string `json:"contentType"`
+}
+
+// SetThumbnailVariants は、ブログにサムネイル画像の派生画像のURLを設定する
+func (blogs Blogs) SetThumbnailVariants(variants func(thumbnail string) []*ImageVariant) {
+	for _, blog := range blogs {
+		blog.ThumbnailImageVariants = variants(blog.ThumbnailImageFileName)
+	}
+}
+
+// UploadURL は、画像をアップロードするための署名付きURL
+// FileNameはサーバーで生成した衝突しないファイル名で、ブログのサムネイルなどに指定する
+type UploadURL struct {
+	SignedURL string
+	ObjectURL string
+	FileName  string
+}
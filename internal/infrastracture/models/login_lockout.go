@@ -0,0 +1,38 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+type LoginLockoutId int64
+
+type LoginLockoutTargetType string
+
+const (
+	LoginLockoutTargetEmail     LoginLockoutTargetType = "email"
+	LoginLockoutTargetIpAddress LoginLockoutTargetType = "ip"
+)
+
+// LoginLockout は、ログイン試行の失敗によりメールアドレスまたはIPアドレスをロックした監査記録
+// IpAddress, UserAgentはロックの契機となったリクエストのクライアントの情報
+type LoginLockout struct {
+	Id          LoginLockoutId         `json:"id" db:"id"`
+	TargetType  LoginLockoutTargetType `json:"targetType" db:"target_type"`
+	Target      string                 `json:"target" db:"target"`
+	Failures    int64                  `json:"failures" db:"failures"`
+	LockedUntil uint                   `json:"lockedUntil" db:"locked_until"`
+	IpAddress   string                 `json:"ipAddress" db:"ip_address"`
+	UserAgent   string                 `json:"userAgent" db:"user_agent"`
+	Created     uint                   `json:"created" db:"created"`
+}
+
+// LoginLockedError は、ロック中のためログインを試行できないことを表す
+// RetryAfterはロックが解除されるまでの時間
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return fmt.Sprintf("login is locked, retry after %s", e.RetryAfter)
+}
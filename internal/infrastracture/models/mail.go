@@ -0,0 +1,9 @@
+package models
+
+// Mail は、Mailerで送信するテキストのメール
+type Mail struct {
+	From    string
+	To      string
+	Subject string
+	Body    string
+}
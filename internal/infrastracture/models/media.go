@@ -0,0 +1,70 @@
+package models
+
+type MediaId int64
+
+type MediaKind string
+
+const (
+	// MediaKindThumbnail は、ブログのサムネイル画像
+	MediaKindThumbnail MediaKind = "thumbnail"
+	// MediaKindContent は、ブログの本文に埋め込む画像
+	MediaKindContent MediaKind = "content"
+)
+
+// Valid は、メディアの種類が定義済みの値かを判定する
+func (k MediaKind) Valid() bool {
+	switch k {
+	case MediaKindThumbnail, MediaKindContent:
+		return true
+	}
+	return false
+}
+
+type MediaStatus string
+
+const (
+	// MediaStatusPending は、署名付きURLを発行し、アップロードの完了を待っているメディア
+	MediaStatusPending MediaStatus = "pending"
+	// MediaStatusUploaded は、アップロードが完了したメディア
+	MediaStatusUploaded MediaStatus = "uploaded"
+)
+
+// Valid は、メディアの状態が定義済みの値かを判定する
+func (s MediaStatus) Valid() bool {
+	switch s {
+	case MediaStatusPending, MediaStatusUploaded:
+		return true
+	}
+	return false
+}
+
+// Media は、ストレージにアップロードした画像
+// Keyはサーバーで生成したファイル名から拡張子を除いたもので、ブログからの参照の判定に使用する
+// Sizeはアップロードの完了時に実際のオブジェクトのサイズで更新する
+// VariantsProcessedAtはサムネイルの派生画像を生成した日時で、生成していない場合はnil
+// BlogIdsは本文・サムネイルでメディアを参照しているブログ
+type Media struct {
+	Id                  MediaId     `json:"id" db:"id"`
+	Key                 string      `json:"key" db:"media_key"`
+	Kind                MediaKind   `json:"kind" db:"kind"`
+	FileName            string      `json:"fileName" db:"file_name"`
+	ObjectURL           string      `json:"objectUrl" db:"object_url"`
+	ContentType         string      `json:"contentType" db:"content_type"`
+	Size                int64       `json:"size" db:"size"`
+	Status              MediaStatus `json:"status" db:"status"`
+	UploadedBy          *UserId     `json:"uploadedBy,omitempty" db:"uploaded_by"`
+	UploadedAt          *uint       `json:"uploadedAt,omitempty" db:"uploaded_at"`
+	VariantsProcessedAt *uint       `json:"variantsProcessedAt,omitempty" db:"variants_processed_at"`
+	BlogIds             []BlogId    `json:"blogIds" db:"-"`
+	Created             uint        `json:"created" db:"created"`
+	Modified            uint        `json:"modified" db:"modified"`
+}
+
+// MediaFilter は、メディアの一覧の絞り込み条件
+// nilの条件は絞り込まない
+type MediaFilter struct {
+	Kind       *MediaKind
+	Status     *MediaStatus
+	UploadedBy *UserId
+	Referenced *bool
+}
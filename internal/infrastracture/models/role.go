@@ -0,0 +1,31 @@
+package models
+
+// Role は、ユーザーの権限の種別を表す
+type Role string
+
+const (
+	// RoleAdmin は、ユーザー管理を含むすべての操作ができる
+	RoleAdmin Role = "admin"
+	// RoleEditor は、他のユーザーのブログの編集やコメント・シリーズの管理ができる
+	RoleEditor Role = "editor"
+	// RoleAuthor は、自分のブログの作成・編集ができる
+	RoleAuthor Role = "author"
+	// RoleViewer は、管理画面の閲覧のみができる
+	RoleViewer Role = "viewer"
+)
+
+var roles = []Role{
+	RoleAdmin,
+	RoleEditor,
+	RoleAuthor,
+	RoleViewer,
+}
+
+func (r Role) IsValid() bool {
+	for _, v := range roles {
+		if r == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package models
+
+import "time"
+
+type SeriesId int64
+
+// Series は、連載記事のように順序を持つブログのまとまり
+// Partsはシリーズに含まれるブログで、Positionの昇順に並ぶ
+type Series struct {
+	Id          SeriesId      `json:"id" db:"id"`
+	Title       string        `json:"title" db:"title"`
+	Description string        `json:"description" db:"description"`
+	Parts       []*SeriesPart `json:"parts,omitempty" db:"-"`
+	Created     uint          `json:"created" db:"created"`
+	Modified    uint          `json:"modified" db:"modified"`
+}
+
+// SeriesPart は、シリーズに含まれるブログ
+type SeriesPart struct {
+	BlogId    BlogId `json:"blogId" db:"blog_id"`
+	Title     string `json:"title" db:"title"`
+	Slug      string `json:"slug" db:"slug"`
+	Position  int    `json:"position" db:"position"`
+	IsPublic  bool   `json:"isPublic" db:"is_public"`
+	PublishAt uint   `json:"publishAt" db:"publish_at"`
+}
+
+// IsPublished は、指定した時刻においてブログが公開されているかを判定する
+func (p *SeriesPart) IsPublished(now time.Time) bool {
+	return p.IsPublic && int64(p.PublishAt) <= now.Unix()
+}
+
+// SeriesLink は、前後のブログへのリンク
+type SeriesLink struct {
+	BlogId BlogId `json:"blogId"`
+	Title  string `json:"title"`
+	Slug   string `json:"slug"`
+}
+
+// BlogSeries は、ブログ詳細に含めるシリーズ内での位置と前後のブログ
+type BlogSeries struct {
+	Id       SeriesId    `json:"id"`
+	Title    string      `json:"title"`
+	Position int         `json:"position"`
+	Total    int         `json:"total"`
+	Prev     *SeriesLink `json:"prev,omitempty"`
+	Next     *SeriesLink `json:"next,omitempty"`
+}
+
+// Navigation は、シリーズ内でのブログの位置と前後のブログを返す
+// 未公開のブログは読者に見せないため、対象のブログ以外は公開中のものだけを数える
+// ブログがシリーズに含まれない場合はnilを返す
+func (s *Series) Navigation(blogId BlogId, now time.Time) *BlogSeries {
+	visible := []*SeriesPart{}
+	current := -1
+	for _, p := range s.Parts {
+		if p.BlogId == blogId {
+			current = len(visible)
+			visible = append(visible, p)
+			continue
+		}
+		if p.IsPublished(now) {
+			visible = append(visible, p)
+		}
+	}
+	if current < 0 {
+		return nil
+	}
+	nav := &BlogSeries{
+		Id:       s.Id,
+		Title:    s.Title,
+		Position: current + 1,
+		Total:    len(visible),
+	}
+	if current > 0 {
+		p := visible[current-1]
+		nav.Prev = &SeriesLink{BlogId: p.BlogId, Title: p.Title, Slug: p.Slug}
+	}
+	if current < len(visible)-1 {
+		p := visible[current+1]
+		nav.Next = &SeriesLink{BlogId: p.BlogId, Title: p.Title, Slug: p.Slug}
+	}
+	return nav
+}
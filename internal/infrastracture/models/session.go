@@ -0,0 +1,13 @@
+package models
+
+// Session は、ログイン中のセッション(アクセストークン)
+// Idはアクセストークンのjtiで、Currentはリクエストに使用したトークンのセッションかを表す
+type Session struct {
+	Id        string `json:"id"`
+	UserId    UserId `json:"-"`
+	UserAgent string `json:"userAgent"`
+	IpAddress string `json:"ipAddress"`
+	Created   uint   `json:"created"`
+	LastSeen  uint   `json:"lastSeen"`
+	Current   bool   `json:"current"`
+}
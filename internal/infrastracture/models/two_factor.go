@@ -0,0 +1,19 @@
+package models
+
+type RecoveryCodeId int64
+
+// RecoveryCode は、2段階認証の使い捨てのリカバリーコード
+// コードはハッシュ化して保存し、使用済みのコードはUsedAtを持つ
+type RecoveryCode struct {
+	Id       RecoveryCodeId `json:"id" db:"id"`
+	UserId   UserId         `json:"userId" db:"user_id"`
+	CodeHash string         `json:"-" db:"code_hash"`
+	UsedAt   *uint          `json:"usedAt" db:"used_at"`
+	Created  uint           `json:"created" db:"created"`
+}
+
+// TOTPSetup は、2段階認証の登録時に認証アプリへ設定する情報
+type TOTPSetup struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
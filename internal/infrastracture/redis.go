@@ -57,6 +57,14 @@ func (r *RedisKVS) Save(ctx context.Context, key string, value string) error {
 	return nil
 }
 
+// SaveWithTTL は、Saveと異なり有効期限を指定して保存する
+func (r *RedisKVS) SaveWithTTL(ctx context.Context, key string, value string, ttl time.Duration) error {
+	if err := r.cli.Set(ctx, key, value, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set key: %w", err)
+	}
+	return nil
+}
+
 func (r *RedisKVS) Load(ctx context.Context, key string) (string, error) {
 	ret := r.cli.Get(ctx, key)
 	if ret.Err() != nil {
@@ -64,3 +72,87 @@ func (r *RedisKVS) Load(ctx context.Context, key string) (string, error) {
 	}
 	return ret.Val(), nil
 }
+
+// LoadAndDelete は、キーの値を取得すると同時に削除する(GETDEL)
+// 同じキーを同時に取得した場合も、値を返すのはいずれか1つのみとなる
+func (r *RedisKVS) LoadAndDelete(ctx context.Context, key string) (string, error) {
+	v, err := r.cli.GetDel(ctx, key).Result()
+	if err != nil {
+		return "", fmt.Errorf("failed to get and delete key: %w", err)
+	}
+	return v, nil
+}
+
+// compareAndSwapScript は、キーの値がARGV[1]と一致する場合のみARGV[2]で上書きする
+var compareAndSwapScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	redis.call("SET", KEYS[1], ARGV[2], "PX", ARGV[3])
+	return 1
+end
+return 0
+`)
+
+// CompareAndSwap は、キーの値がoldと一致する場合のみnewで上書きし、上書きしたかどうかを返す
+// 比較と上書きはLuaスクリプトで同時に行う
+func (r *RedisKVS) CompareAndSwap(
+	ctx context.Context, key string, old string, new string, ttl time.Duration,
+) (bool, error) {
+	swapped, err := compareAndSwapScript.Run(ctx, r.cli, []string{key}, old, new, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to compare and swap key: %w", err)
+	}
+	return swapped == 1, nil
+}
+
+// incrementScript は、キーの値を1加算し、有効期限が設定されていない場合はARGV[1]ミリ秒後に失効させる
+var incrementScript = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+if redis.call("PTTL", KEYS[1]) == -1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return count
+`)
+
+// Increment は、キーの値を1加算し、加算後の値を返す
+// キーが新規に作成された場合はwindow後に失効させる(固定ウィンドウのカウンタ)
+// 有効期限のないカウンタが残らないよう、加算と有効期限の設定はLuaスクリプトで同時に行う
+func (r *RedisKVS) Increment(ctx context.Context, key string, window time.Duration) (int64, error) {
+	count, err := incrementScript.Run(ctx, r.cli, []string{key}, window.Milliseconds()).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("failed to increment key: %w", err)
+	}
+	return count, nil
+}
+
+func (r *RedisKVS) Delete(ctx context.Context, key string) error {
+	if err := r.cli.Del(ctx, key).Err(); err != nil {
+		return fmt.Errorf("failed to delete key: %w", err)
+	}
+	return nil
+}
+
+// AddSetMember は、キーの集合に値を追加し、集合の有効期限をttlに延長する
+func (r *RedisKVS) AddSetMember(ctx context.Context, key string, member string, ttl time.Duration) error {
+	pipe := r.cli.TxPipeline()
+	pipe.SAdd(ctx, key, member)
+	pipe.Expire(ctx, key, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to add set member: %w", err)
+	}
+	return nil
+}
+
+func (r *RedisKVS) RemoveSetMember(ctx context.Context, key string, member string) error {
+	if err := r.cli.SRem(ctx, key, member).Err(); err != nil {
+		return fmt.Errorf("failed to remove set member: %w", err)
+	}
+	return nil
+}
+
+func (r *RedisKVS) SetMembers(ctx context.Context, key string) ([]string, error) {
+	members, err := r.cli.SMembers(ctx, key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get set members: %w", err)
+	}
+	return members, nil
+}
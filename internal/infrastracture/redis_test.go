@@ -2,8 +2,12 @@ package infrastracture_test
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
+	"time"
 
+	"github.com/redis/go-redis/v9"
 	"github.com/shoet/blog/internal/infrastracture"
 )
 
@@ -49,3 +53,120 @@ func Test_Load(t *testing.T) {
 		t.Errorf("want %s, got %s", want, ret)
 	}
 }
+
+func Test_Increment(t *testing.T) {
+	ctx := context.Background()
+	kvs, err := infrastracture.NewRedisKVS(ctx, "127.0.0.1", 6379, "default", "redispw", 10, false)
+	if err != nil {
+		t.Fatalf("failed to create redis kvs: %v", err)
+	}
+
+	key := fmt.Sprintf("test_increment_%d", time.Now().UnixNano())
+	for want := int64(1); want <= 3; want++ {
+		got, err := kvs.Increment(ctx, key, 10*time.Second)
+		if err != nil {
+			t.Fatalf("failed to increment: %v", err)
+		}
+		if got != want {
+			t.Errorf("want %d, but got %d", want, got)
+		}
+	}
+}
+
+func Test_Delete(t *testing.T) {
+	ctx := context.Background()
+	kvs, err := infrastracture.NewRedisKVS(ctx, "127.0.0.1", 6379, "default", "redispw", 300, false)
+	if err != nil {
+		t.Fatalf("failed to create redis kvs: %v", err)
+	}
+
+	if err := kvs.Save(ctx, "test_delete", "test"); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+	if err := kvs.Delete(ctx, "test_delete"); err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+	if _, err := kvs.Load(ctx, "test_delete"); !errors.Is(err, redis.Nil) {
+		t.Errorf("want redis.Nil, but got %v", err)
+	}
+}
+
+func Test_LoadAndDelete(t *testing.T) {
+	ctx := context.Background()
+	kvs, err := infrastracture.NewRedisKVS(ctx, "127.0.0.1", 6379, "default", "redispw", 300, false)
+	if err != nil {
+		t.Fatalf("failed to create redis kvs: %v", err)
+	}
+
+	if err := kvs.Save(ctx, "test_load_and_delete", "test"); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+	got, err := kvs.LoadAndDelete(ctx, "test_load_and_delete")
+	if err != nil {
+		t.Fatalf("failed to load and delete: %v", err)
+	}
+	if got != "test" {
+		t.Errorf("want test, got %s", got)
+	}
+	if _, err := kvs.LoadAndDelete(ctx, "test_load_and_delete"); !errors.Is(err, redis.Nil) {
+		t.Errorf("want redis.Nil, but got %v", err)
+	}
+}
+
+func Test_CompareAndSwap(t *testing.T) {
+	ctx := context.Background()
+	kvs, err := infrastracture.NewRedisKVS(ctx, "127.0.0.1", 6379, "default", "redispw", 300, false)
+	if err != nil {
+		t.Fatalf("failed to create redis kvs: %v", err)
+	}
+
+	if err := kvs.Save(ctx, "test_compare_and_swap", "old"); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+	swapped, err := kvs.CompareAndSwap(ctx, "test_compare_and_swap", "old", "new", 10*time.Second)
+	if err != nil {
+		t.Fatalf("failed to compare and swap: %v", err)
+	}
+	if !swapped {
+		t.Errorf("want swapped")
+	}
+	swapped, err = kvs.CompareAndSwap(ctx, "test_compare_and_swap", "old", "other", 10*time.Second)
+	if err != nil {
+		t.Fatalf("failed to compare and swap: %v", err)
+	}
+	if swapped {
+		t.Errorf("want not swapped")
+	}
+	ret, err := kvs.Load(ctx, "test_compare_and_swap")
+	if err != nil {
+		t.Fatalf("failed to load: %v", err)
+	}
+	if ret != "new" {
+		t.Errorf("want new, got %s", ret)
+	}
+}
+
+func Test_SetMembers(t *testing.T) {
+	ctx := context.Background()
+	kvs, err := infrastracture.NewRedisKVS(ctx, "127.0.0.1", 6379, "default", "redispw", 300, false)
+	if err != nil {
+		t.Fatalf("failed to create redis kvs: %v", err)
+	}
+
+	key := fmt.Sprintf("test_set_%d", time.Now().UnixNano())
+	for _, m := range []string{"a", "b"} {
+		if err := kvs.AddSetMember(ctx, key, m, 10*time.Second); err != nil {
+			t.Fatalf("failed to add set member: %v", err)
+		}
+	}
+	if err := kvs.RemoveSetMember(ctx, key, "a"); err != nil {
+		t.Fatalf("failed to remove set member: %v", err)
+	}
+	got, err := kvs.SetMembers(ctx, key)
+	if err != nil {
+		t.Fatalf("failed to get set members: %v", err)
+	}
+	if len(got) != 1 || got[0] != "b" {
+		t.Errorf("want [b], but got %v", got)
+	}
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/shoet/blog/internal/clocker"
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+)
+
+type APIKeyRepository struct {
+	Clocker clocker.Clocker
+}
+
+func NewAPIKeyRepository(clocker clocker.Clocker) *APIKeyRepository {
+	return &APIKeyRepository{Clocker: clocker}
+}
+
+var apiKeyColumns = []interface{}{
+	"id", "user_id", "name", "prefix", "key_hash", "scopes",
+	"last_used_at", "revoked_at", "created", "modified",
+}
+
+func (r *APIKeyRepository) Add(
+	ctx context.Context, tx infrastracture.TX, key *models.APIKey,
+) (models.APIKeyId, error) {
+	scopes, err := key.Scopes.Value()
+	if err != nil {
+		return 0, fmt.Errorf("failed to convert scopes: %w", err)
+	}
+	sql, params, err := goqu.
+		Insert("api_keys").
+		Cols("user_id", "name", "prefix", "key_hash", "scopes").
+		Vals(goqu.Vals{key.UserId, key.Name, key.Prefix, key.KeyHash, scopes}).
+		Returning("id").
+		ToSQL()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build sql: %w", err)
+	}
+	var id models.APIKeyId
+	if err := tx.QueryRowxContext(ctx, sql, params...).Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to insert api key: %w", err)
+	}
+	return id, nil
+}
+
+// GetByPrefix は、識別用のprefixでAPIキーを取得する
+// 存在しない場合はnilを返す
+func (r *APIKeyRepository) GetByPrefix(
+	ctx context.Context, tx infrastracture.TX, prefix string,
+) (*models.APIKey, error) {
+	sql, params, err := goqu.
+		From("api_keys").
+		Select(apiKeyColumns...).
+		Where(goqu.Ex{"prefix": prefix}).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql: %w", err)
+	}
+	var keys []*models.APIKey
+	if err := tx.SelectContext(ctx, &keys, sql, params...); err != nil {
+		return nil, fmt.Errorf("failed to select api keys: %w", err)
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	return keys[0], nil
+}
+
+// ListByUserId は、ユーザーのAPIキーを失効済みのものも含めて新しい順に取得する
+func (r *APIKeyRepository) ListByUserId(
+	ctx context.Context, tx infrastracture.TX, userId models.UserId,
+) ([]*models.APIKey, error) {
+	sql, params, err := goqu.
+		From("api_keys").
+		Select(apiKeyColumns...).
+		Where(goqu.Ex{"user_id": userId}).
+		Order(goqu.I("id").Desc()).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql: %w", err)
+	}
+	keys := []*models.APIKey{}
+	if err := tx.SelectContext(ctx, &keys, sql, params...); err != nil {
+		return nil, fmt.Errorf("failed to select api keys: %w", err)
+	}
+	return keys, nil
+}
+
+// Revoke は、ユーザーのAPIキーを失効させる
+// 対象のキーが存在しないまたは失効済みの場合はfalseを返す
+func (r *APIKeyRepository) Revoke(
+	ctx context.Context, tx infrastracture.TX, userId models.UserId, id models.APIKeyId,
+) (bool, error) {
+	sql, params, err := goqu.
+		Update("api_keys").
+		Set(goqu.Record{"revoked_at": r.Clocker.Now().Unix()}).
+		Where(goqu.Ex{"id": id, "user_id": userId, "revoked_at": nil}).
+		ToSQL()
+	if err != nil {
+		return false, fmt.Errorf("failed to build sql: %w", err)
+	}
+	result, err := tx.ExecContext(ctx, sql, params...)
+	if err != nil {
+		return false, fmt.Errorf("failed to update api key: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return affected > 0, nil
+}
+
+// TouchLastUsed は、APIキーの最終使用日時を更新する
+func (r *APIKeyRepository) TouchLastUsed(
+	ctx context.Context, tx infrastracture.TX, id models.APIKeyId,
+) error {
+	sql, params, err := goqu.
+		Update("api_keys").
+		Set(goqu.Record{"last_used_at": r.Clocker.Now().Unix()}).
+		Where(goqu.Ex{"id": id}).
+		ToSQL()
+	if err != nil {
+		return fmt.Errorf("failed to build sql: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, sql, params...); err != nil {
+		return fmt.Errorf("failed to update api key: %w", err)
+	}
+	return nil
+}
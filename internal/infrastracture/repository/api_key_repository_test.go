@@ -0,0 +1,83 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/shoet/blog/internal/clocker"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/infrastracture/repository"
+	"github.com/shoet/blog/internal/testutil"
+)
+
+func Test_APIKeyRepository(t *testing.T) {
+	ctx := context.Background()
+	db, err := testutil.NewDBPostgreSQLForTest(t, ctx)
+	if err != nil {
+		t.Fatalf("failed to create db: %v", err)
+	}
+	testutil.RepositoryTestPrepare(t, ctx, db)
+
+	c := &clocker.FiexedClocker{}
+	userRepo, err := repository.NewUserRepository(c)
+	if err != nil {
+		t.Fatalf("failed to create user repository: %v", err)
+	}
+	sut := repository.NewAPIKeyRepository(c)
+
+	tx, err := db.Beginx()
+	if err != nil {
+		t.Fatalf("failed to create tx: %v", err)
+	}
+	defer tx.Rollback()
+
+	user, err := userRepo.Add(ctx, tx, &models.User{Name: "ci", Email: "ci@test.com", Password: "test"})
+	if err != nil {
+		t.Fatalf("failed to add user: %v", err)
+	}
+	id, err := sut.Add(ctx, tx, &models.APIKey{
+		UserId:  user.Id,
+		Name:    "ci",
+		Prefix:  "abcd1234",
+		KeyHash: "hash",
+		Scopes:  models.APIKeyScopes{models.APIKeyScopeBlogsWrite, models.APIKeyScopeFilesWrite},
+	})
+	if err != nil {
+		t.Fatalf("failed to add api key: %v", err)
+	}
+
+	got, err := sut.GetByPrefix(ctx, tx, "abcd1234")
+	if err != nil {
+		t.Fatalf("failed to get api key: %v", err)
+	}
+	if got == nil || got.Id != id || got.KeyHash != "hash" {
+		t.Fatalf("unexpected api key: %+v", got)
+	}
+	want := models.APIKeyScopes{models.APIKeyScopeBlogsWrite, models.APIKeyScopeFilesWrite}
+	if diff := cmp.Diff(got.Scopes, want); diff != "" {
+		t.Errorf("(-got +want)\n%s", diff)
+	}
+	if notFound, err := sut.GetByPrefix(ctx, tx, "notfound"); err != nil || notFound != nil {
+		t.Errorf("want nil, but got %+v, %v", notFound, err)
+	}
+
+	// 他のユーザーのキーは失効できない
+	if revoked, err := sut.Revoke(ctx, tx, user.Id+1, id); err != nil || revoked {
+		t.Errorf("want not revoked, but got %v, %v", revoked, err)
+	}
+	if revoked, err := sut.Revoke(ctx, tx, user.Id, id); err != nil || !revoked {
+		t.Errorf("want revoked, but got %v, %v", revoked, err)
+	}
+	if revoked, err := sut.Revoke(ctx, tx, user.Id, id); err != nil || revoked {
+		t.Errorf("want not revoked twice, but got %v, %v", revoked, err)
+	}
+
+	keys, err := sut.ListByUserId(ctx, tx, user.Id)
+	if err != nil {
+		t.Fatalf("failed to list api keys: %v", err)
+	}
+	if len(keys) != 1 || keys[0].RevokedAt == nil {
+		t.Errorf("want 1 revoked key, but got %+v", keys)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
 	"github.com/shoet/blog/internal/clocker"
 	"github.com/shoet/blog/internal/infrastracture"
 	"github.com/shoet/blog/internal/infrastracture/models"
@@ -26,10 +27,14 @@ func NewBlogRepository(clocker clocker.Clocker) *BlogRepository {
 func (r *BlogRepository) Add(ctx context.Context, tx infrastracture.TX, blog *models.Blog) (models.BlogId, error) {
 	sql, params, err := goqu.
 		Insert("blogs").
-		Cols("author_id", "title", "content", "description", "thumbnail_image_file_name", "is_public").
+		Cols(
+			"author_id", "title", "slug", "content", "description", "thumbnail_image_file_name", "is_public", "publish_at",
+			"content_html", "toc", "word_count", "char_count", "reading_time",
+		).
 		Vals(goqu.Vals{
-			blog.AuthorId, blog.Title, blog.Content, blog.Description,
-			blog.ThumbnailImageFileName, blog.IsPublic,
+			blog.AuthorId, blog.Title, blog.Slug, blog.Content, blog.Description,
+			blog.ThumbnailImageFileName, blog.IsPublic, blog.PublishAt,
+			blog.ContentHTML, blog.Toc, blog.WordCount, blog.CharCount, blog.ReadingTime,
 		}).
 		Returning("id").
 		ToSQL()
@@ -47,6 +52,15 @@ func (r *BlogRepository) Add(ctx context.Context, tx infrastracture.TX, blog *mo
 	return models.BlogId(id), nil
 }
 
+// publicCondition は、公開中のブログを絞り込む条件を生成する
+// 公開フラグが立っていても、公開日時が未来の場合は予約投稿として扱い除外する
+func (r *BlogRepository) publicCondition() goqu.Ex {
+	return goqu.Ex{
+		"is_public":  true,
+		"publish_at": goqu.Op{"lte": r.Clocker.Now().Unix()},
+	}
+}
+
 type BlogTag struct {
 	BlogId models.BlogId `db:"blog_id"`
 	Tag    string        `db:"tag"`
@@ -82,14 +96,17 @@ func (r *BlogRepository) List(
 ) ([]*models.Blog, error) {
 	builder := goqu.
 		Select(
-			"id", "author_id", "title", "description",
-			"thumbnail_image_file_name", "is_public", "created", "modified",
+			"id", "author_id", "title", "slug", "description",
+			"thumbnail_image_file_name", "is_public", "publish_at", "created", "modified",
 		).
 		From("blogs").
 		Order(goqu.I("id").Desc()).
 		Limit(uint(option.Limit))
 	if option.IsPublic {
-		builder = builder.Where(goqu.Ex{"is_public": true})
+		builder = builder.Where(r.publicCondition())
+	}
+	if option.AuthorId != nil {
+		builder = builder.Where(goqu.Ex{"author_id": *option.AuthorId})
 	}
 	if option.CursorId != nil {
 		if option.PageDirection == "prev" {
@@ -127,11 +144,13 @@ func (r *BlogRepository) List(
 		blogs = append(blogs, &models.Blog{
 			Id:                     t.Id,
 			Title:                  t.Title,
+			Slug:                   t.Slug,
 			Description:            t.Description,
 			Content:                t.Content,
 			AuthorId:               t.AuthorId,
 			ThumbnailImageFileName: t.ThumbnailImageFileName,
 			IsPublic:               t.IsPublic,
+			PublishAt:              t.PublishAt,
 			Tags:                   tags,
 			Created:                t.Created,
 			Modified:               t.Modified,
@@ -163,12 +182,15 @@ func (r *BlogRepository) ListByTag(
 		).
 		Order(goqu.I("id").Desc()).
 		Select(
-			"id", "author_id", "title", "description",
-			"thumbnail_image_file_name", "is_public", "created", "modified",
+			"id", "author_id", "title", "slug", "description",
+			"thumbnail_image_file_name", "is_public", "publish_at", "created", "modified",
 		).
 		Limit(uint(option.Limit))
 	if option.IsPublic {
-		builder = builder.Where(goqu.Ex{"is_public": true})
+		builder = builder.Where(r.publicCondition())
+	}
+	if option.AuthorId != nil {
+		builder = builder.Where(goqu.Ex{"author_id": *option.AuthorId})
 	}
 	if option.CursorId != nil {
 		if option.PageDirection == "prev" {
@@ -193,46 +215,125 @@ func (r *BlogRepository) ListByTag(
 	return blogs, nil
 }
 
-// TODO: ページング
+// ListByKeywordはキーワードに一致するブログを検索する
+// 検索順位の高い順に並べ、同順位の場合はIDの降順とする
 // カーソルページング実装
 func (r *BlogRepository) ListByKeyword(
 	ctx context.Context, tx infrastracture.TX, keyword string, option *options.ListBlogOptions,
 ) (models.Blogs, error) {
 	builder := goqu.
-		From("blogs").
-		Where(goqu.ExOr{
-			"title":       goqu.Op{"like": "%" + keyword + "%"},
-			"description": goqu.Op{"like": "%" + keyword + "%"},
-		}).
-		Order(goqu.I("id").Desc()).
+		From("ranked").
+		With("ranked", r.rankedByKeyword(keyword, option)).
 		Select(
-			"id", "author_id", "title", "description",
-			"thumbnail_image_file_name", "is_public", "created", "modified",
+			"id", "author_id", "title", "slug", "description",
+			"thumbnail_image_file_name", "is_public", "publish_at", "created", "modified", "rank",
 		).
+		Order(goqu.I("rank").Desc(), goqu.I("id").Desc()).
 		Limit(uint(option.Limit))
-	if option.IsPublic {
-		builder = builder.Where(goqu.Ex{"is_public": true})
-	}
 	if option.CursorId != nil {
+		// カーソルのブログと順位、IDの組で比較する
+		cursorId := int64(*option.CursorId)
+		cursorRank := goqu.L("COALESCE((SELECT rank FROM ranked WHERE id = ?), 0)", cursorId)
 		if option.PageDirection == "prev" {
-			builder = builder.Where(goqu.Ex{"id": goqu.Op{"gt": option.CursorId}}).Order(goqu.I("id").Asc())
+			builder = builder.
+				Where(goqu.L("(rank, id) > (?, ?)", cursorRank, cursorId)).
+				Order(goqu.I("rank").Asc(), goqu.I("id").Asc())
 		}
 		if option.PageDirection == "next" {
-			builder = builder.Where(goqu.Ex{"id": goqu.Op{"lt": option.CursorId}}).Order(goqu.I("id").Desc())
+			builder = builder.
+				Where(goqu.L("(rank, id) < (?, ?)", cursorRank, cursorId)).
+				Order(goqu.I("rank").Desc(), goqu.I("id").Desc())
 		}
 	}
 	sql, params, err := builder.ToSQL()
 	if err != nil {
 		return nil, fmt.Errorf("failed to build sql: %w", err)
 	}
-	var blogs models.Blogs
-	if err := tx.SelectContext(ctx, &blogs, sql, params...); err != nil {
+	var ranked []*rankedBlog
+	if err := tx.SelectContext(ctx, &ranked, sql, params...); err != nil {
 		return nil, fmt.Errorf("failed to SelectContext: %w", err)
 	}
-	if len(blogs) == 0 {
-		return []*models.Blog{}, nil
+	return sortRankedBlogs(ranked), nil
+}
+
+// rankedBlog は、検索順位付きのブログ
+type rankedBlog struct {
+	models.Blog
+	Rank float64 `db:"rank"`
+}
+
+// sortRankedBlogs は、検索結果を順位の降順、IDの降順に並べ替える
+func sortRankedBlogs(ranked []*rankedBlog) models.Blogs {
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Rank != ranked[j].Rank {
+			return ranked[i].Rank > ranked[j].Rank
+		}
+		return ranked[i].Id > ranked[j].Id
+	})
+	blogs := make(models.Blogs, 0, len(ranked))
+	for _, b := range ranked {
+		blog := b.Blog
+		blogs = append(blogs, &blog)
+	}
+	return blogs
+}
+
+// rankedByKeyword は、キーワードに一致するブログを検索順位付きで取得するクエリを生成する
+// 検索対象はトリガーで更新される search_vector と search_text で、タイトル、概要、本文、タグを含む
+func (r *BlogRepository) rankedByKeyword(keyword string, option *options.ListBlogOptions) *goqu.SelectDataset {
+	builder := goqu.
+		From("blogs").
+		Select(
+			"id", "author_id", "title", "slug", "description",
+			"thumbnail_image_file_name", "is_public", "publish_at", "created", "modified",
+			goqu.L("ts_rank_cd(search_vector, websearch_to_tsquery('simple', ?))", keyword).As("rank"),
+		).
+		Where(keywordCondition(keyword))
+	if option.IsPublic {
+		builder = builder.Where(r.publicCondition())
+	}
+	if option.AuthorId != nil {
+		builder = builder.Where(goqu.Ex{"author_id": *option.AuthorId})
+	}
+	return builder
+}
+
+// keywordCondition は、キーワードに一致するブログを絞り込む条件を生成する
+// 単語単位の全文検索に加え、日本語のように単語の区切りがない文章のために
+// トライグラムインデックスを使った大文字小文字を区別しない部分一致でも検索する
+func keywordCondition(keyword string) exp.ExpressionList {
+	return goqu.Or(
+		goqu.L("search_vector @@ websearch_to_tsquery('simple', ?)", keyword),
+		goqu.L("search_text LIKE ?", "%"+escapeLike(strings.ToLower(keyword))+"%"),
+	)
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// escapeLike は、LIKE句のワイルドカードをエスケープする
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// SelectBlogsContents は、検索結果のスニペット生成のためにブログの概要と本文を取得する
+func (r *BlogRepository) SelectBlogsContents(
+	ctx context.Context, tx infrastracture.TX, ids []models.BlogId,
+) (models.Blogs, error) {
+	if len(ids) == 0 {
+		return models.Blogs{}, nil
+	}
+	sql, params, err := goqu.
+		Select("id", "description", "content").
+		From("blogs").
+		Where(goqu.Ex{"id": ids}).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql: %w", err)
+	}
+	var blogs models.Blogs
+	if err := tx.SelectContext(ctx, &blogs, sql, params...); err != nil {
+		return nil, fmt.Errorf("failed to select blogs: %w", err)
 	}
-	sort.SliceStable(blogs, func(i, j int) bool { return blogs[i].Id > blogs[j].Id })
 	return blogs, nil
 }
 
@@ -240,8 +341,9 @@ func (r *BlogRepository) Get(
 	ctx context.Context, tx infrastracture.TX, id models.BlogId,
 ) (*models.Blog, error) {
 	sql, params, err := goqu.
-		Select("id", "author_id", "title", "content", "description",
-			"thumbnail_image_file_name", "is_public", "created", "modified",
+		Select("id", "author_id", "title", "slug", "content", "description",
+			"thumbnail_image_file_name", "is_public", "publish_at", "created", "modified",
+			"content_html", "toc", "word_count", "char_count", "reading_time",
 		).
 		From("blogs").
 		Where(goqu.Ex{"id": id}).
@@ -276,6 +378,146 @@ func (r *BlogRepository) Get(
 	return blogs[0], nil
 }
 
+// GetBySlug は、スラッグに一致するブログを取得する
+// 存在しない場合はnilを返す
+func (r *BlogRepository) GetBySlug(
+	ctx context.Context, tx infrastracture.TX, slug string,
+) (*models.Blog, error) {
+	if slug == "" {
+		return nil, nil
+	}
+	sql, params, err := goqu.
+		Select("id").
+		From("blogs").
+		Where(goqu.Ex{"slug": slug}).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql: %w", err)
+	}
+	var ids []models.BlogId
+	if err := tx.SelectContext(ctx, &ids, sql, params...); err != nil {
+		return nil, fmt.Errorf("failed to select blog: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return r.Get(ctx, tx, ids[0])
+}
+
+// ExistsSlug は、スラッグが他のブログの現在または過去のスラッグとして使用されているかを判定する
+func (r *BlogRepository) ExistsSlug(
+	ctx context.Context, tx infrastracture.TX, slug string, excludeBlogId models.BlogId,
+) (bool, error) {
+	sql := `
+	SELECT
+		EXISTS (
+			SELECT 1 FROM blogs WHERE slug = $1 AND id <> $2
+		)
+		OR EXISTS (
+			SELECT 1 FROM blog_slug_histories WHERE slug = $1 AND blog_id <> $2
+		)
+	;
+	`
+	var exists bool
+	if err := tx.QueryRowxContext(ctx, sql, slug, excludeBlogId).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to select slug: %w", err)
+	}
+	return exists, nil
+}
+
+// ExistsByAuthorId は、ユーザーを著者とするブログが存在するかを判定する
+func (r *BlogRepository) ExistsByAuthorId(
+	ctx context.Context, tx infrastracture.TX, authorId models.UserId,
+) (bool, error) {
+	sql := `SELECT EXISTS (SELECT 1 FROM blogs WHERE author_id = $1);`
+	var exists bool
+	if err := tx.QueryRowxContext(ctx, sql, authorId).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to select blogs: %w", err)
+	}
+	return exists, nil
+}
+
+// ExistsPublicByAuthorId は、ユーザーを著者とする公開中のブログが存在するかを判定する
+func (r *BlogRepository) ExistsPublicByAuthorId(
+	ctx context.Context, tx infrastracture.TX, authorId models.UserId,
+) (bool, error) {
+	sql, params, err := goqu.
+		Select(goqu.L("EXISTS ?", goqu.
+			From("blogs").
+			Select(goqu.L("1")).
+			Where(r.publicCondition(), goqu.Ex{"author_id": authorId}),
+		)).
+		ToSQL()
+	if err != nil {
+		return false, fmt.Errorf("failed to build sql: %w", err)
+	}
+	var exists bool
+	if err := tx.QueryRowxContext(ctx, sql, params...).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to select blogs: %w", err)
+	}
+	return exists, nil
+}
+
+// AddSlugHistory は、変更前のスラッグを履歴として保存する
+func (r *BlogRepository) AddSlugHistory(
+	ctx context.Context, tx infrastracture.TX, blogId models.BlogId, slug string,
+) error {
+	sql := `
+	INSERT INTO blog_slug_histories
+		(blog_id, slug)
+	VALUES
+		($1, $2)
+	ON CONFLICT (slug) DO UPDATE SET blog_id = EXCLUDED.blog_id
+	;
+	`
+	if _, err := tx.ExecContext(ctx, sql, blogId, slug); err != nil {
+		return fmt.Errorf("failed to insert blog_slug_histories: %w", err)
+	}
+	return nil
+}
+
+// DeleteSlugHistory は、スラッグの履歴を削除する
+// 過去のスラッグを再び現在のスラッグとして使用する場合に呼び出す
+func (r *BlogRepository) DeleteSlugHistory(
+	ctx context.Context, tx infrastracture.TX, slug string,
+) error {
+	sql := `
+	DELETE FROM
+		blog_slug_histories
+	WHERE
+		slug = $1
+	;
+	`
+	if _, err := tx.ExecContext(ctx, sql, slug); err != nil {
+		return fmt.Errorf("failed to delete blog_slug_histories: %w", err)
+	}
+	return nil
+}
+
+// GetBlogIdBySlugHistory は、過去のスラッグから現在のブログIDを取得する
+// 存在しない場合は0を返す
+func (r *BlogRepository) GetBlogIdBySlugHistory(
+	ctx context.Context, tx infrastracture.TX, slug string,
+) (models.BlogId, error) {
+	sql := `
+	SELECT
+		blog_id
+	FROM
+		blog_slug_histories
+	WHERE
+		slug = $1
+	;
+	`
+	var ids []models.BlogId
+	if err := tx.SelectContext(ctx, &ids, sql, slug); err != nil {
+		return 0, fmt.Errorf("failed to select blog_slug_histories: %w", err)
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	return ids[0], nil
+}
+
 func (r *BlogRepository) Delete(ctx context.Context, tx infrastracture.TX, id models.BlogId) error {
 	sql, params, err := goqu.
 		Delete("blogs").
@@ -300,10 +542,17 @@ func (r *BlogRepository) Put(
 		Set(goqu.Record{
 			"author_id":                 blog.AuthorId,
 			"title":                     blog.Title,
+			"slug":                      blog.Slug,
 			"content":                   blog.Content,
 			"description":               blog.Description,
 			"thumbnail_image_file_name": blog.ThumbnailImageFileName,
 			"is_public":                 blog.IsPublic,
+			"publish_at":                blog.PublishAt,
+			"content_html":              blog.ContentHTML,
+			"toc":                       blog.Toc,
+			"word_count":                blog.WordCount,
+			"char_count":                blog.CharCount,
+			"reading_time":              blog.ReadingTime,
 			"modified":                  blog.Modified,
 		}).
 		Where(goqu.Ex{"id": blog.Id}).
@@ -317,6 +566,88 @@ func (r *BlogRepository) Put(
 	return blog.Id, nil
 }
 
+// PutRendered は、本文のレンダリング結果のキャッシュのみを更新する
+func (r *BlogRepository) PutRendered(
+	ctx context.Context, tx infrastracture.TX, blog *models.Blog,
+) error {
+	sql, params, err := goqu.
+		Update("blogs").
+		Set(goqu.Record{
+			"content_html": blog.ContentHTML,
+			"toc":          blog.Toc,
+			"word_count":   blog.WordCount,
+			"char_count":   blog.CharCount,
+			"reading_time": blog.ReadingTime,
+		}).
+		Where(goqu.Ex{"id": blog.Id}).
+		ToSQL()
+	if err != nil {
+		return fmt.Errorf("failed to build sql: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, sql, params...); err != nil {
+		return fmt.Errorf("failed to update blog: %w", err)
+	}
+	return nil
+}
+
+// ListIds は、すべてのブログのIDを昇順で取得する
+func (r *BlogRepository) ListIds(
+	ctx context.Context, tx infrastracture.TX,
+) ([]models.BlogId, error) {
+	sql, params, err := goqu.
+		Select("id").
+		From("blogs").
+		Order(goqu.I("id").Asc()).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql: %w", err)
+	}
+	var ids []models.BlogId
+	if err := tx.SelectContext(ctx, &ids, sql, params...); err != nil {
+		return nil, fmt.Errorf("failed to select blogs: %w", err)
+	}
+	return ids, nil
+}
+
+// ListImageSources は、画像の参照の判定のために下書きを含むすべてのブログのサムネイルと本文を取得する
+func (r *BlogRepository) ListImageSources(
+	ctx context.Context, tx infrastracture.TX,
+) (models.Blogs, error) {
+	sql, params, err := goqu.
+		Select("id", "thumbnail_image_file_name", "content").
+		From("blogs").
+		Order(goqu.I("id").Asc()).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql: %w", err)
+	}
+	blogs := models.Blogs{}
+	if err := tx.SelectContext(ctx, &blogs, sql, params...); err != nil {
+		return nil, fmt.Errorf("failed to select blogs: %w", err)
+	}
+	return blogs, nil
+}
+
+// ListRevisionImageSources は、画像の参照の判定のためにすべての編集履歴のサムネイルと本文を取得する
+// 履歴の復元で参照が戻るため、現在のブログから参照されていない画像も残す必要がある
+func (r *BlogRepository) ListRevisionImageSources(
+	ctx context.Context, tx infrastracture.TX,
+) ([]*models.BlogRevision, error) {
+	sql, params, err := goqu.
+		Select("id", "blog_id", "thumbnail_image_file_name", "content").
+		From("blog_revisions").
+		Order(goqu.I("id").Asc()).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql: %w", err)
+	}
+	revisions := []*models.BlogRevision{}
+	if err := tx.SelectContext(ctx, &revisions, sql, params...); err != nil {
+		return nil, fmt.Errorf("failed to select blog revisions: %w", err)
+	}
+	return revisions, nil
+}
+
 func (r *BlogRepository) AddBlogTag(
 	ctx context.Context, tx infrastracture.TX, blogId models.BlogId, tagId models.TagId,
 ) (int64, error) {
@@ -381,6 +712,101 @@ func (r *BlogRepository) SelectBlogsTagsByOtherUsingBlog(
 	return result, nil
 }
 
+type relatedBlog struct {
+	models.Blog
+	Score float64 `db:"score"`
+}
+
+/*
+ListRelatedは、そのブログとタグを共有する公開中の他のブログを関連度の高い順に取得する
+関連度は共有するタグごとの希少度(IDF)の合計で、多くのブログが使うタグほど重みが小さい
+関連度が同じ場合は公開日時(作成日時と公開日時の遅い方)の新しい順とする
+*/
+func (r *BlogRepository) ListRelated(
+	ctx context.Context, tx infrastracture.TX, blogId models.BlogId, limit int64,
+) (models.Blogs, error) {
+	sql := `
+	WITH public_blogs AS (
+		SELECT
+			id
+		FROM
+			blogs
+		WHERE
+			is_public = TRUE
+			AND publish_at <= $2
+	), tag_frequencies AS (
+		SELECT
+			blogs_tags.tag_id
+			, COUNT(*) AS df
+		FROM
+			blogs_tags
+		JOIN public_blogs
+			ON blogs_tags.blog_id = public_blogs.id
+		GROUP BY
+			blogs_tags.tag_id
+	), total AS (
+		SELECT
+			COUNT(*) AS n
+		FROM
+			public_blogs
+	)
+	SELECT
+		blogs.id
+		, blogs.author_id
+		, blogs.title
+		, blogs.slug
+		, blogs.description
+		, blogs.thumbnail_image_file_name
+		, blogs.is_public
+		, blogs.publish_at
+		, blogs.created
+		, blogs.modified
+		, SUM(LN(1 + total.n::FLOAT / tag_frequencies.df)) AS score
+	FROM
+		blogs_tags AS target
+	JOIN blogs_tags AS other
+		ON target.tag_id = other.tag_id
+		AND target.blog_id <> other.blog_id
+	JOIN public_blogs
+		ON other.blog_id = public_blogs.id
+	JOIN tag_frequencies
+		ON target.tag_id = tag_frequencies.tag_id
+	JOIN blogs
+		ON other.blog_id = blogs.id
+	CROSS JOIN total
+	WHERE
+		target.blog_id = $1
+	GROUP BY
+		blogs.id
+	ORDER BY
+		score DESC
+		, GREATEST(blogs.created, blogs.publish_at) DESC
+		, blogs.id DESC
+	LIMIT $3
+	;
+	`
+	var related []*relatedBlog
+	if err := tx.SelectContext(ctx, &related, sql, blogId, r.Clocker.Now().Unix(), limit); err != nil {
+		return nil, fmt.Errorf("failed to select related blogs: %w", err)
+	}
+	blogs := make(models.Blogs, 0, len(related))
+	for _, rb := range related {
+		blogTag, err := r.WithBlogTags(ctx, tx, rb.Id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to select blogs_tags: %w", err)
+		}
+		tags := make([]string, 0, len(blogTag))
+		for _, t := range blogTag {
+			tags = append(tags, t.Tag)
+		}
+		sort.Strings(tags)
+		blog := rb.Blog
+		blog.Tags = tags
+		blogs = append(blogs, &blog)
+	}
+	return blogs, nil
+}
+
 func (r *BlogRepository) SelectBlogsTags(
 	ctx context.Context, tx infrastracture.TX, blogId models.BlogId,
 ) ([]*models.BlogsTags, error) {
@@ -495,3 +921,131 @@ func (r *BlogRepository) ListTags(
 	}
 	return tags, nil
 }
+
+// ListPublishedModified は、公開中のすべてのブログのIDとスラッグ、更新日時、公開日時をIDの昇順で取得する
+func (r *BlogRepository) ListPublishedModified(
+	ctx context.Context, tx infrastracture.TX,
+) (models.Blogs, error) {
+	sql, params, err := goqu.
+		Select("id", "slug", "modified", "publish_at").
+		From("blogs").
+		Where(r.publicCondition()).
+		Order(goqu.I("id").Asc()).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql: %w", err)
+	}
+	var blogs models.Blogs
+	if err := tx.SelectContext(ctx, &blogs, sql, params...); err != nil {
+		return nil, fmt.Errorf("failed to select blogs: %w", err)
+	}
+	return blogs, nil
+}
+
+// ListTagsModified は、公開中のブログに付いているタグと、
+// そのタグが付いたブログの最終更新日時(更新日時と公開日時の遅い方)をタグ名の昇順で取得する
+func (r *BlogRepository) ListTagsModified(
+	ctx context.Context, tx infrastracture.TX,
+) ([]*models.TagModified, error) {
+	sql, params, err := goqu.
+		Select(goqu.I("tags.name"), goqu.MAX(goqu.Func("GREATEST", goqu.I("blogs.modified"), goqu.I("blogs.publish_at"))).As("modified")).
+		From("tags").
+		Join(goqu.T("blogs_tags"), goqu.On(goqu.Ex{"blogs_tags.tag_id": goqu.I("tags.id")})).
+		Join(goqu.T("blogs"), goqu.On(goqu.Ex{"blogs_tags.blog_id": goqu.I("blogs.id")})).
+		Where(r.publicCondition()).
+		GroupBy(goqu.I("tags.name")).
+		Order(goqu.I("tags.name").Asc()).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql: %w", err)
+	}
+	var tags []*models.TagModified
+	if err := tx.SelectContext(ctx, &tags, sql, params...); err != nil {
+		return nil, fmt.Errorf("failed to select tags: %w", err)
+	}
+	return tags, nil
+}
+
+// AddRevision は、ブログの履歴を追加する
+// リビジョン番号はブログごとに1から採番する
+func (r *BlogRepository) AddRevision(
+	ctx context.Context, tx infrastracture.TX, revision *models.BlogRevision,
+) (*models.BlogRevision, error) {
+	sql := `
+	INSERT INTO blog_revisions
+		(
+			blog_id, revision, editor_id, title, content, description,
+			thumbnail_image_file_name
+		)
+	SELECT
+		$1, COALESCE(MAX(revision), 0) + 1, $2, $3, $4, $5, $6
+	FROM
+		blog_revisions
+	WHERE
+		blog_id = $1
+	RETURNING id, revision, created
+	;
+	`
+	row := tx.QueryRowxContext(
+		ctx, sql,
+		revision.BlogId, revision.EditorId, revision.Title, revision.Content,
+		revision.Description, revision.ThumbnailImageFileName,
+	)
+	if row.Err() != nil {
+		return nil, fmt.Errorf("failed to insert blog_revisions: %w", row.Err())
+	}
+	if err := row.Scan(&revision.Id, &revision.Revision, &revision.Created); err != nil {
+		return nil, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+	return revision, nil
+}
+
+// ListRevisions は、ブログの履歴を新しい順に取得する
+// 本文は返却しない
+func (r *BlogRepository) ListRevisions(
+	ctx context.Context, tx infrastracture.TX, blogId models.BlogId,
+) ([]*models.BlogRevision, error) {
+	sql, params, err := goqu.
+		Select(
+			"id", "blog_id", "revision", "editor_id", "title", "description",
+			"thumbnail_image_file_name", "created",
+		).
+		From("blog_revisions").
+		Where(goqu.Ex{"blog_id": blogId}).
+		Order(goqu.I("revision").Desc()).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql: %w", err)
+	}
+	revisions := make([]*models.BlogRevision, 0)
+	if err := tx.SelectContext(ctx, &revisions, sql, params...); err != nil {
+		return nil, fmt.Errorf("failed to select blog_revisions: %w", err)
+	}
+	return revisions, nil
+}
+
+// GetRevision は、ブログの指定したリビジョンを取得する
+// 存在しない場合はnilを返す
+func (r *BlogRepository) GetRevision(
+	ctx context.Context, tx infrastracture.TX, blogId models.BlogId, revision int64,
+) (*models.BlogRevision, error) {
+	sql, params, err := goqu.
+		Select(
+			"id", "blog_id", "revision", "editor_id", "title", "content", "description",
+			"thumbnail_image_file_name", "created",
+		).
+		From("blog_revisions").
+		Where(goqu.Ex{"blog_id": blogId, "revision": revision}).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql: %w", err)
+	}
+	var revisions []*models.BlogRevision
+	if err := tx.SelectContext(ctx, &revisions, sql, params...); err != nil {
+		return nil, fmt.Errorf("failed to select blog_revisions: %w", err)
+	}
+	if len(revisions) == 0 {
+		return nil, nil
+	}
+	return revisions[0], nil
+}
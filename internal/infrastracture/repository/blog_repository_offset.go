@@ -38,14 +38,14 @@ func (r *BlogRepositoryOffset) List(
 ) (models.Blogs, error) {
 	builder := goqu.
 		Select(
-			"id", "author_id", "title", "description",
-			"thumbnail_image_file_name", "is_public", "created", "modified",
+			"id", "author_id", "title", "slug", "description",
+			"thumbnail_image_file_name", "is_public", "publish_at", "created", "modified",
 		).
 		From("blogs").
 		Order(goqu.I("id").Desc()).
 		Limit(uint(option.Limit))
 	if option.IsPublic {
-		builder = builder.Where(goqu.Ex{"is_public": true})
+		builder = builder.Where(r.publicCondition())
 	}
 
 	offset := r.buildOffset(option.Page, option.Limit)
@@ -79,11 +79,13 @@ func (r *BlogRepositoryOffset) List(
 		blogs = append(blogs, &models.Blog{
 			Id:                     t.Id,
 			Title:                  t.Title,
+			Slug:                   t.Slug,
 			Description:            t.Description,
 			Content:                t.Content,
 			AuthorId:               t.AuthorId,
 			ThumbnailImageFileName: t.ThumbnailImageFileName,
 			IsPublic:               t.IsPublic,
+			PublishAt:              t.PublishAt,
 			Tags:                   tags,
 			Created:                t.Created,
 			Modified:               t.Modified,
@@ -113,12 +115,12 @@ func (r *BlogRepositoryOffset) ListByTag(
 		).
 		Order(goqu.I("id").Desc()).
 		Select(
-			"id", "author_id", "title", "description",
-			"thumbnail_image_file_name", "is_public", "created", "modified",
+			"id", "author_id", "title", "slug", "description",
+			"thumbnail_image_file_name", "is_public", "publish_at", "created", "modified",
 		).
 		Limit(uint(option.Limit))
 	if option.IsPublic {
-		builder = builder.Where(goqu.Ex{"is_public": true})
+		builder = builder.Where(r.publicCondition())
 	}
 	offset := r.buildOffset(option.Page, option.Limit)
 	builder = builder.Offset(uint(offset))
@@ -137,39 +139,31 @@ func (r *BlogRepositoryOffset) ListByTag(
 	return blogs, nil
 }
 
+// ListByKeywordはキーワードに一致するブログを検索する
+// 検索順位の高い順に並べ、同順位の場合はIDの降順とする
 func (r *BlogRepositoryOffset) ListByKeyword(
 	ctx context.Context, tx infrastracture.TX, keyword string, option *options.ListBlogOptions,
 ) (models.Blogs, error) {
 	builder := goqu.
-		From("blogs").
-		Where(goqu.ExOr{
-			"title":       goqu.Op{"like": "%" + keyword + "%"},
-			"description": goqu.Op{"like": "%" + keyword + "%"},
-		}).
-		Order(goqu.I("id").Desc()).
+		From("ranked").
+		With("ranked", r.rankedByKeyword(keyword, option)).
 		Select(
-			"id", "author_id", "title", "description",
-			"thumbnail_image_file_name", "is_public", "created", "modified",
+			"id", "author_id", "title", "slug", "description",
+			"thumbnail_image_file_name", "is_public", "publish_at", "created", "modified", "rank",
 		).
+		Order(goqu.I("rank").Desc(), goqu.I("id").Desc()).
 		Limit(uint(option.Limit))
-	if option.IsPublic {
-		builder = builder.Where(goqu.Ex{"is_public": true})
-	}
 	offset := r.buildOffset(option.Page, option.Limit)
 	builder = builder.Offset(uint(offset))
 	sql, params, err := builder.ToSQL()
 	if err != nil {
 		return nil, fmt.Errorf("failed to build sql: %w", err)
 	}
-	var blogs models.Blogs
-	if err := tx.SelectContext(ctx, &blogs, sql, params...); err != nil {
+	var ranked []*rankedBlog
+	if err := tx.SelectContext(ctx, &ranked, sql, params...); err != nil {
 		return nil, fmt.Errorf("failed to SelectContext: %w", err)
 	}
-	if len(blogs) == 0 {
-		return []*models.Blog{}, nil
-	}
-	sort.SliceStable(blogs, func(i, j int) bool { return blogs[i].Id > blogs[j].Id })
-	return blogs, nil
+	return sortRankedBlogs(ranked), nil
 }
 
 func (r *BlogRepositoryOffset) CountBlogs(
@@ -177,7 +171,7 @@ func (r *BlogRepositoryOffset) CountBlogs(
 ) (int64, error) {
 	builder := goqu.Select(goqu.COUNT("*").As("count")).From("blogs")
 	if option.IsPublic {
-		builder = builder.Where(goqu.Ex{"is_public": true})
+		builder = builder.Where(r.publicCondition())
 	}
 	sql, params, err := builder.ToSQL()
 	if err != nil {
@@ -213,7 +207,7 @@ func (r *BlogRepositoryOffset) CountBlogsByTag(
 		).
 		Select(goqu.COUNT("*").As("count"))
 	if option.IsPublic {
-		builder = builder.Where(goqu.Ex{"is_public": true})
+		builder = builder.Where(r.publicCondition())
 	}
 	sql, params, err := builder.ToSQL()
 	if err != nil {
@@ -234,13 +228,10 @@ func (r *BlogRepositoryOffset) CountBlogsByKeyword(
 ) (int64, error) {
 	builder := goqu.
 		From("blogs").
-		Where(goqu.ExOr{
-			"title":       goqu.Op{"like": "%" + keyword + "%"},
-			"description": goqu.Op{"like": "%" + keyword + "%"},
-		}).
+		Where(keywordCondition(keyword)).
 		Select(goqu.COUNT("*").As("count"))
 	if option.IsPublic {
-		builder = builder.Where(goqu.Ex{"is_public": true})
+		builder = builder.Where(r.publicCondition())
 	}
 	sql, params, err := builder.ToSQL()
 	if err != nil {
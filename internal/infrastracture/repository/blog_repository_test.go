@@ -75,11 +75,12 @@ func Test_BlogRepository_Add(t *testing.T) {
 				t.Fatalf("failed to add blog: %v", err)
 			}
 
-			row := tx.QueryRowContext(ctx, "SELECT * FROM blogs WHERE id = $1", blogId)
+			row := tx.QueryRowContext(ctx, "SELECT id, author_id, title, content, description, thumbnail_image_file_name, is_public, created, modified, publish_at, slug FROM blogs WHERE id = $1", blogId)
 			var got models.Blog
 			if err := row.Scan(
 				&got.Id, &got.AuthorId, &got.Title, &got.Content, &got.Description,
 				&got.ThumbnailImageFileName, &got.IsPublic, &got.Created, &got.Modified,
+				&got.PublishAt, &got.Slug,
 			); err != nil {
 				t.Fatalf("failed to scan row: %v", err)
 			}
@@ -322,6 +323,99 @@ func Test_BlogRepository_List(t *testing.T) {
 	}
 }
 
+func Test_BlogRepository_List_PublishAt(t *testing.T) {
+	clocker := &clocker.FiexedClocker{}
+	ctx := context.Background()
+	db, err := testutil.NewDBPostgreSQLForTest(t, ctx)
+	if err != nil {
+		t.Fatalf("failed to create db: %v", err)
+	}
+	testutil.RepositoryTestPrepare(t, ctx, db)
+
+	sut := repository.NewBlogRepository(clocker)
+	sutOffset := repository.NewBlogRepositoryOffset(clocker)
+
+	now := clocker.Now()
+	testdata := []*models.Blog{
+		{AuthorId: 1, Title: "immediate", IsPublic: true, PublishAt: 0},
+		{AuthorId: 1, Title: "past", IsPublic: true, PublishAt: uint(now.Add(-time.Hour).Unix())},
+		{AuthorId: 1, Title: "just now", IsPublic: true, PublishAt: uint(now.Unix())},
+		{AuthorId: 1, Title: "scheduled", IsPublic: true, PublishAt: uint(now.Add(time.Hour).Unix())},
+		{AuthorId: 1, Title: "private", IsPublic: false, PublishAt: 0},
+	}
+
+	type args struct {
+		isPublic bool
+	}
+
+	type want struct {
+		titles []string
+	}
+
+	tests := []struct {
+		id   string
+		args args
+		want want
+	}{
+		{
+			id:   "公開日時を過ぎた公開記事のみ取得される",
+			args: args{isPublic: true},
+			want: want{titles: []string{"just now", "past", "immediate"}},
+		},
+		{
+			id:   "公開のみでない場合は予約投稿も取得される",
+			args: args{isPublic: false},
+			want: want{titles: []string{"private", "scheduled", "just now", "past", "immediate"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			tx := db.MustBegin()
+			defer tx.Rollback()
+
+			for _, b := range testdata {
+				prepareTask := `
+				INSERT INTO blogs
+					(
+						author_id, title, content, description,
+						thumbnail_image_file_name, is_public, publish_at)
+				VALUES
+					($1, $2, $3, $4, $5, $6, $7)
+				`
+				if _, err := tx.ExecContext(
+					ctx, prepareTask,
+					b.AuthorId, b.Title, "content", "description",
+					"thumbnail", b.IsPublic, b.PublishAt,
+				); err != nil {
+					t.Fatalf("failed to prepare task: %v", err)
+				}
+			}
+
+			option := &options.ListBlogOptions{IsPublic: tt.args.isPublic, Limit: 10, Page: 1}
+			blogs, err := sut.List(ctx, tx, option)
+			if err != nil {
+				t.Fatalf("failed to list blogs: %v", err)
+			}
+			got := make([]string, 0, len(blogs))
+			for _, b := range blogs {
+				got = append(got, b.Title)
+			}
+			if diff := cmp.Diff(tt.want.titles, got); diff != "" {
+				t.Errorf("differs: (-want +got)\n%s", diff)
+			}
+
+			count, err := sutOffset.CountBlogs(ctx, tx, option)
+			if err != nil {
+				t.Fatalf("failed to count blogs: %v", err)
+			}
+			if count != int64(len(tt.want.titles)) {
+				t.Errorf("count: want %d, got %d", len(tt.want.titles), count)
+			}
+		})
+	}
+}
+
 func Test_BlogRepository_Delete(t *testing.T) {
 	clocker := &clocker.FiexedClocker{}
 	ctx := context.Background()
@@ -602,7 +696,7 @@ func Test_BlogRepository_Put(t *testing.T) {
 				t.Fatalf("failed to put blog: %v", err)
 			}
 
-			selectQuery := `SELECT * FROM blogs WHERE id = $1`
+			selectQuery := `SELECT id, author_id, title, content, description, thumbnail_image_file_name, is_public, created, modified, publish_at, slug FROM blogs WHERE id = $1`
 			var got []*models.Blog
 			if err := tx.SelectContext(ctx, &got, selectQuery, blogId); err != nil {
 				t.Fatalf("failed to scan row: %v", err)
@@ -985,6 +1079,7 @@ func Test_BlogRepository_ListByKeyword(t *testing.T) {
 		prepare      func(ctx context.Context, tx infrastracture.TX) error
 		keyword      string
 		isPublicOnly bool
+		authorId     *models.UserId
 	}
 	type wants struct {
 		blogs models.Blogs
@@ -1122,6 +1217,129 @@ func Test_BlogRepository_ListByKeyword(t *testing.T) {
 				err: nil,
 			},
 		},
+		{
+			name: "本文を大文字小文字を区別せずに検索する",
+			args: args{
+				prepare: func(ctx context.Context, tx infrastracture.TX) error {
+					blog := &models.Blog{
+						AuthorId:               1,
+						Title:                  "title",
+						Content:                "Hello Golang",
+						Description:            "description",
+						ThumbnailImageFileName: "thumbnail_image_file_name",
+						IsPublic:               true,
+					}
+					// blogsにinsert
+					_, err := sut.Add(ctx, tx, blog)
+					if err != nil {
+						return fmt.Errorf("failed to Add blog: %w", err)
+					}
+					return nil
+				},
+				keyword: "GOLANG",
+			},
+			wants: wants{
+				blogs: models.Blogs{
+					{
+						AuthorId:               1,
+						Title:                  "title",
+						Description:            "description",
+						ThumbnailImageFileName: "thumbnail_image_file_name",
+						IsPublic:               true,
+					},
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "タイトルに一致する記事を上位にする",
+			args: args{
+				prepare: func(ctx context.Context, tx infrastracture.TX) error {
+					blog := &models.Blog{
+						AuthorId:               1,
+						Title:                  "golang tutorial",
+						Content:                "content",
+						Description:            "description",
+						ThumbnailImageFileName: "thumbnail_image_file_name",
+						IsPublic:               true,
+					}
+					// blogsにinsert
+					_, err := sut.Add(ctx, tx, blog)
+					if err != nil {
+						return fmt.Errorf("failed to Add blog: %w", err)
+					}
+					// 本文のみに一致するblogを後から作成
+					blog.Title = "title"
+					blog.Content = "learn golang"
+					_, err = sut.Add(ctx, tx, blog)
+					if err != nil {
+						return fmt.Errorf("failed to Add blog: %w", err)
+					}
+					return nil
+				},
+				keyword: "golang",
+			},
+			wants: wants{
+				blogs: models.Blogs{
+					{
+						AuthorId:               1,
+						Title:                  "golang tutorial",
+						Description:            "description",
+						ThumbnailImageFileName: "thumbnail_image_file_name",
+						IsPublic:               true,
+					},
+					{
+						AuthorId:               1,
+						Title:                  "title",
+						Description:            "description",
+						ThumbnailImageFileName: "thumbnail_image_file_name",
+						IsPublic:               true,
+					},
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "著者で絞り込む",
+			args: args{
+				prepare: func(ctx context.Context, tx infrastracture.TX) error {
+					blog := &models.Blog{
+						AuthorId:               1,
+						Title:                  "aaakeywordaaa",
+						Content:                "content",
+						Description:            "description",
+						ThumbnailImageFileName: "thumbnail_image_file_name",
+						IsPublic:               true,
+					}
+					// blogsにinsert
+					_, err := sut.Add(ctx, tx, blog)
+					if err != nil {
+						return fmt.Errorf("failed to Add blog: %w", err)
+					}
+					// 他の著者のblogを作成
+					blog.AuthorId = 2
+					_, err = sut.Add(ctx, tx, blog)
+					if err != nil {
+						return fmt.Errorf("failed to Add blog: %w", err)
+					}
+					return nil
+				},
+				keyword:  "keyword",
+				authorId: func() *models.UserId { id := models.UserId(2); return &id }(),
+			},
+			wants: wants{
+				blogs: models.Blogs{
+					{
+						AuthorId:               2,
+						Title:                  "aaakeywordaaa",
+						Description:            "description",
+						ThumbnailImageFileName: "thumbnail_image_file_name",
+						IsPublic:               true,
+					},
+				},
+				err: nil,
+			},
+		},
 		{
 			name: "存在しないkeywordを検索する",
 			args: args{
@@ -1174,6 +1392,7 @@ func Test_BlogRepository_ListByKeyword(t *testing.T) {
 			if err != nil {
 				t.Fatalf("failed to create list option: %v", err)
 			}
+			option.AuthorId = tt.args.authorId
 
 			blogs, err := sut.ListByKeyword(ctx, tx, tt.args.keyword, option)
 			if err != tt.wants.err {
@@ -1196,3 +1415,703 @@ func Test_BlogRepository_SelectTags(t *testing.T)                      {}
 func Test_BlogRepository_AddTag(t *testing.T)                          {}
 func Test_BlogRepository_DeleteTag(t *testing.T)                       {}
 func Test_BlogRepository_ListTags(t *testing.T)                        {}
+
+func Test_BlogRepository_GetBySlug(t *testing.T) {
+	clocker := &clocker.FiexedClocker{}
+	ctx := context.Background()
+	db, err := testutil.NewDBPostgreSQLForTest(t, ctx)
+	if err != nil {
+		t.Fatalf("failed to create db: %v", err)
+	}
+	testutil.RepositoryTestPrepare(t, ctx, db)
+
+	sut := repository.NewBlogRepository(clocker)
+
+	type args struct {
+		slug string
+	}
+
+	type want struct {
+		title         string
+		found         bool
+		historyBlogId bool
+	}
+
+	tests := []struct {
+		id   string
+		args args
+		want want
+	}{
+		{
+			id:   "現在のスラッグで取得できる",
+			args: args{slug: "current-slug"},
+			want: want{title: "title", found: true},
+		},
+		{
+			id:   "変更前のスラッグは履歴から取得できる",
+			args: args{slug: "old-slug"},
+			want: want{found: false, historyBlogId: true},
+		},
+		{
+			id:   "存在しないスラッグ",
+			args: args{slug: "not-found"},
+			want: want{found: false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			tx := db.MustBegin()
+			defer tx.Rollback()
+
+			blogId, err := sut.Add(ctx, tx, &models.Blog{
+				AuthorId: 1, Title: "title", Slug: "current-slug",
+				Content: "content", Description: "description", IsPublic: true,
+			})
+			if err != nil {
+				t.Fatalf("failed to add blog: %v", err)
+			}
+			if err := sut.AddSlugHistory(ctx, tx, blogId, "old-slug"); err != nil {
+				t.Fatalf("failed to add slug history: %v", err)
+			}
+
+			got, err := sut.GetBySlug(ctx, tx, tt.args.slug)
+			if err != nil {
+				t.Fatalf("failed to get blog by slug: %v", err)
+			}
+			if (got != nil) != tt.want.found {
+				t.Fatalf("found: want %v, got %v", tt.want.found, got != nil)
+			}
+			if got != nil && got.Title != tt.want.title {
+				t.Errorf("title: want %s, got %s", tt.want.title, got.Title)
+			}
+
+			historyBlogId, err := sut.GetBlogIdBySlugHistory(ctx, tx, tt.args.slug)
+			if err != nil {
+				t.Fatalf("failed to get blog id by slug history: %v", err)
+			}
+			if (historyBlogId == blogId) != tt.want.historyBlogId {
+				t.Errorf("history blog id: want match %v, got %d", tt.want.historyBlogId, historyBlogId)
+			}
+
+			exists, err := sut.ExistsSlug(ctx, tx, tt.args.slug, 0)
+			if err != nil {
+				t.Fatalf("failed to exists slug: %v", err)
+			}
+			if exists != (tt.want.found || tt.want.historyBlogId) {
+				t.Errorf("exists: got %v", exists)
+			}
+		})
+	}
+}
+
+func Test_BlogRepository_ExistsPublicByAuthorId(t *testing.T) {
+	clocker := &clocker.FiexedClocker{}
+	ctx := context.Background()
+	db, err := testutil.NewDBPostgreSQLForTest(t, ctx)
+	if err != nil {
+		t.Fatalf("failed to create db: %v", err)
+	}
+	testutil.RepositoryTestPrepare(t, ctx, db)
+
+	sut := repository.NewBlogRepository(clocker)
+
+	now := clocker.Now()
+
+	type args struct {
+		blogs []*models.Blog
+	}
+
+	type want struct {
+		exists bool
+	}
+
+	tests := []struct {
+		id   string
+		args args
+		want want
+	}{
+		{
+			id: "公開中のブログがある",
+			args: args{blogs: []*models.Blog{
+				{AuthorId: 1, Title: "private", IsPublic: false},
+				{AuthorId: 1, Title: "public", IsPublic: true},
+			}},
+			want: want{exists: true},
+		},
+		{
+			id: "非公開と予約投稿のみ",
+			args: args{blogs: []*models.Blog{
+				{AuthorId: 1, Title: "private", IsPublic: false},
+				{AuthorId: 1, Title: "scheduled", IsPublic: true, PublishAt: uint(now.Add(time.Hour).Unix())},
+			}},
+			want: want{exists: false},
+		},
+		{
+			id:   "ブログが無い",
+			args: args{blogs: []*models.Blog{}},
+			want: want{exists: false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			tx := db.MustBegin()
+			defer tx.Rollback()
+
+			for _, b := range tt.args.blogs {
+				prepareTask := `
+				INSERT INTO blogs
+					(
+						author_id, title, content, description,
+						thumbnail_image_file_name, is_public, publish_at)
+				VALUES
+					($1, $2, $3, $4, $5, $6, $7)
+				`
+				if _, err := tx.ExecContext(
+					ctx, prepareTask,
+					b.AuthorId, b.Title, "content", "description",
+					"thumbnail", b.IsPublic, b.PublishAt,
+				); err != nil {
+					t.Fatalf("failed to prepare task: %v", err)
+				}
+			}
+
+			got, err := sut.ExistsPublicByAuthorId(ctx, tx, 1)
+			if err != nil {
+				t.Fatalf("failed to exists public blogs: %v", err)
+			}
+			if got != tt.want.exists {
+				t.Errorf("exists: want %v, got %v", tt.want.exists, got)
+			}
+		})
+	}
+}
+
+func Test_BlogRepository_AddRevision(t *testing.T) {
+	clocker := &clocker.FiexedClocker{}
+	ctx := context.Background()
+	db, err := testutil.NewDBPostgreSQLForTest(t, ctx)
+	if err != nil {
+		t.Fatalf("failed to create db: %v", err)
+	}
+	testutil.RepositoryTestPrepare(t, ctx, db)
+
+	sut := repository.NewBlogRepository(clocker)
+
+	type args struct {
+		titles []string
+	}
+
+	type want struct {
+		revisions []*models.BlogRevision
+	}
+
+	tests := []struct {
+		id   string
+		args args
+		want want
+	}{
+		{
+			id: "リビジョン番号がブログごとに採番される",
+			args: args{
+				titles: []string{"title1", "title2", "title3"},
+			},
+			want: want{
+				revisions: []*models.BlogRevision{
+					{Revision: 3, EditorId: 1, Title: "title3", Description: "description"},
+					{Revision: 2, EditorId: 1, Title: "title2", Description: "description"},
+					{Revision: 1, EditorId: 1, Title: "title1", Description: "description"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			tx := db.MustBegin()
+			defer tx.Rollback()
+
+			prepareTask := `
+			INSERT INTO blogs
+				(
+					author_id, title, content, description,
+					thumbnail_image_file_name, is_public)
+			VALUES
+				($1, $2, $3, $4, $5, $6)
+			RETURNING
+				id
+			`
+			var blogId models.BlogId
+			if err := tx.QueryRowxContext(
+				ctx, prepareTask, 1, "title", "content", "description", "thumbnail", true,
+			).Scan(&blogId); err != nil {
+				t.Fatalf("failed to prepare task: %v", err)
+			}
+
+			for _, title := range tt.args.titles {
+				revision := models.NewBlogRevision(&models.Blog{
+					Id:          blogId,
+					Title:       title,
+					Content:     "content",
+					Description: "description",
+				}, 1)
+				if _, err := sut.AddRevision(ctx, tx, revision); err != nil {
+					t.Fatalf("failed to add revision: %v", err)
+				}
+			}
+
+			got, err := sut.ListRevisions(ctx, tx, blogId)
+			if err != nil {
+				t.Fatalf("failed to list revisions: %v", err)
+			}
+			cmpOptions := cmpopts.IgnoreFields(models.BlogRevision{}, "Id", "BlogId", "Created")
+			if diff := cmp.Diff(tt.want.revisions, got, cmpOptions); diff != "" {
+				t.Errorf("differs: (-want +got)\n%s", diff)
+			}
+
+			revision, err := sut.GetRevision(ctx, tx, blogId, 2)
+			if err != nil {
+				t.Fatalf("failed to get revision: %v", err)
+			}
+			if revision.Title != "title2" || revision.Content != "content" {
+				t.Errorf("unexpected revision: %+v", revision)
+			}
+		})
+	}
+}
+
+func Test_BlogRepository_ListRevisions_Backfill(t *testing.T) {
+	clocker := &clocker.FiexedClocker{}
+	ctx := context.Background()
+	db, err := testutil.NewDBPostgreSQLForTest(t, ctx)
+	if err != nil {
+		t.Fatalf("failed to create db: %v", err)
+	}
+	testutil.RepositoryTestPrepare(t, ctx, db)
+
+	sut := repository.NewBlogRepository(clocker)
+
+	tx := db.MustBegin()
+	defer tx.Rollback()
+
+	// リビジョンの導入前から存在するブログとして、リビジョンを追加せずに登録する
+	prepareTask := `
+	INSERT INTO blogs
+		(
+			author_id, title, content, description,
+			thumbnail_image_file_name, is_public, modified)
+	VALUES
+		($1, $2, $3, $4, NULL, $5, $6)
+	RETURNING
+		id
+	`
+	var blogId models.BlogId
+	if err := tx.QueryRowxContext(
+		ctx, prepareTask, 1, "original title", "original content", "description", true, 100,
+	).Scan(&blogId); err != nil {
+		t.Fatalf("failed to prepare task: %v", err)
+	}
+
+	// リビジョンを導入するマイグレーションを再度適用する
+	testutil.ExecMigrationUp(t, ctx, tx, "20261018100000-add-blog-revisions.sql")
+
+	got, err := sut.ListRevisions(ctx, tx, blogId)
+	if err != nil {
+		t.Fatalf("failed to list revisions: %v", err)
+	}
+	want := []*models.BlogRevision{
+		{
+			BlogId: blogId, Revision: 1, EditorId: 1,
+			Title: "original title", Description: "description", Created: 100,
+		},
+	}
+	cmpOptions := cmpopts.IgnoreFields(models.BlogRevision{}, "Id")
+	if diff := cmp.Diff(want, got, cmpOptions); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
+
+	revision, err := sut.GetRevision(ctx, tx, blogId, 1)
+	if err != nil {
+		t.Fatalf("failed to get revision: %v", err)
+	}
+	if revision.Content != "original content" {
+		t.Errorf("unexpected revision: %+v", revision)
+	}
+}
+
+func Test_BlogRepository_PutRendered(t *testing.T) {
+	clocker := &clocker.FiexedClocker{}
+	ctx := context.Background()
+	db, err := testutil.NewDBPostgreSQLForTest(t, ctx)
+	if err != nil {
+		t.Fatalf("failed to create db: %v", err)
+	}
+	testutil.RepositoryTestPrepare(t, ctx, db)
+
+	sut := repository.NewBlogRepository(clocker)
+
+	type args struct {
+		rendered *models.Blog
+	}
+
+	type want struct {
+		blog *models.Blog
+	}
+
+	tests := []struct {
+		id   string
+		args args
+		want want
+	}{
+		{
+			id: "レンダリング結果のキャッシュを更新する",
+			args: args{
+				rendered: &models.Blog{
+					ContentHTML: "<h1 id=\"title\">title</h1>",
+					Toc: models.TableOfContents{
+						{Level: 1, Text: "title", Id: "title"},
+					},
+					WordCount:   1,
+					CharCount:   5,
+					ReadingTime: 1,
+				},
+			},
+			want: want{
+				blog: &models.Blog{
+					AuthorId:               1,
+					Title:                  "title",
+					Content:                "# title",
+					Description:            "description",
+					ThumbnailImageFileName: "thumbnail",
+					IsPublic:               true,
+					ContentHTML:            "<h1 id=\"title\">title</h1>",
+					Toc: models.TableOfContents{
+						{Level: 1, Text: "title", Id: "title"},
+					},
+					WordCount:   1,
+					CharCount:   5,
+					ReadingTime: 1,
+				},
+			},
+		},
+		{
+			id: "見出しがない場合は目次がnilとなる",
+			args: args{
+				rendered: &models.Blog{},
+			},
+			want: want{
+				blog: &models.Blog{
+					AuthorId:               1,
+					Title:                  "title",
+					Content:                "# title",
+					Description:            "description",
+					ThumbnailImageFileName: "thumbnail",
+					IsPublic:               true,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			tx := db.MustBegin()
+			defer tx.Rollback()
+
+			blogId, err := sut.Add(ctx, tx, &models.Blog{
+				AuthorId:               1,
+				Title:                  "title",
+				Content:                "# title",
+				Description:            "description",
+				ThumbnailImageFileName: "thumbnail",
+				IsPublic:               true,
+			})
+			if err != nil {
+				t.Fatalf("failed to add blog: %v", err)
+			}
+
+			tt.args.rendered.Id = blogId
+			if err := sut.PutRendered(ctx, tx, tt.args.rendered); err != nil {
+				t.Fatalf("failed to put rendered: %v", err)
+			}
+
+			got, err := sut.Get(ctx, tx, blogId)
+			if err != nil {
+				t.Fatalf("failed to get blog: %v", err)
+			}
+			cmpOptions := cmpopts.IgnoreFields(models.Blog{}, "Id", "Slug", "Tags", "Created", "Modified")
+			if diff := cmp.Diff(tt.want.blog, got, cmpOptions); diff != "" {
+				t.Errorf("differs: (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_BlogRepository_ListTagsModified(t *testing.T) {
+	clocker := &clocker.FiexedClocker{}
+	ctx := context.Background()
+	db, err := testutil.NewDBPostgreSQLForTest(t, ctx)
+	if err != nil {
+		t.Fatalf("failed to create db: %v", err)
+	}
+	testutil.RepositoryTestPrepare(t, ctx, db)
+
+	sut := repository.NewBlogRepository(clocker)
+
+	type blog struct {
+		isPublic  bool
+		modified  uint
+		publishAt uint
+		tags      []string
+	}
+
+	type args struct {
+		blogs []blog
+	}
+
+	type want struct {
+		tags []*models.TagModified
+	}
+
+	tests := []struct {
+		id   string
+		args args
+		want want
+	}{
+		{
+			id: "公開中のブログの最終更新日時をタグごとに取得する",
+			args: args{
+				blogs: []blog{
+					{isPublic: true, modified: 100, tags: []string{"Go", "SQL"}},
+					{isPublic: true, modified: 200, tags: []string{"Go"}},
+					{isPublic: false, modified: 300, tags: []string{"Go", "Private"}},
+				},
+			},
+			want: want{
+				tags: []*models.TagModified{
+					{Name: "Go", Modified: 200},
+					{Name: "SQL", Modified: 100},
+				},
+			},
+		},
+		{
+			id: "予約投稿は更新日時より後の公開日時を最終更新日時とする",
+			args: args{
+				blogs: []blog{
+					{isPublic: true, modified: 100, tags: []string{"Go"}},
+					{isPublic: true, modified: 50, publishAt: 150, tags: []string{"Go"}},
+				},
+			},
+			want: want{
+				tags: []*models.TagModified{
+					{Name: "Go", Modified: 150},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			tx := db.MustBegin()
+			defer tx.Rollback()
+
+			tagIds := map[string]models.TagId{}
+			for _, b := range tt.args.blogs {
+				var blogId models.BlogId
+				if err := tx.QueryRowxContext(ctx, `
+				INSERT INTO blogs
+					(author_id, title, content, description, thumbnail_image_file_name, is_public, publish_at)
+				VALUES
+					($1, $2, $3, $4, $5, $6, $7)
+				RETURNING
+					id
+				`, 1, "title", "content", "description", "thumbnail", b.isPublic, b.publishAt,
+				).Scan(&blogId); err != nil {
+					t.Fatalf("failed to insert blog: %v", err)
+				}
+				for _, tag := range b.tags {
+					tagId, ok := tagIds[tag]
+					if !ok {
+						tagId, err = sut.AddTag(ctx, tx, tag)
+						if err != nil {
+							t.Fatalf("failed to add tag: %v", err)
+						}
+						tagIds[tag] = tagId
+					}
+					if _, err := sut.AddBlogTag(ctx, tx, blogId, tagId); err != nil {
+						t.Fatalf("failed to add blog tag: %v", err)
+					}
+				}
+				// 更新日時はトリガーで上書きされるため、トリガーを無効化して設定する
+				if _, err := tx.ExecContext(ctx, "ALTER TABLE blogs DISABLE TRIGGER update_blogs_trigger_mod"); err != nil {
+					t.Fatalf("failed to disable trigger: %v", err)
+				}
+				if _, err := tx.ExecContext(ctx, "UPDATE blogs SET modified = $1 WHERE id = $2", b.modified, blogId); err != nil {
+					t.Fatalf("failed to update modified: %v", err)
+				}
+				if _, err := tx.ExecContext(ctx, "ALTER TABLE blogs ENABLE TRIGGER update_blogs_trigger_mod"); err != nil {
+					t.Fatalf("failed to enable trigger: %v", err)
+				}
+			}
+
+			got, err := sut.ListTagsModified(ctx, tx)
+			if err != nil {
+				t.Fatalf("failed to list tags modified: %v", err)
+			}
+			if diff := cmp.Diff(tt.want.tags, got); diff != "" {
+				t.Errorf("differs: (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_BlogRepository_ListRelated(t *testing.T) {
+	clocker := &clocker.FiexedClocker{}
+	ctx := context.Background()
+	db, err := testutil.NewDBPostgreSQLForTest(t, ctx)
+	if err != nil {
+		t.Fatalf("failed to create db: %v", err)
+	}
+	testutil.RepositoryTestPrepare(t, ctx, db)
+
+	sut := repository.NewBlogRepository(clocker)
+
+	type blog struct {
+		isPublic  bool
+		publishAt uint
+		created   uint
+		tags      []string
+	}
+
+	type args struct {
+		// blogs[0]を関連ブログの取得対象とする
+		blogs []blog
+		limit int64
+	}
+
+	type want struct {
+		// blogsのインデックスを関連度の高い順に並べる
+		indexes []int
+	}
+
+	tests := []struct {
+		id   string
+		args args
+		want want
+	}{
+		{
+			id: "希少なタグを共有するブログを上位とし、同じ関連度は新しい順とする",
+			args: args{
+				blogs: []blog{
+					{isPublic: true, created: 100, tags: []string{"Go", "Rare"}},
+					{isPublic: true, created: 100, tags: []string{"Go"}},
+					{isPublic: true, created: 100, tags: []string{"Rare"}},
+					{isPublic: true, created: 200, tags: []string{"Go"}},
+				},
+				limit: 10,
+			},
+			want: want{
+				indexes: []int{2, 3, 1},
+			},
+		},
+		{
+			id: "共有するタグが多いブログを上位とする",
+			args: args{
+				blogs: []blog{
+					{isPublic: true, created: 100, tags: []string{"Go", "SQL"}},
+					{isPublic: true, created: 100, tags: []string{"Go", "SQL"}},
+					{isPublic: true, created: 200, tags: []string{"Go"}},
+				},
+				limit: 10,
+			},
+			want: want{
+				indexes: []int{1, 2},
+			},
+		},
+		{
+			id: "下書き・公開日時前・タグを共有しないブログは含まない",
+			args: args{
+				blogs: []blog{
+					{isPublic: true, created: 100, tags: []string{"Go"}},
+					{isPublic: false, created: 100, tags: []string{"Go"}},
+					{isPublic: true, publishAt: 1893456000, created: 100, tags: []string{"Go"}},
+					{isPublic: true, created: 100, tags: []string{"Go"}},
+					{isPublic: true, created: 100, tags: []string{"SQL"}},
+				},
+				limit: 10,
+			},
+			want: want{
+				indexes: []int{3},
+			},
+		},
+		{
+			id: "件数を制限する",
+			args: args{
+				blogs: []blog{
+					{isPublic: true, created: 100, tags: []string{"Go"}},
+					{isPublic: true, created: 100, tags: []string{"Go"}},
+					{isPublic: true, created: 200, tags: []string{"Go"}},
+					{isPublic: true, created: 300, tags: []string{"Go"}},
+				},
+				limit: 2,
+			},
+			want: want{
+				indexes: []int{3, 2},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			tx := db.MustBegin()
+			defer tx.Rollback()
+
+			tagIds := map[string]models.TagId{}
+			blogIds := []models.BlogId{}
+			for _, b := range tt.args.blogs {
+				var blogId models.BlogId
+				if err := tx.QueryRowxContext(ctx, `
+				INSERT INTO blogs
+					(author_id, title, content, description, thumbnail_image_file_name, is_public, publish_at, created)
+				VALUES
+					($1, $2, $3, $4, $5, $6, $7, $8)
+				RETURNING
+					id
+				`, 1, "title", "content", "description", "thumbnail", b.isPublic, b.publishAt, b.created,
+				).Scan(&blogId); err != nil {
+					t.Fatalf("failed to insert blog: %v", err)
+				}
+				blogIds = append(blogIds, blogId)
+				for _, tag := range b.tags {
+					tagId, ok := tagIds[tag]
+					if !ok {
+						tagId, err = sut.AddTag(ctx, tx, tag)
+						if err != nil {
+							t.Fatalf("failed to add tag: %v", err)
+						}
+						tagIds[tag] = tagId
+					}
+					if _, err := sut.AddBlogTag(ctx, tx, blogId, tagId); err != nil {
+						t.Fatalf("failed to add blog tag: %v", err)
+					}
+				}
+			}
+
+			got, err := sut.ListRelated(ctx, tx, blogIds[0], tt.args.limit)
+			if err != nil {
+				t.Fatalf("failed to list related blogs: %v", err)
+			}
+			wantIds := []models.BlogId{}
+			for _, i := range tt.want.indexes {
+				wantIds = append(wantIds, blogIds[i])
+			}
+			gotIds := []models.BlogId{}
+			for _, b := range got {
+				gotIds = append(gotIds, b.Id)
+			}
+			if diff := cmp.Diff(wantIds, gotIds); diff != "" {
+				t.Errorf("differs: (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/shoet/blog/internal/clocker"
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+)
+
+type CommentRepository struct {
+	Clocker clocker.Clocker
+}
+
+func NewCommentRepository(clocker clocker.Clocker) *CommentRepository {
+	return &CommentRepository{
+		Clocker: clocker,
+	}
+}
+
+var commentColumns = []interface{}{
+	"id", "blog_id", "parent_id", "author_name", "author_email", "body",
+	"status", "ip_address", "user_agent", "created", "modified",
+}
+
+func (r *CommentRepository) Add(
+	ctx context.Context, tx infrastracture.TX, comment *models.Comment,
+) (models.CommentId, error) {
+	sql, params, err := goqu.
+		Insert("comments").
+		Cols("blog_id", "parent_id", "author_name", "author_email", "body", "status", "ip_address", "user_agent").
+		Vals(goqu.Vals{
+			comment.BlogId, comment.ParentId, comment.AuthorName, comment.AuthorEmail, comment.Body,
+			comment.Status, comment.IpAddress, comment.UserAgent,
+		}).
+		Returning("id").
+		ToSQL()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build sql: %w", err)
+	}
+	var id models.CommentId
+	if err := tx.QueryRowxContext(ctx, sql, params...).Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to insert comment: %w", err)
+	}
+	return id, nil
+}
+
+// Get は、コメントを取得する
+// 存在しない場合はnilを返す
+func (r *CommentRepository) Get(
+	ctx context.Context, tx infrastracture.TX, id models.CommentId,
+) (*models.Comment, error) {
+	sql, params, err := goqu.
+		Select(commentColumns...).
+		From("comments").
+		Where(goqu.Ex{"id": id}).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql: %w", err)
+	}
+	var comments []*models.Comment
+	if err := tx.SelectContext(ctx, &comments, sql, params...); err != nil {
+		return nil, fmt.Errorf("failed to select comment: %w", err)
+	}
+	if len(comments) == 0 {
+		return nil, nil
+	}
+	return comments[0], nil
+}
+
+// ListByBlogId は、ブログに付いた指定の状態のコメントを投稿順に取得する
+func (r *CommentRepository) ListByBlogId(
+	ctx context.Context, tx infrastracture.TX, blogId models.BlogId, status models.CommentStatus,
+) ([]*models.Comment, error) {
+	sql, params, err := goqu.
+		Select(commentColumns...).
+		From("comments").
+		Where(goqu.Ex{"blog_id": blogId, "status": status}).
+		Order(goqu.I("id").Asc()).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql: %w", err)
+	}
+	comments := []*models.Comment{}
+	if err := tx.SelectContext(ctx, &comments, sql, params...); err != nil {
+		return nil, fmt.Errorf("failed to select comments: %w", err)
+	}
+	return comments, nil
+}
+
+// ListByStatus は、指定の状態のコメントを新しい順に取得する
+// 管理画面のモデレーション待ちの一覧に使用する
+func (r *CommentRepository) ListByStatus(
+	ctx context.Context, tx infrastracture.TX, status models.CommentStatus, limit int64, offset int64,
+) ([]*models.Comment, error) {
+	sql, params, err := goqu.
+		Select(commentColumns...).
+		From("comments").
+		Where(goqu.Ex{"status": status}).
+		Order(goqu.I("id").Desc()).
+		Limit(uint(limit)).
+		Offset(uint(offset)).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql: %w", err)
+	}
+	comments := []*models.Comment{}
+	if err := tx.SelectContext(ctx, &comments, sql, params...); err != nil {
+		return nil, fmt.Errorf("failed to select comments: %w", err)
+	}
+	return comments, nil
+}
+
+func (r *CommentRepository) UpdateStatus(
+	ctx context.Context, tx infrastracture.TX, id models.CommentId, status models.CommentStatus,
+) error {
+	sql, params, err := goqu.
+		Update("comments").
+		Set(goqu.Record{"status": status}).
+		Where(goqu.Ex{"id": id}).
+		ToSQL()
+	if err != nil {
+		return fmt.Errorf("failed to build sql: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, sql, params...); err != nil {
+		return fmt.Errorf("failed to update comment: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/shoet/blog/internal/clocker"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/infrastracture/repository"
+	"github.com/shoet/blog/internal/testutil"
+)
+
+func Test_CommentRepository_ListByBlogId(t *testing.T) {
+	clocker := &clocker.FiexedClocker{}
+	ctx := context.Background()
+	db, err := testutil.NewDBPostgreSQLForTest(t, ctx)
+	if err != nil {
+		t.Fatalf("failed to create db: %v", err)
+	}
+	testutil.RepositoryTestPrepare(t, ctx, db)
+
+	blogRepo := repository.NewBlogRepository(clocker)
+	sut := repository.NewCommentRepository(clocker)
+
+	type args struct {
+		comments []*models.Comment
+		status   models.CommentStatus
+	}
+
+	type want struct {
+		bodies []string
+	}
+
+	tests := []struct {
+		id   string
+		args args
+		want want
+	}{
+		{
+			id: "指定の状態のコメントを投稿順に取得する",
+			args: args{
+				comments: []*models.Comment{
+					{AuthorName: "a", Body: "first", Status: models.CommentStatusApproved},
+					{AuthorName: "b", Body: "pending", Status: models.CommentStatusPending},
+					{AuthorName: "c", Body: "second", Status: models.CommentStatusApproved},
+					{AuthorName: "d", Body: "spam", Status: models.CommentStatusSpam},
+				},
+				status: models.CommentStatusApproved,
+			},
+			want: want{
+				bodies: []string{"first", "second"},
+			},
+		},
+		{
+			id: "該当するコメントがない場合は空となる",
+			args: args{
+				comments: []*models.Comment{
+					{AuthorName: "a", Body: "pending", Status: models.CommentStatusPending},
+				},
+				status: models.CommentStatusApproved,
+			},
+			want: want{
+				bodies: []string{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			tx := db.MustBegin()
+			defer tx.Rollback()
+
+			blogId, err := blogRepo.Add(ctx, tx, &models.Blog{
+				AuthorId: 1, Title: "title", Content: "content", Description: "description", IsPublic: true,
+			})
+			if err != nil {
+				t.Fatalf("failed to add blog: %v", err)
+			}
+			for _, c := range tt.args.comments {
+				c.BlogId = blogId
+				if _, err := sut.Add(ctx, tx, c); err != nil {
+					t.Fatalf("failed to add comment: %v", err)
+				}
+			}
+
+			got, err := sut.ListByBlogId(ctx, tx, blogId, tt.args.status)
+			if err != nil {
+				t.Fatalf("failed to list comments: %v", err)
+			}
+			bodies := []string{}
+			for _, c := range got {
+				bodies = append(bodies, c.Body)
+			}
+			if diff := cmp.Diff(tt.want.bodies, bodies); diff != "" {
+				t.Errorf("differs: (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_CommentRepository_UpdateStatus(t *testing.T) {
+	clocker := &clocker.FiexedClocker{}
+	ctx := context.Background()
+	db, err := testutil.NewDBPostgreSQLForTest(t, ctx)
+	if err != nil {
+		t.Fatalf("failed to create db: %v", err)
+	}
+	testutil.RepositoryTestPrepare(t, ctx, db)
+
+	blogRepo := repository.NewBlogRepository(clocker)
+	sut := repository.NewCommentRepository(clocker)
+
+	tx := db.MustBegin()
+	defer tx.Rollback()
+
+	blogId, err := blogRepo.Add(ctx, tx, &models.Blog{
+		AuthorId: 1, Title: "title", Content: "content", Description: "description", IsPublic: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to add blog: %v", err)
+	}
+	parentId, err := sut.Add(ctx, tx, &models.Comment{
+		BlogId: blogId, AuthorName: "parent", Body: "parent", Status: models.CommentStatusApproved,
+	})
+	if err != nil {
+		t.Fatalf("failed to add comment: %v", err)
+	}
+	id, err := sut.Add(ctx, tx, &models.Comment{
+		BlogId:      blogId,
+		ParentId:    &parentId,
+		AuthorName:  "name",
+		AuthorEmail: "name@example.com",
+		Body:        "body",
+		Status:      models.CommentStatusPending,
+		IpAddress:   "127.0.0.1",
+		UserAgent:   "test",
+	})
+	if err != nil {
+		t.Fatalf("failed to add comment: %v", err)
+	}
+
+	if err := sut.UpdateStatus(ctx, tx, id, models.CommentStatusApproved); err != nil {
+		t.Fatalf("failed to update status: %v", err)
+	}
+
+	got, err := sut.Get(ctx, tx, id)
+	if err != nil {
+		t.Fatalf("failed to get comment: %v", err)
+	}
+	want := &models.Comment{
+		BlogId:      blogId,
+		ParentId:    &parentId,
+		AuthorName:  "name",
+		AuthorEmail: "name@example.com",
+		Body:        "body",
+		Status:      models.CommentStatusApproved,
+		IpAddress:   "127.0.0.1",
+		UserAgent:   "test",
+	}
+	opt := cmpopts.IgnoreFields(models.Comment{}, "Id", "Created", "Modified")
+	if diff := cmp.Diff(want, got, opt); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
+
+	notFound, err := sut.Get(ctx, tx, id+1000)
+	if err != nil {
+		t.Fatalf("failed to get comment: %v", err)
+	}
+	if notFound != nil {
+		t.Errorf("want nil, got %v", notFound)
+	}
+}
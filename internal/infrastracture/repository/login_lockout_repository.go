@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/shoet/blog/internal/clocker"
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+)
+
+type LoginLockoutRepository struct {
+	Clocker clocker.Clocker
+}
+
+func NewLoginLockoutRepository(clocker clocker.Clocker) *LoginLockoutRepository {
+	return &LoginLockoutRepository{Clocker: clocker}
+}
+
+func (r *LoginLockoutRepository) Add(
+	ctx context.Context, tx infrastracture.TX, lockout *models.LoginLockout,
+) (models.LoginLockoutId, error) {
+	sql, params, err := goqu.
+		Insert("login_lockouts").
+		Cols("target_type", "target", "failures", "locked_until", "ip_address", "user_agent").
+		Vals(goqu.Vals{
+			lockout.TargetType,
+			lockout.Target,
+			lockout.Failures,
+			lockout.LockedUntil,
+			lockout.IpAddress,
+			lockout.UserAgent,
+		}).
+		Returning("id").
+		ToSQL()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build sql: %w", err)
+	}
+	var id models.LoginLockoutId
+	if err := tx.QueryRowxContext(ctx, sql, params...).Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to insert login lockout: %w", err)
+	}
+	return id, nil
+}
+
+// ListByTarget は、メールアドレスまたはIPアドレスのロックアウトの記録を新しい順に取得する
+func (r *LoginLockoutRepository) ListByTarget(
+	ctx context.Context, tx infrastracture.TX, targetType models.LoginLockoutTargetType, target string,
+) ([]*models.LoginLockout, error) {
+	sql, params, err := goqu.
+		From("login_lockouts").
+		Select(
+			"id", "target_type", "target", "failures", "locked_until",
+			"ip_address", "user_agent", "created",
+		).
+		Where(goqu.Ex{"target_type": targetType, "target": target}).
+		Order(goqu.I("id").Desc()).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql: %w", err)
+	}
+	var lockouts []*models.LoginLockout
+	if err := tx.SelectContext(ctx, &lockouts, sql, params...); err != nil {
+		return nil, fmt.Errorf("failed to select login lockouts: %w", err)
+	}
+	return lockouts, nil
+}
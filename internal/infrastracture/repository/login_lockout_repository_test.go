@@ -0,0 +1,51 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/shoet/blog/internal/clocker"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/infrastracture/repository"
+	"github.com/shoet/blog/internal/testutil"
+)
+
+func Test_LoginLockoutRepository_ListByTarget(t *testing.T) {
+	ctx := context.Background()
+	db, err := testutil.NewDBPostgreSQLForTest(t, ctx)
+	if err != nil {
+		t.Fatalf("failed to create db: %v", err)
+	}
+	testutil.RepositoryTestPrepare(t, ctx, db)
+
+	sut := repository.NewLoginLockoutRepository(&clocker.FiexedClocker{})
+
+	tx, err := db.Beginx()
+	if err != nil {
+		t.Fatalf("failed to create tx: %v", err)
+	}
+	defer tx.Rollback()
+
+	lockouts := []*models.LoginLockout{
+		{TargetType: models.LoginLockoutTargetEmail, Target: "a@example.com", Failures: 5, LockedUntil: 100, IpAddress: "192.0.2.1"},
+		{TargetType: models.LoginLockoutTargetIpAddress, Target: "192.0.2.1", Failures: 20, LockedUntil: 100, IpAddress: "192.0.2.1"},
+		{TargetType: models.LoginLockoutTargetEmail, Target: "a@example.com", Failures: 6, LockedUntil: 200, IpAddress: "192.0.2.2"},
+	}
+	for _, l := range lockouts {
+		if _, err := sut.Add(ctx, tx, l); err != nil {
+			t.Fatalf("failed to add login lockout: %v", err)
+		}
+	}
+
+	got, err := sut.ListByTarget(ctx, tx, models.LoginLockoutTargetEmail, "a@example.com")
+	if err != nil {
+		t.Fatalf("failed to list login lockouts: %v", err)
+	}
+	want := []*models.LoginLockout{lockouts[2], lockouts[0]}
+	opt := cmpopts.IgnoreFields(models.LoginLockout{}, "Id", "Created")
+	if diff := cmp.Diff(got, want, opt); diff != "" {
+		t.Errorf("(-got +want)\n%s", diff)
+	}
+}
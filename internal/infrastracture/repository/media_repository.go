@@ -0,0 +1,315 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/shoet/blog/internal/clocker"
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+)
+
+type MediaRepository struct {
+	Clocker clocker.Clocker
+}
+
+func NewMediaRepository(clocker clocker.Clocker) *MediaRepository {
+	return &MediaRepository{
+		Clocker: clocker,
+	}
+}
+
+var mediaColumns = []interface{}{
+	"id", "media_key", "kind", "file_name", "object_url", "content_type", "size",
+	"status", "uploaded_by", "uploaded_at", "variants_processed_at", "created", "modified",
+}
+
+func (r *MediaRepository) Add(
+	ctx context.Context, tx infrastracture.TX, media *models.Media,
+) (models.MediaId, error) {
+	sql, params, err := goqu.
+		Insert("media").
+		Cols("media_key", "kind", "file_name", "object_url", "content_type", "size", "status", "uploaded_by").
+		Vals(goqu.Vals{
+			media.Key, media.Kind, media.FileName, media.ObjectURL, media.ContentType, media.Size,
+			models.MediaStatusPending, media.UploadedBy,
+		}).
+		Returning("id").
+		ToSQL()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build sql: %w", err)
+	}
+	var id models.MediaId
+	if err := tx.QueryRowxContext(ctx, sql, params...).Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to insert media: %w", err)
+	}
+	return id, nil
+}
+
+// Get は、メディアを取得する
+// 存在しない場合はnilを返す
+func (r *MediaRepository) Get(
+	ctx context.Context, tx infrastracture.TX, id models.MediaId,
+) (*models.Media, error) {
+	sql, params, err := goqu.
+		Select(mediaColumns...).
+		From("media").
+		Where(goqu.Ex{"id": id}).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql: %w", err)
+	}
+	var media []*models.Media
+	if err := tx.SelectContext(ctx, &media, sql, params...); err != nil {
+		return nil, fmt.Errorf("failed to select media: %w", err)
+	}
+	if len(media) == 0 {
+		return nil, nil
+	}
+	return media[0], nil
+}
+
+// GetByKey は、キーのメディアを取得する
+// 存在しない場合はnilを返す
+func (r *MediaRepository) GetByKey(
+	ctx context.Context, tx infrastracture.TX, key string,
+) (*models.Media, error) {
+	sql, params, err := goqu.
+		Select(mediaColumns...).
+		From("media").
+		Where(goqu.Ex{"media_key": key}).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql: %w", err)
+	}
+	var media []*models.Media
+	if err := tx.SelectContext(ctx, &media, sql, params...); err != nil {
+		return nil, fmt.Errorf("failed to select media: %w", err)
+	}
+	if len(media) == 0 {
+		return nil, nil
+	}
+	return media[0], nil
+}
+
+// MarkVariantsProcessed は、メディアのサムネイルの派生画像を生成済みとする
+func (r *MediaRepository) MarkVariantsProcessed(
+	ctx context.Context, tx infrastracture.TX, id models.MediaId,
+) error {
+	sql, params, err := goqu.
+		Update("media").
+		Set(goqu.Record{"variants_processed_at": r.Clocker.Now().Unix()}).
+		Where(goqu.Ex{"id": id}).
+		ToSQL()
+	if err != nil {
+		return fmt.Errorf("failed to build sql: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, sql, params...); err != nil {
+		return fmt.Errorf("failed to update media: %w", err)
+	}
+	return nil
+}
+
+// ListVariantsProcessedKeys は、keysのうちサムネイルの派生画像を生成済みのメディアのキーを取得する
+func (r *MediaRepository) ListVariantsProcessedKeys(
+	ctx context.Context, tx infrastracture.TX, keys []string,
+) ([]string, error) {
+	result := []string{}
+	if len(keys) == 0 {
+		return result, nil
+	}
+	sql, params, err := goqu.
+		Select("media_key").
+		From("media").
+		Where(
+			goqu.Ex{"media_key": keys},
+			goqu.C("variants_processed_at").IsNotNull(),
+		).
+		Order(goqu.I("media_key").Asc()).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql: %w", err)
+	}
+	if err := tx.SelectContext(ctx, &result, sql, params...); err != nil {
+		return nil, fmt.Errorf("failed to select media: %w", err)
+	}
+	return result, nil
+}
+
+// MarkUploaded は、メディアをアップロード済みとし、サイズを実際のオブジェクトのサイズで更新する
+func (r *MediaRepository) MarkUploaded(
+	ctx context.Context, tx infrastracture.TX, id models.MediaId, size int64,
+) error {
+	sql, params, err := goqu.
+		Update("media").
+		Set(goqu.Record{
+			"status":      models.MediaStatusUploaded,
+			"size":        size,
+			"uploaded_at": r.Clocker.Now().Unix(),
+		}).
+		Where(goqu.Ex{"id": id}).
+		ToSQL()
+	if err != nil {
+		return fmt.Errorf("failed to build sql: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, sql, params...); err != nil {
+		return fmt.Errorf("failed to update media: %w", err)
+	}
+	return nil
+}
+
+func (r *MediaRepository) filterConditions(filter *models.MediaFilter) []exp.Expression {
+	conditions := []exp.Expression{}
+	if filter.Kind != nil {
+		conditions = append(conditions, goqu.Ex{"kind": *filter.Kind})
+	}
+	if filter.Status != nil {
+		conditions = append(conditions, goqu.Ex{"status": *filter.Status})
+	}
+	if filter.UploadedBy != nil {
+		conditions = append(conditions, goqu.Ex{"uploaded_by": *filter.UploadedBy})
+	}
+	if filter.Referenced != nil {
+		referenced := goqu.
+			From("media_references").
+			Select(goqu.L("1")).
+			Where(goqu.Ex{"media_references.media_id": goqu.I("media.id")})
+		if *filter.Referenced {
+			conditions = append(conditions, goqu.L("EXISTS ?", referenced))
+		} else {
+			conditions = append(conditions, goqu.L("NOT EXISTS ?", referenced))
+		}
+	}
+	return conditions
+}
+
+// List は、条件に一致するメディアを新しい順に取得する
+func (r *MediaRepository) List(
+	ctx context.Context, tx infrastracture.TX, filter *models.MediaFilter, limit int64, offset int64,
+) ([]*models.Media, error) {
+	sql, params, err := goqu.
+		Select(mediaColumns...).
+		From("media").
+		Where(r.filterConditions(filter)...).
+		Order(goqu.I("id").Desc()).
+		Limit(uint(limit)).
+		Offset(uint(offset)).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql: %w", err)
+	}
+	media := []*models.Media{}
+	if err := tx.SelectContext(ctx, &media, sql, params...); err != nil {
+		return nil, fmt.Errorf("failed to select media: %w", err)
+	}
+	return media, nil
+}
+
+// Count は、条件に一致するメディアの件数を取得する
+func (r *MediaRepository) Count(
+	ctx context.Context, tx infrastracture.TX, filter *models.MediaFilter,
+) (int64, error) {
+	sql, params, err := goqu.
+		Select(goqu.COUNT("*").As("count")).
+		From("media").
+		Where(r.filterConditions(filter)...).
+		ToSQL()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build sql: %w", err)
+	}
+	var count int64
+	if err := tx.QueryRowxContext(ctx, sql, params...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count media: %w", err)
+	}
+	return count, nil
+}
+
+// ListBlogIds は、メディアごとに参照しているブログのIDを取得する
+func (r *MediaRepository) ListBlogIds(
+	ctx context.Context, tx infrastracture.TX, mediaIds []models.MediaId,
+) (map[models.MediaId][]models.BlogId, error) {
+	result := map[models.MediaId][]models.BlogId{}
+	if len(mediaIds) == 0 {
+		return result, nil
+	}
+	sql, params, err := goqu.
+		Select("media_id", "blog_id").
+		From("media_references").
+		Where(goqu.Ex{"media_id": mediaIds}).
+		Order(goqu.I("media_id").Asc(), goqu.I("blog_id").Asc()).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql: %w", err)
+	}
+	type Row struct {
+		MediaId models.MediaId `db:"media_id"`
+		BlogId  models.BlogId  `db:"blog_id"`
+	}
+	var rows []Row
+	if err := tx.SelectContext(ctx, &rows, sql, params...); err != nil {
+		return nil, fmt.Errorf("failed to select media references: %w", err)
+	}
+	for _, row := range rows {
+		result[row.MediaId] = append(result[row.MediaId], row.BlogId)
+	}
+	return result, nil
+}
+
+// ReplaceBlogReferences は、ブログが参照するメディアをkeysのメディアに置き換える
+// 登録されていないキーは無視する
+func (r *MediaRepository) ReplaceBlogReferences(
+	ctx context.Context, tx infrastracture.TX, blogId models.BlogId, keys []string,
+) error {
+	sql, params, err := goqu.
+		Delete("media_references").
+		Where(goqu.Ex{"blog_id": blogId}).
+		ToSQL()
+	if err != nil {
+		return fmt.Errorf("failed to build sql: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, sql, params...); err != nil {
+		return fmt.Errorf("failed to delete media references: %w", err)
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	sql, params, err = goqu.
+		Insert("media_references").
+		Cols("media_id", "blog_id").
+		FromQuery(
+			goqu.From("media").
+				Select("id", goqu.V(blogId)).
+				Where(goqu.Ex{"media_key": keys}),
+		).
+		ToSQL()
+	if err != nil {
+		return fmt.Errorf("failed to build sql: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, sql, params...); err != nil {
+		return fmt.Errorf("failed to insert media references: %w", err)
+	}
+	return nil
+}
+
+// DeleteByFileNames は、ファイル名のメディアを削除する
+// ストレージから削除した画像の記録を消すために使用する
+func (r *MediaRepository) DeleteByFileNames(
+	ctx context.Context, tx infrastracture.TX, fileNames []string,
+) error {
+	if len(fileNames) == 0 {
+		return nil
+	}
+	sql, params, err := goqu.
+		Delete("media").
+		Where(goqu.Ex{"file_name": fileNames}).
+		ToSQL()
+	if err != nil {
+		return fmt.Errorf("failed to build sql: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, sql, params...); err != nil {
+		return fmt.Errorf("failed to delete media: %w", err)
+	}
+	return nil
+}
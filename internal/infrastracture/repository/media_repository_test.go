@@ -0,0 +1,155 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/shoet/blog/internal/clocker"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/infrastracture/repository"
+	"github.com/shoet/blog/internal/testutil"
+)
+
+func Test_MediaRepository_ReplaceBlogReferences(t *testing.T) {
+	clocker := &clocker.FiexedClocker{}
+	ctx := context.Background()
+	db, err := testutil.NewDBPostgreSQLForTest(t, ctx)
+	if err != nil {
+		t.Fatalf("failed to create db: %v", err)
+	}
+	testutil.RepositoryTestPrepare(t, ctx, db)
+
+	blogRepo := repository.NewBlogRepository(clocker)
+	sut := repository.NewMediaRepository(clocker)
+
+	tx := db.MustBegin()
+	defer tx.Rollback()
+
+	blogId, err := blogRepo.Add(ctx, tx, &models.Blog{
+		AuthorId: 1, Title: "title", Content: "content", Description: "description", IsPublic: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to add blog: %v", err)
+	}
+	keys := []string{
+		"0b7e1d2c-1a2b-4c3d-8e9f-0a1b2c3d4e5f",
+		"1c8f2e3d-2b3c-4d4e-9f0a-1b2c3d4e5f60",
+		"2d9a3f4e-3c4d-4e5f-8a1b-2c3d4e5f6071",
+	}
+	ids := []models.MediaId{}
+	for _, key := range keys {
+		id, err := sut.Add(ctx, tx, &models.Media{
+			Key:         key,
+			Kind:        models.MediaKindContent,
+			FileName:    key + ".png",
+			ObjectURL:   "https://cdn.example.com/content/" + key + ".png",
+			ContentType: "image/png",
+			Size:        1024,
+		})
+		if err != nil {
+			t.Fatalf("failed to add media: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := sut.MarkUploaded(ctx, tx, ids[0], 512); err != nil {
+		t.Fatalf("failed to mark uploaded: %v", err)
+	}
+
+	if err := sut.ReplaceBlogReferences(ctx, tx, blogId, keys[:2]); err != nil {
+		t.Fatalf("failed to replace references: %v", err)
+	}
+	// 参照を置き換え、登録されていないキーは無視する
+	if err := sut.ReplaceBlogReferences(
+		ctx, tx, blogId, []string{keys[1], keys[2], "3e0b4a5f-4d5e-4f60-9b2c-3d4e5f607182"},
+	); err != nil {
+		t.Fatalf("failed to replace references: %v", err)
+	}
+
+	gotBlogIds, err := sut.ListBlogIds(ctx, tx, ids)
+	if err != nil {
+		t.Fatalf("failed to list blog ids: %v", err)
+	}
+	wantBlogIds := map[models.MediaId][]models.BlogId{
+		ids[1]: {blogId},
+		ids[2]: {blogId},
+	}
+	if diff := cmp.Diff(wantBlogIds, gotBlogIds); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
+
+	referenced := false
+	uploaded := models.MediaStatusUploaded
+	tests := []struct {
+		id     string
+		filter *models.MediaFilter
+		want   []models.MediaId
+	}{
+		{id: "条件なし", filter: &models.MediaFilter{}, want: []models.MediaId{ids[2], ids[1], ids[0]}},
+		{id: "参照されていない", filter: &models.MediaFilter{Referenced: &referenced}, want: []models.MediaId{ids[0]}},
+		{id: "アップロード済み", filter: &models.MediaFilter{Status: &uploaded}, want: []models.MediaId{ids[0]}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			media, err := sut.List(ctx, tx, tt.filter, 100, 0)
+			if err != nil {
+				t.Fatalf("failed to list media: %v", err)
+			}
+			got := []models.MediaId{}
+			for _, m := range media {
+				if m.Id >= ids[0] {
+					got = append(got, m.Id)
+				}
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("differs: (-want +got)\n%s", diff)
+			}
+		})
+	}
+
+	got, err := sut.Get(ctx, tx, ids[0])
+	if err != nil {
+		t.Fatalf("failed to get media: %v", err)
+	}
+	if got.Status != models.MediaStatusUploaded || got.Size != 512 || got.UploadedAt == nil {
+		t.Errorf("want uploaded media, but got %+v", got)
+	}
+
+	// 派生画像を生成済みのメディアのキーのみ返す
+	if err := sut.MarkVariantsProcessed(ctx, tx, ids[2]); err != nil {
+		t.Fatalf("failed to mark variants processed: %v", err)
+	}
+	processed, err := sut.ListVariantsProcessedKeys(ctx, tx, keys)
+	if err != nil {
+		t.Fatalf("failed to list variants processed keys: %v", err)
+	}
+	if diff := cmp.Diff([]string{keys[2]}, processed); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
+	byKey, err := sut.GetByKey(ctx, tx, keys[2])
+	if err != nil {
+		t.Fatalf("failed to get media by key: %v", err)
+	}
+	if byKey == nil || byKey.Id != ids[2] || byKey.VariantsProcessedAt == nil {
+		t.Errorf("want processed media %d, but got %+v", ids[2], byKey)
+	}
+
+	// メディアの削除で参照も削除される
+	if err := sut.DeleteByFileNames(ctx, tx, []string{keys[1] + ".png"}); err != nil {
+		t.Fatalf("failed to delete media: %v", err)
+	}
+	deleted, err := sut.Get(ctx, tx, ids[1])
+	if err != nil {
+		t.Fatalf("failed to get media: %v", err)
+	}
+	if deleted != nil {
+		t.Errorf("want nil, but got %+v", deleted)
+	}
+	gotBlogIds, err = sut.ListBlogIds(ctx, tx, ids)
+	if err != nil {
+		t.Fatalf("failed to list blog ids: %v", err)
+	}
+	if diff := cmp.Diff(map[models.MediaId][]models.BlogId{ids[2]: {blogId}}, gotBlogIds); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
+}
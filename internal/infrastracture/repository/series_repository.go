@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/shoet/blog/internal/clocker"
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+)
+
+type SeriesRepository struct {
+	Clocker clocker.Clocker
+}
+
+func NewSeriesRepository(clocker clocker.Clocker) *SeriesRepository {
+	return &SeriesRepository{
+		Clocker: clocker,
+	}
+}
+
+var seriesColumns = []interface{}{"id", "title", "description", "created", "modified"}
+
+func (r *SeriesRepository) Add(
+	ctx context.Context, tx infrastracture.TX, series *models.Series,
+) (models.SeriesId, error) {
+	sql, params, err := goqu.
+		Insert("series").
+		Cols("title", "description").
+		Vals(goqu.Vals{series.Title, series.Description}).
+		Returning("id").
+		ToSQL()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build sql: %w", err)
+	}
+	var id models.SeriesId
+	if err := tx.QueryRowxContext(ctx, sql, params...).Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to insert series: %w", err)
+	}
+	return id, nil
+}
+
+// Get は、シリーズを所属するブログとともに取得する
+// 存在しない場合はnilを返す
+func (r *SeriesRepository) Get(
+	ctx context.Context, tx infrastracture.TX, id models.SeriesId,
+) (*models.Series, error) {
+	sql, params, err := goqu.
+		Select(seriesColumns...).
+		From("series").
+		Where(goqu.Ex{"id": id}).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql: %w", err)
+	}
+	var series []*models.Series
+	if err := tx.SelectContext(ctx, &series, sql, params...); err != nil {
+		return nil, fmt.Errorf("failed to select series: %w", err)
+	}
+	if len(series) == 0 {
+		return nil, nil
+	}
+	parts, err := r.ListParts(ctx, tx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list parts: %w", err)
+	}
+	series[0].Parts = parts
+	return series[0], nil
+}
+
+// GetByBlogId は、ブログが所属するシリーズを取得する
+// 所属していない場合はnilを返す
+func (r *SeriesRepository) GetByBlogId(
+	ctx context.Context, tx infrastracture.TX, blogId models.BlogId,
+) (*models.Series, error) {
+	seriesId, err := r.GetSeriesIdByBlogId(ctx, tx, blogId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get series id: %w", err)
+	}
+	if seriesId == 0 {
+		return nil, nil
+	}
+	return r.Get(ctx, tx, seriesId)
+}
+
+// GetSeriesIdByBlogId は、ブログが所属するシリーズのIDを取得する
+// 所属していない場合は0を返す
+func (r *SeriesRepository) GetSeriesIdByBlogId(
+	ctx context.Context, tx infrastracture.TX, blogId models.BlogId,
+) (models.SeriesId, error) {
+	sql, params, err := goqu.
+		Select("series_id").
+		From("series_blogs").
+		Where(goqu.Ex{"blog_id": blogId}).
+		ToSQL()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build sql: %w", err)
+	}
+	var ids []models.SeriesId
+	if err := tx.SelectContext(ctx, &ids, sql, params...); err != nil {
+		return 0, fmt.Errorf("failed to select series id: %w", err)
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	return ids[0], nil
+}
+
+func (r *SeriesRepository) List(ctx context.Context, tx infrastracture.TX) ([]*models.Series, error) {
+	sql, params, err := goqu.
+		Select(seriesColumns...).
+		From("series").
+		Order(goqu.I("id").Desc()).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql: %w", err)
+	}
+	series := []*models.Series{}
+	if err := tx.SelectContext(ctx, &series, sql, params...); err != nil {
+		return nil, fmt.Errorf("failed to select series: %w", err)
+	}
+	return series, nil
+}
+
+// ListParts は、シリーズに所属するブログを順番どおりに取得する
+func (r *SeriesRepository) ListParts(
+	ctx context.Context, tx infrastracture.TX, seriesId models.SeriesId,
+) ([]*models.SeriesPart, error) {
+	sql, params, err := goqu.
+		Select(
+			goqu.I("series_blogs.blog_id"),
+			goqu.I("series_blogs.position"),
+			goqu.I("blogs.title"),
+			goqu.I("blogs.slug"),
+			goqu.I("blogs.is_public"),
+			goqu.I("blogs.publish_at"),
+		).
+		From("series_blogs").
+		Join(goqu.T("blogs"), goqu.On(goqu.Ex{"series_blogs.blog_id": goqu.I("blogs.id")})).
+		Where(goqu.Ex{"series_blogs.series_id": seriesId}).
+		Order(goqu.I("series_blogs.position").Asc()).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql: %w", err)
+	}
+	parts := []*models.SeriesPart{}
+	if err := tx.SelectContext(ctx, &parts, sql, params...); err != nil {
+		return nil, fmt.Errorf("failed to select series parts: %w", err)
+	}
+	return parts, nil
+}
+
+func (r *SeriesRepository) Put(ctx context.Context, tx infrastracture.TX, series *models.Series) error {
+	sql, params, err := goqu.
+		Update("series").
+		Set(goqu.Record{"title": series.Title, "description": series.Description}).
+		Where(goqu.Ex{"id": series.Id}).
+		ToSQL()
+	if err != nil {
+		return fmt.Errorf("failed to build sql: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, sql, params...); err != nil {
+		return fmt.Errorf("failed to update series: %w", err)
+	}
+	return nil
+}
+
+func (r *SeriesRepository) Delete(ctx context.Context, tx infrastracture.TX, id models.SeriesId) error {
+	sql, params, err := goqu.
+		Delete("series").
+		Where(goqu.Ex{"id": id}).
+		ToSQL()
+	if err != nil {
+		return fmt.Errorf("failed to build sql: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, sql, params...); err != nil {
+		return fmt.Errorf("failed to delete series: %w", err)
+	}
+	return nil
+}
+
+// SetParts は、シリーズに所属するブログを指定した順番で置き換える
+func (r *SeriesRepository) SetParts(
+	ctx context.Context, tx infrastracture.TX, seriesId models.SeriesId, blogIds []models.BlogId,
+) error {
+	sql, params, err := goqu.
+		Delete("series_blogs").
+		Where(goqu.Ex{"series_id": seriesId}).
+		ToSQL()
+	if err != nil {
+		return fmt.Errorf("failed to build sql: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, sql, params...); err != nil {
+		return fmt.Errorf("failed to delete series parts: %w", err)
+	}
+	if len(blogIds) == 0 {
+		return nil
+	}
+
+	rows := make([]interface{}, 0, len(blogIds))
+	for i, blogId := range blogIds {
+		rows = append(rows, goqu.Record{"series_id": seriesId, "blog_id": blogId, "position": i + 1})
+	}
+	sql, params, err = goqu.
+		Insert("series_blogs").
+		Rows(rows...).
+		ToSQL()
+	if err != nil {
+		return fmt.Errorf("failed to build sql: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, sql, params...); err != nil {
+		return fmt.Errorf("failed to insert series parts: %w", err)
+	}
+	return nil
+}
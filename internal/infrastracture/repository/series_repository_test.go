@@ -0,0 +1,128 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/shoet/blog/internal/clocker"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/infrastracture/repository"
+	"github.com/shoet/blog/internal/testutil"
+)
+
+func Test_SeriesRepository_SetParts(t *testing.T) {
+	clocker := &clocker.FiexedClocker{}
+	ctx := context.Background()
+	db, err := testutil.NewDBPostgreSQLForTest(t, ctx)
+	if err != nil {
+		t.Fatalf("failed to create db: %v", err)
+	}
+	testutil.RepositoryTestPrepare(t, ctx, db)
+
+	blogRepo := repository.NewBlogRepository(clocker)
+	sut := repository.NewSeriesRepository(clocker)
+
+	type args struct {
+		// blogsのインデックスで指定する
+		first  []int
+		second []int
+	}
+
+	type want struct {
+		titles []string
+	}
+
+	tests := []struct {
+		id   string
+		args args
+		want want
+	}{
+		{
+			id: "指定した順番で所属するブログを取得する",
+			args: args{
+				first: []int{2, 0, 1},
+			},
+			want: want{
+				titles: []string{"part3", "part1", "part2"},
+			},
+		},
+		{
+			id: "所属するブログを置き換えて並べ替える",
+			args: args{
+				first:  []int{0, 1, 2},
+				second: []int{1, 0},
+			},
+			want: want{
+				titles: []string{"part2", "part1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			tx := db.MustBegin()
+			defer tx.Rollback()
+
+			blogs := []models.BlogId{}
+			for _, title := range []string{"part1", "part2", "part3"} {
+				blogId, err := blogRepo.Add(ctx, tx, &models.Blog{
+					AuthorId: 1, Title: title, Content: "content", Description: "description", IsPublic: true,
+				})
+				if err != nil {
+					t.Fatalf("failed to add blog: %v", err)
+				}
+				blogs = append(blogs, blogId)
+			}
+			seriesId, err := sut.Add(ctx, tx, &models.Series{Title: "series"})
+			if err != nil {
+				t.Fatalf("failed to add series: %v", err)
+			}
+
+			for _, indexes := range [][]int{tt.args.first, tt.args.second} {
+				if indexes == nil {
+					continue
+				}
+				blogIds := []models.BlogId{}
+				for _, i := range indexes {
+					blogIds = append(blogIds, blogs[i])
+				}
+				if err := sut.SetParts(ctx, tx, seriesId, blogIds); err != nil {
+					t.Fatalf("failed to set parts: %v", err)
+				}
+			}
+
+			got, err := sut.Get(ctx, tx, seriesId)
+			if err != nil {
+				t.Fatalf("failed to get series: %v", err)
+			}
+			titles := []string{}
+			for i, p := range got.Parts {
+				if p.Position != i+1 {
+					t.Errorf("position differs: want %d, got %d", i+1, p.Position)
+				}
+				titles = append(titles, p.Title)
+			}
+			if diff := cmp.Diff(tt.want.titles, titles); diff != "" {
+				t.Errorf("differs: (-want +got)\n%s", diff)
+			}
+
+			// シリーズから外れたブログは所属なしとなる
+			for i, blogId := range blogs {
+				belongs, err := sut.GetSeriesIdByBlogId(ctx, tx, blogId)
+				if err != nil {
+					t.Fatalf("failed to get series id: %v", err)
+				}
+				want := models.SeriesId(0)
+				for _, title := range tt.want.titles {
+					if title == []string{"part1", "part2", "part3"}[i] {
+						want = seriesId
+					}
+				}
+				if belongs != want {
+					t.Errorf("series id differs: want %d, got %d", want, belongs)
+				}
+			}
+		})
+	}
+}
@@ -24,7 +24,8 @@ func (t *UserRepository) Get(
 	sql, params, err := goqu.
 		From("users").
 		Select(
-			"id", "name", "created", "modified",
+			"id", "name", "email", "role", "totp_secret", "totp_enabled", "disabled_at", "created", "modified",
+			"display_name", "bio", "avatar_url", "social_links",
 		).
 		Where(goqu.Ex{"id": id}).
 		ToSQL()
@@ -36,7 +37,7 @@ func (t *UserRepository) Get(
 		return nil, fmt.Errorf("failed to select users: %w", err)
 	}
 	if len(users) == 0 {
-		return nil, fmt.Errorf("user not found")
+		return nil, ErrUserNotFound
 	}
 	return users[0], nil
 }
@@ -49,7 +50,8 @@ func (u *UserRepository) GetByEmail(
 	sql, params, err := goqu.
 		From("users").
 		Select(
-			"id", "name", "email", "password", "created", "modified",
+			"id", "name", "email", "password", "role", "totp_secret", "totp_enabled", "disabled_at",
+			"created", "modified", "display_name", "bio", "avatar_url", "social_links",
 		).
 		Where(goqu.Ex{"email": email}).
 		ToSQL()
@@ -69,10 +71,14 @@ func (u *UserRepository) GetByEmail(
 func (u *UserRepository) Add(
 	ctx context.Context, tx infrastracture.TX, user *models.User,
 ) (*models.User, error) {
+	// 権限が指定されていない場合は閲覧のみ可能なユーザーとして登録する
+	if user.Role == "" {
+		user.Role = models.RoleViewer
+	}
 	sql, params, err := goqu.
 		Insert("users").
-		Cols("name", "email", "password").
-		Vals(goqu.Vals{user.Name, user.Email, user.Password}).
+		Cols("name", "email", "password", "role").
+		Vals(goqu.Vals{user.Name, user.Email, user.Password, user.Role}).
 		Returning("id").
 		ToSQL()
 	if err != nil {
@@ -89,3 +95,325 @@ func (u *UserRepository) Add(
 	user.Id = userId
 	return user, nil
 }
+
+// PutTOTP は、2段階認証の共有鍵と有効化の状態を更新する
+func (u *UserRepository) PutTOTP(
+	ctx context.Context, tx infrastracture.TX, id models.UserId, secret string, enabled bool,
+) error {
+	sql, params, err := goqu.
+		Update("users").
+		Set(goqu.Record{"totp_secret": secret, "totp_enabled": enabled}).
+		Where(goqu.Ex{"id": id}).
+		ToSQL()
+	if err != nil {
+		return fmt.Errorf("failed to build sql: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, sql, params...); err != nil {
+		return fmt.Errorf("failed to update user totp: %w", err)
+	}
+	return nil
+}
+
+// ReplaceRecoveryCodes は、ユーザーのリカバリーコードを削除し、新しいコードのハッシュを登録する
+func (u *UserRepository) ReplaceRecoveryCodes(
+	ctx context.Context, tx infrastracture.TX, userId models.UserId, codeHashes []string,
+) error {
+	sql, params, err := goqu.
+		Delete("user_recovery_codes").
+		Where(goqu.Ex{"user_id": userId}).
+		ToSQL()
+	if err != nil {
+		return fmt.Errorf("failed to build sql: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, sql, params...); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+	if len(codeHashes) == 0 {
+		return nil
+	}
+	rows := make([]interface{}, 0, len(codeHashes))
+	for _, h := range codeHashes {
+		rows = append(rows, goqu.Record{"user_id": userId, "code_hash": h})
+	}
+	sql, params, err = goqu.
+		Insert("user_recovery_codes").
+		Rows(rows...).
+		ToSQL()
+	if err != nil {
+		return fmt.Errorf("failed to build sql: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, sql, params...); err != nil {
+		return fmt.Errorf("failed to insert recovery codes: %w", err)
+	}
+	return nil
+}
+
+// ListUnusedRecoveryCodes は、ユーザーの未使用のリカバリーコードを取得する
+func (u *UserRepository) ListUnusedRecoveryCodes(
+	ctx context.Context, tx infrastracture.TX, userId models.UserId,
+) ([]*models.RecoveryCode, error) {
+	sql, params, err := goqu.
+		From("user_recovery_codes").
+		Select("id", "user_id", "code_hash", "used_at", "created").
+		Where(goqu.Ex{"user_id": userId, "used_at": nil}).
+		Order(goqu.I("id").Asc()).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql: %w", err)
+	}
+	var codes []*models.RecoveryCode
+	if err := tx.SelectContext(ctx, &codes, sql, params...); err != nil {
+		return nil, fmt.Errorf("failed to select recovery codes: %w", err)
+	}
+	return codes, nil
+}
+
+// UseRecoveryCode は、リカバリーコードを使用済みにする
+// 既に使用済みの場合はfalseを返す
+func (u *UserRepository) UseRecoveryCode(
+	ctx context.Context, tx infrastracture.TX, id models.RecoveryCodeId,
+) (bool, error) {
+	sql, params, err := goqu.
+		Update("user_recovery_codes").
+		Set(goqu.Record{"used_at": u.Clocker.Now().Unix()}).
+		Where(goqu.Ex{"id": id, "used_at": nil}).
+		ToSQL()
+	if err != nil {
+		return false, fmt.Errorf("failed to build sql: %w", err)
+	}
+	result, err := tx.ExecContext(ctx, sql, params...)
+	if err != nil {
+		return false, fmt.Errorf("failed to update recovery code: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return affected > 0, nil
+}
+
+// UseTOTPStep は、認証コードを受け付けたTOTPのタイムステップを記録する
+// 記録済みのステップ以前の場合は記録せずfalseを返す
+func (u *UserRepository) UseTOTPStep(
+	ctx context.Context, tx infrastracture.TX, id models.UserId, step int64,
+) (bool, error) {
+	sql, params, err := goqu.
+		Update("users").
+		Set(goqu.Record{"totp_last_step": step}).
+		Where(
+			goqu.Ex{"id": id},
+			goqu.Or(
+				goqu.C("totp_last_step").IsNull(),
+				goqu.C("totp_last_step").Lt(step),
+			),
+		).
+		ToSQL()
+	if err != nil {
+		return false, fmt.Errorf("failed to build sql: %w", err)
+	}
+	result, err := tx.ExecContext(ctx, sql, params...)
+	if err != nil {
+		return false, fmt.Errorf("failed to update totp last step: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return affected > 0, nil
+}
+
+// GetPasswordHash は、ユーザーのパスワードのハッシュを取得する
+func (u *UserRepository) GetPasswordHash(
+	ctx context.Context, tx infrastracture.TX, id models.UserId,
+) (string, error) {
+	sql, params, err := goqu.
+		From("users").
+		Select("password").
+		Where(goqu.Ex{"id": id}).
+		ToSQL()
+	if err != nil {
+		return "", fmt.Errorf("failed to build sql: %w", err)
+	}
+	var passwords []string
+	if err := tx.SelectContext(ctx, &passwords, sql, params...); err != nil {
+		return "", fmt.Errorf("failed to select users: %w", err)
+	}
+	if len(passwords) == 0 {
+		return "", ErrUserNotFound
+	}
+	return passwords[0], nil
+}
+
+// PutPassword は、ハッシュ化済みのパスワードを更新する
+func (u *UserRepository) PutPassword(
+	ctx context.Context, tx infrastracture.TX, id models.UserId, hashedPassword string,
+) error {
+	sql, params, err := goqu.
+		Update("users").
+		Set(goqu.Record{"password": hashedPassword}).
+		Where(goqu.Ex{"id": id}).
+		ToSQL()
+	if err != nil {
+		return fmt.Errorf("failed to build sql: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, sql, params...); err != nil {
+		return fmt.Errorf("failed to update user password: %w", err)
+	}
+	return nil
+}
+
+// List は、全てのユーザーを登録順に取得する
+// パスワードと2段階認証の共有鍵は取得しない
+func (u *UserRepository) List(
+	ctx context.Context, tx infrastracture.TX,
+) ([]*models.User, error) {
+	sql, params, err := goqu.
+		From("users").
+		Select(
+			"id", "name", "email", "role", "totp_enabled", "disabled_at", "created", "modified",
+			"display_name", "bio", "avatar_url", "social_links",
+		).
+		Order(goqu.I("id").Asc()).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql: %w", err)
+	}
+	var users []*models.User
+	if err := tx.SelectContext(ctx, &users, sql, params...); err != nil {
+		return nil, fmt.Errorf("failed to select users: %w", err)
+	}
+	return users, nil
+}
+
+// Put は、ユーザーの名前・メールアドレス・権限・無効化の日時を更新する
+func (u *UserRepository) Put(
+	ctx context.Context, tx infrastracture.TX, user *models.User,
+) error {
+	sql, params, err := goqu.
+		Update("users").
+		Set(goqu.Record{
+			"name":        user.Name,
+			"email":       user.Email,
+			"role":        user.Role,
+			"disabled_at": user.DisabledAt,
+		}).
+		Where(goqu.Ex{"id": user.Id}).
+		ToSQL()
+	if err != nil {
+		return fmt.Errorf("failed to build sql: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, sql, params...); err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+	return nil
+}
+
+func (u *UserRepository) Delete(
+	ctx context.Context, tx infrastracture.TX, id models.UserId,
+) error {
+	sql, params, err := goqu.
+		Delete("users").
+		Where(goqu.Ex{"id": id}).
+		ToSQL()
+	if err != nil {
+		return fmt.Errorf("failed to build sql: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, sql, params...); err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	return nil
+}
+
+// CountActiveAdmins は、無効化されていない管理者の数を返す
+func (u *UserRepository) CountActiveAdmins(
+	ctx context.Context, tx infrastracture.TX,
+) (int64, error) {
+	sql, params, err := goqu.
+		From("users").
+		Select(goqu.COUNT("id")).
+		Where(goqu.Ex{"role": models.RoleAdmin, "disabled_at": nil}).
+		ToSQL()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build sql: %w", err)
+	}
+	var count int64
+	if err := tx.QueryRowxContext(ctx, sql, params...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count users: %w", err)
+	}
+	return count, nil
+}
+
+// ListAuthors は、ユーザーの公開用のプロフィールをまとめて取得する
+// 存在しないユーザーは結果に含めない
+func (u *UserRepository) ListAuthors(
+	ctx context.Context, tx infrastracture.TX, ids []models.UserId,
+) ([]*models.Author, error) {
+	if len(ids) == 0 {
+		return []*models.Author{}, nil
+	}
+	sql, params, err := goqu.
+		From("users").
+		Select(
+			"id",
+			goqu.L("COALESCE(NULLIF(display_name, ''), name)").As("display_name"),
+			"bio", "avatar_url", "social_links",
+		).
+		Where(goqu.Ex{"id": ids}).
+		Order(goqu.I("id").Asc()).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql: %w", err)
+	}
+	var authors []*models.Author
+	if err := tx.SelectContext(ctx, &authors, sql, params...); err != nil {
+		return nil, fmt.Errorf("failed to select users: %w", err)
+	}
+	return authors, nil
+}
+
+// ListAvatarURLs は、画像の参照の判定のために設定済みのアバターのURLを取得する
+func (u *UserRepository) ListAvatarURLs(
+	ctx context.Context, tx infrastracture.TX,
+) ([]string, error) {
+	sql, params, err := goqu.
+		From("users").
+		Select("avatar_url").
+		Where(goqu.Ex{"avatar_url": goqu.Op{"neq": ""}}).
+		Order(goqu.I("id").Asc()).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql: %w", err)
+	}
+	urls := []string{}
+	if err := tx.SelectContext(ctx, &urls, sql, params...); err != nil {
+		return nil, fmt.Errorf("failed to select users: %w", err)
+	}
+	return urls, nil
+}
+
+// PutProfile は、ユーザーの公開用のプロフィールを更新する
+func (u *UserRepository) PutProfile(
+	ctx context.Context, tx infrastracture.TX, id models.UserId, profile *models.Profile,
+) error {
+	socialLinks, err := profile.SocialLinks.Value()
+	if err != nil {
+		return fmt.Errorf("failed to convert social links: %w", err)
+	}
+	sql, params, err := goqu.
+		Update("users").
+		Set(goqu.Record{
+			"display_name": profile.DisplayName,
+			"bio":          profile.Bio,
+			"avatar_url":   profile.AvatarURL,
+			"social_links": socialLinks,
+		}).
+		Where(goqu.Ex{"id": id}).
+		ToSQL()
+	if err != nil {
+		return fmt.Errorf("failed to build sql: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, sql, params...); err != nil {
+		return fmt.Errorf("failed to update user profile: %w", err)
+	}
+	return nil
+}
@@ -35,7 +35,9 @@ func Test_UserRepository_Get(t *testing.T) {
 					Password: "test",
 				}},
 			want: &models.User{
-				Name: "test",
+				Name:  "test",
+				Email: "test@test.com",
+				Role:  models.RoleViewer,
 			},
 			wantErr: nil,
 		},
@@ -133,6 +135,7 @@ func Test_UserRepository_GetByEmail(t *testing.T) {
 					Name:     "test",
 					Email:    "test@test.com",
 					Password: "test",
+					Role:     models.RoleViewer,
 				},
 				error: nil,
 			},
@@ -237,6 +240,26 @@ func Test_UserRepository_Add(t *testing.T) {
 					Name:     "test",
 					Email:    "test@test.com",
 					Password: "test",
+					Role:     models.RoleViewer,
+				},
+			},
+		},
+		{
+			name: "with role",
+			args: args{
+				user: &models.User{
+					Name:     "editor",
+					Email:    "editor@test.com",
+					Password: "test",
+					Role:     models.RoleEditor,
+				},
+			},
+			want: want{
+				user: &models.User{
+					Name:     "editor",
+					Email:    "editor@test.com",
+					Password: "test",
+					Role:     models.RoleEditor,
 				},
 			},
 		},
@@ -282,3 +305,242 @@ func Test_UserRepository_Add(t *testing.T) {
 		})
 	}
 }
+
+func Test_UserRepository_RecoveryCodes(t *testing.T) {
+	ctx := context.Background()
+	sut, err := repository.NewUserRepository(&clocker.FiexedClocker{})
+	if err != nil {
+		t.Fatalf("failed to create user repository: %v", err)
+	}
+
+	db, err := testutil.NewDBPostgreSQLForTest(t, ctx)
+	if err != nil {
+		t.Fatalf("failed to create db: %v", err)
+	}
+	testutil.RepositoryTestPrepare(t, ctx, db)
+
+	tx, err := db.Beginx()
+	if err != nil {
+		t.Fatalf("failed to create tx: %v", err)
+	}
+	defer tx.Rollback()
+
+	user, err := sut.Add(ctx, tx, &models.User{Name: "test", Email: "totp@test.com", Password: "test"})
+	if err != nil {
+		t.Fatalf("failed to add user: %v", err)
+	}
+	if err := sut.PutTOTP(ctx, tx, user.Id, "SECRET", true); err != nil {
+		t.Fatalf("failed to put totp: %v", err)
+	}
+	got, err := sut.Get(ctx, tx, user.Id)
+	if err != nil {
+		t.Fatalf("failed to get user: %v", err)
+	}
+	if got.TOTPSecret != "SECRET" || !got.TOTPEnabled {
+		t.Errorf("want totp enabled with secret, but got %+v", got)
+	}
+
+	// 記録済みのステップ以前の認証コードは受け付けない
+	for _, c := range []struct {
+		step int64
+		want bool
+	}{
+		{step: 100, want: true},
+		{step: 100, want: false},
+		{step: 99, want: false},
+		{step: 101, want: true},
+	} {
+		used, err := sut.UseTOTPStep(ctx, tx, user.Id, c.step)
+		if err != nil {
+			t.Fatalf("failed to use totp step: %v", err)
+		}
+		if used != c.want {
+			t.Errorf("step %d: want %v, but got %v", c.step, c.want, used)
+		}
+	}
+
+	if err := sut.ReplaceRecoveryCodes(ctx, tx, user.Id, []string{"old"}); err != nil {
+		t.Fatalf("failed to replace recovery codes: %v", err)
+	}
+	if err := sut.ReplaceRecoveryCodes(ctx, tx, user.Id, []string{"hash1", "hash2"}); err != nil {
+		t.Fatalf("failed to replace recovery codes: %v", err)
+	}
+	codes, err := sut.ListUnusedRecoveryCodes(ctx, tx, user.Id)
+	if err != nil {
+		t.Fatalf("failed to list recovery codes: %v", err)
+	}
+	hashes := []string{}
+	for _, c := range codes {
+		hashes = append(hashes, c.CodeHash)
+	}
+	if diff := cmp.Diff(hashes, []string{"hash1", "hash2"}); diff != "" {
+		t.Errorf("(-got +want)\n%s", diff)
+	}
+
+	used, err := sut.UseRecoveryCode(ctx, tx, codes[0].Id)
+	if err != nil {
+		t.Fatalf("failed to use recovery code: %v", err)
+	}
+	if !used {
+		t.Errorf("want used, but not used")
+	}
+	// 使用済みのコードは再度使用できない
+	used, err = sut.UseRecoveryCode(ctx, tx, codes[0].Id)
+	if err != nil {
+		t.Fatalf("failed to use recovery code: %v", err)
+	}
+	if used {
+		t.Errorf("want not used for already used code")
+	}
+	codes, err = sut.ListUnusedRecoveryCodes(ctx, tx, user.Id)
+	if err != nil {
+		t.Fatalf("failed to list recovery codes: %v", err)
+	}
+	if len(codes) != 1 || codes[0].CodeHash != "hash2" {
+		t.Errorf("want only hash2 unused, but got %+v", codes)
+	}
+}
+
+func Test_UserRepository_PutAndDelete(t *testing.T) {
+	ctx := context.Background()
+	sut, err := repository.NewUserRepository(&clocker.FiexedClocker{})
+	if err != nil {
+		t.Fatalf("failed to create user repository: %v", err)
+	}
+
+	db, err := testutil.NewDBPostgreSQLForTest(t, ctx)
+	if err != nil {
+		t.Fatalf("failed to create db: %v", err)
+	}
+	testutil.RepositoryTestPrepare(t, ctx, db)
+
+	tx, err := db.Beginx()
+	if err != nil {
+		t.Fatalf("failed to create tx: %v", err)
+	}
+	defer tx.Rollback()
+
+	before, err := sut.CountActiveAdmins(ctx, tx)
+	if err != nil {
+		t.Fatalf("failed to count admins: %v", err)
+	}
+	user, err := sut.Add(ctx, tx, &models.User{
+		Name: "admin", Email: "admin-put@test.com", Password: "test", Role: models.RoleAdmin,
+	})
+	if err != nil {
+		t.Fatalf("failed to add user: %v", err)
+	}
+	after, err := sut.CountActiveAdmins(ctx, tx)
+	if err != nil {
+		t.Fatalf("failed to count admins: %v", err)
+	}
+	if after != before+1 {
+		t.Errorf("want %d admins, but got %d", before+1, after)
+	}
+
+	// 無効化した管理者は有効な管理者として数えない
+	disabledAt := uint(1700000000)
+	user.Name = "editor"
+	user.Email = "editor-put@test.com"
+	user.Role = models.RoleEditor
+	user.DisabledAt = &disabledAt
+	if err := sut.Put(ctx, tx, user); err != nil {
+		t.Fatalf("failed to put user: %v", err)
+	}
+	got, err := sut.Get(ctx, tx, user.Id)
+	if err != nil {
+		t.Fatalf("failed to get user: %v", err)
+	}
+	want := &models.User{
+		Name:       "editor",
+		Email:      "editor-put@test.com",
+		Role:       models.RoleEditor,
+		DisabledAt: &disabledAt,
+	}
+	opt := cmpopts.IgnoreFields(models.User{}, "Id", "Created", "Modified")
+	if diff := cmp.Diff(got, want, opt); diff != "" {
+		t.Errorf("(-got +want)\n%s", diff)
+	}
+	count, err := sut.CountActiveAdmins(ctx, tx)
+	if err != nil {
+		t.Fatalf("failed to count admins: %v", err)
+	}
+	if count != before {
+		t.Errorf("want %d admins, but got %d", before, count)
+	}
+
+	users, err := sut.List(ctx, tx)
+	if err != nil {
+		t.Fatalf("failed to list users: %v", err)
+	}
+	if len(users) == 0 || users[len(users)-1].Id != user.Id || users[len(users)-1].Password != "" {
+		t.Errorf("want added user last without password, but got %+v", users)
+	}
+
+	if err := sut.Delete(ctx, tx, user.Id); err != nil {
+		t.Fatalf("failed to delete user: %v", err)
+	}
+	if _, err := sut.Get(ctx, tx, user.Id); !errors.Is(err, repository.ErrUserNotFound) {
+		t.Errorf("want %v, but got %v", repository.ErrUserNotFound, err)
+	}
+}
+
+func Test_UserRepository_Profile(t *testing.T) {
+	ctx := context.Background()
+	sut, err := repository.NewUserRepository(&clocker.FiexedClocker{})
+	if err != nil {
+		t.Fatalf("failed to create user repository: %v", err)
+	}
+
+	db, err := testutil.NewDBPostgreSQLForTest(t, ctx)
+	if err != nil {
+		t.Fatalf("failed to create db: %v", err)
+	}
+	testutil.RepositoryTestPrepare(t, ctx, db)
+
+	tx, err := db.Beginx()
+	if err != nil {
+		t.Fatalf("failed to create tx: %v", err)
+	}
+	defer tx.Rollback()
+
+	withProfile, err := sut.Add(ctx, tx, &models.User{Name: "user1", Email: "profile1@test.com", Password: "test"})
+	if err != nil {
+		t.Fatalf("failed to add user: %v", err)
+	}
+	withoutProfile, err := sut.Add(ctx, tx, &models.User{Name: "user2", Email: "profile2@test.com", Password: "test"})
+	if err != nil {
+		t.Fatalf("failed to add user: %v", err)
+	}
+
+	profile := &models.Profile{
+		DisplayName: "User One",
+		Bio:         "hello",
+		AvatarURL:   "https://example.com/avatar.png",
+		SocialLinks: models.SocialLinks{{Service: "github", URL: "https://github.com/user1"}},
+	}
+	if err := sut.PutProfile(ctx, tx, withProfile.Id, profile); err != nil {
+		t.Fatalf("failed to put profile: %v", err)
+	}
+
+	authors, err := sut.ListAuthors(ctx, tx, []models.UserId{withProfile.Id, withoutProfile.Id})
+	if err != nil {
+		t.Fatalf("failed to list authors: %v", err)
+	}
+	// 表示名が設定されていない場合はユーザー名を表示名とする
+	want := []*models.Author{
+		{Id: withProfile.Id, Profile: *profile},
+		{Id: withoutProfile.Id, Profile: models.Profile{DisplayName: "user2"}},
+	}
+	if diff := cmp.Diff(authors, want); diff != "" {
+		t.Errorf("(-got +want)\n%s", diff)
+	}
+
+	empty, err := sut.ListAuthors(ctx, tx, []models.UserId{})
+	if err != nil {
+		t.Fatalf("failed to list authors: %v", err)
+	}
+	if len(empty) != 0 {
+		t.Errorf("want no authors, but got %d", len(empty))
+	}
+}
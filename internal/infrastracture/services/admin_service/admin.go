@@ -43,6 +43,7 @@ func (a *AdminService) SeedAdminUser(
 		Email:    cfg.AdminEmail,
 		Password: string(passwordHashed),
 		Name:     cfg.AdminName,
+		Role:     models.RoleAdmin,
 	}
 	u, err := a.user.Add(ctx, a.db, user)
 	if err != nil {
@@ -2,11 +2,17 @@ package auth_service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/shoet/blog/internal/apikey"
+	"github.com/shoet/blog/internal/clocker"
 	"github.com/shoet/blog/internal/infrastracture"
 	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/infrastracture/services/jwt_service"
+	"github.com/shoet/blog/internal/totp"
+	"github.com/shoet/blog/internal/util"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -14,53 +20,179 @@ type UserRepository interface {
 	Add(ctx context.Context, tx infrastracture.TX, user *models.User) (*models.User, error)
 	Get(ctx context.Context, tx infrastracture.TX, id models.UserId) (*models.User, error)
 	GetByEmail(ctx context.Context, tx infrastracture.TX, email string) (*models.User, error)
+	ListUnusedRecoveryCodes(ctx context.Context, tx infrastracture.TX, userId models.UserId) ([]*models.RecoveryCode, error)
+	UseRecoveryCode(ctx context.Context, tx infrastracture.TX, id models.RecoveryCodeId) (bool, error)
+	UseTOTPStep(ctx context.Context, tx infrastracture.TX, id models.UserId, step int64) (bool, error)
+}
+
+type APIKeyRepository interface {
+	GetByPrefix(ctx context.Context, tx infrastracture.TX, prefix string) (*models.APIKey, error)
+	TouchLastUsed(ctx context.Context, tx infrastracture.TX, id models.APIKeyId) error
 }
 
 type JWTer interface {
 	GenerateToken(ctx context.Context, u *models.User) (string, error)
 	VerifyToken(ctx context.Context, token string) (models.UserId, error)
+	GenerateTokenPair(ctx context.Context, u *models.User) (*models.AuthToken, error)
+	RefreshToken(ctx context.Context, refreshToken string) (*models.AuthToken, error)
+	RevokeToken(ctx context.Context, token string) error
+	RevokeAllTokens(ctx context.Context, userId models.UserId) error
+	SessionId(token string) (string, error)
+	ListSessions(ctx context.Context, userId models.UserId) ([]*models.Session, error)
+	RevokeSession(ctx context.Context, userId models.UserId, sessionId string) error
+	GenerateChallengeToken(ctx context.Context, userId models.UserId, email string) (string, error)
+	ChallengeEmail(ctx context.Context, token string) (string, error)
+	UseChallengeToken(ctx context.Context, token string) (models.UserId, error)
+	DeleteChallengeToken(ctx context.Context, token string) error
 }
 
+var ErrTwoFactorCodeInvalid = errors.New("two factor code is invalid")
+var ErrAPIKeyInvalid = errors.New("api key is invalid")
+var ErrUserDisabled = errors.New("user is disabled")
+
 type AuthService struct {
-	db    *sqlx.DB
-	user  UserRepository
-	jwter JWTer
+	db      *sqlx.DB
+	user    UserRepository
+	apiKey  APIKeyRepository
+	jwter   JWTer
+	clocker clocker.Clocker
 }
 
 func NewAuthService(
-	db *sqlx.DB, user UserRepository, jwter JWTer,
+	db *sqlx.DB, user UserRepository, apiKey APIKeyRepository, jwter JWTer, clocker clocker.Clocker,
 ) (*AuthService, error) {
 	return &AuthService{
-		db:    db,
-		user:  user,
-		jwter: jwter,
+		db:      db,
+		user:    user,
+		apiKey:  apiKey,
+		jwter:   jwter,
+		clocker: clocker,
 	}, nil
 }
 
 func (a *AuthService) Login(
 	ctx context.Context, email string, password string,
-) (string, error) {
+) (*models.AuthToken, error) {
 
 	// get user
 	u, err := a.user.GetByEmail(ctx, a.db, email)
 	if err != nil {
-		return "", fmt.Errorf("failed to get user by email: %w", err)
+		return nil, fmt.Errorf("failed to get user by email: %w", err)
 	}
 
 	// compare password
 	if err := bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password)); err != nil {
-		return "", fmt.Errorf("failed to compare password: %w", err)
+		return nil, fmt.Errorf("failed to compare password: %w", err)
+	}
+
+	if u.IsDisabled() {
+		return nil, ErrUserDisabled
+	}
+
+	// 2段階認証が有効な場合は、認証コードと交換するチャレンジトークンのみを返す
+	if u.TOTPEnabled {
+		challenge, err := a.jwter.GenerateChallengeToken(ctx, u.Id, u.Email)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate challenge token: %w", err)
+		}
+		return &models.AuthToken{TwoFactorRequired: true, ChallengeToken: challenge}, nil
 	}
 
 	// generate token and save session kvs
-	token, err := a.jwter.GenerateToken(ctx, u)
+	token, err := a.jwter.GenerateTokenPair(ctx, u)
 	if err != nil {
-		return "", fmt.Errorf("failed to generate token: %w", err)
+		return nil, fmt.Errorf("failed to generate token: %w", err)
 	}
 
 	return token, nil
 }
 
+// ChallengeEmail は、チャレンジトークンを発行したログインのメールアドレスを返す
+// 無効なトークンの場合は空文字を返す
+func (a *AuthService) ChallengeEmail(ctx context.Context, challengeToken string) (string, error) {
+	email, err := a.jwter.ChallengeEmail(ctx, challengeToken)
+	if err != nil {
+		if errors.Is(err, jwt_service.ErrChallengeTokenInvalid) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get challenge email: %w", err)
+	}
+	return email, nil
+}
+
+// LoginTwoFactor は、チャレンジトークンとTOTPの認証コードまたはリカバリーコードを検証し、トークンの組を発行する
+func (a *AuthService) LoginTwoFactor(
+	ctx context.Context, challengeToken string, code string,
+) (*models.AuthToken, error) {
+	userId, err := a.jwter.UseChallengeToken(ctx, challengeToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to use challenge token: %w", err)
+	}
+	u, err := a.user.Get(ctx, a.db, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if u.IsDisabled() {
+		return nil, ErrUserDisabled
+	}
+	if !u.TOTPEnabled {
+		return nil, ErrTwoFactorCodeInvalid
+	}
+
+	// 受け付け済みのタイムステップ以前の認証コードは再利用とみなし拒否する
+	step, ok := totp.VerifyStep(u.TOTPSecret, code, a.clocker.Now())
+	if ok {
+		ok, err = a.user.UseTOTPStep(ctx, a.db, userId, step)
+		if err != nil {
+			return nil, fmt.Errorf("failed to use totp step: %w", err)
+		}
+	}
+	if !ok {
+		ok, err = a.useRecoveryCode(ctx, userId, code)
+		if err != nil {
+			return nil, fmt.Errorf("failed to use recovery code: %w", err)
+		}
+	}
+	if !ok {
+		return nil, ErrTwoFactorCodeInvalid
+	}
+
+	if err := a.jwter.DeleteChallengeToken(ctx, challengeToken); err != nil {
+		return nil, fmt.Errorf("failed to delete challenge token: %w", err)
+	}
+	token, err := a.jwter.GenerateTokenPair(ctx, u)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+	return token, nil
+}
+
+// useRecoveryCode は、未使用のリカバリーコードと照合し、一致したコードを使用済みにする
+func (a *AuthService) useRecoveryCode(
+	ctx context.Context, userId models.UserId, code string,
+) (bool, error) {
+	code = totp.NormalizeRecoveryCode(code)
+	if code == "" {
+		return false, nil
+	}
+	codes, err := a.user.ListUnusedRecoveryCodes(ctx, a.db, userId)
+	if err != nil {
+		return false, fmt.Errorf("failed to list recovery codes: %w", err)
+	}
+	for _, c := range codes {
+		if !util.ComparePassword(c.CodeHash, code) {
+			continue
+		}
+		// 同時に使用された場合に二重に使えないよう、未使用の場合のみ更新する
+		used, err := a.user.UseRecoveryCode(ctx, a.db, c.Id)
+		if err != nil {
+			return false, fmt.Errorf("failed to update recovery code: %w", err)
+		}
+		return used, nil
+	}
+	return false, nil
+}
+
 func (a *AuthService) LoginSession(
 	ctx context.Context, token string,
 ) (*models.User, error) {
@@ -73,5 +205,92 @@ func (a *AuthService) LoginSession(
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
+	if u.IsDisabled() {
+		return nil, ErrUserDisabled
+	}
+	return u, nil
+}
+
+func (a *AuthService) Logout(ctx context.Context, token string) error {
+	// revoke session kvs
+	if err := a.jwter.RevokeToken(ctx, token); err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+	return nil
+}
+
+func (a *AuthService) LogoutAll(ctx context.Context, userId models.UserId) error {
+	// revoke all sessions of the user
+	if err := a.jwter.RevokeAllTokens(ctx, userId); err != nil {
+		return fmt.Errorf("failed to revoke all tokens: %w", err)
+	}
+	return nil
+}
+
+func (a *AuthService) Refresh(ctx context.Context, refreshToken string) (*models.AuthToken, error) {
+	// rotate refresh token and generate new access token
+	token, err := a.jwter.RefreshToken(ctx, refreshToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh token: %w", err)
+	}
+	return token, nil
+}
+
+// ListSessions は、ユーザーのログイン中のセッションを取得する
+// tokenのセッションをCurrentとする
+func (a *AuthService) ListSessions(
+	ctx context.Context, userId models.UserId, token string,
+) ([]*models.Session, error) {
+	sessions, err := a.jwter.ListSessions(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	currentId, err := a.jwter.SessionId(token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session id: %w", err)
+	}
+	for _, s := range sessions {
+		s.Current = s.Id == currentId
+	}
+	return sessions, nil
+}
+
+func (a *AuthService) RevokeSession(ctx context.Context, userId models.UserId, sessionId string) error {
+	if err := a.jwter.RevokeSession(ctx, userId, sessionId); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return nil
+}
+
+// VerifyAPIKey は、APIキーを検証し、有効なキーの情報を返す
+// 存在しない・失効済み・ハッシュが一致しないキーはErrAPIKeyInvalidを返す
+func (a *AuthService) VerifyAPIKey(ctx context.Context, key string) (*models.APIKey, error) {
+	prefix, ok := apikey.Parse(key)
+	if !ok {
+		return nil, ErrAPIKeyInvalid
+	}
+	k, err := a.apiKey.GetByPrefix(ctx, a.db, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+	if k == nil || k.RevokedAt != nil || !apikey.Verify(key, k.KeyHash) {
+		return nil, ErrAPIKeyInvalid
+	}
+	if err := a.apiKey.TouchLastUsed(ctx, a.db, k.Id); err != nil {
+		return nil, fmt.Errorf("failed to update api key: %w", err)
+	}
+	return k, nil
+}
+
+// GetUser は、認証済みのユーザーの情報を返す
+// 無効化されたユーザーの場合はErrUserDisabledを返す
+func (a *AuthService) GetUser(ctx context.Context, userId models.UserId) (*models.User, error) {
+	u, err := a.user.Get(ctx, a.db, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if u.IsDisabled() {
+		return nil, ErrUserDisabled
+	}
 	return u, nil
 }
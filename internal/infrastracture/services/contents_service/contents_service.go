@@ -1,32 +1,361 @@
 package contents_service
 
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/shoet/blog/internal/imaging"
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/adapter"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/mediaref"
+	"golang.org/x/exp/slices"
+)
+
 type S3StorageAdapter interface {
-	GeneratePreSignedURL(destinationPath string, fileName string) (presignedUrl, objectUrl string, err error)
+	GeneratePreSignedURL(
+		destinationPath string, fileName string, contentType string, contentLength int64,
+	) (presignedUrl, objectUrl string, err error)
+	GetObject(ctx context.Context, key string) (io.ReadCloser, error)
+	PutObject(ctx context.Context, key string, contentType string, body io.Reader) error
+	HeadObject(ctx context.Context, key string) (*adapter.ObjectInfo, error)
+}
+
+type MediaRepository interface {
+	Add(ctx context.Context, tx infrastracture.TX, media *models.Media) (models.MediaId, error)
+	Get(ctx context.Context, tx infrastracture.TX, id models.MediaId) (*models.Media, error)
+	GetByKey(ctx context.Context, tx infrastracture.TX, key string) (*models.Media, error)
+	MarkVariantsProcessed(ctx context.Context, tx infrastracture.TX, id models.MediaId) error
+	ListVariantsProcessedKeys(ctx context.Context, tx infrastracture.TX, keys []string) ([]string, error)
 }
 
+// ThumbnailWidths は、サムネイル画像の派生画像の幅
+var ThumbnailWidths = []int{320, 640, 1280}
+
+// thumbnailVariantContentTypes は、派生画像の形式
+// WebPに対応していないブラウザ向けにJPEGも生成する
+// cgoなしでビルドした場合はWebPをエンコードできないため、JPEGのみ生成する
+var thumbnailVariantContentTypes = func() []string {
+	if imaging.WebPSupported {
+		return []string{imaging.ContentTypeWebP, imaging.ContentTypeJPEG}
+	}
+	return []string{imaging.ContentTypeJPEG}
+}()
+
+// MaxImageSize は、処理する画像の最大のバイト数
+const MaxImageSize = 20 << 20
+
+// uploadExtensions は、アップロードを許可するContent-Typeと対応する拡張子
+// 先頭の拡張子をファイル名の拡張子が対応しない場合に使用する
+var uploadExtensions = map[string][]string{
+	imaging.ContentTypeJPEG: {".jpg", ".jpeg"},
+	imaging.ContentTypePNG:  {".png"},
+	imaging.ContentTypeGIF:  {".gif"},
+	imaging.ContentTypeWebP: {".webp"},
+}
+
+var (
+	ErrInvalidFileName        = errors.New("invalid file name")
+	ErrUnsupportedContentType = errors.New("unsupported content type")
+	ErrFileTooLarge           = errors.New("file is too large")
+	ErrMediaNotFound          = errors.New("media is not found")
+)
+
 type ContentsService struct {
 	s3adapter             S3StorageAdapter
+	mediaRepository       MediaRepository
 	thumbnailDirectory    string
 	contentImageDirectory string
+	cdnDomain             string
+	maxUploadSize         int64
 }
 
 func NewContentsService(
 	s3adapter S3StorageAdapter,
+	mediaRepository MediaRepository,
 	thumnailDirectory string,
 	contentImageDirectory string,
+	cdnDomain string,
+	maxUploadSize int64,
 ) (*ContentsService, error) {
 	return &ContentsService{
 		s3adapter:             s3adapter,
+		mediaRepository:       mediaRepository,
 		thumbnailDirectory:    thumnailDirectory,
 		contentImageDirectory: contentImageDirectory,
+		cdnDomain:             cdnDomain,
+		maxUploadSize:         maxUploadSize,
 	}, nil
 }
 
 // GeneratePutURL generates a signed url for put object.
-func (c *ContentsService) GenerateThumbnailPutURL(fileName string) (presignedUrl, objectUrl string, err error) {
-	return c.s3adapter.GeneratePreSignedURL(c.thumbnailDirectory, fileName)
+func (c *ContentsService) GenerateThumbnailPutURL(
+	fileName string, contentType string, contentLength int64,
+) (*models.UploadURL, error) {
+	return c.generatePutURL(c.thumbnailDirectory, fileName, contentType, contentLength)
+}
+
+func (c *ContentsService) GenerateContentImagePutURL(
+	fileName string, contentType string, contentLength int64,
+) (*models.UploadURL, error) {
+	return c.generatePutURL(c.contentImageDirectory, fileName, contentType, contentLength)
+}
+
+// generatePutURL は、アップロードする画像の種類とサイズを検証して署名付きURLを生成する
+// 既存のオブジェクトを上書きできないよう、指定されたファイル名は拡張子のみ使用する
+func (c *ContentsService) generatePutURL(
+	directory string, fileName string, contentType string, contentLength int64,
+) (*models.UploadURL, error) {
+	extensions, ok := uploadExtensions[contentType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedContentType, contentType)
+	}
+	if contentLength <= 0 || contentLength > c.maxUploadSize {
+		return nil, fmt.Errorf("%w: %d bytes (max %d bytes)", ErrFileTooLarge, contentLength, c.maxUploadSize)
+	}
+	uploadFileName := uuid.NewString() + uploadExtension(fileName, extensions)
+	presignedUrl, objectUrl, err := c.s3adapter.GeneratePreSignedURL(
+		directory, uploadFileName, contentType, contentLength)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate presigned url: %w", err)
+	}
+	return &models.UploadURL{
+		SignedURL: presignedUrl,
+		ObjectURL: objectUrl,
+		FileName:  uploadFileName,
+	}, nil
+}
+
+// RegisterUpload は、署名付きURLを発行したファイルをアップロード待ちのメディアとして登録する
+// サイズはアップロードの完了時に実際のオブジェクトのサイズで更新する
+func (c *ContentsService) RegisterUpload(
+	ctx context.Context, tx infrastracture.TX, kind models.MediaKind, uploadURL *models.UploadURL,
+	contentType string, contentLength int64, uploadedBy *models.UserId,
+) (*models.Media, error) {
+	id, err := c.mediaRepository.Add(ctx, tx, &models.Media{
+		Key:         mediaref.Key(uploadURL.FileName),
+		Kind:        kind,
+		FileName:    uploadURL.FileName,
+		ObjectURL:   uploadURL.ObjectURL,
+		ContentType: contentType,
+		Size:        contentLength,
+		UploadedBy:  uploadedBy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to add media: %w", err)
+	}
+	media, err := c.mediaRepository.Get(ctx, tx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get media: %w", err)
+	}
+	return media, nil
+}
+
+// ProcessThumbnail は、アップロードされたサムネイル画像を検証し、派生画像を生成する
+// 元の画像もメタデータを除去するために再エンコードして上書きする
+// アニメーションを保持するため、GIFの元の画像は上書きしない
+// 派生画像を生成したメディアは生成済みとして記録し、以降はURLを返すようにする
+// メディアとして登録されていない画像の場合はErrMediaNotFoundを返す
+func (c *ContentsService) ProcessThumbnail(
+	ctx context.Context, tx infrastracture.TX, fileName string,
+) ([]*models.ImageVariant, error) {
+	mediaKey, ok := thumbnailMediaKey(fileName)
+	if !ok {
+		return nil, ErrInvalidFileName
+	}
+	media, err := c.mediaRepository.GetByKey(ctx, tx, mediaKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get media: %w", err)
+	}
+	if media == nil || media.Kind != models.MediaKindThumbnail || media.FileName != fileName {
+		return nil, ErrMediaNotFound
+	}
+
+	key := path.Join(c.thumbnailDirectory, fileName)
+	b, err := c.readObject(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	img, contentType, err := imaging.Decode(b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	if contentType != imaging.ContentTypeGIF {
+		if err := c.putImage(ctx, key, img, contentType); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, width := range ThumbnailWidths {
+		resized := imaging.Resize(img, width)
+		for _, variantContentType := range thumbnailVariantContentTypes {
+			variantKey := path.Join(c.thumbnailDirectory, thumbnailVariantFileName(mediaKey, width, variantContentType))
+			if err := c.putImage(ctx, variantKey, resized, variantContentType); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := c.mediaRepository.MarkVariantsProcessed(ctx, tx, media.Id); err != nil {
+		return nil, fmt.Errorf("failed to mark variants processed: %w", err)
+	}
+	return c.thumbnailVariants(mediaKey), nil
+}
+
+// SetThumbnailVariants は、ブログにサムネイル画像の派生画像のURLを設定する
+// 派生画像を生成済みのメディアのサムネイルのみ設定し、それ以外はnilとする
+func (c *ContentsService) SetThumbnailVariants(ctx context.Context, tx infrastracture.TX, blogs models.Blogs) error {
+	keys := []string{}
+	for _, blog := range blogs {
+		if key, ok := c.thumbnailKey(blog.ThumbnailImageFileName); ok {
+			keys = append(keys, key)
+		}
+	}
+	processedKeys, err := c.mediaRepository.ListVariantsProcessedKeys(ctx, tx, keys)
+	if err != nil {
+		return fmt.Errorf("failed to list variants processed keys: %w", err)
+	}
+	processed := map[string]bool{}
+	for _, key := range processedKeys {
+		processed[key] = true
+	}
+	blogs.SetThumbnailVariants(func(thumbnail string) []*models.ImageVariant {
+		key, ok := c.thumbnailKey(thumbnail)
+		if !ok || !processed[key] {
+			return nil
+		}
+		return c.thumbnailVariants(key)
+	})
+	return nil
+}
+
+// thumbnailKey は、ブログのサムネイルに指定された値からサムネイル画像のメディアのキーを返す
+// CDN以外の画像やメディアでない画像が指定されている場合はfalseを返す
+func (c *ContentsService) thumbnailKey(thumbnail string) (string, bool) {
+	if c.cdnDomain == "" || thumbnail == "" {
+		return "", false
+	}
+	fileName := strings.TrimPrefix(thumbnail, "/")
+	if strings.HasPrefix(thumbnail, "http://") || strings.HasPrefix(thumbnail, "https://") {
+		prefix := c.thumbnailURL("")
+		if !strings.HasPrefix(thumbnail, prefix) {
+			return "", false
+		}
+		fileName = strings.TrimPrefix(thumbnail, prefix)
+	}
+	return thumbnailMediaKey(fileName)
+}
+
+// thumbnailVariants は、サムネイル画像の派生画像のURLを返す
+// 派生画像の名前はメディアのキーから決まるため、ストレージへの問い合わせは行わない
+func (c *ContentsService) thumbnailVariants(mediaKey string) []*models.ImageVariant {
+	variants := make([]*models.ImageVariant, 0, len(ThumbnailWidths)*len(thumbnailVariantContentTypes))
+	for _, width := range ThumbnailWidths {
+		for _, contentType := range thumbnailVariantContentTypes {
+			variants = append(variants, &models.ImageVariant{
+				URL:         c.thumbnailURL(thumbnailVariantFileName(mediaKey, width, contentType)),
+				Width:       width,
+				ContentType: contentType,
+			})
+		}
+	}
+	return variants
+}
+
+// UploadedSize は、アップロードされたメディアのオブジェクトのバイト数を返す
+// オブジェクトが存在しない場合はadapter.ErrObjectNotFoundを返す
+func (c *ContentsService) UploadedSize(ctx context.Context, kind models.MediaKind, fileName string) (int64, error) {
+	if !validFileName(fileName) {
+		return 0, ErrInvalidFileName
+	}
+	directory := c.contentImageDirectory
+	if kind == models.MediaKindThumbnail {
+		directory = c.thumbnailDirectory
+	}
+	info, err := c.s3adapter.HeadObject(ctx, path.Join(directory, fileName))
+	if err != nil {
+		return 0, fmt.Errorf("failed to head object: %w", err)
+	}
+	return info.Size, nil
+}
+
+func (c *ContentsService) readObject(ctx context.Context, key string) ([]byte, error) {
+	body, err := c.s3adapter.GetObject(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object: %w", err)
+	}
+	defer body.Close()
+	b, err := io.ReadAll(io.LimitReader(body, MaxImageSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object: %w", err)
+	}
+	if len(b) > MaxImageSize {
+		return nil, imaging.ErrImageTooLarge
+	}
+	return b, nil
+}
+
+func (c *ContentsService) putImage(ctx context.Context, key string, img image.Image, contentType string) error {
+	var buf bytes.Buffer
+	if err := imaging.Encode(&buf, img, contentType); err != nil {
+		return fmt.Errorf("failed to encode image: %w", err)
+	}
+	if err := c.s3adapter.PutObject(ctx, key, contentType, &buf); err != nil {
+		return fmt.Errorf("failed to put object: %w", err)
+	}
+	return nil
+}
+
+// thumbnailURL は、サムネイル画像のディレクトリのファイルのURLを返す
+// CdnDomainにスキームが含まれていない場合はhttpsとする
+func (c *ContentsService) thumbnailURL(fileName string) string {
+	base := strings.TrimSuffix(c.cdnDomain, "/")
+	if !strings.Contains(base, "://") {
+		base = "https://" + base
+	}
+	return base + "/" + c.thumbnailDirectory + "/" + fileName
+}
+
+// thumbnailMediaKey は、サーバーで生成したメディアのファイル名であればメディアのキーを返す
+// 派生画像のファイル名の場合はfalseを返す
+func thumbnailMediaKey(fileName string) (string, bool) {
+	if !validFileName(fileName) {
+		return "", false
+	}
+	key, ok := mediaref.KeyOf(fileName)
+	if !ok || key != mediaref.Key(fileName) {
+		return "", false
+	}
+	return key, true
+}
+
+// thumbnailVariantFileName は、派生画像のファイル名を返す
+// メディアのキーはアップロードごとに一意のため、元の画像の拡張子が異なっても衝突しない
+// 例: <uuid>.png -> <uuid>_w640.webp
+func thumbnailVariantFileName(mediaKey string, width int, contentType string) string {
+	return fmt.Sprintf("%s_w%d%s", mediaKey, width, imaging.Extension(contentType))
+}
+
+// uploadExtension は、ファイル名の拡張子がContent-Typeに対応する場合はそれを小文字で返し、
+// 対応しない場合はContent-Typeの既定の拡張子を返す
+func uploadExtension(fileName string, extensions []string) string {
+	ext := strings.ToLower(path.Ext(fileName))
+	if slices.Contains(extensions, ext) {
+		return ext
+	}
+	return extensions[0]
 }
 
-func (c *ContentsService) GenerateContentImagePutURL(fileName string) (presignedUrl, objectUrl string, err error) {
-	return c.s3adapter.GeneratePreSignedURL(c.contentImageDirectory, fileName)
+// validFileName は、ディレクトリを含まないファイル名かを判定する
+func validFileName(fileName string) bool {
+	return fileName != "" &&
+		!strings.ContainsAny(fileName, "/\\") &&
+		!strings.HasPrefix(fileName, ".")
 }
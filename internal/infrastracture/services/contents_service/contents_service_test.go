@@ -0,0 +1,371 @@
+package contents_service_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/jpeg"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/shoet/blog/internal/imaging"
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/adapter"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/infrastracture/services/contents_service"
+)
+
+// memoryStorage は、テスト用のインメモリのストレージ
+type memoryStorage struct {
+	objects map[string][]byte
+}
+
+func (m *memoryStorage) GeneratePreSignedURL(
+	destinationPath string, fileName string, contentType string, contentLength int64,
+) (string, string, error) {
+	key := destinationPath + "/" + fileName
+	return "https://storage.example.com/" + key + "?signature=test", "https://cdn.example.com/" + key, nil
+}
+
+func (m *memoryStorage) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	b, ok := m.objects[key]
+	if !ok {
+		return nil, adapter.ErrObjectNotFound
+	}
+	return io.NopCloser(bytes.NewReader(b)), nil
+}
+
+func (m *memoryStorage) PutObject(ctx context.Context, key string, contentType string, body io.Reader) error {
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	m.objects[key] = b
+	return nil
+}
+
+func (m *memoryStorage) HeadObject(ctx context.Context, key string) (*adapter.ObjectInfo, error) {
+	b, ok := m.objects[key]
+	if !ok {
+		return nil, adapter.ErrObjectNotFound
+	}
+	return &adapter.ObjectInfo{Key: key, Size: int64(len(b))}, nil
+}
+
+// memoryMediaRepository は、テスト用のキーごとにメディアを保持するMediaRepository
+type memoryMediaRepository struct {
+	media map[string]*models.Media
+}
+
+func (m *memoryMediaRepository) Add(ctx context.Context, tx infrastracture.TX, media *models.Media) (models.MediaId, error) {
+	added := *media
+	added.Id = models.MediaId(len(m.media) + 1)
+	added.Status = models.MediaStatusPending
+	m.media[added.Key] = &added
+	return added.Id, nil
+}
+
+func (m *memoryMediaRepository) Get(ctx context.Context, tx infrastracture.TX, id models.MediaId) (*models.Media, error) {
+	for _, media := range m.media {
+		if media.Id == id {
+			return media, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryMediaRepository) GetByKey(ctx context.Context, tx infrastracture.TX, key string) (*models.Media, error) {
+	return m.media[key], nil
+}
+
+func (m *memoryMediaRepository) MarkVariantsProcessed(ctx context.Context, tx infrastracture.TX, id models.MediaId) error {
+	now := uint(1)
+	for _, media := range m.media {
+		if media.Id == id {
+			media.VariantsProcessedAt = &now
+		}
+	}
+	return nil
+}
+
+func (m *memoryMediaRepository) ListVariantsProcessedKeys(
+	ctx context.Context, tx infrastracture.TX, keys []string,
+) ([]string, error) {
+	result := []string{}
+	for _, key := range keys {
+		if media, ok := m.media[key]; ok && media.VariantsProcessedAt != nil {
+			result = append(result, key)
+		}
+	}
+	return result, nil
+}
+
+// テスト用のメディアのキー
+const (
+	sampleKey      = "0b7e1d2c-1a2b-4c3d-8e9f-0a1b2c3d4e5f"
+	notFoundKey    = "1c8f2e3d-2b3c-4d4e-9f0a-1b2c3d4e5f60"
+	unprocessedKey = "2d9a3f4e-3c4d-4e5f-8a1b-2c3d4e5f6071"
+)
+
+func Test_ContentsService_ProcessThumbnail(t *testing.T) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 800, 400)), nil); err != nil {
+		t.Fatalf("failed to encode jpeg: %v", err)
+	}
+	// SOIの直後にEXIFを挿入する
+	exif := append([]byte{0xFF, 0xE1, 0x00, 0x10}, []byte("Exif\x00\x00MM\x00\x2a\x00\x00\x00\x08")...)
+	original := append(append(append([]byte{}, buf.Bytes()[:2]...), exif...), buf.Bytes()[2:]...)
+
+	storage := &memoryStorage{objects: map[string][]byte{"thumbnail/" + sampleKey + ".jpg": original}}
+	mediaRepository := &memoryMediaRepository{media: map[string]*models.Media{
+		sampleKey: {Id: 1, Key: sampleKey, Kind: models.MediaKindThumbnail, FileName: sampleKey + ".jpg"},
+	}}
+	sut, err := contents_service.NewContentsService(storage, mediaRepository, "thumbnail", "content", "cdn.example.com", 1024)
+	if err != nil {
+		t.Fatalf("failed to create contents service: %v", err)
+	}
+
+	// 派生画像の生成前はURLを返さない
+	blogs := models.Blogs{{ThumbnailImageFileName: "https://cdn.example.com/thumbnail/" + sampleKey + ".jpg"}}
+	if err := sut.SetThumbnailVariants(context.Background(), nil, blogs); err != nil {
+		t.Fatalf("failed to set thumbnail variants: %v", err)
+	}
+	if blogs[0].ThumbnailImageVariants != nil {
+		t.Errorf("want no variants before processing, but got %v", blogs[0].ThumbnailImageVariants)
+	}
+
+	variants, err := sut.ProcessThumbnail(context.Background(), nil, sampleKey+".jpg")
+	if err != nil {
+		t.Fatalf("failed to process thumbnail: %v", err)
+	}
+	if err := sut.SetThumbnailVariants(context.Background(), nil, blogs); err != nil {
+		t.Fatalf("failed to set thumbnail variants: %v", err)
+	}
+	if diff := cmp.Diff(variants, blogs[0].ThumbnailImageVariants); diff != "" {
+		t.Errorf("(-got +want)\n%s", diff)
+	}
+	// cgoなしでビルドした場合はJPEGのみ生成する
+	wantCount := len(contents_service.ThumbnailWidths)
+	if imaging.WebPSupported {
+		wantCount *= 2
+	}
+	if len(variants) != wantCount {
+		t.Fatalf("want %d variants, but got %d", wantCount, len(variants))
+	}
+
+	want := map[string]int{
+		"thumbnail/" + sampleKey + "_w320.jpg":  320,
+		"thumbnail/" + sampleKey + "_w1280.jpg": 800,
+	}
+	if imaging.WebPSupported {
+		want["thumbnail/"+sampleKey+"_w320.webp"] = 320
+		want["thumbnail/"+sampleKey+"_w640.webp"] = 640
+		want["thumbnail/"+sampleKey+"_w1280.webp"] = 800
+	}
+	for key, width := range want {
+		b, ok := storage.objects[key]
+		if !ok {
+			t.Errorf("variant %s is not stored", key)
+			continue
+		}
+		img, _, err := imaging.Decode(b)
+		if err != nil {
+			t.Fatalf("failed to decode %s: %v", key, err)
+		}
+		if img.Bounds().Dx() != width {
+			t.Errorf("%s: want width %d, but got %d", key, width, img.Bounds().Dx())
+		}
+	}
+	if bytes.Contains(storage.objects["thumbnail/"+sampleKey+".jpg"], []byte("Exif")) {
+		t.Errorf("exif is not stripped from the original")
+	}
+
+	mediaRepository.media[notFoundKey] = &models.Media{
+		Id: 2, Key: notFoundKey, Kind: models.MediaKindThumbnail, FileName: notFoundKey + ".jpg",
+	}
+	if _, err := sut.ProcessThumbnail(context.Background(), nil, notFoundKey+".jpg"); !errors.Is(err, adapter.ErrObjectNotFound) {
+		t.Errorf("want %v, but got %v", adapter.ErrObjectNotFound, err)
+	}
+	if _, err := sut.ProcessThumbnail(context.Background(), nil, unprocessedKey+".jpg"); !errors.Is(err, contents_service.ErrMediaNotFound) {
+		t.Errorf("want %v, but got %v", contents_service.ErrMediaNotFound, err)
+	}
+	// メディアでない画像や派生画像、ディレクトリを含むファイル名は処理しない
+	for _, fileName := range []string{"sample.jpg", sampleKey + "_w640.webp", "../" + sampleKey + ".jpg"} {
+		if _, err := sut.ProcessThumbnail(context.Background(), nil, fileName); !errors.Is(err, contents_service.ErrInvalidFileName) {
+			t.Errorf("%s: want %v, but got %v", fileName, contents_service.ErrInvalidFileName, err)
+		}
+	}
+}
+
+func Test_ContentsService_SetThumbnailVariants(t *testing.T) {
+	processedAt := uint(1)
+	mediaRepository := &memoryMediaRepository{media: map[string]*models.Media{
+		sampleKey:      {Id: 1, Key: sampleKey, Kind: models.MediaKindThumbnail, FileName: sampleKey + ".png", VariantsProcessedAt: &processedAt},
+		unprocessedKey: {Id: 2, Key: unprocessedKey, Kind: models.MediaKindThumbnail, FileName: unprocessedKey + ".png"},
+	}}
+	sut, err := contents_service.NewContentsService(&memoryStorage{}, mediaRepository, "thumbnail", "content", "cdn.example.com", 1024)
+	if err != nil {
+		t.Fatalf("failed to create contents service: %v", err)
+	}
+	first := &models.ImageVariant{
+		URL: "https://cdn.example.com/thumbnail/" + sampleKey + "_w320.webp", Width: 320, ContentType: imaging.ContentTypeWebP,
+	}
+	if !imaging.WebPSupported {
+		first = &models.ImageVariant{
+			URL: "https://cdn.example.com/thumbnail/" + sampleKey + "_w320.jpg", Width: 320, ContentType: imaging.ContentTypeJPEG,
+		}
+	}
+	tests := []struct {
+		name      string
+		thumbnail string
+		wantFirst *models.ImageVariant
+	}{
+		{name: "file name", thumbnail: sampleKey + ".png", wantFirst: first},
+		{name: "cdn url", thumbnail: "https://cdn.example.com/thumbnail/" + sampleKey + ".png", wantFirst: first},
+		{name: "not processed", thumbnail: "https://cdn.example.com/thumbnail/" + unprocessedKey + ".png", wantFirst: nil},
+		{name: "not registered", thumbnail: "https://cdn.example.com/thumbnail/legacy.png", wantFirst: nil},
+		{name: "external url", thumbnail: "https://example.com/sample.png", wantFirst: nil},
+		{name: "empty", thumbnail: "", wantFirst: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blogs := models.Blogs{{ThumbnailImageFileName: tt.thumbnail}}
+			if err := sut.SetThumbnailVariants(context.Background(), nil, blogs); err != nil {
+				t.Fatalf("failed to set thumbnail variants: %v", err)
+			}
+			got := blogs[0].ThumbnailImageVariants
+			if tt.wantFirst == nil {
+				if got != nil {
+					t.Errorf("want nil, but got %v", got)
+				}
+				return
+			}
+			if diff := cmp.Diff(got[0], tt.wantFirst); diff != "" {
+				t.Errorf("(-got +want)\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_ContentsService_GenerateThumbnailPutURL(t *testing.T) {
+	sut, err := contents_service.NewContentsService(&memoryStorage{}, &memoryMediaRepository{}, "thumbnail", "content", "cdn.example.com", 1024)
+	if err != nil {
+		t.Fatalf("failed to create contents service: %v", err)
+	}
+	fileNamePattern := regexp.MustCompile(`^[0-9a-f-]{36}\.(jpg|jpeg|png|gif|webp)$`)
+
+	tests := []struct {
+		name          string
+		fileName      string
+		contentType   string
+		contentLength int64
+		wantExt       string
+		wantErr       error
+	}{
+		{name: "jpeg", fileName: "photo.JPEG", contentType: "image/jpeg", contentLength: 1024, wantExt: ".jpeg"},
+		{name: "mismatched extension", fileName: "photo.html", contentType: "image/png", contentLength: 10, wantExt: ".png"},
+		{name: "path in file name", fileName: "../../index.webp", contentType: "image/webp", contentLength: 10, wantExt: ".webp"},
+		{name: "unsupported content type", fileName: "image.svg", contentType: "image/svg+xml", contentLength: 10, wantErr: contents_service.ErrUnsupportedContentType},
+		{name: "too large", fileName: "photo.jpg", contentType: "image/jpeg", contentLength: 1025, wantErr: contents_service.ErrFileTooLarge},
+		{name: "empty", fileName: "photo.jpg", contentType: "image/jpeg", contentLength: 0, wantErr: contents_service.ErrFileTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sut.GenerateThumbnailPutURL(tt.fileName, tt.contentType, tt.contentLength)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("want %v, but got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to generate put url: %v", err)
+			}
+			if !fileNamePattern.MatchString(got.FileName) || path.Ext(got.FileName) != tt.wantExt {
+				t.Errorf("unexpected file name: %s", got.FileName)
+			}
+			if got.ObjectURL != "https://cdn.example.com/thumbnail/"+got.FileName {
+				t.Errorf("unexpected object url: %s", got.ObjectURL)
+			}
+		})
+	}
+
+	first, _ := sut.GenerateThumbnailPutURL("photo.jpg", "image/jpeg", 10)
+	second, _ := sut.GenerateThumbnailPutURL("photo.jpg", "image/jpeg", 10)
+	if first.FileName == second.FileName {
+		t.Errorf("file names should not collide: %s", first.FileName)
+	}
+}
+
+func Test_ContentsService_RegisterUpload(t *testing.T) {
+	mediaRepository := &memoryMediaRepository{media: map[string]*models.Media{}}
+	sut, err := contents_service.NewContentsService(&memoryStorage{}, mediaRepository, "thumbnail", "content", "cdn.example.com", 1024)
+	if err != nil {
+		t.Fatalf("failed to create contents service: %v", err)
+	}
+	uploadURL, err := sut.GenerateContentImagePutURL("photo.png", "image/png", 10)
+	if err != nil {
+		t.Fatalf("failed to generate put url: %v", err)
+	}
+	uploadedBy := models.UserId(1)
+
+	got, err := sut.RegisterUpload(
+		context.Background(), nil, models.MediaKindContent, uploadURL, "image/png", 10, &uploadedBy)
+	if err != nil {
+		t.Fatalf("failed to register upload: %v", err)
+	}
+	want := &models.Media{
+		Id:          1,
+		Key:         strings.TrimSuffix(uploadURL.FileName, ".png"),
+		Kind:        models.MediaKindContent,
+		FileName:    uploadURL.FileName,
+		ObjectURL:   uploadURL.ObjectURL,
+		ContentType: "image/png",
+		Size:        10,
+		Status:      models.MediaStatusPending,
+		UploadedBy:  &uploadedBy,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
+}
+
+func Test_ContentsService_UploadedSize(t *testing.T) {
+	storage := &memoryStorage{objects: map[string][]byte{
+		"thumbnail/sample.jpg": make([]byte, 100),
+		"content/sample.png":   make([]byte, 200),
+	}}
+	sut, err := contents_service.NewContentsService(storage, &memoryMediaRepository{}, "thumbnail", "content", "cdn.example.com", 1024)
+	if err != nil {
+		t.Fatalf("failed to create contents service: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		kind     models.MediaKind
+		fileName string
+		want     int64
+		wantErr  error
+	}{
+		{name: "thumbnail", kind: models.MediaKindThumbnail, fileName: "sample.jpg", want: 100},
+		{name: "content", kind: models.MediaKindContent, fileName: "sample.png", want: 200},
+		{name: "not uploaded", kind: models.MediaKindContent, fileName: "sample.jpg", wantErr: adapter.ErrObjectNotFound},
+		{name: "path in file name", kind: models.MediaKindContent, fileName: "../sample.jpg", wantErr: contents_service.ErrInvalidFileName},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sut.UploadedSize(context.Background(), tt.kind, tt.fileName)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("want %v, but got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("want %d, but got %d", tt.want, got)
+			}
+		})
+	}
+}
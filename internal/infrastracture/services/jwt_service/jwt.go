@@ -2,9 +2,16 @@ package jwt_service
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -12,18 +19,27 @@ import (
 	"github.com/redis/go-redis/v9"
 	"github.com/shoet/blog/internal/clocker"
 	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/session"
 )
 
 type KVSer interface {
 	Save(ctx context.Context, key string, value string) error
+	SaveWithTTL(ctx context.Context, key string, value string, ttl time.Duration) error
 	Load(ctx context.Context, key string) (string, error)
+	LoadAndDelete(ctx context.Context, key string) (string, error)
+	CompareAndSwap(ctx context.Context, key string, old string, new string, ttl time.Duration) (bool, error)
+	Delete(ctx context.Context, key string) error
+	AddSetMember(ctx context.Context, key string, member string, ttl time.Duration) error
+	RemoveSetMember(ctx context.Context, key string, member string) error
+	SetMembers(ctx context.Context, key string) ([]string, error)
 }
 
 type JWTService struct {
-	kvs               KVSer
-	clocker           clocker.Clocker
-	secretKey         []byte
-	tokenExpiresInSec int
+	kvs                      KVSer
+	clocker                  clocker.Clocker
+	secretKey                []byte
+	tokenExpiresInSec        int
+	refreshTokenExpiresInSec int
 }
 
 func NewJWTService(
@@ -31,19 +47,28 @@ func NewJWTService(
 	clocker clocker.Clocker,
 	secretKey []byte,
 	tokenExpiresInSec int,
+	refreshTokenExpiresInSec int,
 ) *JWTService {
 	return &JWTService{
-		kvs:               kvs,
-		clocker:           clocker,
-		secretKey:         secretKey,
-		tokenExpiresInSec: tokenExpiresInSec,
+		kvs:                      kvs,
+		clocker:                  clocker,
+		secretKey:                secretKey,
+		tokenExpiresInSec:        tokenExpiresInSec,
+		refreshTokenExpiresInSec: refreshTokenExpiresInSec,
 	}
 }
 
 func (j *JWTService) GenerateToken(ctx context.Context, u *models.User) (string, error) {
-	uuid := uuid.New().String()
+	return j.generateAccessToken(ctx, u.Id, uuid.New().String())
+}
+
+// generateAccessToken は、sessionIdをjtiとするJWTを発行し、セッションとしてjtiをKVSに保存する
+// セッションにはコンテキストのクライアントの情報を記録する
+func (j *JWTService) generateAccessToken(
+	ctx context.Context, userId models.UserId, sessionId string,
+) (string, error) {
 	claims := jwt.RegisteredClaims{
-		ID:       uuid,
+		ID:       sessionId,
 		Subject:  "blog",
 		IssuedAt: jwt.NewNumericDate(j.clocker.Now()),
 		ExpiresAt: jwt.NewNumericDate(
@@ -54,13 +79,78 @@ func (j *JWTService) GenerateToken(ctx context.Context, u *models.User) (string,
 	if err != nil {
 		return "", fmt.Errorf("failed to sign token: %w", err)
 	}
-	userIdStr := strconv.Itoa(int(u.Id))
-	if err := j.kvs.Save(context.Background(), uuid, userIdStr); err != nil {
+	client := session.GetClientInfo(ctx)
+	now := uint(j.clocker.Now().Unix())
+	v, err := json.Marshal(&sessionValue{
+		UserId:    userId,
+		UserAgent: client.UserAgent,
+		IpAddress: client.IpAddress,
+		Created:   now,
+		LastSeen:  now,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal session: %w", err)
+	}
+	if err := j.kvs.Save(context.Background(), sessionId, string(v)); err != nil {
 		return "", fmt.Errorf("failed to save token: %w", err)
 	}
+	// ユーザーの全セッションを失効できるように、ユーザーごとにセッションIDを記録する
+	if err := j.kvs.AddSetMember(ctx, userSessionsKey(userId), sessionId, j.sessionIndexTTL()); err != nil {
+		return "", fmt.Errorf("failed to save user session: %w", err)
+	}
 	return ss, nil
 }
 
+// sessionValue は、jtiをキーとしてKVSに保存するセッションの情報
+type sessionValue struct {
+	UserId    models.UserId `json:"userId"`
+	UserAgent string        `json:"userAgent"`
+	IpAddress string        `json:"ipAddress"`
+	Created   uint          `json:"created"`
+	LastSeen  uint          `json:"lastSeen"`
+}
+
+// lastSeenInterval は、セッションの最終アクセス日時を更新する最小の間隔
+// リクエストのたびにKVSへ書き込まないよう間引く
+const lastSeenInterval = 60 * time.Second
+
+// loadSession は、セッションの情報を取得する
+// 存在しない場合はnilを返す
+// ユーザーIDのみを保存していた以前の形式の値も読み込む
+func (j *JWTService) loadSession(ctx context.Context, sessionId string) (*sessionValue, error) {
+	v, err := j.kvs.Load(ctx, sessionId)
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to load token: %w", err)
+	}
+	if userId, err := strconv.Atoi(v); err == nil {
+		return &sessionValue{UserId: models.UserId(userId)}, nil
+	}
+	var sv sessionValue
+	if err := json.Unmarshal([]byte(v), &sv); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal session: %w", err)
+	}
+	return &sv, nil
+}
+
+// userSessionsKey は、ユーザーのセッションID(jti)とリフレッシュトークンのファミリーの集合を保存するキー
+// 集合の要素はそれぞれ削除するKVSのキーとする
+func userSessionsKey(userId models.UserId) string {
+	return fmt.Sprintf("user_sessions:%d", userId)
+}
+
+// sessionIndexTTL は、ユーザーごとのセッションの集合の有効期限
+// リフレッシュトークンより先に失効しないよう、長い方の有効期限とする
+func (j *JWTService) sessionIndexTTL() time.Duration {
+	sec := j.tokenExpiresInSec
+	if j.refreshTokenExpiresInSec > sec {
+		sec = j.refreshTokenExpiresInSec
+	}
+	return time.Duration(sec) * time.Second
+}
+
 var ErrSessionNotFound = errors.New("session is not found")
 
 func (j *JWTService) VerifyToken(ctx context.Context, token string) (models.UserId, error) {
@@ -74,20 +164,546 @@ func (j *JWTService) VerifyToken(ctx context.Context, token string) (models.User
 	if err != nil {
 		return 0, fmt.Errorf("failed to parse token: %w", err)
 	}
-
 	claims := parsed.Claims.(*jwt.RegisteredClaims)
 
 	// check session kvs
-	v, err := j.kvs.Load(ctx, claims.ID)
+	sv, err := j.loadSession(ctx, claims.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load session: %w", err)
+	}
+	if sv == nil {
+		return 0, ErrSessionNotFound
+	}
+	if err := j.touchSession(ctx, claims, sv); err != nil {
+		return 0, fmt.Errorf("failed to update session: %w", err)
+	}
+	return sv.UserId, nil
+}
+
+// touchSession は、セッションの最終アクセス日時を更新する
+// 有効期限はトークンの有効期限までとし、延長しない
+func (j *JWTService) touchSession(ctx context.Context, claims *jwt.RegisteredClaims, sv *sessionValue) error {
+	now := j.clocker.Now()
+	if sv.Created == 0 || now.Sub(time.Unix(int64(sv.LastSeen), 0)) < lastSeenInterval {
+		return nil
+	}
+	if claims.ExpiresAt == nil {
+		return nil
+	}
+	ttl := claims.ExpiresAt.Time.Sub(now)
+	if ttl <= 0 {
+		return nil
+	}
+	sv.LastSeen = uint(now.Unix())
+	v, err := json.Marshal(sv)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+	if err := j.kvs.SaveWithTTL(ctx, claims.ID, string(v), ttl); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	return nil
+}
+
+// SessionId は、トークンのセッションID(jti)を返す
+func (j *JWTService) SessionId(token string) (string, error) {
+	parsed, err := jwt.ParseWithClaims(
+		token,
+		&jwt.RegisteredClaims{},
+		func(token *jwt.Token) (interface{}, error) {
+			return j.secretKey, nil
+		},
+		jwt.WithoutClaimsValidation(),
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse token: %w", err)
+	}
+	return parsed.Claims.(*jwt.RegisteredClaims).ID, nil
+}
+
+// ListSessions は、ユーザーの有効なセッションを新しい順に取得する
+// 失効済みのセッションはユーザーごとのセッションの集合から取り除く
+func (j *JWTService) ListSessions(ctx context.Context, userId models.UserId) ([]*models.Session, error) {
+	key := userSessionsKey(userId)
+	members, err := j.kvs.SetMembers(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user sessions: %w", err)
+	}
+	sessions := []*models.Session{}
+	for _, sessionId := range members {
+		// リフレッシュトークンのファミリーのキーは除く
+		if strings.Contains(sessionId, ":") {
+			continue
+		}
+		sv, err := j.loadSession(ctx, sessionId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load session: %w", err)
+		}
+		if sv == nil {
+			if err := j.kvs.RemoveSetMember(ctx, key, sessionId); err != nil {
+				return nil, fmt.Errorf("failed to remove user session: %w", err)
+			}
+			continue
+		}
+		sessions = append(sessions, &models.Session{
+			Id:        sessionId,
+			UserId:    sv.UserId,
+			UserAgent: sv.UserAgent,
+			IpAddress: sv.IpAddress,
+			Created:   sv.Created,
+			LastSeen:  sv.LastSeen,
+		})
+	}
+	sort.SliceStable(sessions, func(i, j int) bool {
+		if sessions[i].Created != sessions[j].Created {
+			return sessions[i].Created > sessions[j].Created
+		}
+		return sessions[i].Id < sessions[j].Id
+	})
+	return sessions, nil
+}
+
+// RevokeSession は、ユーザーのセッションを失効させる
+// 他のユーザーのセッションや存在しないセッションの場合はErrSessionNotFoundを返す
+func (j *JWTService) RevokeSession(ctx context.Context, userId models.UserId, sessionId string) error {
+	sv, err := j.loadSession(ctx, sessionId)
+	if err != nil {
+		return fmt.Errorf("failed to load session: %w", err)
+	}
+	if sv == nil || sv.UserId != userId {
+		return ErrSessionNotFound
+	}
+	return j.revokeSession(ctx, userId, sessionId)
+}
+
+// revokeSession は、セッションとセッションに紐づくリフレッシュトークンを失効させる
+func (j *JWTService) revokeSession(ctx context.Context, userId models.UserId, sessionId string) error {
+	familyId, err := j.kvs.Load(ctx, sessionFamilyKey(sessionId))
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return fmt.Errorf("failed to load refresh token family: %w", err)
+	}
+	if familyId != "" {
+		if err := j.revokeFamily(ctx, familyId); err != nil {
+			return fmt.Errorf("failed to revoke refresh token family: %w", err)
+		}
+	}
+	return j.deleteSession(ctx, userId, sessionId)
+}
+
+// RevokeToken は、トークンのセッションをKVSから削除し、以降のVerifyTokenを失敗させる
+// セッションに紐づくリフレッシュトークンも失効させる
+// 不正なトークンや既に失効済みのセッションの場合は何もしない
+func (j *JWTService) RevokeToken(ctx context.Context, token string) error {
+	// 期限切れのトークンでもセッションは削除する
+	sessionId, err := j.SessionId(token)
+	if err != nil {
+		return nil
+	}
+	sv, err := j.loadSession(ctx, sessionId)
+	if err != nil {
+		return fmt.Errorf("failed to load session: %w", err)
+	}
+	if sv == nil {
+		return nil
+	}
+	return j.revokeSession(ctx, sv.UserId, sessionId)
+}
+
+// RevokeAllTokens は、ユーザーの全てのセッションとリフレッシュトークンをKVSから削除する
+func (j *JWTService) RevokeAllTokens(ctx context.Context, userId models.UserId) error {
+	key := userSessionsKey(userId)
+	sessionKeys, err := j.kvs.SetMembers(ctx, key)
+	if err != nil {
+		return fmt.Errorf("failed to get user sessions: %w", err)
+	}
+	for _, k := range sessionKeys {
+		if err := j.kvs.Delete(ctx, k); err != nil {
+			return fmt.Errorf("failed to delete token: %w", err)
+		}
+	}
+	if err := j.kvs.Delete(ctx, key); err != nil {
+		return fmt.Errorf("failed to delete user sessions: %w", err)
+	}
+	return nil
+}
+
+var ErrRefreshTokenInvalid = errors.New("refresh token is invalid")
+var ErrRefreshTokenReused = errors.New("refresh token is reused")
+
+// refreshFamily は、ログインを起点に発行されたリフレッシュトークンの系列
+// CurrentHashは最新のリフレッシュトークンのハッシュで、それ以外のトークンは使用済みとなる
+// SessionIdは最新のアクセストークンのjti
+type refreshFamily struct {
+	UserId      models.UserId `json:"userId"`
+	CurrentHash string        `json:"currentHash"`
+	SessionId   string        `json:"sessionId"`
+}
+
+func refreshTokenKey(hash string) string {
+	return fmt.Sprintf("refresh_token:%s", hash)
+}
+
+func refreshFamilyKey(familyId string) string {
+	return fmt.Sprintf("refresh_family:%s", familyId)
+}
+
+// sessionFamilyKey は、アクセストークンのjtiからリフレッシュトークンのファミリーを引くキー
+func sessionFamilyKey(sessionId string) string {
+	return fmt.Sprintf("session_family:%s", sessionId)
+}
+
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func newRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to read random: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// GenerateTokenPair は、ログイン時にアクセストークンと新しいファミリーのリフレッシュトークンを発行する
+func (j *JWTService) GenerateTokenPair(ctx context.Context, u *models.User) (*models.AuthToken, error) {
+	familyId := uuid.New().String()
+	refreshToken, family, err := newFamily(u.Id)
+	if err != nil {
+		return nil, err
+	}
+	v, err := json.Marshal(family)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal refresh token family: %w", err)
+	}
+	if err := j.kvs.SaveWithTTL(ctx, refreshFamilyKey(familyId), string(v), j.refreshTokenTTL()); err != nil {
+		return nil, fmt.Errorf("failed to save refresh token family: %w", err)
+	}
+	return j.issueTokenPair(ctx, familyId, refreshToken, family)
+}
+
+// RefreshToken は、リフレッシュトークンを使用済みにし、新しいトークンの組を発行する(ローテーション)
+// 使用済みのリフレッシュトークンが再利用された場合は漏洩とみなし、ファミリー全体を失効させる
+// ファミリーの更新は読み込んだ値との比較と同時に行うため、同じトークンを同時に使用した場合も再利用として扱う
+func (j *JWTService) RefreshToken(ctx context.Context, refreshToken string) (*models.AuthToken, error) {
+	hash := hashRefreshToken(refreshToken)
+	familyId, err := j.kvs.Load(ctx, refreshTokenKey(hash))
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrRefreshTokenInvalid
+		}
+		return nil, fmt.Errorf("failed to load refresh token: %w", err)
+	}
+	current, err := j.kvs.Load(ctx, refreshFamilyKey(familyId))
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			// ファミリーが失効済み
+			return nil, ErrRefreshTokenInvalid
+		}
+		return nil, fmt.Errorf("failed to load refresh token family: %w", err)
+	}
+	var family refreshFamily
+	if err := json.Unmarshal([]byte(current), &family); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal refresh token family: %w", err)
+	}
+	if family.CurrentHash != hash {
+		return nil, j.reuseDetected(ctx, familyId)
+	}
+
+	nextToken, next, err := newFamily(family.UserId)
+	if err != nil {
+		return nil, err
+	}
+	v, err := json.Marshal(next)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal refresh token family: %w", err)
+	}
+	swapped, err := j.kvs.CompareAndSwap(ctx, refreshFamilyKey(familyId), current, string(v), j.refreshTokenTTL())
+	if err != nil {
+		return nil, fmt.Errorf("failed to save refresh token family: %w", err)
+	}
+	if !swapped {
+		// 読み込み後に他のリクエストがローテーションした
+		return nil, j.reuseDetected(ctx, familyId)
+	}
+
+	// ローテーション前のアクセストークンは失効させる
+	if err := j.deleteSession(ctx, family.UserId, family.SessionId); err != nil {
+		return nil, fmt.Errorf("failed to delete session: %w", err)
+	}
+	return j.issueTokenPair(ctx, familyId, nextToken, next)
+}
+
+// reuseDetected は、リフレッシュトークンの再利用を検知したファミリーを失効させ、ErrRefreshTokenReusedを返す
+func (j *JWTService) reuseDetected(ctx context.Context, familyId string) error {
+	if err := j.revokeFamily(ctx, familyId); err != nil {
+		return fmt.Errorf("failed to revoke refresh token family: %w", err)
+	}
+	return ErrRefreshTokenReused
+}
+
+// newFamily は、新しいリフレッシュトークンと、それを最新とするファミリーの値を生成する
+// ファミリーのSessionIdには、これから発行するアクセストークンのjtiを設定する
+func newFamily(userId models.UserId) (string, *refreshFamily, error) {
+	refreshToken, err := newRefreshToken()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	return refreshToken, &refreshFamily{
+		UserId:      userId,
+		CurrentHash: hashRefreshToken(refreshToken),
+		SessionId:   uuid.New().String(),
+	}, nil
+}
+
+func (j *JWTService) refreshTokenTTL() time.Duration {
+	return time.Duration(j.refreshTokenExpiresInSec) * time.Second
+}
+
+// issueTokenPair は、保存済みのファミリーに対応するアクセストークンを発行し、トークンの組を返す
+func (j *JWTService) issueTokenPair(
+	ctx context.Context, familyId string, refreshToken string, family *refreshFamily,
+) (*models.AuthToken, error) {
+	accessToken, err := j.generateAccessToken(ctx, family.UserId, family.SessionId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate access token: %w", err)
+	}
+	if err := j.kvs.SaveWithTTL(ctx, refreshTokenKey(family.CurrentHash), familyId, j.refreshTokenTTL()); err != nil {
+		return nil, fmt.Errorf("failed to save refresh token: %w", err)
+	}
+	accessTTL := time.Duration(j.tokenExpiresInSec) * time.Second
+	if err := j.kvs.SaveWithTTL(ctx, sessionFamilyKey(family.SessionId), familyId, accessTTL); err != nil {
+		return nil, fmt.Errorf("failed to save session family: %w", err)
+	}
+	if err := j.kvs.AddSetMember(ctx, userSessionsKey(family.UserId), refreshFamilyKey(familyId), j.sessionIndexTTL()); err != nil {
+		return nil, fmt.Errorf("failed to save user session: %w", err)
+	}
+	return &models.AuthToken{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    j.tokenExpiresInSec,
+	}, nil
+}
+
+// loadFamily は、リフレッシュトークンのファミリーを取得する
+// 存在しない場合はnilを返す
+func (j *JWTService) loadFamily(ctx context.Context, familyId string) (*refreshFamily, error) {
+	v, err := j.kvs.Load(ctx, refreshFamilyKey(familyId))
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to load refresh token family: %w", err)
+	}
+	var family refreshFamily
+	if err := json.Unmarshal([]byte(v), &family); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal refresh token family: %w", err)
+	}
+	return &family, nil
+}
+
+// revokeFamily は、リフレッシュトークンのファミリーと最新のアクセストークンを失効させる
+func (j *JWTService) revokeFamily(ctx context.Context, familyId string) error {
+	family, err := j.loadFamily(ctx, familyId)
+	if err != nil {
+		return err
+	}
+	if family == nil {
+		return nil
+	}
+	if err := j.kvs.Delete(ctx, refreshFamilyKey(familyId)); err != nil {
+		return fmt.Errorf("failed to delete refresh token family: %w", err)
+	}
+	if err := j.kvs.RemoveSetMember(ctx, userSessionsKey(family.UserId), refreshFamilyKey(familyId)); err != nil {
+		return fmt.Errorf("failed to remove user session: %w", err)
+	}
+	return j.deleteSession(ctx, family.UserId, family.SessionId)
+}
+
+func (j *JWTService) deleteSession(ctx context.Context, userId models.UserId, sessionId string) error {
+	if err := j.kvs.Delete(ctx, sessionId); err != nil {
+		return fmt.Errorf("failed to delete token: %w", err)
+	}
+	if err := j.kvs.Delete(ctx, sessionFamilyKey(sessionId)); err != nil {
+		return fmt.Errorf("failed to delete session family: %w", err)
+	}
+	if err := j.kvs.RemoveSetMember(ctx, userSessionsKey(userId), sessionId); err != nil {
+		return fmt.Errorf("failed to remove user session: %w", err)
+	}
+	return nil
+}
+
+var ErrChallengeTokenInvalid = errors.New("challenge token is invalid")
+
+const (
+	// challengeTokenTTL は、2段階認証のチャレンジトークンの有効期限
+	challengeTokenTTL = 5 * time.Minute
+	// challengeMaxAttempts は、1つのチャレンジトークンで認証コードを試行できる回数
+	challengeMaxAttempts = 5
+)
+
+// loginChallenge は、パスワード認証後に2段階認証を待つログインの情報
+// Emailはログイン試行の失敗回数をメールアドレスごとに数えるために保持する
+type loginChallenge struct {
+	UserId   models.UserId `json:"userId"`
+	Email    string        `json:"email"`
+	Attempts int           `json:"attempts"`
+}
+
+func challengeTokenKey(hash string) string {
+	return fmt.Sprintf("login_challenge:%s", hash)
+}
+
+// GenerateChallengeToken は、2段階認証が有効なユーザーのパスワード認証後に短命なチャレンジトークンを発行する
+// チャレンジトークンはJWTと交換するまでのみ有効で、KVSにはハッシュを保存する
+func (j *JWTService) GenerateChallengeToken(
+	ctx context.Context, userId models.UserId, email string,
+) (string, error) {
+	token, err := newRefreshToken()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate challenge token: %w", err)
+	}
+	v, err := json.Marshal(&loginChallenge{UserId: userId, Email: email})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal challenge: %w", err)
+	}
+	if err := j.kvs.SaveWithTTL(ctx, challengeTokenKey(hashRefreshToken(token)), string(v), challengeTokenTTL); err != nil {
+		return "", fmt.Errorf("failed to save challenge token: %w", err)
+	}
+	return token, nil
+}
+
+// ChallengeEmail は、チャレンジトークンを発行したログインのメールアドレスを返す
+// 試行回数は記録しない
+func (j *JWTService) ChallengeEmail(ctx context.Context, token string) (string, error) {
+	v, err := j.kvs.Load(ctx, challengeTokenKey(hashRefreshToken(token)))
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", ErrChallengeTokenInvalid
+		}
+		return "", fmt.Errorf("failed to load challenge token: %w", err)
+	}
+	var c loginChallenge
+	if err := json.Unmarshal([]byte(v), &c); err != nil {
+		return "", fmt.Errorf("failed to unmarshal challenge: %w", err)
+	}
+	return c.Email, nil
+}
+
+// UseChallengeToken は、チャレンジトークンのユーザーIDを返し、試行回数を記録する
+// 試行回数の上限に達したトークンは破棄し、ErrChallengeTokenInvalidを返す
+func (j *JWTService) UseChallengeToken(ctx context.Context, token string) (models.UserId, error) {
+	key := challengeTokenKey(hashRefreshToken(token))
+	v, err := j.kvs.Load(ctx, key)
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return 0, ErrChallengeTokenInvalid
+		}
+		return 0, fmt.Errorf("failed to load challenge token: %w", err)
+	}
+	var c loginChallenge
+	if err := json.Unmarshal([]byte(v), &c); err != nil {
+		return 0, fmt.Errorf("failed to unmarshal challenge: %w", err)
+	}
+	c.Attempts++
+	if c.Attempts > challengeMaxAttempts {
+		if err := j.kvs.Delete(ctx, key); err != nil {
+			return 0, fmt.Errorf("failed to delete challenge token: %w", err)
+		}
+		return 0, ErrChallengeTokenInvalid
+	}
+	b, err := json.Marshal(&c)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal challenge: %w", err)
+	}
+	if err := j.kvs.SaveWithTTL(ctx, key, string(b), challengeTokenTTL); err != nil {
+		return 0, fmt.Errorf("failed to save challenge token: %w", err)
+	}
+	return c.UserId, nil
+}
+
+// DeleteChallengeToken は、JWTと交換したチャレンジトークンを破棄する
+func (j *JWTService) DeleteChallengeToken(ctx context.Context, token string) error {
+	if err := j.kvs.Delete(ctx, challengeTokenKey(hashRefreshToken(token))); err != nil {
+		return fmt.Errorf("failed to delete challenge token: %w", err)
+	}
+	return nil
+}
+
+func passwordResetTokenKey(hash string) string {
+	return fmt.Sprintf("password_reset:%s", hash)
+}
+
+// passwordResetUserKey は、ユーザーの最新のパスワードリセットトークンのハッシュを保存するキー
+func passwordResetUserKey(userId models.UserId) string {
+	return fmt.Sprintf("password_reset_user:%d", userId)
+}
+
+// GeneratePasswordResetToken は、使い捨てのパスワードリセットトークンを発行する
+// 発行済みのトークンは無効にし、ユーザーごとに最新のトークンのみを有効とする
+func (j *JWTService) GeneratePasswordResetToken(
+	ctx context.Context, userId models.UserId, ttl time.Duration,
+) (string, error) {
+	if err := j.deletePasswordResetToken(ctx, userId); err != nil {
+		return "", err
+	}
+	token, err := newRefreshToken()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate password reset token: %w", err)
+	}
+	hash := hashRefreshToken(token)
+	if err := j.kvs.SaveWithTTL(ctx, passwordResetTokenKey(hash), strconv.FormatInt(int64(userId), 10), ttl); err != nil {
+		return "", fmt.Errorf("failed to save password reset token: %w", err)
+	}
+	if err := j.kvs.SaveWithTTL(ctx, passwordResetUserKey(userId), hash, ttl); err != nil {
+		return "", fmt.Errorf("failed to save password reset token: %w", err)
+	}
+	return token, nil
+}
+
+// UsePasswordResetToken は、パスワードリセットトークンを破棄し、ユーザーIDを返す
+// 取得と破棄は同時に行うため、同じトークンを同時に使用してもユーザーIDを返すのは1回のみとなる
+// 存在しないまたは期限切れのトークンの場合は0を返す
+func (j *JWTService) UsePasswordResetToken(ctx context.Context, token string) (models.UserId, error) {
+	hash := hashRefreshToken(token)
+	v, err := j.kvs.LoadAndDelete(ctx, passwordResetTokenKey(hash))
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
-			return 0, ErrSessionNotFound
+			return 0, nil
 		}
-		return 0, fmt.Errorf("failed to load token: %w", err)
+		return 0, fmt.Errorf("failed to load password reset token: %w", err)
 	}
-	userId, err := strconv.Atoi(v)
+	userId, err := strconv.ParseInt(v, 10, 64)
 	if err != nil {
-		return 0, fmt.Errorf("failed to convert user id: %w", err)
+		return 0, fmt.Errorf("failed to parse password reset token: %w", err)
+	}
+
+	// 使用したトークンがユーザーの最新のトークンの場合のみ、ユーザーとの紐付けを削除する
+	latest, err := j.kvs.Load(ctx, passwordResetUserKey(models.UserId(userId)))
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return 0, fmt.Errorf("failed to load password reset token: %w", err)
+	}
+	if latest == hash {
+		if err := j.kvs.Delete(ctx, passwordResetUserKey(models.UserId(userId))); err != nil {
+			return 0, fmt.Errorf("failed to delete password reset token: %w", err)
+		}
 	}
 	return models.UserId(userId), nil
 }
+
+func (j *JWTService) deletePasswordResetToken(ctx context.Context, userId models.UserId) error {
+	hash, err := j.kvs.Load(ctx, passwordResetUserKey(userId))
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil
+		}
+		return fmt.Errorf("failed to load password reset token: %w", err)
+	}
+	if err := j.kvs.Delete(ctx, passwordResetTokenKey(hash)); err != nil {
+		return fmt.Errorf("failed to delete password reset token: %w", err)
+	}
+	if err := j.kvs.Delete(ctx, passwordResetUserKey(userId)); err != nil {
+		return fmt.Errorf("failed to delete password reset token: %w", err)
+	}
+	return nil
+}
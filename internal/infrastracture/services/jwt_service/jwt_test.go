@@ -2,14 +2,18 @@ package jwt_service_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/redis/go-redis/v9"
 	"github.com/shoet/blog/internal/clocker"
 	"github.com/shoet/blog/internal/infrastracture/models"
 	"github.com/shoet/blog/internal/infrastracture/services/jwt_service"
+	"github.com/shoet/blog/internal/session"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -27,6 +31,141 @@ func (m *KVSerMock) Load(ctx context.Context, key string) (string, error) {
 	return args.String(0), args.Error(1)
 }
 
+func (m *KVSerMock) LoadAndDelete(ctx context.Context, key string) (string, error) {
+	args := m.Called(ctx, key)
+	return args.String(0), args.Error(1)
+}
+
+func (m *KVSerMock) CompareAndSwap(ctx context.Context, key string, old string, new string, ttl time.Duration) (bool, error) {
+	args := m.Called(ctx, key, old, new, ttl)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *KVSerMock) Delete(ctx context.Context, key string) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (m *KVSerMock) SaveWithTTL(ctx context.Context, key string, value string, ttl time.Duration) error {
+	args := m.Called(ctx, key, value, ttl)
+	return args.Error(0)
+}
+
+func (m *KVSerMock) AddSetMember(ctx context.Context, key string, member string, ttl time.Duration) error {
+	args := m.Called(ctx, key, member, ttl)
+	return args.Error(0)
+}
+
+func (m *KVSerMock) RemoveSetMember(ctx context.Context, key string, member string) error {
+	args := m.Called(ctx, key, member)
+	return args.Error(0)
+}
+
+func (m *KVSerMock) SetMembers(ctx context.Context, key string) ([]string, error) {
+	args := m.Called(ctx, key)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+// memoryKVS は、テスト用のインメモリのKVSer
+// 有効期限は扱わない
+type memoryKVS struct {
+	values map[string]string
+	sets   map[string]map[string]struct{}
+}
+
+func newMemoryKVS() *memoryKVS {
+	return &memoryKVS{
+		values: map[string]string{},
+		sets:   map[string]map[string]struct{}{},
+	}
+}
+
+func (m *memoryKVS) Save(ctx context.Context, key string, value string) error {
+	m.values[key] = value
+	return nil
+}
+
+func (m *memoryKVS) SaveWithTTL(ctx context.Context, key string, value string, ttl time.Duration) error {
+	m.values[key] = value
+	return nil
+}
+
+func (m *memoryKVS) Load(ctx context.Context, key string) (string, error) {
+	v, ok := m.values[key]
+	if !ok {
+		return "", fmt.Errorf("failed to get key: %w", redis.Nil)
+	}
+	return v, nil
+}
+
+func (m *memoryKVS) LoadAndDelete(ctx context.Context, key string) (string, error) {
+	v, err := m.Load(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	delete(m.values, key)
+	return v, nil
+}
+
+func (m *memoryKVS) CompareAndSwap(ctx context.Context, key string, old string, new string, ttl time.Duration) (bool, error) {
+	if v, ok := m.values[key]; !ok || v != old {
+		return false, nil
+	}
+	m.values[key] = new
+	return true, nil
+}
+
+func (m *memoryKVS) Delete(ctx context.Context, key string) error {
+	delete(m.values, key)
+	delete(m.sets, key)
+	return nil
+}
+
+func (m *memoryKVS) AddSetMember(ctx context.Context, key string, member string, ttl time.Duration) error {
+	if _, ok := m.sets[key]; !ok {
+		m.sets[key] = map[string]struct{}{}
+	}
+	m.sets[key][member] = struct{}{}
+	return nil
+}
+
+func (m *memoryKVS) RemoveSetMember(ctx context.Context, key string, member string) error {
+	delete(m.sets[key], member)
+	return nil
+}
+
+func (m *memoryKVS) SetMembers(ctx context.Context, key string) ([]string, error) {
+	members := []string{}
+	for member := range m.sets[key] {
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+// racingKVS は、CompareAndSwapの直前にbeforeSwapを呼び出し、同時に実行されたリクエストを再現する
+type racingKVS struct {
+	*memoryKVS
+	beforeSwap func()
+}
+
+func (r *racingKVS) CompareAndSwap(ctx context.Context, key string, old string, new string, ttl time.Duration) (bool, error) {
+	if r.beforeSwap != nil {
+		r.beforeSwap()
+	}
+	return r.memoryKVS.CompareAndSwap(ctx, key, old, new, ttl)
+}
+
+// sessionIds は、KVSに保存されているセッションID(jti)を返す
+func (m *memoryKVS) sessionIds(userId models.UserId) []string {
+	ids := []string{}
+	for member := range m.sets[fmt.Sprintf("user_sessions:%d", userId)] {
+		if _, ok := m.values[member]; ok && !strings.Contains(member, ":") {
+			ids = append(ids, member)
+		}
+	}
+	return ids
+}
+
 func Test_JWTService_GenerateToken(t *testing.T) {
 	type args struct {
 		user *models.User
@@ -55,12 +194,13 @@ func Test_JWTService_GenerateToken(t *testing.T) {
 			ctx := context.Background()
 			kvsMock := &KVSerMock{}
 			userIdStr := strconv.Itoa(int(tt.want.user.Id))
-			kvsMock.On("Save", mock.Anything, mock.AnythingOfType("string"), userIdStr).Return(nil)
+			kvsMock.On("Save", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil)
+			kvsMock.On("AddSetMember", mock.Anything, "user_sessions:"+userIdStr, mock.AnythingOfType("string"), mock.Anything).Return(nil)
 			clockerMock := &clocker.FiexedClocker{}
 
 			testSecret := "12345678"
 			testTokenExpiresInSec := 60
-			sut := jwt_service.NewJWTService(kvsMock, clockerMock, []byte(testSecret), testTokenExpiresInSec)
+			sut := jwt_service.NewJWTService(kvsMock, clockerMock, []byte(testSecret), testTokenExpiresInSec, 3600)
 
 			token, err := sut.GenerateToken(ctx, tt.args.user)
 			if err != nil {
@@ -112,14 +252,15 @@ func Test_JWTService_VerifyToken(t *testing.T) {
 			ctx := context.Background()
 			kvsMock := &KVSerMock{}
 			userIdStr := strconv.Itoa(int(tt.want.user.Id))
-			kvsMock.On("Save", mock.Anything, mock.AnythingOfType("string"), userIdStr).Return(nil)
+			kvsMock.On("Save", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil)
+			kvsMock.On("AddSetMember", mock.Anything, "user_sessions:"+userIdStr, mock.AnythingOfType("string"), mock.Anything).Return(nil)
 			kvsMock.On("Load", mock.Anything, mock.AnythingOfType("string")).Return(userIdStr, nil)
 
 			clockerMock := &clocker.RealClocker{}
 			testSecret := "12345678"
 			testTokenExpiresInSec := tt.args.tokenExpireInSec
 
-			sut := jwt_service.NewJWTService(kvsMock, clockerMock, []byte(testSecret), testTokenExpiresInSec)
+			sut := jwt_service.NewJWTService(kvsMock, clockerMock, []byte(testSecret), testTokenExpiresInSec, 3600)
 
 			token, err := sut.GenerateToken(ctx, tt.args.user)
 			if err != nil {
@@ -143,3 +284,307 @@ func Test_JWTService_VerifyToken(t *testing.T) {
 	}
 
 }
+
+func Test_JWTService_RevokeAllTokens(t *testing.T) {
+	ctx := context.Background()
+	kvsMock := &KVSerMock{}
+	kvsMock.On("SetMembers", mock.Anything, "user_sessions:1").Return([]string{"a", "b"}, nil)
+	kvsMock.On("Delete", mock.Anything, mock.AnythingOfType("string"), mock.Anything).Return(nil)
+
+	sut := jwt_service.NewJWTService(kvsMock, &clocker.RealClocker{}, []byte("12345678"), 60, 3600)
+
+	if err := sut.RevokeAllTokens(ctx, 1); err != nil {
+		t.Fatalf("failed revoke all tokens: %v", err)
+	}
+	for _, key := range []string{"a", "b", "user_sessions:1"} {
+		kvsMock.AssertCalled(t, "Delete", mock.Anything, key)
+	}
+}
+
+func Test_JWTService_RevokeToken(t *testing.T) {
+	ctx := context.Background()
+	kvs := newMemoryKVS()
+	sut := jwt_service.NewJWTService(kvs, &clocker.FiexedClocker{}, []byte("12345678"), 60, 3600)
+
+	token, err := sut.GenerateTokenPair(ctx, &models.User{Id: 1})
+	if err != nil {
+		t.Fatalf("failed generate token pair: %v", err)
+	}
+	if err := sut.RevokeToken(ctx, token.AccessToken); err != nil {
+		t.Fatalf("failed revoke token: %v", err)
+	}
+	if ids := kvs.sessionIds(1); len(ids) != 0 {
+		t.Errorf("want no sessions, but got %v", ids)
+	}
+	// セッションに紐づくリフレッシュトークンも失効する
+	if _, err := sut.RefreshToken(ctx, token.RefreshToken); !errors.Is(err, jwt_service.ErrRefreshTokenInvalid) {
+		t.Errorf("want ErrRefreshTokenInvalid, but got %v", err)
+	}
+	// 不正なトークンは何もしない
+	if err := sut.RevokeToken(ctx, "invalid"); err != nil {
+		t.Fatalf("failed revoke invalid token: %v", err)
+	}
+}
+
+func Test_JWTService_RefreshToken(t *testing.T) {
+	type step struct {
+		// 使用するリフレッシュトークンの発行順(0はログイン時)
+		use     int
+		wantErr error
+	}
+
+	tests := []struct {
+		name  string
+		steps []step
+		// 最後に有効なセッション数
+		wantSessions int
+	}{
+		{
+			name: "リフレッシュのたびにトークンをローテーションする",
+			steps: []step{
+				{use: 0},
+				{use: 1},
+				{use: 2},
+			},
+			wantSessions: 1,
+		},
+		{
+			name: "使用済みのトークンの再利用でファミリー全体を失効させる",
+			steps: []step{
+				{use: 0},
+				{use: 0, wantErr: jwt_service.ErrRefreshTokenReused},
+				{use: 1, wantErr: jwt_service.ErrRefreshTokenInvalid},
+			},
+			wantSessions: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			kvs := newMemoryKVS()
+			sut := jwt_service.NewJWTService(kvs, &clocker.FiexedClocker{}, []byte("12345678"), 60, 3600)
+
+			token, err := sut.GenerateTokenPair(ctx, &models.User{Id: 1})
+			if err != nil {
+				t.Fatalf("failed generate token pair: %v", err)
+			}
+			if token.ExpiresIn != 60 {
+				t.Errorf("want expires in 60, but got %d", token.ExpiresIn)
+			}
+			issued := []string{token.RefreshToken}
+			for _, s := range tt.steps {
+				got, err := sut.RefreshToken(ctx, issued[s.use])
+				if s.wantErr != nil {
+					if !errors.Is(err, s.wantErr) {
+						t.Fatalf("want %v, but got %v", s.wantErr, err)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("failed refresh token: %v", err)
+				}
+				if got.RefreshToken == issued[s.use] {
+					t.Fatalf("refresh token is not rotated")
+				}
+				issued = append(issued, got.RefreshToken)
+			}
+			if ids := kvs.sessionIds(1); len(ids) != tt.wantSessions {
+				t.Errorf("want %d sessions, but got %v", tt.wantSessions, ids)
+			}
+		})
+	}
+
+	t.Run("同時に使用されたトークンは再利用として扱う", func(t *testing.T) {
+		ctx := context.Background()
+		kvs := &racingKVS{memoryKVS: newMemoryKVS()}
+		sut := jwt_service.NewJWTService(kvs, &clocker.FiexedClocker{}, []byte("12345678"), 60, 3600)
+
+		token, err := sut.GenerateTokenPair(ctx, &models.User{Id: 1})
+		if err != nil {
+			t.Fatalf("failed generate token pair: %v", err)
+		}
+		// ファミリーの読み込みから更新までの間に、同じトークンで別のリクエストがローテーションする
+		kvs.beforeSwap = func() {
+			kvs.beforeSwap = nil
+			if _, err := sut.RefreshToken(ctx, token.RefreshToken); err != nil {
+				t.Fatalf("failed refresh token: %v", err)
+			}
+		}
+		if _, err := sut.RefreshToken(ctx, token.RefreshToken); !errors.Is(err, jwt_service.ErrRefreshTokenReused) {
+			t.Errorf("want ErrRefreshTokenReused, but got %v", err)
+		}
+		if ids := kvs.sessionIds(1); len(ids) != 0 {
+			t.Errorf("want no sessions, but got %v", ids)
+		}
+	})
+
+	t.Run("不正なトークン", func(t *testing.T) {
+		sut := jwt_service.NewJWTService(newMemoryKVS(), &clocker.FiexedClocker{}, []byte("12345678"), 60, 3600)
+		if _, err := sut.RefreshToken(context.Background(), "invalid"); !errors.Is(err, jwt_service.ErrRefreshTokenInvalid) {
+			t.Errorf("want ErrRefreshTokenInvalid, but got %v", err)
+		}
+	})
+}
+
+func Test_JWTService_RevokeAllTokens_RefreshToken(t *testing.T) {
+	ctx := context.Background()
+	kvs := newMemoryKVS()
+	sut := jwt_service.NewJWTService(kvs, &clocker.FiexedClocker{}, []byte("12345678"), 60, 3600)
+
+	tokens := []*models.AuthToken{}
+	for i := 0; i < 2; i++ {
+		token, err := sut.GenerateTokenPair(ctx, &models.User{Id: 1})
+		if err != nil {
+			t.Fatalf("failed generate token pair: %v", err)
+		}
+		tokens = append(tokens, token)
+	}
+	if err := sut.RevokeAllTokens(ctx, 1); err != nil {
+		t.Fatalf("failed revoke all tokens: %v", err)
+	}
+	if ids := kvs.sessionIds(1); len(ids) != 0 {
+		t.Errorf("want no sessions, but got %v", ids)
+	}
+	for _, token := range tokens {
+		if _, err := sut.RefreshToken(ctx, token.RefreshToken); !errors.Is(err, jwt_service.ErrRefreshTokenInvalid) {
+			t.Errorf("want ErrRefreshTokenInvalid, but got %v", err)
+		}
+	}
+}
+
+func Test_JWTService_ListSessions(t *testing.T) {
+	ctx := context.Background()
+	kvs := newMemoryKVS()
+	sut := jwt_service.NewJWTService(kvs, &clocker.FiexedClocker{}, []byte("12345678"), 60, 3600)
+
+	clientCtx := session.SetClientInfo(ctx, &session.ClientInfo{UserAgent: "test-agent", IpAddress: "192.0.2.1"})
+	token, err := sut.GenerateTokenPair(clientCtx, &models.User{Id: 1})
+	if err != nil {
+		t.Fatalf("failed generate token pair: %v", err)
+	}
+	if _, err := sut.GenerateTokenPair(ctx, &models.User{Id: 2}); err != nil {
+		t.Fatalf("failed generate token pair: %v", err)
+	}
+
+	got, err := sut.ListSessions(ctx, 1)
+	if err != nil {
+		t.Fatalf("failed list sessions: %v", err)
+	}
+	sessionId, err := sut.SessionId(token.AccessToken)
+	if err != nil {
+		t.Fatalf("failed get session id: %v", err)
+	}
+	now := uint(clocker.NewFixedClocker().Now().Unix())
+	want := []*models.Session{
+		{Id: sessionId, UserId: 1, UserAgent: "test-agent", IpAddress: "192.0.2.1", Created: now, LastSeen: now},
+	}
+	if len(got) != len(want) || *got[0] != *want[0] {
+		t.Errorf("want %+v, but got %+v", want[0], got)
+	}
+
+	// 他のユーザーのセッションは失効できない
+	if err := sut.RevokeSession(ctx, 2, sessionId); !errors.Is(err, jwt_service.ErrSessionNotFound) {
+		t.Errorf("want ErrSessionNotFound, but got %v", err)
+	}
+	if err := sut.RevokeSession(ctx, 1, sessionId); err != nil {
+		t.Fatalf("failed revoke session: %v", err)
+	}
+	got, err = sut.ListSessions(ctx, 1)
+	if err != nil {
+		t.Fatalf("failed list sessions: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("want no sessions, but got %+v", got)
+	}
+	if _, err := sut.RefreshToken(ctx, token.RefreshToken); !errors.Is(err, jwt_service.ErrRefreshTokenInvalid) {
+		t.Errorf("want ErrRefreshTokenInvalid, but got %v", err)
+	}
+}
+
+func Test_JWTService_ChallengeToken(t *testing.T) {
+	ctx := context.Background()
+	kvs := newMemoryKVS()
+	sut := jwt_service.NewJWTService(kvs, &clocker.FiexedClocker{}, []byte("12345678"), 60, 3600)
+
+	token, err := sut.GenerateChallengeToken(ctx, 1, "user@example.com")
+	if err != nil {
+		t.Fatalf("failed generate challenge token: %v", err)
+	}
+
+	if _, err := sut.UseChallengeToken(ctx, "unknown"); !errors.Is(err, jwt_service.ErrChallengeTokenInvalid) {
+		t.Errorf("want ErrChallengeTokenInvalid, but got %v", err)
+	}
+
+	email, err := sut.ChallengeEmail(ctx, token)
+	if err != nil {
+		t.Fatalf("failed get challenge email: %v", err)
+	}
+	if email != "user@example.com" {
+		t.Errorf("want user@example.com, but got %s", email)
+	}
+	if _, err := sut.ChallengeEmail(ctx, "unknown"); !errors.Is(err, jwt_service.ErrChallengeTokenInvalid) {
+		t.Errorf("want ErrChallengeTokenInvalid, but got %v", err)
+	}
+
+	// 試行回数の上限まではユーザーIDを返す
+	// メールアドレスの取得は試行回数に含めない
+	for i := 0; i < 5; i++ {
+		userId, err := sut.UseChallengeToken(ctx, token)
+		if err != nil {
+			t.Fatalf("failed use challenge token: %v", err)
+		}
+		if userId != 1 {
+			t.Errorf("want user id 1, but got %d", userId)
+		}
+	}
+	if _, err := sut.UseChallengeToken(ctx, token); !errors.Is(err, jwt_service.ErrChallengeTokenInvalid) {
+		t.Errorf("want ErrChallengeTokenInvalid after max attempts, but got %v", err)
+	}
+
+	// 交換済みのトークンは利用できない
+	token, err = sut.GenerateChallengeToken(ctx, 1, "user@example.com")
+	if err != nil {
+		t.Fatalf("failed generate challenge token: %v", err)
+	}
+	if err := sut.DeleteChallengeToken(ctx, token); err != nil {
+		t.Fatalf("failed delete challenge token: %v", err)
+	}
+	if _, err := sut.UseChallengeToken(ctx, token); !errors.Is(err, jwt_service.ErrChallengeTokenInvalid) {
+		t.Errorf("want ErrChallengeTokenInvalid after delete, but got %v", err)
+	}
+}
+
+func Test_JWTService_PasswordResetToken(t *testing.T) {
+	ctx := context.Background()
+	kvs := newMemoryKVS()
+	sut := jwt_service.NewJWTService(kvs, &clocker.FiexedClocker{}, []byte("12345678"), 60, 3600)
+
+	old, err := sut.GeneratePasswordResetToken(ctx, 1, time.Hour)
+	if err != nil {
+		t.Fatalf("failed generate password reset token: %v", err)
+	}
+	token, err := sut.GeneratePasswordResetToken(ctx, 1, time.Hour)
+	if err != nil {
+		t.Fatalf("failed generate password reset token: %v", err)
+	}
+
+	// 再発行前のトークンは無効
+	if userId, err := sut.UsePasswordResetToken(ctx, old); err != nil || userId != 0 {
+		t.Errorf("want invalid old token, but got %d, %v", userId, err)
+	}
+	userId, err := sut.UsePasswordResetToken(ctx, token)
+	if err != nil {
+		t.Fatalf("failed use password reset token: %v", err)
+	}
+	if userId != 1 {
+		t.Errorf("want user id 1, but got %d", userId)
+	}
+	if _, ok := kvs.values["password_reset_user:1"]; ok {
+		t.Errorf("want password reset user key deleted")
+	}
+	// 使用済みのトークンは無効
+	if userId, err := sut.UsePasswordResetToken(ctx, token); err != nil || userId != 0 {
+		t.Errorf("want invalid used token, but got %d, %v", userId, err)
+	}
+}
@@ -0,0 +1,185 @@
+package login_guard_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/redis/go-redis/v9"
+	"github.com/shoet/blog/internal/clocker"
+	"github.com/shoet/blog/internal/config"
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+)
+
+type KVSer interface {
+	SaveWithTTL(ctx context.Context, key string, value string, ttl time.Duration) error
+	Load(ctx context.Context, key string) (string, error)
+	Delete(ctx context.Context, key string) error
+	Increment(ctx context.Context, key string, window time.Duration) (int64, error)
+}
+
+type LoginLockoutRepository interface {
+	Add(ctx context.Context, tx infrastracture.TX, lockout *models.LoginLockout) (models.LoginLockoutId, error)
+}
+
+// Limits は、ログイン試行の失敗の許容回数とロックの期間
+// 失敗回数が上限に達するとLockoutBaseの期間ロックし、以降は失敗するたびに期間を2倍にする(LockoutMaxまで)
+type Limits struct {
+	MaxFailuresPerEmail int64
+	MaxFailuresPerIp    int64
+	FailureWindow       time.Duration
+	LockoutBase         time.Duration
+	LockoutMax          time.Duration
+}
+
+func NewLimits(cfg *config.Config) Limits {
+	return Limits{
+		MaxFailuresPerEmail: cfg.LoginMaxFailuresPerEmail,
+		MaxFailuresPerIp:    cfg.LoginMaxFailuresPerIp,
+		FailureWindow:       time.Duration(cfg.LoginFailureWindowSec) * time.Second,
+		LockoutBase:         time.Duration(cfg.LoginLockoutBaseSec) * time.Second,
+		LockoutMax:          time.Duration(cfg.LoginLockoutMaxSec) * time.Second,
+	}
+}
+
+// LoginGuardService は、メールアドレスとIPアドレスごとにログインの失敗回数を数え、総当たり攻撃を防ぐ
+type LoginGuardService struct {
+	db      *sqlx.DB
+	kvs     KVSer
+	lockout LoginLockoutRepository
+	clocker clocker.Clocker
+	limits  Limits
+}
+
+func NewLoginGuardService(
+	db *sqlx.DB, kvs KVSer, lockout LoginLockoutRepository, clocker clocker.Clocker, limits Limits,
+) *LoginGuardService {
+	return &LoginGuardService{
+		db:      db,
+		kvs:     kvs,
+		lockout: lockout,
+		clocker: clocker,
+		limits:  limits,
+	}
+}
+
+type target struct {
+	targetType  models.LoginLockoutTargetType
+	value       string
+	maxFailures int64
+}
+
+// targets は、ログイン試行の対象とするメールアドレスとIPアドレスを返す
+// 空の値は対象としない
+func (g *LoginGuardService) targets(email string, ipAddress string) []target {
+	targets := []target{}
+	if email = normalizeEmail(email); email != "" {
+		targets = append(targets, target{models.LoginLockoutTargetEmail, email, g.limits.MaxFailuresPerEmail})
+	}
+	if ipAddress != "" {
+		targets = append(targets, target{models.LoginLockoutTargetIpAddress, ipAddress, g.limits.MaxFailuresPerIp})
+	}
+	return targets
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func failuresKey(t target) string {
+	return fmt.Sprintf("login_failures:%s:%s", t.targetType, t.value)
+}
+
+func lockKey(t target) string {
+	return fmt.Sprintf("login_lock:%s:%s", t.targetType, t.value)
+}
+
+// Check は、メールアドレスまたはIPアドレスがロック中の場合に解除までの時間を返す
+// ロックされていない場合は0を返す
+func (g *LoginGuardService) Check(ctx context.Context, email string, ipAddress string) (time.Duration, error) {
+	var retryAfter time.Duration
+	for _, t := range g.targets(email, ipAddress) {
+		v, err := g.kvs.Load(ctx, lockKey(t))
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				continue
+			}
+			return 0, fmt.Errorf("failed to load login lock: %w", err)
+		}
+		until, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse login lock: %w", err)
+		}
+		if d := time.Unix(until, 0).Sub(g.clocker.Now()); d > retryAfter {
+			retryAfter = d
+		}
+	}
+	return retryAfter, nil
+}
+
+// RecordFailure は、ログインの失敗を記録し、失敗回数が上限に達した対象をロックする
+// ロックした場合は監査記録を残し、ロックの期間を返す
+func (g *LoginGuardService) RecordFailure(
+	ctx context.Context, email string, ipAddress string, userAgent string,
+) (time.Duration, error) {
+	var locked time.Duration
+	for _, t := range g.targets(email, ipAddress) {
+		failures, err := g.kvs.Increment(ctx, failuresKey(t), g.limits.FailureWindow)
+		if err != nil {
+			return 0, fmt.Errorf("failed to increment login failures: %w", err)
+		}
+		if failures < t.maxFailures {
+			continue
+		}
+		d := g.lockoutDuration(failures - t.maxFailures)
+		until := g.clocker.Now().Add(d)
+		if err := g.kvs.SaveWithTTL(ctx, lockKey(t), strconv.FormatInt(until.Unix(), 10), d); err != nil {
+			return 0, fmt.Errorf("failed to save login lock: %w", err)
+		}
+		if _, err := g.lockout.Add(ctx, g.db, &models.LoginLockout{
+			TargetType:  t.targetType,
+			Target:      t.value,
+			Failures:    failures,
+			LockedUntil: uint(until.Unix()),
+			IpAddress:   ipAddress,
+			UserAgent:   userAgent,
+		}); err != nil {
+			return 0, fmt.Errorf("failed to add login lockout: %w", err)
+		}
+		if d > locked {
+			locked = d
+		}
+	}
+	return locked, nil
+}
+
+// lockoutDuration は、上限を超えた失敗回数に応じて指数的に増加するロックの期間を返す
+func (g *LoginGuardService) lockoutDuration(exceeded int64) time.Duration {
+	d := g.limits.LockoutBase
+	for i := int64(0); i < exceeded && d < g.limits.LockoutMax; i++ {
+		d *= 2
+	}
+	if d > g.limits.LockoutMax {
+		d = g.limits.LockoutMax
+	}
+	return d
+}
+
+// Reset は、失敗回数とロックを解除する
+// ログインの成功時と、CLIからのロックの解除で使用する
+func (g *LoginGuardService) Reset(ctx context.Context, email string, ipAddress string) error {
+	for _, t := range g.targets(email, ipAddress) {
+		if err := g.kvs.Delete(ctx, failuresKey(t)); err != nil {
+			return fmt.Errorf("failed to delete login failures: %w", err)
+		}
+		if err := g.kvs.Delete(ctx, lockKey(t)); err != nil {
+			return fmt.Errorf("failed to delete login lock: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package login_guard_service_test
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/shoet/blog/internal/clocker"
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/infrastracture/services/login_guard_service"
+)
+
+// memoryKVS は、テスト用のインメモリのKVSer
+// 有効期限は扱わない
+type memoryKVS struct {
+	values map[string]string
+}
+
+func (m *memoryKVS) SaveWithTTL(ctx context.Context, key string, value string, ttl time.Duration) error {
+	m.values[key] = value
+	return nil
+}
+
+func (m *memoryKVS) Load(ctx context.Context, key string) (string, error) {
+	v, ok := m.values[key]
+	if !ok {
+		return "", fmt.Errorf("failed to get key: %w", redis.Nil)
+	}
+	return v, nil
+}
+
+func (m *memoryKVS) Delete(ctx context.Context, key string) error {
+	delete(m.values, key)
+	return nil
+}
+
+func (m *memoryKVS) Increment(ctx context.Context, key string, window time.Duration) (int64, error) {
+	n, _ := strconv.ParseInt(m.values[key], 10, 64)
+	n++
+	m.values[key] = strconv.FormatInt(n, 10)
+	return n, nil
+}
+
+type lockoutRepository struct {
+	lockouts []*models.LoginLockout
+}
+
+func (r *lockoutRepository) Add(
+	ctx context.Context, tx infrastracture.TX, lockout *models.LoginLockout,
+) (models.LoginLockoutId, error) {
+	r.lockouts = append(r.lockouts, lockout)
+	return models.LoginLockoutId(len(r.lockouts)), nil
+}
+
+func Test_LoginGuardService(t *testing.T) {
+	ctx := context.Background()
+	kvs := &memoryKVS{values: map[string]string{}}
+	repo := &lockoutRepository{}
+	sut := login_guard_service.NewLoginGuardService(nil, kvs, repo, &clocker.FiexedClocker{}, login_guard_service.Limits{
+		MaxFailuresPerEmail: 3,
+		MaxFailuresPerIp:    10,
+		FailureWindow:       time.Hour,
+		LockoutBase:         time.Minute,
+		LockoutMax:          3 * time.Minute,
+	})
+
+	// 上限に達するまではロックしない
+	for i := 0; i < 2; i++ {
+		locked, err := sut.RecordFailure(ctx, "Admin@example.com", "192.0.2.1", "agent")
+		if err != nil {
+			t.Fatalf("failed to record failure: %v", err)
+		}
+		if locked != 0 {
+			t.Fatalf("want not locked, but locked %s", locked)
+		}
+	}
+	if d, err := sut.Check(ctx, "admin@example.com", "192.0.2.1"); err != nil || d != 0 {
+		t.Fatalf("want not locked, but got %s, %v", d, err)
+	}
+
+	// 上限を超えるたびにロックの期間を2倍にする
+	for _, want := range []time.Duration{time.Minute, 2 * time.Minute, 3 * time.Minute, 3 * time.Minute} {
+		locked, err := sut.RecordFailure(ctx, "admin@example.com", "192.0.2.1", "agent")
+		if err != nil {
+			t.Fatalf("failed to record failure: %v", err)
+		}
+		if locked != want {
+			t.Errorf("want locked %s, but got %s", want, locked)
+		}
+	}
+	if d, err := sut.Check(ctx, "admin@example.com", ""); err != nil || d != 3*time.Minute {
+		t.Errorf("want retry after 3m, but got %s, %v", d, err)
+	}
+	// 他のメールアドレスはIPアドレスの上限に達するまでロックしない
+	if d, err := sut.Check(ctx, "other@example.com", "192.0.2.1"); err != nil || d != 0 {
+		t.Errorf("want not locked, but got %s, %v", d, err)
+	}
+	if len(repo.lockouts) != 4 {
+		t.Fatalf("want 4 lockouts, but got %d", len(repo.lockouts))
+	}
+	if l := repo.lockouts[0]; l.TargetType != models.LoginLockoutTargetEmail || l.Target != "admin@example.com" || l.Failures != 3 {
+		t.Errorf("unexpected lockout: %+v", l)
+	}
+
+	if err := sut.Reset(ctx, "admin@example.com", ""); err != nil {
+		t.Fatalf("failed to reset: %v", err)
+	}
+	if d, err := sut.Check(ctx, "admin@example.com", "192.0.2.1"); err != nil || d != 0 {
+		t.Errorf("want not locked after reset, but got %s, %v", d, err)
+	}
+	// リセット後は失敗回数を数え直す
+	if locked, err := sut.RecordFailure(ctx, "admin@example.com", "", ""); err != nil || locked != 0 {
+		t.Errorf("want not locked after reset, but got %s, %v", locked, err)
+	}
+}
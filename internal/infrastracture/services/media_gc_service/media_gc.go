@@ -0,0 +1,103 @@
+package media_gc_service
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/shoet/blog/internal/clocker"
+	"github.com/shoet/blog/internal/infrastracture/adapter"
+	"github.com/shoet/blog/internal/mediaref"
+)
+
+type StorageAdapter interface {
+	ListObjects(ctx context.Context, prefix string) ([]*adapter.ObjectInfo, error)
+	DeleteObject(ctx context.Context, key string) error
+}
+
+// variantSuffixPattern は、サムネイルの派生画像のファイル名の幅の接尾辞に一致する
+var variantSuffixPattern = regexp.MustCompile(`_w[0-9]+$`)
+
+// MediaGCService は、ブログから参照されなくなった画像をストレージから削除する
+// ブログの削除や画像の差し替えで残ったオブジェクトが対象となる
+type MediaGCService struct {
+	storage     StorageAdapter
+	clocker     clocker.Clocker
+	directories []string
+}
+
+func NewMediaGCService(storage StorageAdapter, clocker clocker.Clocker, directories ...string) *MediaGCService {
+	return &MediaGCService{
+		storage:     storage,
+		clocker:     clocker,
+		directories: directories,
+	}
+}
+
+// FindOrphans は、画像のディレクトリのオブジェクトのうち、referencesのいずれからも参照されていないものを返す
+// アップロード直後でまだブログに保存されていない画像を消さないよう、gracePeriod以内に更新されたものは除く
+func (s *MediaGCService) FindOrphans(
+	ctx context.Context, references []string, gracePeriod time.Duration,
+) ([]*adapter.ObjectInfo, error) {
+	referencedKeys := map[string]bool{}
+	for _, key := range mediaref.Extract(references...) {
+		referencedKeys[key] = true
+	}
+	threshold := s.clocker.Now().Add(-gracePeriod)
+
+	orphans := []*adapter.ObjectInfo{}
+	for _, directory := range s.directories {
+		objects, err := s.storage.ListObjects(ctx, strings.TrimSuffix(directory, "/")+"/")
+		if err != nil {
+			return nil, fmt.Errorf("failed to list objects: %w", err)
+		}
+		for _, o := range objects {
+			if !o.LastModified.Before(threshold) {
+				continue
+			}
+			if isReferenced(path.Base(o.Key), referencedKeys, references) {
+				continue
+			}
+			orphans = append(orphans, o)
+		}
+	}
+	return orphans, nil
+}
+
+// Delete は、オブジェクトを削除し、削除できたオブジェクトを返す
+// 削除に失敗したオブジェクトがあっても残りのオブジェクトの削除を続け、失敗したものをまとめてエラーとして返す
+func (s *MediaGCService) Delete(ctx context.Context, objects []*adapter.ObjectInfo) ([]*adapter.ObjectInfo, error) {
+	deleted := make([]*adapter.ObjectInfo, 0, len(objects))
+	failures := []string{}
+	for _, o := range objects {
+		if err := s.storage.DeleteObject(ctx, o.Key); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", o.Key, err))
+			continue
+		}
+		deleted = append(deleted, o)
+	}
+	if len(failures) > 0 {
+		return deleted, fmt.Errorf(
+			"failed to delete %d objects: %s", len(failures), strings.Join(failures, "; "))
+	}
+	return deleted, nil
+}
+
+// isReferenced は、ファイル名の画像が参照されているかを判定する
+// サーバーで生成したファイル名はキーで判定し、それ以前にアップロードされた画像は
+// 派生画像の接尾辞と拡張子を除いたファイル名が含まれていれば参照されているとみなす
+func isReferenced(fileName string, referencedKeys map[string]bool, references []string) bool {
+	if key, ok := mediaref.KeyOf(fileName); ok {
+		return referencedKeys[key]
+	}
+	stem := variantSuffixPattern.ReplaceAllString(strings.TrimSuffix(fileName, path.Ext(fileName)), "")
+	for _, r := range references {
+		if strings.Contains(r, stem+".") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package media_gc_service_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/shoet/blog/internal/clocker"
+	"github.com/shoet/blog/internal/config"
+	"github.com/shoet/blog/internal/infrastracture/adapter"
+	"github.com/shoet/blog/internal/infrastracture/services/media_gc_service"
+)
+
+func Test_MediaGCService(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	storage, err := adapter.NewLocalStorageAdapter(&config.Config{StorageLocalDir: dir}, clocker.NewFixedClocker())
+	if err != nil {
+		t.Fatalf("failed to create local storage adapter: %v", err)
+	}
+
+	// FixedClockerの現在時刻は2020-01-01
+	old := time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC)
+	recent := time.Date(2019, 12, 31, 23, 0, 0, 0, time.UTC)
+	objects := map[string]time.Time{
+		"thumbnail/0b7e1d2c-1a2b-4c3d-8e9f-0a1b2c3d4e5f.jpg":       old,
+		"thumbnail/0b7e1d2c-1a2b-4c3d-8e9f-0a1b2c3d4e5f_w320.webp": old,
+		"thumbnail/1c8f2e3d-2b3c-4d4e-9f0a-1b2c3d4e5f60.jpg":       old,
+		"thumbnail/1c8f2e3d-2b3c-4d4e-9f0a-1b2c3d4e5f60_w320.webp": old,
+		"content/2d9a3f4e-3c4d-4e5f-8a1b-2c3d4e5f6071.png":         recent,
+		"content/3e0b4a5f-4d5e-4f60-9b2c-3d4e5f607182.png":         old,
+		"content/sample.png":                             old,
+		"content/legacy.png":                             old,
+		"other/4f1c5b60-5e6f-4071-8c3d-4e5f60718293.png": old,
+	}
+	for key, modified := range objects {
+		if err := storage.PutObject(ctx, key, "image/png", strings.NewReader("image")); err != nil {
+			t.Fatalf("failed to put object: %v", err)
+		}
+		if err := os.Chtimes(filepath.Join(dir, filepath.FromSlash(key)), modified, modified); err != nil {
+			t.Fatalf("failed to change times: %v", err)
+		}
+	}
+
+	sut := media_gc_service.NewMediaGCService(storage, clocker.NewFixedClocker(), "thumbnail", "content/")
+	references := []string{
+		"https://cdn.example.com/thumbnail/0b7e1d2c-1a2b-4c3d-8e9f-0a1b2c3d4e5f.jpg",
+		"![image](https://cdn.example.com/content/sample.png)",
+	}
+	orphans, err := sut.FindOrphans(ctx, references, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("failed to find orphans: %v", err)
+	}
+	got := []string{}
+	for _, o := range orphans {
+		got = append(got, o.Key)
+	}
+	want := []string{
+		"thumbnail/1c8f2e3d-2b3c-4d4e-9f0a-1b2c3d4e5f60.jpg",
+		"thumbnail/1c8f2e3d-2b3c-4d4e-9f0a-1b2c3d4e5f60_w320.webp",
+		"content/3e0b4a5f-4d5e-4f60-9b2c-3d4e5f607182.png",
+		"content/legacy.png",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
+
+	deleted, err := sut.Delete(ctx, orphans)
+	if err != nil {
+		t.Fatalf("failed to delete orphans: %v", err)
+	}
+	if len(deleted) != len(orphans) {
+		t.Errorf("want %d deleted, but got %d", len(orphans), len(deleted))
+	}
+	remaining, err := storage.ListObjects(ctx, "")
+	if err != nil {
+		t.Fatalf("failed to list objects: %v", err)
+	}
+	if len(remaining) != len(objects)-len(want) {
+		t.Errorf("want %d objects, but got %d", len(objects)-len(want), len(remaining))
+	}
+	for _, key := range want {
+		if _, err := storage.HeadObject(ctx, key); !errors.Is(err, adapter.ErrObjectNotFound) {
+			t.Errorf("want %s deleted, but got %v", key, err)
+		}
+	}
+}
+
+// failingStorage は、指定したキーのオブジェクトの削除に失敗するストレージ
+type failingStorage struct {
+	media_gc_service.StorageAdapter
+	failKey string
+}
+
+func (s *failingStorage) DeleteObject(ctx context.Context, key string) error {
+	if key == s.failKey {
+		return errors.New("delete failed")
+	}
+	return s.StorageAdapter.DeleteObject(ctx, key)
+}
+
+func Test_MediaGCService_Delete_PartialFailure(t *testing.T) {
+	ctx := context.Background()
+	storage, err := adapter.NewLocalStorageAdapter(&config.Config{StorageLocalDir: t.TempDir()}, clocker.NewFixedClocker())
+	if err != nil {
+		t.Fatalf("failed to create local storage adapter: %v", err)
+	}
+	keys := []string{"content/first.png", "content/failed.png", "content/last.png"}
+	objects := make([]*adapter.ObjectInfo, 0, len(keys))
+	for _, key := range keys {
+		if err := storage.PutObject(ctx, key, "image/png", strings.NewReader("image")); err != nil {
+			t.Fatalf("failed to put object: %v", err)
+		}
+		objects = append(objects, &adapter.ObjectInfo{Key: key})
+	}
+	sut := media_gc_service.NewMediaGCService(
+		&failingStorage{StorageAdapter: storage, failKey: "content/failed.png"}, clocker.NewFixedClocker(), "content")
+
+	deleted, err := sut.Delete(ctx, objects)
+	if err == nil || !strings.Contains(err.Error(), "content/failed.png") {
+		t.Errorf("want error for the failed object, but got %v", err)
+	}
+	got := []string{}
+	for _, o := range deleted {
+		got = append(got, o.Key)
+	}
+	if diff := cmp.Diff([]string{"content/first.png", "content/last.png"}, got); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
+	if _, err := storage.HeadObject(ctx, "content/failed.png"); err != nil {
+		t.Errorf("want failed object kept, but got %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+package clientip
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Resolver は、信頼するプロキシの設定に従ってクライアントのIPアドレスを判定する
+// X-Forwarded-Forはクライアントが任意の値を設定できるため、
+// 直接の接続元が信頼するプロキシの場合のみ参照する
+type Resolver struct {
+	trustedProxies []*net.IPNet
+}
+
+// NewResolver は、カンマ区切りのIPアドレスまたはCIDRを信頼するプロキシとするResolverを返す
+// 空の場合はX-Forwarded-Forを参照しない
+func NewResolver(trustedProxies string) (*Resolver, error) {
+	nets := []*net.IPNet{}
+	for _, p := range strings.Split(trustedProxies, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if !strings.Contains(p, "/") {
+			ip := net.ParseIP(p)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy: %s", p)
+			}
+			bits := 32
+			if ip.To4() == nil {
+				bits = 128
+			}
+			p = fmt.Sprintf("%s/%d", p, bits)
+		}
+		_, n, err := net.ParseCIDR(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy: %s: %w", p, err)
+		}
+		nets = append(nets, n)
+	}
+	return &Resolver{trustedProxies: nets}, nil
+}
+
+// Resolve は、リクエスト元のクライアントのIPアドレスを返す
+// 接続元が信頼するプロキシの場合は、X-Forwarded-Forを右から辿り、最初の信頼しないアドレスを使用する
+func (r *Resolver) Resolve(req *http.Request) string {
+	remote := remoteIP(req)
+	if !r.trusted(remote) {
+		return remote
+	}
+	hops := []string{}
+	for _, v := range req.Header.Values("X-Forwarded-For") {
+		for _, hop := range strings.Split(v, ",") {
+			hops = append(hops, strings.TrimSpace(hop))
+		}
+	}
+	for i := len(hops) - 1; i >= 0; i-- {
+		if net.ParseIP(hops[i]) == nil {
+			// 解釈できないアドレスより左は信頼できないため、直前の信頼するアドレスとする
+			return remote
+		}
+		if !r.trusted(hops[i]) {
+			return hops[i]
+		}
+		remote = hops[i]
+	}
+	return remote
+}
+
+func (r *Resolver) trusted(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, n := range r.trustedProxies {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+type contextKey struct{}
+
+// Middleware は、判定したクライアントのIPアドレスをコンテキストに設定するミドルウェア
+func (r *Resolver) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := context.WithValue(req.Context(), contextKey{}, r.Resolve(req))
+		next.ServeHTTP(w, req.WithContext(ctx))
+	})
+}
+
+// FromRequest は、リクエスト元のクライアントのIPアドレスを取得する
+// Middlewareを適用していない場合は直接の接続元のアドレスを返す
+func FromRequest(r *http.Request) string {
+	if ip, ok := r.Context().Value(contextKey{}).(string); ok {
+		return ip
+	}
+	return remoteIP(r)
+}
+
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,102 @@
+package clientip_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shoet/blog/internal/interfaces/clientip"
+)
+
+func Test_Resolver_Resolve(t *testing.T) {
+	tests := []struct {
+		name           string
+		trustedProxies string
+		remoteAddr     string
+		xForwardedFor  string
+		want           string
+	}{
+		{name: "RemoteAddr", remoteAddr: "192.0.2.1:1234", want: "192.0.2.1"},
+		{name: "ポートなし", remoteAddr: "192.0.2.1", want: "192.0.2.1"},
+		{
+			name:          "信頼するプロキシがない場合はX-Forwarded-Forを無視する",
+			remoteAddr:    "192.0.2.1:1234",
+			xForwardedFor: "198.51.100.1",
+			want:          "192.0.2.1",
+		},
+		{
+			name:           "信頼しない接続元からのX-Forwarded-Forは無視する",
+			trustedProxies: "10.0.0.0/8",
+			remoteAddr:     "192.0.2.1:1234",
+			xForwardedFor:  "198.51.100.1",
+			want:           "192.0.2.1",
+		},
+		{
+			name:           "信頼するプロキシを除いた右端のアドレスを使用する",
+			trustedProxies: "10.0.0.0/8",
+			remoteAddr:     "10.0.0.1:1234",
+			xForwardedFor:  "203.0.113.9, 198.51.100.1, 10.0.0.2",
+			want:           "198.51.100.1",
+		},
+		{
+			name:           "クライアントが付与した左端のアドレスは使用しない",
+			trustedProxies: "10.0.0.1",
+			remoteAddr:     "10.0.0.1:1234",
+			xForwardedFor:  "203.0.113.9, 198.51.100.1",
+			want:           "198.51.100.1",
+		},
+		{
+			name:           "解釈できないアドレスがある場合は直前の信頼するアドレスとする",
+			trustedProxies: "10.0.0.0/8",
+			remoteAddr:     "10.0.0.1:1234",
+			xForwardedFor:  "unknown, 10.0.0.2",
+			want:           "10.0.0.2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sut, err := clientip.NewResolver(tt.trustedProxies)
+			if err != nil {
+				t.Fatalf("failed to create resolver: %v", err)
+			}
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = tt.remoteAddr
+			if tt.xForwardedFor != "" {
+				r.Header.Set("X-Forwarded-For", tt.xForwardedFor)
+			}
+			if got := sut.Resolve(r); got != tt.want {
+				t.Errorf("Resolve() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_FromRequest(t *testing.T) {
+	sut, err := clientip.NewResolver("10.0.0.0/8")
+	if err != nil {
+		t.Fatalf("failed to create resolver: %v", err)
+	}
+	r := httptest.NewRequest("GET", "/", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+	r.Header.Set("X-Forwarded-For", "198.51.100.1")
+
+	if got := clientip.FromRequest(r); got != "10.0.0.1" {
+		t.Errorf("FromRequest() without middleware = %s, want 10.0.0.1", got)
+	}
+	var got string
+	sut.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = clientip.FromRequest(r)
+	})).ServeHTTP(httptest.NewRecorder(), r)
+	if got != "198.51.100.1" {
+		t.Errorf("FromRequest() = %s, want 198.51.100.1", got)
+	}
+}
+
+func Test_NewResolver(t *testing.T) {
+	if _, err := clientip.NewResolver("10.0.0.0/8, 192.0.2.1, ::1"); err != nil {
+		t.Errorf("want nil, but got %v", err)
+	}
+	if _, err := clientip.NewResolver("proxy.example.com"); err == nil {
+		t.Errorf("want error, but got nil")
+	}
+}
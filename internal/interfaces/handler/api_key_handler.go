@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/interfaces/response"
+	"github.com/shoet/blog/internal/logging"
+	"github.com/shoet/blog/internal/session"
+	"github.com/shoet/blog/internal/usecase/create_api_key"
+	"github.com/shoet/blog/internal/usecase/get_api_keys"
+	"github.com/shoet/blog/internal/usecase/revoke_api_key"
+)
+
+type APIKeyListHandler struct {
+	Usecase *get_api_keys.Usecase
+}
+
+func NewAPIKeyListHandler(usecase *get_api_keys.Usecase) *APIKeyListHandler {
+	return &APIKeyListHandler{
+		Usecase: usecase,
+	}
+}
+
+func (a *APIKeyListHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+	userId, err := session.GetUserId(ctx)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to get user id: %v", err))
+		response.RespondUnauthorized(w, r, err)
+		return
+	}
+	keys, err := a.Usecase.Run(ctx, userId)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to list api keys: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	if err := response.RespondJSON(w, r, http.StatusOK, keys); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
+	}
+}
+
+type APIKeyAddHandler struct {
+	Usecase   *create_api_key.Usecase
+	Validator *validator.Validate
+}
+
+func NewAPIKeyAddHandler(usecase *create_api_key.Usecase, validator *validator.Validate) *APIKeyAddHandler {
+	return &APIKeyAddHandler{
+		Usecase:   usecase,
+		Validator: validator,
+	}
+}
+
+func (a *APIKeyAddHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+	userId, err := session.GetUserId(ctx)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to get user id: %v", err))
+		response.RespondUnauthorized(w, r, err)
+		return
+	}
+	var reqBody struct {
+		Name   string               `json:"name" validate:"required,max=255"`
+		Scopes []models.APIKeyScope `json:"scopes" validate:"required,min=1"`
+	}
+	defer r.Body.Close()
+	if err := response.JsonToStruct(r, &reqBody); err != nil {
+		logger.Error(fmt.Sprintf("failed to parse request body: %v", err))
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+
+	if err := a.Validator.Struct(reqBody); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request body: %v", err))
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+
+	result, err := a.Usecase.Run(ctx, userId, reqBody.Name, reqBody.Scopes)
+	if err != nil {
+		if errors.Is(err, create_api_key.ErrInvalidScope) {
+			response.ResponsdBadRequest(w, r, err)
+			return
+		}
+		logger.Error(fmt.Sprintf("failed to create api key: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	if err := response.RespondJSON(w, r, http.StatusOK, result); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
+	}
+}
+
+type APIKeyRevokeHandler struct {
+	Usecase *revoke_api_key.Usecase
+}
+
+func NewAPIKeyRevokeHandler(usecase *revoke_api_key.Usecase) *APIKeyRevokeHandler {
+	return &APIKeyRevokeHandler{
+		Usecase: usecase,
+	}
+}
+
+func (a *APIKeyRevokeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+	userId, err := session.GetUserId(ctx)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to get user id: %v", err))
+		response.RespondUnauthorized(w, r, err)
+		return
+	}
+	id, err := strconv.Atoi(strings.TrimSpace(chi.URLParam(r, "id")))
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to convert id to int: %v", err))
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+	if err := a.Usecase.Run(ctx, userId, models.APIKeyId(id)); err != nil {
+		if errors.Is(err, revoke_api_key.ErrAPIKeyNotFound) {
+			response.ResponsdNotFound(w, r, err)
+			return
+		}
+		logger.Error(fmt.Sprintf("failed to revoke api key: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	resp := struct {
+		Id models.APIKeyId `json:"id"`
+	}{
+		Id: models.APIKeyId(id),
+	}
+	if err := response.RespondJSON(w, r, http.StatusOK, resp); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
+	}
+}
@@ -1,15 +1,25 @@
 package handler
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/interfaces/clientip"
 	"github.com/shoet/blog/internal/interfaces/response"
 	"github.com/shoet/blog/internal/logging"
+	"github.com/shoet/blog/internal/session"
 	"github.com/shoet/blog/internal/usecase/login_user"
 	"github.com/shoet/blog/internal/usecase/login_user_session"
+	"github.com/shoet/blog/internal/usecase/logout_user"
+	"github.com/shoet/blog/internal/usecase/logout_user_all"
+	"github.com/shoet/blog/internal/usecase/refresh_token"
 )
 
 type AuthLoginHandler struct {
@@ -31,7 +41,7 @@ func NewAuthLoginHandler(
 }
 
 func (a *AuthLoginHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+	ctx := withClientInfo(r)
 	logger := logging.GetLogger(ctx)
 	var reqBody struct {
 		Email    string `json:"email" validate:"required"`
@@ -52,22 +62,79 @@ func (a *AuthLoginHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	token, err := a.Usecase.Run(ctx, reqBody.Email, reqBody.Password)
 	if err != nil {
+		var lockedErr *models.LoginLockedError
+		if errors.As(err, &lockedErr) {
+			respondLoginLocked(w, r, lockedErr)
+			return
+		}
 		logger.Error(fmt.Sprintf("failed login: %v", err))
 		response.RespondUnauthorized(w, r, err)
 		return
 	}
-	resp := struct {
-		AuthToken string `json:"authToken"`
-	}{
-		AuthToken: token,
+	// 2段階認証が必要な場合は、チャレンジトークンのみを返し、クッキーは設定しない
+	if !token.TwoFactorRequired {
+		if err := a.Cookie.SetCookie(w, "authToken", token.AccessToken); err != nil {
+			logger.Error(fmt.Sprintf("failed to set cookie: %v", err))
+			response.ResponsdInternalServerError(w, r, err)
+			return
+		}
+	}
+
+	if err := response.RespondJSON(w, r, http.StatusOK, token); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
+	}
+}
+
+type AuthRefreshHandler struct {
+	Usecase   *refresh_token.Usecase
+	Validator *validator.Validate
+	Cookie    Cookier
+}
+
+func NewAuthRefreshHandler(
+	usecase *refresh_token.Usecase,
+	validator *validator.Validate,
+	cookie Cookier,
+) *AuthRefreshHandler {
+	return &AuthRefreshHandler{
+		Usecase:   usecase,
+		Validator: validator,
+		Cookie:    cookie,
+	}
+}
+
+func (a *AuthRefreshHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := withClientInfo(r)
+	logger := logging.GetLogger(ctx)
+	var reqBody struct {
+		RefreshToken string `json:"refreshToken" validate:"required"`
+	}
+	defer r.Body.Close()
+	if err := response.JsonToStruct(r, &reqBody); err != nil {
+		logger.Error(fmt.Sprintf("failed to parse request body: %v", err))
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+
+	if err := a.Validator.Struct(reqBody); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request body: %v", err))
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+
+	token, err := a.Usecase.Run(ctx, reqBody.RefreshToken)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed refresh: %v", err))
+		response.RespondUnauthorized(w, r, err)
+		return
 	}
-	if err := a.Cookie.SetCookie(w, "authToken", resp.AuthToken); err != nil {
+	if err := a.Cookie.SetCookie(w, "authToken", token.AccessToken); err != nil {
 		logger.Error(fmt.Sprintf("failed to set cookie: %v", err))
 		response.ResponsdInternalServerError(w, r, err)
 		return
 	}
 
-	if err := response.RespondJSON(w, r, http.StatusOK, resp); err != nil {
+	if err := response.RespondJSON(w, r, http.StatusOK, token); err != nil {
 		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
 	}
 }
@@ -116,20 +183,31 @@ func (a *AuthSessionLoginHandler) ServeHTTP(w http.ResponseWriter, r *http.Reque
 }
 
 type AuthLogoutHandler struct {
-	Cookie Cookier
+	Usecase *logout_user.Usecase
+	Cookie  Cookier
 }
 
 func NewAuthLogoutHandler(
+	usecase *logout_user.Usecase,
 	cookie Cookier,
 ) *AuthLogoutHandler {
 	return &AuthLogoutHandler{
-		Cookie: cookie,
+		Usecase: usecase,
+		Cookie:  cookie,
 	}
 }
 
 func (a *AuthLogoutHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	logger := logging.GetLogger(ctx)
+	// セッションを失効させ、トークンが漏洩していても利用できないようにする
+	if token := requestToken(r); token != "" {
+		if err := a.Usecase.Run(ctx, token); err != nil {
+			logger.Error(fmt.Sprintf("failed logout: %v", err))
+			response.ResponsdInternalServerError(w, r, err)
+			return
+		}
+	}
 	a.Cookie.ClearCookie(w, "authToken")
 	resp := struct {
 		Message string `json:"message"`
@@ -140,3 +218,72 @@ func (a *AuthLogoutHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
 	}
 }
+
+type AuthLogoutAllHandler struct {
+	Usecase *logout_user_all.Usecase
+	Cookie  Cookier
+}
+
+func NewAuthLogoutAllHandler(
+	usecase *logout_user_all.Usecase,
+	cookie Cookier,
+) *AuthLogoutAllHandler {
+	return &AuthLogoutAllHandler{
+		Usecase: usecase,
+		Cookie:  cookie,
+	}
+}
+
+func (a *AuthLogoutAllHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+	userId, err := session.GetUserId(ctx)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to get user id: %v", err))
+		response.RespondUnauthorized(w, r, err)
+		return
+	}
+	if err := a.Usecase.Run(ctx, userId); err != nil {
+		logger.Error(fmt.Sprintf("failed logout all sessions: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	a.Cookie.ClearCookie(w, "authToken")
+	resp := struct {
+		Message string `json:"message"`
+	}{
+		Message: "success",
+	}
+	if err := response.RespondJSON(w, r, http.StatusOK, resp); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
+	}
+}
+
+// requestToken は、Authorizationヘッダまたはクッキーから認証トークンを取得する
+// どちらにもない場合は空文字を返す
+func requestToken(r *http.Request) string {
+	if token := r.Header.Get("Authorization"); strings.HasPrefix(token, "Bearer ") {
+		return strings.TrimPrefix(token, "Bearer ")
+	}
+	if c, err := r.Cookie("authToken"); err == nil {
+		return c.Value
+	}
+	return ""
+}
+
+// withClientInfo は、セッションに記録するクライアントの情報をコンテキストに設定する
+// IPアドレスはログイン試行のIPアドレスごとのロックにも使用するため、クライアントが偽装できる
+// X-Forwarded-Forは信頼するプロキシを経由した場合のみ参照する(clientip.Resolver)
+func withClientInfo(r *http.Request) context.Context {
+	return session.SetClientInfo(r.Context(), &session.ClientInfo{
+		UserAgent: r.UserAgent(),
+		IpAddress: clientip.FromRequest(r),
+	})
+}
+
+// respondLoginLocked は、ロック中のログインの試行に429とRetry-Afterを返す
+func respondLoginLocked(w http.ResponseWriter, r *http.Request, err *models.LoginLockedError) {
+	retryAfter := int(math.Ceil(err.RetryAfter.Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	response.RespondTooManyRequests(w, r, err)
+}
@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/shoet/blog/internal/interfaces/response"
+	"github.com/shoet/blog/internal/logging"
+	"github.com/shoet/blog/internal/session"
+	"github.com/shoet/blog/internal/usecase/change_password"
+	"github.com/shoet/blog/internal/usecase/request_password_reset"
+	"github.com/shoet/blog/internal/usecase/reset_password"
+)
+
+type AuthPasswordChangeHandler struct {
+	Usecase   *change_password.Usecase
+	Validator *validator.Validate
+	Cookie    Cookier
+}
+
+func NewAuthPasswordChangeHandler(
+	usecase *change_password.Usecase,
+	validator *validator.Validate,
+	cookie Cookier,
+) *AuthPasswordChangeHandler {
+	return &AuthPasswordChangeHandler{
+		Usecase:   usecase,
+		Validator: validator,
+		Cookie:    cookie,
+	}
+}
+
+func (a *AuthPasswordChangeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+	userId, err := session.GetUserId(ctx)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to get user id: %v", err))
+		response.RespondUnauthorized(w, r, err)
+		return
+	}
+	var reqBody struct {
+		CurrentPassword string `json:"currentPassword" validate:"required"`
+		NewPassword     string `json:"newPassword" validate:"required,min=8"`
+	}
+	defer r.Body.Close()
+	if err := response.JsonToStruct(r, &reqBody); err != nil {
+		logger.Error(fmt.Sprintf("failed to parse request body: %v", err))
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+
+	if err := a.Validator.Struct(reqBody); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request body: %v", err))
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+
+	if err := a.Usecase.Run(ctx, userId, reqBody.CurrentPassword, reqBody.NewPassword); err != nil {
+		if errors.Is(err, change_password.ErrPasswordMismatch) {
+			response.ResponsdBadRequest(w, r, err)
+			return
+		}
+		logger.Error(fmt.Sprintf("failed to change password: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	// 既存のセッションは失効しているため、再度ログインさせる
+	a.Cookie.ClearCookie(w, "authToken")
+	resp := struct {
+		Message string `json:"message"`
+	}{
+		Message: "success",
+	}
+	if err := response.RespondJSON(w, r, http.StatusOK, resp); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
+	}
+}
+
+type AuthPasswordResetRequestHandler struct {
+	Usecase   *request_password_reset.Usecase
+	Validator *validator.Validate
+}
+
+func NewAuthPasswordResetRequestHandler(
+	usecase *request_password_reset.Usecase,
+	validator *validator.Validate,
+) *AuthPasswordResetRequestHandler {
+	return &AuthPasswordResetRequestHandler{
+		Usecase:   usecase,
+		Validator: validator,
+	}
+}
+
+func (a *AuthPasswordResetRequestHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+	var reqBody struct {
+		Email string `json:"email" validate:"required,email"`
+	}
+	defer r.Body.Close()
+	if err := response.JsonToStruct(r, &reqBody); err != nil {
+		logger.Error(fmt.Sprintf("failed to parse request body: %v", err))
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+
+	if err := a.Validator.Struct(reqBody); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request body: %v", err))
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+
+	if err := a.Usecase.Run(ctx, reqBody.Email); err != nil {
+		logger.Error(fmt.Sprintf("failed to request password reset: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	// メールアドレスの登録有無に関わらず同じ応答を返す
+	resp := struct {
+		Message string `json:"message"`
+	}{
+		Message: "accepted",
+	}
+	if err := response.RespondJSON(w, r, http.StatusAccepted, resp); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
+	}
+}
+
+type AuthPasswordResetConfirmHandler struct {
+	Usecase   *reset_password.Usecase
+	Validator *validator.Validate
+}
+
+func NewAuthPasswordResetConfirmHandler(
+	usecase *reset_password.Usecase,
+	validator *validator.Validate,
+) *AuthPasswordResetConfirmHandler {
+	return &AuthPasswordResetConfirmHandler{
+		Usecase:   usecase,
+		Validator: validator,
+	}
+}
+
+func (a *AuthPasswordResetConfirmHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+	var reqBody struct {
+		Token       string `json:"token" validate:"required"`
+		NewPassword string `json:"newPassword" validate:"required,min=8"`
+	}
+	defer r.Body.Close()
+	if err := response.JsonToStruct(r, &reqBody); err != nil {
+		logger.Error(fmt.Sprintf("failed to parse request body: %v", err))
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+
+	if err := a.Validator.Struct(reqBody); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request body: %v", err))
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+
+	if err := a.Usecase.Run(ctx, reqBody.Token, reqBody.NewPassword); err != nil {
+		if errors.Is(err, reset_password.ErrTokenInvalid) {
+			response.ResponsdBadRequest(w, r, err)
+			return
+		}
+		logger.Error(fmt.Sprintf("failed to reset password: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	resp := struct {
+		Message string `json:"message"`
+	}{
+		Message: "success",
+	}
+	if err := response.RespondJSON(w, r, http.StatusOK, resp); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
+	}
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shoet/blog/internal/interfaces/response"
+	"github.com/shoet/blog/internal/logging"
+	"github.com/shoet/blog/internal/session"
+	"github.com/shoet/blog/internal/usecase/get_sessions"
+	"github.com/shoet/blog/internal/usecase/revoke_session"
+)
+
+type AuthSessionListHandler struct {
+	Usecase *get_sessions.Usecase
+}
+
+func NewAuthSessionListHandler(usecase *get_sessions.Usecase) *AuthSessionListHandler {
+	return &AuthSessionListHandler{
+		Usecase: usecase,
+	}
+}
+
+func (a *AuthSessionListHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+	userId, err := session.GetUserId(ctx)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to get user id: %v", err))
+		response.RespondUnauthorized(w, r, err)
+		return
+	}
+	sessions, err := a.Usecase.Run(ctx, userId, requestToken(r))
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to list sessions: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	if err := response.RespondJSON(w, r, http.StatusOK, sessions); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
+	}
+}
+
+type AuthSessionRevokeHandler struct {
+	Usecase *revoke_session.Usecase
+}
+
+func NewAuthSessionRevokeHandler(usecase *revoke_session.Usecase) *AuthSessionRevokeHandler {
+	return &AuthSessionRevokeHandler{
+		Usecase: usecase,
+	}
+}
+
+func (a *AuthSessionRevokeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+	userId, err := session.GetUserId(ctx)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to get user id: %v", err))
+		response.RespondUnauthorized(w, r, err)
+		return
+	}
+	sessionId := chi.URLParam(r, "jti")
+	if err := a.Usecase.Run(ctx, userId, requestToken(r), sessionId); err != nil {
+		if errors.Is(err, revoke_session.ErrSessionNotFound) {
+			response.ResponsdNotFound(w, r, err)
+			return
+		}
+		logger.Error(fmt.Sprintf("failed to revoke session: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	resp := struct {
+		Id string `json:"id"`
+	}{
+		Id: sessionId,
+	}
+	if err := response.RespondJSON(w, r, http.StatusOK, resp); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
+	}
+}
@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/interfaces/response"
+	"github.com/shoet/blog/internal/logging"
+	"github.com/shoet/blog/internal/session"
+	"github.com/shoet/blog/internal/usecase/activate_totp"
+	"github.com/shoet/blog/internal/usecase/login_user_two_factor"
+	"github.com/shoet/blog/internal/usecase/setup_totp"
+)
+
+type AuthLoginTwoFactorHandler struct {
+	Usecase   *login_user_two_factor.Usecase
+	Validator *validator.Validate
+	Cookie    Cookier
+}
+
+func NewAuthLoginTwoFactorHandler(
+	usecase *login_user_two_factor.Usecase,
+	validator *validator.Validate,
+	cookie Cookier,
+) *AuthLoginTwoFactorHandler {
+	return &AuthLoginTwoFactorHandler{
+		Usecase:   usecase,
+		Validator: validator,
+		Cookie:    cookie,
+	}
+}
+
+func (a *AuthLoginTwoFactorHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := withClientInfo(r)
+	logger := logging.GetLogger(ctx)
+	var reqBody struct {
+		ChallengeToken string `json:"challengeToken" validate:"required"`
+		// Code は認証アプリのワンタイムパスワードまたはリカバリーコード
+		Code string `json:"code" validate:"required"`
+	}
+	defer r.Body.Close()
+	if err := response.JsonToStruct(r, &reqBody); err != nil {
+		logger.Error(fmt.Sprintf("failed to parse request body: %v", err))
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+
+	if err := a.Validator.Struct(reqBody); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request body: %v", err))
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+
+	token, err := a.Usecase.Run(ctx, reqBody.ChallengeToken, reqBody.Code)
+	if err != nil {
+		var lockedErr *models.LoginLockedError
+		if errors.As(err, &lockedErr) {
+			respondLoginLocked(w, r, lockedErr)
+			return
+		}
+		logger.Error(fmt.Sprintf("failed two factor login: %v", err))
+		response.RespondUnauthorized(w, r, err)
+		return
+	}
+	if err := a.Cookie.SetCookie(w, "authToken", token.AccessToken); err != nil {
+		logger.Error(fmt.Sprintf("failed to set cookie: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+
+	if err := response.RespondJSON(w, r, http.StatusOK, token); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
+	}
+}
+
+type AuthTOTPSetupHandler struct {
+	Usecase *setup_totp.Usecase
+}
+
+func NewAuthTOTPSetupHandler(usecase *setup_totp.Usecase) *AuthTOTPSetupHandler {
+	return &AuthTOTPSetupHandler{
+		Usecase: usecase,
+	}
+}
+
+func (a *AuthTOTPSetupHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+	userId, err := session.GetUserId(ctx)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to get user id: %v", err))
+		response.RespondUnauthorized(w, r, err)
+		return
+	}
+	setup, err := a.Usecase.Run(ctx, userId)
+	if err != nil {
+		if errors.Is(err, setup_totp.ErrAlreadyEnabled) {
+			response.ResponsdBadRequest(w, r, err)
+			return
+		}
+		logger.Error(fmt.Sprintf("failed to setup totp: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	if err := response.RespondJSON(w, r, http.StatusOK, setup); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
+	}
+}
+
+type AuthTOTPActivateHandler struct {
+	Usecase   *activate_totp.Usecase
+	Validator *validator.Validate
+}
+
+func NewAuthTOTPActivateHandler(
+	usecase *activate_totp.Usecase, validator *validator.Validate,
+) *AuthTOTPActivateHandler {
+	return &AuthTOTPActivateHandler{
+		Usecase:   usecase,
+		Validator: validator,
+	}
+}
+
+func (a *AuthTOTPActivateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+	userId, err := session.GetUserId(ctx)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to get user id: %v", err))
+		response.RespondUnauthorized(w, r, err)
+		return
+	}
+	var reqBody struct {
+		Code string `json:"code" validate:"required"`
+	}
+	defer r.Body.Close()
+	if err := response.JsonToStruct(r, &reqBody); err != nil {
+		logger.Error(fmt.Sprintf("failed to parse request body: %v", err))
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+
+	if err := a.Validator.Struct(reqBody); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request body: %v", err))
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+
+	codes, err := a.Usecase.Run(ctx, userId, reqBody.Code)
+	if err != nil {
+		if errors.Is(err, activate_totp.ErrAlreadyEnabled) ||
+			errors.Is(err, activate_totp.ErrNotSetup) ||
+			errors.Is(err, activate_totp.ErrCodeInvalid) {
+			response.ResponsdBadRequest(w, r, err)
+			return
+		}
+		logger.Error(fmt.Sprintf("failed to activate totp: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	resp := struct {
+		RecoveryCodes []string `json:"recoveryCodes"`
+	}{
+		RecoveryCodes: codes,
+	}
+	if err := response.RespondJSON(w, r, http.StatusOK, resp); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
+	}
+}
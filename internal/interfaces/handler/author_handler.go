@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/interfaces/response"
+	"github.com/shoet/blog/internal/logging"
+	"github.com/shoet/blog/internal/session"
+	"github.com/shoet/blog/internal/usecase/get_author"
+	"github.com/shoet/blog/internal/usecase/put_profile"
+)
+
+type AuthorGetHandler struct {
+	Usecase *get_author.Usecase
+}
+
+func NewAuthorGetHandler(usecase *get_author.Usecase) *AuthorGetHandler {
+	return &AuthorGetHandler{
+		Usecase: usecase,
+	}
+}
+
+func (h *AuthorGetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+	id, err := userIdFromURL(r)
+	if err != nil {
+		logger.Error(err.Error())
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+	var limit *int64
+	if v := r.URL.Query().Get("limit"); v != "" {
+		l, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			err := fmt.Errorf("limit is invalid")
+			logger.Error(err.Error())
+			response.ResponsdBadRequest(w, r, err)
+			return
+		}
+		limit = &l
+	}
+	output, err := h.Usecase.Run(ctx, id, limit)
+	if err != nil {
+		if errors.Is(err, get_author.ErrAuthorNotFound) {
+			response.ResponsdNotFound(w, r, err)
+			return
+		}
+		logger.Error(fmt.Sprintf("failed to get author: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	if err := response.RespondJSON(w, r, http.StatusOK, output); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
+	}
+}
+
+type AuthProfilePutHandler struct {
+	Usecase   *put_profile.Usecase
+	Validator *validator.Validate
+}
+
+func NewAuthProfilePutHandler(usecase *put_profile.Usecase, validator *validator.Validate) *AuthProfilePutHandler {
+	return &AuthProfilePutHandler{
+		Usecase:   usecase,
+		Validator: validator,
+	}
+}
+
+func (h *AuthProfilePutHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+	userId, err := session.GetUserId(ctx)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to get user id: %v", err))
+		response.RespondUnauthorized(w, r, err)
+		return
+	}
+	type SocialLink struct {
+		Service string `json:"service" validate:"required,max=64"`
+		URL     string `json:"url" validate:"required,http_url,max=2048"`
+	}
+	var reqBody struct {
+		DisplayName string        `json:"displayName" validate:"max=255"`
+		Bio         string        `json:"bio" validate:"max=4000"`
+		AvatarURL   string        `json:"avatarUrl" validate:"omitempty,http_url,max=2048"`
+		SocialLinks []*SocialLink `json:"socialLinks" validate:"max=10,dive,required"`
+	}
+	defer r.Body.Close()
+	if err := response.JsonToStruct(r, &reqBody); err != nil {
+		logger.Error(fmt.Sprintf("failed to parse request body: %v", err))
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+	if err := h.Validator.Struct(reqBody); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request body: %v", err))
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+	profile := &models.Profile{
+		DisplayName: reqBody.DisplayName,
+		Bio:         reqBody.Bio,
+		AvatarURL:   reqBody.AvatarURL,
+	}
+	for _, l := range reqBody.SocialLinks {
+		profile.SocialLinks = append(profile.SocialLinks, &models.SocialLink{Service: l.Service, URL: l.URL})
+	}
+	user, err := h.Usecase.Run(ctx, userId, profile)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to put profile: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	if err := response.RespondJSON(w, r, http.StatusOK, user); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
+	}
+}
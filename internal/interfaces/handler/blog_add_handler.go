@@ -1,11 +1,13 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"github.com/go-playground/validator/v10"
 	"github.com/shoet/blog/internal/infrastracture/models"
 	"github.com/shoet/blog/internal/interfaces/response"
 	"github.com/shoet/blog/internal/logging"
+	"github.com/shoet/blog/internal/policy"
 	"github.com/shoet/blog/internal/usecase/create_blog"
 	"net/http"
 )
@@ -30,11 +32,13 @@ func (a *BlogAddHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	logger := logging.GetLogger(ctx)
 	var reqBody struct {
 		Title                  string        `json:"title" validate:"required"`
+		Slug                   string        `json:"slug"`
 		Content                string        `json:"content" validate:"required"`
 		Description            string        `json:"description" validate:"required"`
 		AuthorId               models.UserId `json:"authorId" validate:"required"`
 		ThumbnailImageFileName string        `json:"thumbnailImageFileName"`
 		IsPublic               bool          `json:"isPublic" default:"false"`
+		PublishAt              uint          `json:"publishAt"`
 		Tags                   []string      `json:"tags" default:"[]"`
 	}
 	defer r.Body.Close()
@@ -52,16 +56,22 @@ func (a *BlogAddHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	blog := &models.Blog{
 		Title:                  reqBody.Title,
+		Slug:                   reqBody.Slug,
 		Content:                reqBody.Content,
 		Description:            reqBody.Description,
 		AuthorId:               reqBody.AuthorId,
 		ThumbnailImageFileName: reqBody.ThumbnailImageFileName,
 		IsPublic:               reqBody.IsPublic,
+		PublishAt:              reqBody.PublishAt,
 		Tags:                   reqBody.Tags,
 	}
 
 	newBlog, err := a.Usecase.Run(ctx, blog)
 	if err != nil {
+		if errors.Is(err, policy.ErrForbidden) {
+			response.RespondForbidden(w, r, err)
+			return
+		}
 		logger.Error(fmt.Sprintf("failed to add blog: %v", err))
 		response.ResponsdInternalServerError(w, r, err)
 		return
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -11,6 +12,7 @@ import (
 	"github.com/shoet/blog/internal/infrastracture/models"
 	"github.com/shoet/blog/internal/interfaces/response"
 	"github.com/shoet/blog/internal/logging"
+	"github.com/shoet/blog/internal/policy"
 	"github.com/shoet/blog/internal/usecase/delete_blog"
 )
 
@@ -47,6 +49,10 @@ func (d *BlogDeleteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 	blogId, err := d.Usecase.Run(ctx, models.BlogId(idInt))
 	if err != nil {
+		if errors.Is(err, policy.ErrForbidden) {
+			response.RespondForbidden(w, r, err)
+			return
+		}
 		logger.Error(fmt.Sprintf("failed to delete blog: %v", err))
 		response.ResponsdInternalServerError(w, r, err)
 		return
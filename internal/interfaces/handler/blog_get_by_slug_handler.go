@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shoet/blog/internal/clocker"
+	"github.com/shoet/blog/internal/interfaces/response"
+	"github.com/shoet/blog/internal/logging"
+	"github.com/shoet/blog/internal/usecase/get_blog_by_slug"
+)
+
+type BlogGetBySlugHandler struct {
+	Usecase *get_blog_by_slug.Usecase
+	jwter   JWTService
+	clocker clocker.Clocker
+}
+
+func NewBlogGetBySlugHandler(
+	usecase *get_blog_by_slug.Usecase, jwter JWTService, clocker clocker.Clocker,
+) *BlogGetBySlugHandler {
+	return &BlogGetBySlugHandler{
+		Usecase: usecase,
+		jwter:   jwter,
+		clocker: clocker,
+	}
+}
+
+func (l *BlogGetBySlugHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+	slug := strings.TrimSpace(chi.URLParam(r, "slug"))
+	if slug == "" {
+		logger.Error("failed to get slug from url")
+		response.ResponsdBadRequest(w, r, nil)
+		return
+	}
+	renderHTML, err := parseRenderQuery(r)
+	if err != nil {
+		logger.Error(err.Error())
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+	output, err := l.Usecase.Run(ctx, slug)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to get blog: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	if output.Blog == nil {
+		response.ResponsdNotFound(w, r, err)
+		return
+	}
+	// 非公開・公開日時前のBlogは認証が必要
+	if !output.Blog.IsPublished(l.clocker.Now()) {
+		if err := verifyAuthorizationHeader(r, l.jwter); err != nil {
+			logger.Error(err.Error())
+			response.ResponsdNotFound(w, r, err)
+			return
+		}
+	}
+	// 変更前のスラッグでアクセスされた場合は現在のスラッグへリダイレクトする
+	if output.RedirectSlug != nil {
+		location := "/blogs/by-slug/" + url.PathEscape(*output.RedirectSlug)
+		if r.URL.RawQuery != "" {
+			location += "?" + r.URL.RawQuery
+		}
+		w.Header().Set("Location", location)
+		resp := struct {
+			Slug string `json:"slug"`
+		}{
+			Slug: *output.RedirectSlug,
+		}
+		if err := response.RespondJSON(w, r, http.StatusMovedPermanently, resp); err != nil {
+			logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
+		}
+		return
+	}
+	if !renderHTML {
+		output.Blog.ContentHTML = ""
+	}
+	if err := response.RespondJSON(w, r, http.StatusOK, output.Blog); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/shoet/blog/internal/clocker"
 	"github.com/shoet/blog/internal/infrastracture/models"
 	"github.com/shoet/blog/internal/interfaces/response"
 	"github.com/shoet/blog/internal/logging"
@@ -16,12 +17,16 @@ import (
 type BlogGetHandler struct {
 	Usecase *get_blog_detail.Usecase
 	jwter   JWTService
+	clocker clocker.Clocker
 }
 
-func NewBlogGetHandler(usecase *get_blog_detail.Usecase, jwter JWTService) *BlogGetHandler {
+func NewBlogGetHandler(
+	usecase *get_blog_detail.Usecase, jwter JWTService, clocker clocker.Clocker,
+) *BlogGetHandler {
 	return &BlogGetHandler{
 		Usecase: usecase,
 		jwter:   jwter,
+		clocker: clocker,
 	}
 }
 
@@ -40,6 +45,12 @@ func (l *BlogGetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		response.ResponsdBadRequest(w, r, err)
 		return
 	}
+	renderHTML, err := parseRenderQuery(r)
+	if err != nil {
+		logger.Error(err.Error())
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
 	blog, err := l.Usecase.Run(ctx, models.BlogId(idInt))
 	if err != nil {
 		logger.Error(fmt.Sprintf("failed to get blog: %v", err))
@@ -50,30 +61,48 @@ func (l *BlogGetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		response.ResponsdNotFound(w, r, err)
 		return
 	}
-	// 非公開のBlogは認証が必要
-	if !blog.IsPublic {
-		token := r.Header.Get("Authorization")
-		if token == "" {
-			logger.Error("failed to get authorization header")
-			response.ResponsdNotFound(w, r, err)
-			return
-		}
-
-		if !strings.HasPrefix(token, "Bearer ") {
-			logger.Error("failed to get authorization token")
-			response.ResponsdNotFound(w, r, err)
-			return
-		}
-
-		token = strings.TrimPrefix(token, "Bearer ")
-		_, err := l.jwter.VerifyToken(ctx, token)
-		if err != nil {
-			logger.Error(fmt.Sprintf("failed to verify token: %v", err))
+	// 非公開・公開日時前のBlogは認証が必要
+	if !blog.IsPublished(l.clocker.Now()) {
+		if err := verifyAuthorizationHeader(r, l.jwter); err != nil {
+			logger.Error(err.Error())
 			response.ResponsdNotFound(w, r, err)
 			return
 		}
 	}
+	if !renderHTML {
+		blog.ContentHTML = ""
+	}
 	if err := response.RespondJSON(w, r, http.StatusOK, blog); err != nil {
 		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
 	}
 }
+
+// verifyAuthorizationHeader は、Authorizationヘッダのトークンを検証する
+// 非公開のBlogを認証済みのユーザーにのみ返却するために使用する
+func verifyAuthorizationHeader(r *http.Request, jwter JWTService) error {
+	token := r.Header.Get("Authorization")
+	if token == "" {
+		return fmt.Errorf("failed to get authorization header")
+	}
+	if !strings.HasPrefix(token, "Bearer ") {
+		return fmt.Errorf("failed to get authorization token")
+	}
+	token = strings.TrimPrefix(token, "Bearer ")
+	if _, err := jwter.VerifyToken(r.Context(), token); err != nil {
+		return fmt.Errorf("failed to verify token: %w", err)
+	}
+	return nil
+}
+
+// parseRenderQuery は、本文をレンダリング済みのHTMLで返却するかをrenderクエリから判定する
+// render=html の場合のみHTMLを返却し、それ以外はMarkdownのみを返却する
+func parseRenderQuery(r *http.Request) (bool, error) {
+	switch render := r.URL.Query().Get("render"); render {
+	case "":
+		return false, nil
+	case "html":
+		return true, nil
+	default:
+		return false, fmt.Errorf("unsupported render format: %s", render)
+	}
+}
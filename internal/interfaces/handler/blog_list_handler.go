@@ -36,6 +36,18 @@ func (l *BlogListHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if keyword != "" {
 		input.KeyWord = &keyword
 	}
+	authorId := v.Get("authorId") // 著者で絞り込む
+	if authorId != "" {
+		v, err := strconv.Atoi(authorId)
+		if err != nil {
+			err := fmt.Errorf("authorId is invalid")
+			logger.Error(err.Error())
+			response.ResponsdBadRequest(w, r, err)
+			return
+		}
+		userId := models.UserId(v)
+		input.AuthorId = &userId
+	}
 	cursor_id := v.Get("cursor_id") // ページネーションのカーソルID
 	if cursor_id != "" {
 		v, err := strconv.Atoi(cursor_id)
@@ -1,11 +1,13 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"github.com/go-playground/validator/v10"
 	"github.com/shoet/blog/internal/infrastracture/models"
 	"github.com/shoet/blog/internal/interfaces/response"
 	"github.com/shoet/blog/internal/logging"
+	"github.com/shoet/blog/internal/policy"
 	"github.com/shoet/blog/internal/usecase/put_blog"
 	"net/http"
 )
@@ -32,10 +34,12 @@ func (p *BlogPutHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		Id                     models.BlogId `json:"id" validate:"required"`
 		AuthorId               models.UserId `json:"authorId" validate:"required"`
 		Title                  string        `json:"title"`
+		Slug                   string        `json:"slug"`
 		Content                string        `json:"content"`
 		Description            string        `json:"description"`
 		ThumbnailImageFileName string        `json:"thumbnailImageFileName"`
 		IsPublic               bool          `json:"isPublic"`
+		PublishAt              uint          `json:"publishAt"`
 		Tags                   []string      `json:"tags"`
 	}
 	defer r.Body.Close()
@@ -55,15 +59,21 @@ func (p *BlogPutHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		Id:                     reqBody.Id,
 		AuthorId:               reqBody.AuthorId,
 		Title:                  reqBody.Title,
+		Slug:                   reqBody.Slug,
 		Content:                reqBody.Content,
 		Description:            reqBody.Description,
 		ThumbnailImageFileName: reqBody.ThumbnailImageFileName,
 		IsPublic:               reqBody.IsPublic,
+		PublishAt:              reqBody.PublishAt,
 		Tags:                   reqBody.Tags,
 	}
 
 	newBlog, err := p.Usecase.Run(ctx, blog)
 	if err != nil {
+		if errors.Is(err, policy.ErrForbidden) {
+			response.RespondForbidden(w, r, err)
+			return
+		}
 		logger.Error(fmt.Sprintf("failed to put blog: %v", err))
 		response.ResponsdInternalServerError(w, r, err)
 		return
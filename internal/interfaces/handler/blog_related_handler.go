@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/interfaces/response"
+	"github.com/shoet/blog/internal/logging"
+	"github.com/shoet/blog/internal/usecase/get_related_blogs"
+)
+
+const (
+	relatedBlogsDefaultLimit = 5
+	relatedBlogsMaxLimit     = 20
+)
+
+type BlogRelatedHandler struct {
+	Usecase *get_related_blogs.Usecase
+}
+
+func NewBlogRelatedHandler(usecase *get_related_blogs.Usecase) *BlogRelatedHandler {
+	return &BlogRelatedHandler{
+		Usecase: usecase,
+	}
+}
+
+func (h *BlogRelatedHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+	id := chi.URLParam(r, "id")
+	idInt, err := strconv.Atoi(strings.TrimSpace(id))
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to convert id to int: %v", err))
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+	var limit int64 = relatedBlogsDefaultLimit
+	if l := r.URL.Query().Get("limit"); l != "" {
+		limit, err = strconv.ParseInt(l, 10, 64)
+		if err != nil || limit < 1 || limit > relatedBlogsMaxLimit {
+			err := fmt.Errorf("limit must be between 1 and %d", relatedBlogsMaxLimit)
+			logger.Error(err.Error())
+			response.ResponsdBadRequest(w, r, err)
+			return
+		}
+	}
+	blogs, err := h.Usecase.Run(ctx, models.BlogId(idInt), limit)
+	if err != nil {
+		if errors.Is(err, get_related_blogs.ErrBlogNotFound) {
+			response.ResponsdNotFound(w, r, err)
+			return
+		}
+		logger.Error(fmt.Sprintf("failed to get related blogs: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	if err := response.RespondJSON(w, r, http.StatusOK, blogs); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
+	}
+}
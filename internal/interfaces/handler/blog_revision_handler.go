@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/interfaces/response"
+	"github.com/shoet/blog/internal/logging"
+	"github.com/shoet/blog/internal/policy"
+	"github.com/shoet/blog/internal/usecase/get_blog_revision_diff"
+	"github.com/shoet/blog/internal/usecase/get_blog_revisions"
+	"github.com/shoet/blog/internal/usecase/restore_blog_revision"
+)
+
+type BlogRevisionListHandler struct {
+	Usecase *get_blog_revisions.Usecase
+}
+
+func NewBlogRevisionListHandler(usecase *get_blog_revisions.Usecase) *BlogRevisionListHandler {
+	return &BlogRevisionListHandler{
+		Usecase: usecase,
+	}
+}
+
+func (l *BlogRevisionListHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+	id := chi.URLParam(r, "id")
+	idInt, err := strconv.Atoi(strings.TrimSpace(id))
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to convert id to int: %v", err))
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+	revisions, err := l.Usecase.Run(ctx, models.BlogId(idInt))
+	if err != nil {
+		if errors.Is(err, get_blog_revisions.ErrBlogNotFound) {
+			response.ResponsdNotFound(w, r, err)
+			return
+		}
+		logger.Error(fmt.Sprintf("failed to list blog revisions: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	if err := response.RespondJSON(w, r, http.StatusOK, revisions); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
+	}
+}
+
+type BlogRevisionDiffHandler struct {
+	Usecase *get_blog_revision_diff.Usecase
+}
+
+func NewBlogRevisionDiffHandler(usecase *get_blog_revision_diff.Usecase) *BlogRevisionDiffHandler {
+	return &BlogRevisionDiffHandler{
+		Usecase: usecase,
+	}
+}
+
+func (d *BlogRevisionDiffHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+	id := chi.URLParam(r, "id")
+	idInt, err := strconv.Atoi(strings.TrimSpace(id))
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to convert id to int: %v", err))
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+	v := r.URL.Query()
+	from, err := strconv.ParseInt(v.Get("from"), 10, 64)
+	if err != nil {
+		err := fmt.Errorf("from is invalid")
+		logger.Error(err.Error())
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+	to, err := strconv.ParseInt(v.Get("to"), 10, 64)
+	if err != nil {
+		err := fmt.Errorf("to is invalid")
+		logger.Error(err.Error())
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+	diff, err := d.Usecase.Run(ctx, models.BlogId(idInt), from, to)
+	if err != nil {
+		if errors.Is(err, get_blog_revision_diff.ErrRevisionNotFound) {
+			response.ResponsdNotFound(w, r, err)
+			return
+		}
+		logger.Error(fmt.Sprintf("failed to get blog revision diff: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	if err := response.RespondJSON(w, r, http.StatusOK, diff); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
+	}
+}
+
+type BlogRevisionRestoreHandler struct {
+	Usecase *restore_blog_revision.Usecase
+}
+
+func NewBlogRevisionRestoreHandler(usecase *restore_blog_revision.Usecase) *BlogRevisionRestoreHandler {
+	return &BlogRevisionRestoreHandler{
+		Usecase: usecase,
+	}
+}
+
+func (h *BlogRevisionRestoreHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+	id := chi.URLParam(r, "id")
+	idInt, err := strconv.Atoi(strings.TrimSpace(id))
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to convert id to int: %v", err))
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+	revision, err := strconv.ParseInt(strings.TrimSpace(chi.URLParam(r, "revision")), 10, 64)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to convert revision to int: %v", err))
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+	blog, err := h.Usecase.Run(ctx, models.BlogId(idInt), revision)
+	if err != nil {
+		if errors.Is(err, restore_blog_revision.ErrBlogNotFound) ||
+			errors.Is(err, restore_blog_revision.ErrRevisionNotFound) {
+			response.ResponsdNotFound(w, r, err)
+			return
+		}
+		if errors.Is(err, policy.ErrForbidden) {
+			response.RespondForbidden(w, r, err)
+			return
+		}
+		logger.Error(fmt.Sprintf("failed to restore blog revision: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	if err := response.RespondJSON(w, r, http.StatusOK, blog); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
+	}
+}
@@ -0,0 +1,257 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/interfaces/clientip"
+	"github.com/shoet/blog/internal/interfaces/response"
+	"github.com/shoet/blog/internal/logging"
+	"github.com/shoet/blog/internal/usecase/create_comment"
+	"github.com/shoet/blog/internal/usecase/get_admin_comments"
+	"github.com/shoet/blog/internal/usecase/get_comments"
+	"github.com/shoet/blog/internal/usecase/moderate_comment"
+)
+
+// publicComment は、公開しない投稿者の情報を除いたコメントを返却する
+func publicComment(c *models.Comment) *models.Comment {
+	p := *c
+	p.AuthorEmail = ""
+	p.IpAddress = ""
+	p.UserAgent = ""
+	p.Replies = nil
+	for _, reply := range c.Replies {
+		p.Replies = append(p.Replies, publicComment(reply))
+	}
+	return &p
+}
+
+type CommentAddHandler struct {
+	Usecase   *create_comment.Usecase
+	Validator *validator.Validate
+}
+
+func NewCommentAddHandler(
+	usecase *create_comment.Usecase,
+	validator *validator.Validate,
+) *CommentAddHandler {
+	return &CommentAddHandler{
+		Usecase:   usecase,
+		Validator: validator,
+	}
+}
+
+func (h *CommentAddHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+	id := chi.URLParam(r, "id")
+	idInt, err := strconv.Atoi(strings.TrimSpace(id))
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to convert id to int: %v", err))
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+	var reqBody struct {
+		AuthorName  string            `json:"authorName" validate:"required,max=64"`
+		AuthorEmail string            `json:"authorEmail" validate:"omitempty,email,max=255"`
+		Body        string            `json:"body" validate:"required,max=4000"`
+		ParentId    *models.CommentId `json:"parentId"`
+		// Website は、ボット対策のハニーポット項目で、画面には表示しない
+		Website string `json:"website"`
+	}
+	defer r.Body.Close()
+	if err := response.JsonToStruct(r, &reqBody); err != nil {
+		logger.Error(fmt.Sprintf("failed to parse request body: %v", err))
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+	if err := h.Validator.Struct(reqBody); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request body: %v", err))
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+
+	// ハニーポットが入力された場合はボットとみなし、保存せずに受け付けたように振る舞う
+	if reqBody.Website != "" {
+		logger.Info(fmt.Sprintf("comment is discarded by honeypot: %s", clientip.FromRequest(r)))
+		comment := &models.Comment{
+			BlogId:     models.BlogId(idInt),
+			ParentId:   reqBody.ParentId,
+			AuthorName: reqBody.AuthorName,
+			Body:       reqBody.Body,
+			Status:     models.CommentStatusPending,
+		}
+		if err := response.RespondJSON(w, r, http.StatusAccepted, comment); err != nil {
+			logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
+		}
+		return
+	}
+
+	input := &create_comment.Input{
+		BlogId:      models.BlogId(idInt),
+		ParentId:    reqBody.ParentId,
+		AuthorName:  strings.TrimSpace(reqBody.AuthorName),
+		AuthorEmail: strings.TrimSpace(reqBody.AuthorEmail),
+		Body:        reqBody.Body,
+		IpAddress:   clientip.FromRequest(r),
+		UserAgent:   r.UserAgent(),
+	}
+	comment, err := h.Usecase.Run(ctx, input)
+	if err != nil {
+		if errors.Is(err, create_comment.ErrBlogNotFound) {
+			response.ResponsdNotFound(w, r, err)
+			return
+		}
+		if errors.Is(err, create_comment.ErrParentCommentNotFound) ||
+			errors.Is(err, create_comment.ErrNestedReply) {
+			response.ResponsdBadRequest(w, r, err)
+			return
+		}
+		logger.Error(fmt.Sprintf("failed to add comment: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	if err := response.RespondJSON(w, r, http.StatusAccepted, publicComment(comment)); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
+	}
+}
+
+type CommentListHandler struct {
+	Usecase *get_comments.Usecase
+}
+
+func NewCommentListHandler(usecase *get_comments.Usecase) *CommentListHandler {
+	return &CommentListHandler{
+		Usecase: usecase,
+	}
+}
+
+func (h *CommentListHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+	id := chi.URLParam(r, "id")
+	idInt, err := strconv.Atoi(strings.TrimSpace(id))
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to convert id to int: %v", err))
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+	comments, err := h.Usecase.Run(ctx, models.BlogId(idInt))
+	if err != nil {
+		if errors.Is(err, get_comments.ErrBlogNotFound) {
+			response.ResponsdNotFound(w, r, err)
+			return
+		}
+		logger.Error(fmt.Sprintf("failed to list comments: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	resp := make([]*models.Comment, 0, len(comments))
+	for _, c := range comments {
+		resp = append(resp, publicComment(c))
+	}
+	if err := response.RespondJSON(w, r, http.StatusOK, resp); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
+	}
+}
+
+type CommentListAdminHandler struct {
+	Usecase *get_admin_comments.Usecase
+}
+
+func NewCommentListAdminHandler(usecase *get_admin_comments.Usecase) *CommentListAdminHandler {
+	return &CommentListAdminHandler{
+		Usecase: usecase,
+	}
+}
+
+func (h *CommentListAdminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+	v := r.URL.Query()
+	input := &get_admin_comments.Input{}
+	if s := v.Get("status"); s != "" {
+		status := models.CommentStatus(s)
+		if !status.Valid() {
+			err := fmt.Errorf("status is invalid")
+			logger.Error(err.Error())
+			response.ResponsdBadRequest(w, r, err)
+			return
+		}
+		input.Status = &status
+	}
+	if l := v.Get("limit"); l != "" {
+		limit, err := strconv.ParseInt(l, 10, 64)
+		if err != nil || limit < 1 {
+			err := fmt.Errorf("limit is invalid")
+			logger.Error(err.Error())
+			response.ResponsdBadRequest(w, r, err)
+			return
+		}
+		input.Limit = &limit
+	}
+	if p := v.Get("page"); p != "" {
+		page, err := strconv.ParseInt(p, 10, 64)
+		if err != nil || page < 1 {
+			err := fmt.Errorf("page is invalid")
+			logger.Error(err.Error())
+			response.ResponsdBadRequest(w, r, err)
+			return
+		}
+		input.Page = &page
+	}
+	comments, err := h.Usecase.Run(ctx, input)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to list comments: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	if err := response.RespondJSON(w, r, http.StatusOK, comments); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
+	}
+}
+
+type CommentModerateHandler struct {
+	Usecase *moderate_comment.Usecase
+	Status  models.CommentStatus
+}
+
+func NewCommentModerateHandler(
+	usecase *moderate_comment.Usecase, status models.CommentStatus,
+) *CommentModerateHandler {
+	return &CommentModerateHandler{
+		Usecase: usecase,
+		Status:  status,
+	}
+}
+
+func (h *CommentModerateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+	id := chi.URLParam(r, "id")
+	idInt, err := strconv.Atoi(strings.TrimSpace(id))
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to convert id to int: %v", err))
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+	comment, err := h.Usecase.Run(ctx, models.CommentId(idInt), h.Status)
+	if err != nil {
+		if errors.Is(err, moderate_comment.ErrCommentNotFound) {
+			response.ResponsdNotFound(w, r, err)
+			return
+		}
+		logger.Error(fmt.Sprintf("failed to moderate comment: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	if err := response.RespondJSON(w, r, http.StatusOK, comment); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
+	}
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/shoet/blog/internal/clocker"
+	"github.com/shoet/blog/internal/config"
+	"github.com/shoet/blog/internal/feed"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/interfaces/response"
+	"github.com/shoet/blog/internal/logging"
+	"github.com/shoet/blog/internal/usecase/get_blogs"
+)
+
+type FeedFormat string
+
+const (
+	FeedFormatRSS  FeedFormat = "rss"
+	FeedFormatAtom FeedFormat = "atom"
+	FeedFormatJSON FeedFormat = "json"
+)
+
+// feedLimit は、フィードに含める記事の件数
+const feedLimit int64 = 20
+
+type FeedHandler struct {
+	Usecase *get_blogs.Usecase
+	Config  *config.Config
+	Clocker clocker.Clocker
+	Format  FeedFormat
+}
+
+func NewFeedHandler(
+	usecase *get_blogs.Usecase, cfg *config.Config, clocker clocker.Clocker, format FeedFormat,
+) *FeedHandler {
+	return &FeedHandler{
+		Usecase: usecase,
+		Config:  cfg,
+		Clocker: clocker,
+		Format:  format,
+	}
+}
+
+func (h *FeedHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+
+	isPublicOnly := true
+	limit := feedLimit
+	input := &get_blogs.GetBlogsInput{IsPublicOnly: &isPublicOnly, Limit: &limit}
+	tag := r.URL.Query().Get("tag")
+	if tag != "" {
+		input.Tag = &tag
+	}
+	blogs, _, _, err := h.Usecase.Run(ctx, input)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to list blog: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+
+	f := h.buildFeed(r, blogs, tag)
+	var body []byte
+	var contentType string
+	switch h.Format {
+	case FeedFormatAtom:
+		body, err = feed.Atom(f)
+		contentType = "application/atom+xml; charset=utf-8"
+	case FeedFormatJSON:
+		body, err = feed.JSON(f)
+		contentType = "application/feed+json; charset=utf-8"
+	default:
+		body, err = feed.RSS(f)
+		contentType = "application/rss+xml; charset=utf-8"
+	}
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to build feed: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	// 記事の削除や非公開は記事の日時に現れないため、Last-Modifiedは付与せずETagのみで判定する
+	if err := response.RespondConditional(w, r, contentType, body, time.Time{}); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond feed: %v", err))
+	}
+}
+
+func (h *FeedHandler) buildFeed(r *http.Request, blogs []*models.Blog, tag string) *feed.Feed {
+	site := siteURL(h.Config)
+	f := &feed.Feed{
+		Title:       h.Config.FeedTitle,
+		Description: h.Config.FeedDescription,
+		Author:      h.Config.AdminName,
+		SiteURL:     site,
+		FeedURL:     requestURL(r),
+		Generated:   h.Clocker.Now(),
+	}
+	if tag != "" {
+		f.Title = fmt.Sprintf("%s - %s", h.Config.FeedTitle, tag)
+	}
+	for _, b := range blogs {
+		// スラッグは変更されうるため、IDはブログのIDのURLで固定する
+		id := site + "/blogs/" + strconv.FormatInt(int64(b.Id), 10)
+		link := blogURL(h.Config, b)
+		// 予約投稿の場合は公開日時を投稿日時とする
+		published := b.Created
+		if b.PublishAt > published {
+			published = b.PublishAt
+		}
+		f.Items = append(f.Items, &feed.Item{
+			Id:        id,
+			Title:     b.Title,
+			Link:      link,
+			Summary:   b.Description,
+			ImageURL:  thumbnailURL(h.Config, b.ThumbnailImageFileName),
+			Tags:      b.Tags,
+			Published: time.Unix(int64(published), 0),
+			Updated:   time.Unix(int64(b.LastModified()), 0),
+		})
+	}
+	return f
+}
@@ -0,0 +1,151 @@
+package handler_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/shoet/blog/internal/clocker"
+	"github.com/shoet/blog/internal/config"
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/interfaces/handler"
+	"github.com/shoet/blog/internal/logging"
+	"github.com/shoet/blog/internal/options"
+	"github.com/shoet/blog/internal/testutil"
+	"github.com/shoet/blog/internal/usecase/get_blogs"
+)
+
+type feedBlogRepository struct {
+	blogs models.Blogs
+}
+
+func (r *feedBlogRepository) List(
+	ctx context.Context, tx infrastracture.TX, option *options.ListBlogOptions,
+) ([]*models.Blog, error) {
+	return r.blogs, nil
+}
+
+func (r *feedBlogRepository) ListByTag(
+	ctx context.Context, tx infrastracture.TX, tag string, option *options.ListBlogOptions,
+) (models.Blogs, error) {
+	return r.blogs, nil
+}
+
+func (r *feedBlogRepository) ListByKeyword(
+	ctx context.Context, tx infrastracture.TX, keyword string, option *options.ListBlogOptions,
+) (models.Blogs, error) {
+	return r.blogs, nil
+}
+
+func (r *feedBlogRepository) SelectBlogsContents(
+	ctx context.Context, tx infrastracture.TX, ids []models.BlogId,
+) (models.Blogs, error) {
+	return r.blogs, nil
+}
+
+type feedUserRepository struct{}
+
+func (r *feedUserRepository) ListAuthors(
+	ctx context.Context, tx infrastracture.TX, ids []models.UserId,
+) ([]*models.Author, error) {
+	return []*models.Author{}, nil
+}
+
+type feedContentsService struct{}
+
+func (s *feedContentsService) SetThumbnailVariants(
+	ctx context.Context, tx infrastracture.TX, blogs models.Blogs,
+) error {
+	return nil
+}
+
+func newFeedRequest(target string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	logger := logging.NewLogger(io.Discard, "info")
+	return req.WithContext(context.WithValue(req.Context(), logging.LoggerKey{}, logger))
+}
+
+func Test_FeedHandler_ScheduledPost(t *testing.T) {
+	ctx := context.Background()
+	// リポジトリはフェイクのため、トランザクションの開始のみに使用する
+	db, err := testutil.NewDBSQLite3ForTest(t, ctx)
+	if err != nil {
+		t.Fatalf("failed to create db: %v", err)
+	}
+	firstModified := time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC)
+	blogRepo := &feedBlogRepository{blogs: models.Blogs{
+		{Id: 1, Slug: "first", Title: "first", IsPublic: true, Modified: uint(firstModified.Unix())},
+	}}
+	usecase := get_blogs.NewUsecase(db, blogRepo, &feedUserRepository{}, &feedContentsService{})
+	cfg := &config.Config{SiteDomain: "example.com", FeedTitle: "blog"}
+	sut := handler.NewFeedHandler(usecase, cfg, clocker.NewFixedClocker(), handler.FeedFormatAtom)
+
+	first := httptest.NewRecorder()
+	sut.ServeHTTP(first, newFeedRequest("/atom.xml"))
+	if first.Code != http.StatusOK {
+		t.Fatalf("want %d, but got %d", http.StatusOK, first.Code)
+	}
+
+	// 前回の取得より前に更新され、その後に公開日時を迎えた予約投稿
+	blogRepo.blogs = append(models.Blogs{
+		{
+			Id: 2, Slug: "scheduled", Title: "scheduled", IsPublic: true,
+			Modified:  uint(firstModified.Add(-time.Hour).Unix()),
+			PublishAt: uint(firstModified.Add(time.Hour).Unix()),
+		},
+	}, blogRepo.blogs...)
+
+	// 記事の更新日時を基準にしたIf-Modified-Sinceでは、予約投稿の公開を検知できない
+	req := newFeedRequest("/atom.xml")
+	req.Header.Set("If-Modified-Since", firstModified.Format(http.TimeFormat))
+	second := httptest.NewRecorder()
+	sut.ServeHTTP(second, req)
+	if second.Code != http.StatusOK {
+		t.Errorf("want %d, but got %d", http.StatusOK, second.Code)
+	}
+
+	req = newFeedRequest("/atom.xml")
+	req.Header.Set("If-None-Match", second.Header().Get("ETag"))
+	third := httptest.NewRecorder()
+	sut.ServeHTTP(third, req)
+	if third.Code != http.StatusNotModified {
+		t.Errorf("want %d, but got %d", http.StatusNotModified, third.Code)
+	}
+}
+
+func Test_FeedHandler_DeletedPost(t *testing.T) {
+	ctx := context.Background()
+	db, err := testutil.NewDBSQLite3ForTest(t, ctx)
+	if err != nil {
+		t.Fatalf("failed to create db: %v", err)
+	}
+	modified := uint(time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC).Unix())
+	blogRepo := &feedBlogRepository{blogs: models.Blogs{
+		{Id: 2, Slug: "second", Title: "second", IsPublic: true, Modified: modified},
+		{Id: 1, Slug: "first", Title: "first", IsPublic: true, Modified: modified},
+	}}
+	usecase := get_blogs.NewUsecase(db, blogRepo, &feedUserRepository{}, &feedContentsService{})
+	cfg := &config.Config{SiteDomain: "example.com", FeedTitle: "blog"}
+	sut := handler.NewFeedHandler(usecase, cfg, clocker.NewFixedClocker(), handler.FeedFormatRSS)
+
+	first := httptest.NewRecorder()
+	sut.ServeHTTP(first, newFeedRequest("/feed.xml"))
+	if first.Header().Get("Last-Modified") != "" {
+		t.Errorf("want no Last-Modified, but got %s", first.Header().Get("Last-Modified"))
+	}
+
+	// 最新の記事を削除しても、残りの記事の日時は変わらない
+	blogRepo.blogs = blogRepo.blogs[1:]
+	req := newFeedRequest("/feed.xml")
+	req.Header.Set("If-None-Match", first.Header().Get("ETag"))
+	second := httptest.NewRecorder()
+	sut.ServeHTTP(second, req)
+	if second.Code != http.StatusOK {
+		t.Errorf("want %d, but got %d", http.StatusOK, second.Code)
+	}
+}
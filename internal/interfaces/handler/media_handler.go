@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/interfaces/response"
+	"github.com/shoet/blog/internal/logging"
+	"github.com/shoet/blog/internal/policy"
+	"github.com/shoet/blog/internal/usecase/complete_media"
+	"github.com/shoet/blog/internal/usecase/get_admin_media"
+)
+
+type MediaCompleteHandler struct {
+	Usecase *complete_media.Usecase
+}
+
+func NewMediaCompleteHandler(usecase *complete_media.Usecase) *MediaCompleteHandler {
+	return &MediaCompleteHandler{
+		Usecase: usecase,
+	}
+}
+
+func (h *MediaCompleteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+	id, err := strconv.Atoi(strings.TrimSpace(chi.URLParam(r, "id")))
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to convert id to int: %v", err))
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+	media, err := h.Usecase.Run(ctx, models.MediaId(id))
+	if err != nil {
+		if errors.Is(err, complete_media.ErrMediaNotFound) {
+			response.ResponsdNotFound(w, r, err)
+			return
+		}
+		if errors.Is(err, policy.ErrForbidden) {
+			response.RespondForbidden(w, r, err)
+			return
+		}
+		if errors.Is(err, complete_media.ErrMediaNotUploaded) {
+			response.ResponsdBadRequest(w, r, err)
+			return
+		}
+		logger.Error(fmt.Sprintf("failed to complete media: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	if err := response.RespondJSON(w, r, http.StatusOK, media); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
+	}
+}
+
+type MediaListAdminHandler struct {
+	Usecase *get_admin_media.Usecase
+}
+
+func NewMediaListAdminHandler(usecase *get_admin_media.Usecase) *MediaListAdminHandler {
+	return &MediaListAdminHandler{
+		Usecase: usecase,
+	}
+}
+
+func (h *MediaListAdminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+	v := r.URL.Query()
+	input := &get_admin_media.Input{}
+	if k := v.Get("kind"); k != "" {
+		kind := models.MediaKind(k)
+		if !kind.Valid() {
+			err := fmt.Errorf("kind is invalid")
+			logger.Error(err.Error())
+			response.ResponsdBadRequest(w, r, err)
+			return
+		}
+		input.Filter.Kind = &kind
+	}
+	if s := v.Get("status"); s != "" {
+		status := models.MediaStatus(s)
+		if !status.Valid() {
+			err := fmt.Errorf("status is invalid")
+			logger.Error(err.Error())
+			response.ResponsdBadRequest(w, r, err)
+			return
+		}
+		input.Filter.Status = &status
+	}
+	if u := v.Get("uploadedBy"); u != "" {
+		uploadedBy, err := strconv.ParseInt(u, 10, 64)
+		if err != nil {
+			err := fmt.Errorf("uploadedBy is invalid")
+			logger.Error(err.Error())
+			response.ResponsdBadRequest(w, r, err)
+			return
+		}
+		userId := models.UserId(uploadedBy)
+		input.Filter.UploadedBy = &userId
+	}
+	if ref := v.Get("referenced"); ref != "" {
+		referenced, err := strconv.ParseBool(ref)
+		if err != nil {
+			err := fmt.Errorf("referenced is invalid")
+			logger.Error(err.Error())
+			response.ResponsdBadRequest(w, r, err)
+			return
+		}
+		input.Filter.Referenced = &referenced
+	}
+	if l := v.Get("limit"); l != "" {
+		limit, err := strconv.ParseInt(l, 10, 64)
+		if err != nil || limit < 1 {
+			err := fmt.Errorf("limit is invalid")
+			logger.Error(err.Error())
+			response.ResponsdBadRequest(w, r, err)
+			return
+		}
+		input.Limit = &limit
+	}
+	if p := v.Get("page"); p != "" {
+		page, err := strconv.ParseInt(p, 10, 64)
+		if err != nil || page < 1 {
+			err := fmt.Errorf("page is invalid")
+			logger.Error(err.Error())
+			response.ResponsdBadRequest(w, r, err)
+			return
+		}
+		input.Page = &page
+	}
+	output, err := h.Usecase.Run(ctx, input)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to list media: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	if err := response.RespondJSON(w, r, http.StatusOK, output); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
+	}
+}
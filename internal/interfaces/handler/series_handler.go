@@ -0,0 +1,282 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/interfaces/response"
+	"github.com/shoet/blog/internal/logging"
+	"github.com/shoet/blog/internal/usecase/create_series"
+	"github.com/shoet/blog/internal/usecase/delete_series"
+	"github.com/shoet/blog/internal/usecase/get_series"
+	"github.com/shoet/blog/internal/usecase/get_series_list"
+	"github.com/shoet/blog/internal/usecase/put_series"
+	"github.com/shoet/blog/internal/usecase/put_series_parts"
+)
+
+func seriesIdFromURL(r *http.Request) (models.SeriesId, error) {
+	id, err := strconv.Atoi(strings.TrimSpace(chi.URLParam(r, "id")))
+	if err != nil {
+		return 0, fmt.Errorf("failed to convert id to int: %w", err)
+	}
+	return models.SeriesId(id), nil
+}
+
+type SeriesListHandler struct {
+	Usecase *get_series_list.Usecase
+}
+
+func NewSeriesListHandler(usecase *get_series_list.Usecase) *SeriesListHandler {
+	return &SeriesListHandler{
+		Usecase: usecase,
+	}
+}
+
+func (h *SeriesListHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+	series, err := h.Usecase.Run(ctx)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to list series: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	if err := response.RespondJSON(w, r, http.StatusOK, series); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
+	}
+}
+
+type SeriesGetHandler struct {
+	Usecase *get_series.Usecase
+}
+
+func NewSeriesGetHandler(usecase *get_series.Usecase) *SeriesGetHandler {
+	return &SeriesGetHandler{
+		Usecase: usecase,
+	}
+}
+
+func (h *SeriesGetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+	id, err := seriesIdFromURL(r)
+	if err != nil {
+		logger.Error(err.Error())
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+	series, err := h.Usecase.Run(ctx, id)
+	if err != nil {
+		if errors.Is(err, get_series.ErrSeriesNotFound) {
+			response.ResponsdNotFound(w, r, err)
+			return
+		}
+		logger.Error(fmt.Sprintf("failed to get series: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	if err := response.RespondJSON(w, r, http.StatusOK, series); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
+	}
+}
+
+type SeriesAddHandler struct {
+	Usecase   *create_series.Usecase
+	Validator *validator.Validate
+}
+
+func NewSeriesAddHandler(usecase *create_series.Usecase, validator *validator.Validate) *SeriesAddHandler {
+	return &SeriesAddHandler{
+		Usecase:   usecase,
+		Validator: validator,
+	}
+}
+
+func (h *SeriesAddHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+	var reqBody struct {
+		Title       string `json:"title" validate:"required,max=255"`
+		Description string `json:"description"`
+	}
+	defer r.Body.Close()
+	if err := response.JsonToStruct(r, &reqBody); err != nil {
+		logger.Error(fmt.Sprintf("failed to parse request body: %v", err))
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+	if err := h.Validator.Struct(reqBody); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request body: %v", err))
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+	series, err := h.Usecase.Run(ctx, &models.Series{
+		Title:       reqBody.Title,
+		Description: reqBody.Description,
+	})
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to add series: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	if err := response.RespondJSON(w, r, http.StatusOK, series); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
+	}
+}
+
+type SeriesPutHandler struct {
+	Usecase   *put_series.Usecase
+	Validator *validator.Validate
+}
+
+func NewSeriesPutHandler(usecase *put_series.Usecase, validator *validator.Validate) *SeriesPutHandler {
+	return &SeriesPutHandler{
+		Usecase:   usecase,
+		Validator: validator,
+	}
+}
+
+func (h *SeriesPutHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+	id, err := seriesIdFromURL(r)
+	if err != nil {
+		logger.Error(err.Error())
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+	var reqBody struct {
+		Title       string `json:"title" validate:"required,max=255"`
+		Description string `json:"description"`
+	}
+	defer r.Body.Close()
+	if err := response.JsonToStruct(r, &reqBody); err != nil {
+		logger.Error(fmt.Sprintf("failed to parse request body: %v", err))
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+	if err := h.Validator.Struct(reqBody); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request body: %v", err))
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+	series, err := h.Usecase.Run(ctx, &models.Series{
+		Id:          id,
+		Title:       reqBody.Title,
+		Description: reqBody.Description,
+	})
+	if err != nil {
+		if errors.Is(err, put_series.ErrSeriesNotFound) {
+			response.ResponsdNotFound(w, r, err)
+			return
+		}
+		logger.Error(fmt.Sprintf("failed to put series: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	if err := response.RespondJSON(w, r, http.StatusOK, series); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
+	}
+}
+
+type SeriesDeleteHandler struct {
+	Usecase *delete_series.Usecase
+}
+
+func NewSeriesDeleteHandler(usecase *delete_series.Usecase) *SeriesDeleteHandler {
+	return &SeriesDeleteHandler{
+		Usecase: usecase,
+	}
+}
+
+func (h *SeriesDeleteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+	id, err := seriesIdFromURL(r)
+	if err != nil {
+		logger.Error(err.Error())
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+	if err := h.Usecase.Run(ctx, id); err != nil {
+		if errors.Is(err, delete_series.ErrSeriesNotFound) {
+			response.ResponsdNotFound(w, r, err)
+			return
+		}
+		logger.Error(fmt.Sprintf("failed to delete series: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	resp := struct {
+		Id int `json:"id"`
+	}{
+		Id: int(id),
+	}
+	if err := response.RespondJSON(w, r, http.StatusOK, resp); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
+	}
+}
+
+type SeriesPartsPutHandler struct {
+	Usecase   *put_series_parts.Usecase
+	Validator *validator.Validate
+}
+
+func NewSeriesPartsPutHandler(
+	usecase *put_series_parts.Usecase, validator *validator.Validate,
+) *SeriesPartsPutHandler {
+	return &SeriesPartsPutHandler{
+		Usecase:   usecase,
+		Validator: validator,
+	}
+}
+
+func (h *SeriesPartsPutHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+	id, err := seriesIdFromURL(r)
+	if err != nil {
+		logger.Error(err.Error())
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+	// blogIdsの並び順がシリーズ内の順番となる
+	var reqBody struct {
+		BlogIds []models.BlogId `json:"blogIds" validate:"required"`
+	}
+	defer r.Body.Close()
+	if err := response.JsonToStruct(r, &reqBody); err != nil {
+		logger.Error(fmt.Sprintf("failed to parse request body: %v", err))
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+	if err := h.Validator.Struct(reqBody); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request body: %v", err))
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+	series, err := h.Usecase.Run(ctx, id, reqBody.BlogIds)
+	if err != nil {
+		switch {
+		case errors.Is(err, put_series_parts.ErrSeriesNotFound):
+			response.ResponsdNotFound(w, r, err)
+		case errors.Is(err, put_series_parts.ErrBlogNotFound),
+			errors.Is(err, put_series_parts.ErrBlogDuplicated),
+			errors.Is(err, put_series_parts.ErrBlogInOtherSeries):
+			response.ResponsdBadRequest(w, r, err)
+		default:
+			logger.Error(fmt.Sprintf("failed to put series parts: %v", err))
+			response.ResponsdInternalServerError(w, r, err)
+		}
+		return
+	}
+	if err := response.RespondJSON(w, r, http.StatusOK, series); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
+	}
+}
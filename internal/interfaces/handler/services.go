@@ -8,7 +8,7 @@ import (
 )
 
 type AuthService interface {
-	Login(ctx context.Context, email string, password string) (string, error)
+	Login(ctx context.Context, email string, password string) (*models.AuthToken, error)
 	LoginSession(ctx context.Context, token string) (*models.User, error)
 }
 
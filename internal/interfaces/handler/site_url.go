@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/shoet/blog/internal/config"
+	"github.com/shoet/blog/internal/infrastracture/models"
+)
+
+// siteURL は、フロントエンドのサイトのURLを生成する
+// SiteDomainにスキームが含まれていない場合はhttpsとする
+func siteURL(cfg *config.Config) string {
+	domain := strings.TrimSuffix(cfg.SiteDomain, "/")
+	if strings.Contains(domain, "://") {
+		return domain
+	}
+	return "https://" + domain
+}
+
+// blogURL は、フロントエンドのブログのページのURLを生成する
+// スラッグが設定されていない場合はIDを使用する
+func blogURL(cfg *config.Config, blog *models.Blog) string {
+	if blog.Slug == "" {
+		return siteURL(cfg) + "/blogs/" + strconv.FormatInt(int64(blog.Id), 10)
+	}
+	return siteURL(cfg) + "/blogs/" + url.PathEscape(blog.Slug)
+}
+
+// thumbnailURL は、サムネイル画像の絶対URLを生成する
+// 絶対URLが保存されている場合はそのまま返却する
+func thumbnailURL(cfg *config.Config, fileName string) string {
+	if fileName == "" {
+		return ""
+	}
+	if strings.HasPrefix(fileName, "http://") || strings.HasPrefix(fileName, "https://") {
+		return fileName
+	}
+	return "https://" + strings.Join(
+		[]string{cfg.CdnDomain, cfg.AWSS3ThumbnailDirectory, strings.TrimPrefix(fileName, "/")}, "/")
+}
+
+// requestURL は、リクエストされたURLを絶対URLで返却する
+func requestURL(r *http.Request) string {
+	return requestBaseURL(r) + r.URL.RequestURI()
+}
+
+// requestBaseURL は、リクエストされたAPIのスキームとホストを返却する
+// リバースプロキシ経由の場合はX-Forwarded-Protoのスキームを使用する
+func requestBaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return scheme + "://" + r.Host
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shoet/blog/internal/config"
+	"github.com/shoet/blog/internal/interfaces/response"
+	"github.com/shoet/blog/internal/logging"
+	"github.com/shoet/blog/internal/sitemap"
+	"github.com/shoet/blog/internal/usecase/get_sitemap"
+)
+
+type SitemapHandler struct {
+	Usecase *get_sitemap.Usecase
+	Config  *config.Config
+}
+
+func NewSitemapHandler(usecase *get_sitemap.Usecase, cfg *config.Config) *SitemapHandler {
+	return &SitemapHandler{
+		Usecase: usecase,
+		Config:  cfg,
+	}
+}
+
+// ServeHTTP は、公開中のブログとタグのページのサイトマップを返却する
+// URLが上限を超える場合は /sitemap.xml でサイトマップインデックスを返却し、
+// 分割した各サイトマップを /sitemap-{page}.xml で返却する
+// URLはリクエストのHostヘッダーを信頼せず、設定のSiteDomainから生成する
+func (h *SitemapHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+
+	page := 0
+	if p := chi.URLParam(r, "page"); p != "" {
+		v, err := strconv.Atoi(p)
+		if err != nil || v < 1 {
+			logger.Error(fmt.Sprintf("page is invalid: %s", p))
+			response.ResponsdNotFound(w, r, err)
+			return
+		}
+		page = v
+	}
+
+	output, err := h.Usecase.Run(ctx)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to get sitemap: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	chunks := sitemap.Split(h.buildURLs(output), sitemap.MaxURLs)
+
+	var body []byte
+	var lastMod time.Time
+	switch {
+	case page == 0 && len(chunks) > 1:
+		var sitemaps []*sitemap.Sitemap
+		for i, chunk := range chunks {
+			sitemaps = append(sitemaps, &sitemap.Sitemap{
+				Loc:     fmt.Sprintf("%s/sitemap-%d.xml", siteURL(h.Config), i+1),
+				LastMod: sitemap.LastMod(chunk),
+			})
+			if sitemaps[i].LastMod.After(lastMod) {
+				lastMod = sitemaps[i].LastMod
+			}
+		}
+		body, err = sitemap.Index(sitemaps)
+	case page == 0:
+		body, err = sitemap.URLSet(chunks[0])
+		lastMod = sitemap.LastMod(chunks[0])
+	case page <= len(chunks):
+		body, err = sitemap.URLSet(chunks[page-1])
+		lastMod = sitemap.LastMod(chunks[page-1])
+	default:
+		response.ResponsdNotFound(w, r, nil)
+		return
+	}
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to build sitemap: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	if err := response.RespondConditional(w, r, "application/xml; charset=utf-8", body, lastMod); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond sitemap: %v", err))
+	}
+}
+
+func (h *SitemapHandler) buildURLs(output *get_sitemap.Output) []*sitemap.URL {
+	site := siteURL(h.Config)
+	urls := make([]*sitemap.URL, 0, len(output.Blogs)+len(output.Tags)+1)
+	top := &sitemap.URL{Loc: site + "/"}
+	urls = append(urls, top)
+	for _, b := range output.Blogs {
+		urls = append(urls, &sitemap.URL{
+			Loc:     blogURL(h.Config, b),
+			LastMod: time.Unix(int64(b.LastModified()), 0),
+		})
+	}
+	for _, t := range output.Tags {
+		urls = append(urls, &sitemap.URL{
+			Loc:     site + "/tags/" + url.PathEscape(t.Name),
+			LastMod: time.Unix(int64(t.Modified), 0),
+		})
+	}
+	// トップページはいずれかの記事が更新されると更新される
+	top.LastMod = sitemap.LastMod(urls)
+	return urls
+}
+
+type RobotsHandler struct {
+	Config *config.Config
+}
+
+func NewRobotsHandler(cfg *config.Config) *RobotsHandler {
+	return &RobotsHandler{
+		Config: cfg,
+	}
+}
+
+// ServeHTTP は、robots.txtを返却する
+// 本番環境以外はすべてのクロールを拒否する
+func (h *RobotsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.GetLogger(r.Context())
+
+	var b strings.Builder
+	b.WriteString("User-agent: *\n")
+	if h.Config.Env != "prod" {
+		b.WriteString("Disallow: /\n")
+	} else {
+		for _, path := range strings.Split(h.Config.RobotsDisallow, ",") {
+			if path = strings.TrimSpace(path); path != "" {
+				b.WriteString("Disallow: " + path + "\n")
+			}
+		}
+	}
+	b.WriteString("\nSitemap: " + siteURL(h.Config) + "/sitemap.xml\n")
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte(b.String())); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond robots.txt: %v", err))
+	}
+}
@@ -1,12 +1,19 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
+	"github.com/shoet/blog/internal/infrastracture/adapter"
+	"github.com/shoet/blog/internal/infrastracture/models"
 	"github.com/shoet/blog/internal/interfaces/response"
 	"github.com/shoet/blog/internal/logging"
+	"github.com/shoet/blog/internal/usecase/process_thumbnail"
+	"github.com/shoet/blog/internal/usecase/storage_local_get"
+	"github.com/shoet/blog/internal/usecase/storage_local_put"
 	"github.com/shoet/blog/internal/usecase/storage_presigned_content"
 	"github.com/shoet/blog/internal/usecase/storage_presigned_thumbnail"
 )
@@ -30,7 +37,9 @@ func (g *GenerateThumbnailImageSignedURLHandler) ServeHTTP(w http.ResponseWriter
 	ctx := r.Context()
 	logger := logging.GetLogger(ctx)
 	var reqBody struct {
-		FileName string `json:"fileName" validate:"required"`
+		FileName    string `json:"fileName" validate:"required"`
+		ContentType string `json:"contentType" validate:"required"`
+		Size        int64  `json:"size" validate:"required,gt=0"`
 	}
 	defer r.Body.Close()
 	if err := response.JsonToStruct(r, &reqBody); err != nil {
@@ -45,19 +54,29 @@ func (g *GenerateThumbnailImageSignedURLHandler) ServeHTTP(w http.ResponseWriter
 		return
 	}
 
-	signedUrl, destinationUrl, err := g.Usecase.Run(ctx, reqBody.FileName)
+	output, err := g.Usecase.Run(ctx, reqBody.FileName, reqBody.ContentType, reqBody.Size)
 	if err != nil {
+		if errors.Is(err, storage_presigned_thumbnail.ErrInvalidUpload) {
+			response.ResponsdBadRequest(w, r, err)
+			return
+		}
 		logger.Error(fmt.Sprintf("failed to validate request body: %v", err))
 		response.ResponsdInternalServerError(w, r, err)
 		return
 	}
 
+	// アップロード時は署名に含まれるContent-TypeとContent-Lengthを指定する
+	// アップロード後はmediaIdを指定して完了を通知する
 	resp := struct {
-		SignedUrl string `json:"signedUrl"`
-		PutedUrl  string `json:"putUrl"`
+		SignedUrl string         `json:"signedUrl"`
+		PutedUrl  string         `json:"putUrl"`
+		FileName  string         `json:"fileName"`
+		MediaId   models.MediaId `json:"mediaId"`
 	}{
-		SignedUrl: signedUrl,
-		PutedUrl:  destinationUrl,
+		SignedUrl: output.UploadURL.SignedURL,
+		PutedUrl:  output.UploadURL.ObjectURL,
+		FileName:  output.UploadURL.FileName,
+		MediaId:   output.Media.Id,
 	}
 	if err := response.RespondJSON(w, r, http.StatusOK, resp); err != nil {
 		logger.Error(fmt.Sprintf("failed to validate request body: %v", err))
@@ -83,7 +102,9 @@ func (g *GenerateContentsImageSignedURLHandler) ServeHTTP(w http.ResponseWriter,
 	ctx := r.Context()
 	logger := logging.GetLogger(ctx)
 	var reqBody struct {
-		FileName string `json:"fileName" validate:"required"`
+		FileName    string `json:"fileName" validate:"required"`
+		ContentType string `json:"contentType" validate:"required"`
+		Size        int64  `json:"size" validate:"required,gt=0"`
 	}
 	defer r.Body.Close()
 	if err := response.JsonToStruct(r, &reqBody); err != nil {
@@ -98,21 +119,138 @@ func (g *GenerateContentsImageSignedURLHandler) ServeHTTP(w http.ResponseWriter,
 		return
 	}
 
-	signedUrl, destinationUrl, err := g.Usecase.Run(ctx, reqBody.FileName)
+	output, err := g.Usecase.Run(ctx, reqBody.FileName, reqBody.ContentType, reqBody.Size)
 	if err != nil {
+		if errors.Is(err, storage_presigned_content.ErrInvalidUpload) {
+			response.ResponsdBadRequest(w, r, err)
+			return
+		}
 		logger.Error(fmt.Sprintf("failed to validate request body: %v", err))
 		response.ResponsdInternalServerError(w, r, err)
 		return
 	}
 
+	// アップロード時は署名に含まれるContent-TypeとContent-Lengthを指定する
+	// アップロード後はmediaIdを指定して完了を通知する
 	resp := struct {
-		SignedUrl string `json:"signedUrl"`
-		PutedUrl  string `json:"putUrl"`
+		SignedUrl string         `json:"signedUrl"`
+		PutedUrl  string         `json:"putUrl"`
+		FileName  string         `json:"fileName"`
+		MediaId   models.MediaId `json:"mediaId"`
 	}{
-		SignedUrl: signedUrl,
-		PutedUrl:  destinationUrl,
+		SignedUrl: output.UploadURL.SignedURL,
+		PutedUrl:  output.UploadURL.ObjectURL,
+		FileName:  output.UploadURL.FileName,
+		MediaId:   output.Media.Id,
 	}
 	if err := response.RespondJSON(w, r, http.StatusOK, resp); err != nil {
 		logger.Error(fmt.Sprintf("failed to validate request body: %v", err))
 	}
 }
+
+type LocalStoragePutHandler struct {
+	Usecase *storage_local_put.Usecase
+}
+
+func NewLocalStoragePutHandler(usecase *storage_local_put.Usecase) *LocalStoragePutHandler {
+	return &LocalStoragePutHandler{
+		Usecase: usecase,
+	}
+}
+
+func (l *LocalStoragePutHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+	key := chi.URLParam(r, "*")
+	v := r.URL.Query()
+	defer r.Body.Close()
+	err := l.Usecase.Run(
+		ctx, key, r.Header.Get("Content-Type"), r.ContentLength, v.Get("expires"), v.Get("signature"), r.Body)
+	if err != nil {
+		if errors.Is(err, adapter.ErrInvalidObjectKey) {
+			response.ResponsdBadRequest(w, r, err)
+			return
+		}
+		if errors.Is(err, adapter.ErrInvalidSignature) || errors.Is(err, adapter.ErrSignatureExpired) {
+			response.RespondForbidden(w, r, err)
+			return
+		}
+		logger.Error(fmt.Sprintf("failed to put object: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	// S3のPutObjectと同様にボディは返却しない
+	w.WriteHeader(http.StatusOK)
+}
+
+type LocalStorageGetHandler struct {
+	Usecase *storage_local_get.Usecase
+}
+
+func NewLocalStorageGetHandler(usecase *storage_local_get.Usecase) *LocalStorageGetHandler {
+	return &LocalStorageGetHandler{
+		Usecase: usecase,
+	}
+}
+
+func (l *LocalStorageGetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+	f, err := l.Usecase.Run(ctx, chi.URLParam(r, "*"))
+	if err != nil {
+		if errors.Is(err, adapter.ErrInvalidObjectKey) || errors.Is(err, adapter.ErrObjectNotFound) {
+			response.ResponsdNotFound(w, r, err)
+			return
+		}
+		logger.Error(fmt.Sprintf("failed to get object: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to stat object: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	// CORSミドルウェアが設定したContent-Typeを外し、ファイル名の拡張子から判定させる
+	w.Header().Del("Content-Type")
+	http.ServeContent(w, r, info.Name(), info.ModTime(), f)
+}
+
+type ProcessThumbnailImageHandler struct {
+	Usecase *process_thumbnail.Usecase
+}
+
+func NewProcessThumbnailImageHandler(usecase *process_thumbnail.Usecase) *ProcessThumbnailImageHandler {
+	return &ProcessThumbnailImageHandler{
+		Usecase: usecase,
+	}
+}
+
+func (p *ProcessThumbnailImageHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+	variants, err := p.Usecase.Run(ctx, chi.URLParam(r, "key"))
+	if err != nil {
+		if errors.Is(err, process_thumbnail.ErrImageNotFound) {
+			response.ResponsdNotFound(w, r, err)
+			return
+		}
+		if errors.Is(err, process_thumbnail.ErrInvalidImage) {
+			response.ResponsdBadRequest(w, r, err)
+			return
+		}
+		logger.Error(fmt.Sprintf("failed to process thumbnail: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	resp := struct {
+		Variants []*models.ImageVariant `json:"variants"`
+	}{
+		Variants: variants,
+	}
+	if err := response.RespondJSON(w, r, http.StatusOK, resp); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
+	}
+}
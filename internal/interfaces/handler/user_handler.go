@@ -0,0 +1,303 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/interfaces/response"
+	"github.com/shoet/blog/internal/logging"
+	"github.com/shoet/blog/internal/usecase/create_user"
+	"github.com/shoet/blog/internal/usecase/delete_user"
+	"github.com/shoet/blog/internal/usecase/get_user"
+	"github.com/shoet/blog/internal/usecase/get_users"
+	"github.com/shoet/blog/internal/usecase/put_user"
+	"github.com/shoet/blog/internal/usecase/reset_user_password"
+)
+
+func userIdFromURL(r *http.Request) (models.UserId, error) {
+	id, err := strconv.Atoi(strings.TrimSpace(chi.URLParam(r, "id")))
+	if err != nil {
+		return 0, fmt.Errorf("failed to convert id to int: %w", err)
+	}
+	return models.UserId(id), nil
+}
+
+type UserListHandler struct {
+	Usecase *get_users.Usecase
+}
+
+func NewUserListHandler(usecase *get_users.Usecase) *UserListHandler {
+	return &UserListHandler{
+		Usecase: usecase,
+	}
+}
+
+func (h *UserListHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+	users, err := h.Usecase.Run(ctx)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to list users: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	if err := response.RespondJSON(w, r, http.StatusOK, users); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
+	}
+}
+
+type UserGetHandler struct {
+	Usecase *get_user.Usecase
+}
+
+func NewUserGetHandler(usecase *get_user.Usecase) *UserGetHandler {
+	return &UserGetHandler{
+		Usecase: usecase,
+	}
+}
+
+func (h *UserGetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+	id, err := userIdFromURL(r)
+	if err != nil {
+		logger.Error(err.Error())
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+	user, err := h.Usecase.Run(ctx, id)
+	if err != nil {
+		if errors.Is(err, get_user.ErrUserNotFound) {
+			response.ResponsdNotFound(w, r, err)
+			return
+		}
+		logger.Error(fmt.Sprintf("failed to get user: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	if err := response.RespondJSON(w, r, http.StatusOK, user); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
+	}
+}
+
+type UserAddHandler struct {
+	Usecase   *create_user.Usecase
+	Validator *validator.Validate
+}
+
+func NewUserAddHandler(usecase *create_user.Usecase, validator *validator.Validate) *UserAddHandler {
+	return &UserAddHandler{
+		Usecase:   usecase,
+		Validator: validator,
+	}
+}
+
+func (h *UserAddHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+	var reqBody struct {
+		Name     string      `json:"name" validate:"required,max=255"`
+		Email    string      `json:"email" validate:"required,email,max=255"`
+		Password string      `json:"password" validate:"required,min=8"`
+		Role     models.Role `json:"role" validate:"required"`
+	}
+	defer r.Body.Close()
+	if err := response.JsonToStruct(r, &reqBody); err != nil {
+		logger.Error(fmt.Sprintf("failed to parse request body: %v", err))
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+	if err := h.Validator.Struct(reqBody); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request body: %v", err))
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+	user, err := h.Usecase.Run(ctx, &create_user.Input{
+		Name:     reqBody.Name,
+		Email:    reqBody.Email,
+		Password: reqBody.Password,
+		Role:     reqBody.Role,
+	})
+	if err != nil {
+		if errors.Is(err, create_user.ErrInvalidRole) ||
+			errors.Is(err, create_user.ErrEmailAlreadyExists) {
+			response.ResponsdBadRequest(w, r, err)
+			return
+		}
+		logger.Error(fmt.Sprintf("failed to add user: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	if err := response.RespondJSON(w, r, http.StatusOK, user); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
+	}
+}
+
+type UserPutHandler struct {
+	Usecase   *put_user.Usecase
+	Validator *validator.Validate
+}
+
+func NewUserPutHandler(usecase *put_user.Usecase, validator *validator.Validate) *UserPutHandler {
+	return &UserPutHandler{
+		Usecase:   usecase,
+		Validator: validator,
+	}
+}
+
+func (h *UserPutHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+	id, err := userIdFromURL(r)
+	if err != nil {
+		logger.Error(err.Error())
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+	var reqBody struct {
+		Name     string      `json:"name" validate:"required,max=255"`
+		Email    string      `json:"email" validate:"required,email,max=255"`
+		Role     models.Role `json:"role" validate:"required"`
+		Disabled bool        `json:"disabled"`
+	}
+	defer r.Body.Close()
+	if err := response.JsonToStruct(r, &reqBody); err != nil {
+		logger.Error(fmt.Sprintf("failed to parse request body: %v", err))
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+	if err := h.Validator.Struct(reqBody); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request body: %v", err))
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+	user, err := h.Usecase.Run(ctx, id, &put_user.Input{
+		Name:     reqBody.Name,
+		Email:    reqBody.Email,
+		Role:     reqBody.Role,
+		Disabled: reqBody.Disabled,
+	})
+	if err != nil {
+		if errors.Is(err, put_user.ErrUserNotFound) {
+			response.ResponsdNotFound(w, r, err)
+			return
+		}
+		if errors.Is(err, put_user.ErrInvalidRole) ||
+			errors.Is(err, put_user.ErrEmailAlreadyExists) ||
+			errors.Is(err, put_user.ErrLastAdmin) {
+			response.ResponsdBadRequest(w, r, err)
+			return
+		}
+		logger.Error(fmt.Sprintf("failed to put user: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	if err := response.RespondJSON(w, r, http.StatusOK, user); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
+	}
+}
+
+type UserDeleteHandler struct {
+	Usecase *delete_user.Usecase
+}
+
+func NewUserDeleteHandler(usecase *delete_user.Usecase) *UserDeleteHandler {
+	return &UserDeleteHandler{
+		Usecase: usecase,
+	}
+}
+
+func (h *UserDeleteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+	id, err := userIdFromURL(r)
+	if err != nil {
+		logger.Error(err.Error())
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+	if err := h.Usecase.Run(ctx, id); err != nil {
+		if errors.Is(err, delete_user.ErrUserNotFound) {
+			response.ResponsdNotFound(w, r, err)
+			return
+		}
+		if errors.Is(err, delete_user.ErrLastAdmin) ||
+			errors.Is(err, delete_user.ErrUserHasBlogs) {
+			response.ResponsdBadRequest(w, r, err)
+			return
+		}
+		logger.Error(fmt.Sprintf("failed to delete user: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	resp := struct {
+		Id int `json:"id"`
+	}{
+		Id: int(id),
+	}
+	if err := response.RespondJSON(w, r, http.StatusOK, resp); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
+	}
+}
+
+type UserPasswordResetHandler struct {
+	Usecase   *reset_user_password.Usecase
+	Validator *validator.Validate
+}
+
+func NewUserPasswordResetHandler(
+	usecase *reset_user_password.Usecase, validator *validator.Validate,
+) *UserPasswordResetHandler {
+	return &UserPasswordResetHandler{
+		Usecase:   usecase,
+		Validator: validator,
+	}
+}
+
+func (h *UserPasswordResetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+	id, err := userIdFromURL(r)
+	if err != nil {
+		logger.Error(err.Error())
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+	var reqBody struct {
+		NewPassword string `json:"newPassword" validate:"required,min=8"`
+	}
+	defer r.Body.Close()
+	if err := response.JsonToStruct(r, &reqBody); err != nil {
+		logger.Error(fmt.Sprintf("failed to parse request body: %v", err))
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+	if err := h.Validator.Struct(reqBody); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request body: %v", err))
+		response.ResponsdBadRequest(w, r, err)
+		return
+	}
+	if err := h.Usecase.Run(ctx, id, reqBody.NewPassword); err != nil {
+		if errors.Is(err, reset_user_password.ErrUserNotFound) {
+			response.ResponsdNotFound(w, r, err)
+			return
+		}
+		logger.Error(fmt.Sprintf("failed to reset user password: %v", err))
+		response.ResponsdInternalServerError(w, r, err)
+		return
+	}
+	resp := struct {
+		Id int `json:"id"`
+	}{
+		Id: int(id),
+	}
+	if err := response.RespondJSON(w, r, http.StatusOK, resp); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond json response: %v", err))
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/shoet/blog/internal/apikey"
 	"github.com/shoet/blog/internal/config"
 	"github.com/shoet/blog/internal/infrastracture/models"
 	"github.com/shoet/blog/internal/interfaces/response"
@@ -51,17 +52,43 @@ type JWTService interface {
 	VerifyToken(ctx context.Context, token string) (models.UserId, error)
 }
 
+type APIKeyVerifier interface {
+	VerifyAPIKey(ctx context.Context, key string) (*models.APIKey, error)
+}
+
+type UserLoader interface {
+	GetUser(ctx context.Context, userId models.UserId) (*models.User, error)
+}
+
 type AuthorizationMiddleware struct {
-	jwter JWTService
+	jwter  JWTService
+	apiKey APIKeyVerifier
+	users  UserLoader
 }
 
-func NewAuthorizationMiddleware(jwter JWTService) *AuthorizationMiddleware {
+func NewAuthorizationMiddleware(
+	jwter JWTService, apiKey APIKeyVerifier, users UserLoader,
+) *AuthorizationMiddleware {
 	return &AuthorizationMiddleware{
-		jwter: jwter,
+		jwter:  jwter,
+		apiKey: apiKey,
+		users:  users,
 	}
 }
 
+// Middleware は、JWTでの認証のみを受け付ける
 func (a *AuthorizationMiddleware) Middleware(next http.Handler) http.Handler {
+	return a.authorize(next, "")
+}
+
+// RequireScope は、JWTに加えて、scopeを持つAPIキーでの認証も受け付けるミドルウェアを返す
+func (a *AuthorizationMiddleware) RequireScope(scope models.APIKeyScope) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return a.authorize(next, scope)
+	}
+}
+
+func (a *AuthorizationMiddleware) authorize(next http.Handler, scope models.APIKeyScope) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 		logger := logging.GetLogger(ctx)
@@ -74,6 +101,27 @@ func (a *AuthorizationMiddleware) Middleware(next http.Handler) http.Handler {
 		}
 
 		token = strings.TrimPrefix(token, "Bearer ")
+		if apikey.IsAPIKey(token) {
+			if scope == "" {
+				logger.Error("api key is not allowed")
+				response.RespondForbidden(w, r, fmt.Errorf("api key is not allowed"))
+				return
+			}
+			k, err := a.apiKey.VerifyAPIKey(ctx, token)
+			if err != nil {
+				logger.Error(fmt.Sprintf("failed to verify api key: %v", err))
+				response.RespondUnauthorized(w, r, fmt.Errorf("failed to verify api key"))
+				return
+			}
+			if !k.Scopes.Has(scope) {
+				logger.Error(fmt.Sprintf("api key does not have scope: %s", scope))
+				response.RespondForbidden(w, r, fmt.Errorf("api key does not have scope"))
+				return
+			}
+			a.serveWithUser(w, r, next, k.UserId)
+			return
+		}
+
 		userId, err := a.jwter.VerifyToken(ctx, token)
 		if err != nil {
 			logger.Error(fmt.Sprintf("failed to verify token: %v", err))
@@ -81,12 +129,31 @@ func (a *AuthorizationMiddleware) Middleware(next http.Handler) http.Handler {
 			return
 		}
 
-		// set UserId to context
-		ctx = session.SetUserId(ctx, userId)
-		next.ServeHTTP(w, r.WithContext(ctx))
+		a.serveWithUser(w, r, next, userId)
 	})
 }
 
+// serveWithUser は、認証済みのユーザーのIDと権限をコンテキストに設定して次のハンドラを呼び出す
+// 権限の変更をすぐに反映するため、権限はトークンに含めずリクエストごとに取得する
+func (a *AuthorizationMiddleware) serveWithUser(
+	w http.ResponseWriter, r *http.Request, next http.Handler, userId models.UserId,
+) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+
+	user, err := a.users.GetUser(ctx, userId)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to get user: %v", err))
+		response.RespondUnauthorized(w, r, fmt.Errorf("failed to get user"))
+		return
+	}
+
+	// set UserId and Role to context
+	ctx = session.SetUserId(ctx, userId)
+	ctx = session.SetRole(ctx, user.Role)
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
+
 // ChallengeAuthorizationHeader は、Authorizationヘッダが大文字・小文字のどちらであっても認証トークンを受け取れるようにする
 func (a *AuthorizationMiddleware) ChallengeAuthorizationHeader(h http.Header) (string, error) {
 	authorizationHeader := []string{"Authorization", "authorization"}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/shoet/blog/internal/interfaces/response"
+	"github.com/shoet/blog/internal/logging"
+	"github.com/shoet/blog/internal/policy"
+	"github.com/shoet/blog/internal/session"
+)
+
+// NewPermissionMiddleware は、ユーザーの権限がpermissionを持たない場合に403を返却するミドルウェアを返す
+// AuthorizationMiddlewareの後に適用する
+func NewPermissionMiddleware(permission policy.Permission) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			logger := logging.GetLogger(ctx)
+
+			role := session.GetRole(ctx)
+			if !policy.Can(role, permission) {
+				logger.Error(fmt.Sprintf("role %q does not have permission: %s", role, permission))
+				response.RespondForbidden(w, r, fmt.Errorf("permission denied"))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/shoet/blog/internal/interfaces/clientip"
+	"github.com/shoet/blog/internal/interfaces/response"
+	"github.com/shoet/blog/internal/logging"
+)
+
+type Counter interface {
+	Increment(ctx context.Context, key string, window time.Duration) (int64, error)
+}
+
+// RateLimitMiddleware は、クライアントのIPアドレスごとにリクエスト数を制限する
+// window の期間内に limit を超えたリクエストは429を返却する
+type RateLimitMiddleware struct {
+	counter Counter
+	name    string
+	limit   int64
+	window  time.Duration
+}
+
+func NewRateLimitMiddleware(counter Counter, name string, limit int64, window time.Duration) *RateLimitMiddleware {
+	return &RateLimitMiddleware{
+		counter: counter,
+		name:    name,
+		limit:   limit,
+		window:  window,
+	}
+}
+
+func (m *RateLimitMiddleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		logger := logging.GetLogger(ctx)
+
+		key := fmt.Sprintf("ratelimit:%s:%s", m.name, clientip.FromRequest(r))
+		count, err := m.counter.Increment(ctx, key, m.window)
+		if err != nil {
+			// カウンタが利用できない場合はリクエストを制限しない
+			logger.Error(fmt.Sprintf("failed to increment rate limit counter: %v", err))
+			next.ServeHTTP(w, r)
+			return
+		}
+		if count > m.limit {
+			w.Header().Set("Retry-After", strconv.Itoa(int(m.window.Seconds())))
+			response.RespondTooManyRequests(w, r, fmt.Errorf("rate limit exceeded"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
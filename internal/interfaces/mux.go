@@ -2,7 +2,9 @@ package interfaces
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
@@ -10,26 +12,72 @@ import (
 	"github.com/shoet/blog/internal/config"
 	"github.com/shoet/blog/internal/infrastracture"
 	"github.com/shoet/blog/internal/infrastracture/adapter"
+	"github.com/shoet/blog/internal/infrastracture/models"
 	"github.com/shoet/blog/internal/infrastracture/repository"
 	"github.com/shoet/blog/internal/infrastracture/services/auth_service"
-	"github.com/shoet/blog/internal/infrastracture/services/blog_service"
 	"github.com/shoet/blog/internal/infrastracture/services/contents_service"
 	"github.com/shoet/blog/internal/infrastracture/services/jwt_service"
+	"github.com/shoet/blog/internal/infrastracture/services/login_guard_service"
+	"github.com/shoet/blog/internal/interfaces/clientip"
 	"github.com/shoet/blog/internal/interfaces/cookie"
 	"github.com/shoet/blog/internal/interfaces/handler"
 	"github.com/shoet/blog/internal/interfaces/middleware"
 	"github.com/shoet/blog/internal/logging"
+	"github.com/shoet/blog/internal/policy"
+	"github.com/shoet/blog/internal/usecase/activate_totp"
+	"github.com/shoet/blog/internal/usecase/change_password"
+	"github.com/shoet/blog/internal/usecase/complete_media"
+	"github.com/shoet/blog/internal/usecase/create_api_key"
 	"github.com/shoet/blog/internal/usecase/create_blog"
+	"github.com/shoet/blog/internal/usecase/create_comment"
+	"github.com/shoet/blog/internal/usecase/create_series"
+	"github.com/shoet/blog/internal/usecase/create_user"
 	"github.com/shoet/blog/internal/usecase/delete_blog"
+	"github.com/shoet/blog/internal/usecase/delete_series"
+	"github.com/shoet/blog/internal/usecase/delete_user"
+	"github.com/shoet/blog/internal/usecase/get_admin_comments"
+	"github.com/shoet/blog/internal/usecase/get_admin_media"
+	"github.com/shoet/blog/internal/usecase/get_api_keys"
+	"github.com/shoet/blog/internal/usecase/get_author"
+	"github.com/shoet/blog/internal/usecase/get_blog_by_slug"
 	"github.com/shoet/blog/internal/usecase/get_blog_detail"
+	"github.com/shoet/blog/internal/usecase/get_blog_revision_diff"
+	"github.com/shoet/blog/internal/usecase/get_blog_revisions"
 	"github.com/shoet/blog/internal/usecase/get_blogs"
 	"github.com/shoet/blog/internal/usecase/get_blogs_offset_paging"
+	"github.com/shoet/blog/internal/usecase/get_comments"
 	"github.com/shoet/blog/internal/usecase/get_github_contributions"
 	"github.com/shoet/blog/internal/usecase/get_github_contributions_latest_week"
+	"github.com/shoet/blog/internal/usecase/get_related_blogs"
+	"github.com/shoet/blog/internal/usecase/get_series"
+	"github.com/shoet/blog/internal/usecase/get_series_list"
+	"github.com/shoet/blog/internal/usecase/get_sessions"
+	"github.com/shoet/blog/internal/usecase/get_sitemap"
 	"github.com/shoet/blog/internal/usecase/get_tags"
+	"github.com/shoet/blog/internal/usecase/get_user"
+	"github.com/shoet/blog/internal/usecase/get_users"
 	"github.com/shoet/blog/internal/usecase/login_user"
 	"github.com/shoet/blog/internal/usecase/login_user_session"
+	"github.com/shoet/blog/internal/usecase/login_user_two_factor"
+	"github.com/shoet/blog/internal/usecase/logout_user"
+	"github.com/shoet/blog/internal/usecase/logout_user_all"
+	"github.com/shoet/blog/internal/usecase/moderate_comment"
+	"github.com/shoet/blog/internal/usecase/process_thumbnail"
 	"github.com/shoet/blog/internal/usecase/put_blog"
+	"github.com/shoet/blog/internal/usecase/put_profile"
+	"github.com/shoet/blog/internal/usecase/put_series"
+	"github.com/shoet/blog/internal/usecase/put_series_parts"
+	"github.com/shoet/blog/internal/usecase/put_user"
+	"github.com/shoet/blog/internal/usecase/refresh_token"
+	"github.com/shoet/blog/internal/usecase/request_password_reset"
+	"github.com/shoet/blog/internal/usecase/reset_password"
+	"github.com/shoet/blog/internal/usecase/reset_user_password"
+	"github.com/shoet/blog/internal/usecase/restore_blog_revision"
+	"github.com/shoet/blog/internal/usecase/revoke_api_key"
+	"github.com/shoet/blog/internal/usecase/revoke_session"
+	"github.com/shoet/blog/internal/usecase/setup_totp"
+	"github.com/shoet/blog/internal/usecase/storage_local_get"
+	"github.com/shoet/blog/internal/usecase/storage_local_put"
 	"github.com/shoet/blog/internal/usecase/storage_presigned_content"
 	"github.com/shoet/blog/internal/usecase/storage_presigned_thumbnail"
 )
@@ -39,15 +87,24 @@ type MuxDependencies struct {
 	DB                   infrastracture.DB
 	BlogRepository       *repository.BlogRepository
 	BlogRepositoryOffset *repository.BlogRepositoryOffset
-	BlogService          *blog_service.BlogService
+	Policy               *policy.Policy
+	CommentRepository    *repository.CommentRepository
+	MediaRepository      *repository.MediaRepository
+	SeriesRepository     *repository.SeriesRepository
+	UserRepository       *repository.UserRepository
+	APIKeyRepository     *repository.APIKeyRepository
 	AuthService          *auth_service.AuthService
+	LoginGuard           *login_guard_service.LoginGuardService
 	ContentsService      *contents_service.ContentsService
+	LocalStorage         *adapter.LocalStorageAdapter // ローカルストレージを使用しない場合はnil
 	JWTer                *jwt_service.JWTService
 	Logger               *logging.Logger
 	Validator            *validator.Validate
 	Cookie               *cookie.CookieController
 	GitHubAPIAdapter     *adapter.GitHubV4APIClient
+	Mailer               adapter.Mailer
 	Clocker              clocker.Clocker
+	KVS                  *infrastracture.RedisKVS
 }
 
 func NewMux(
@@ -55,18 +112,26 @@ func NewMux(
 ) (*chi.Mux, error) {
 	log.Printf("set middleware")
 	router := chi.NewRouter()
-	authMiddleWare := middleware.NewAuthorizationMiddleware(deps.JWTer)
+	authMiddleWare := middleware.NewAuthorizationMiddleware(deps.JWTer, deps.AuthService, deps.AuthService)
 	corsMiddleWare := middleware.NewCORSMiddleWare(deps.Config)
-	router.Use(logging.WithLoggerMiddleware(deps.Logger), corsMiddleWare)
+	// X-Forwarded-Forは信頼するプロキシを経由した場合のみ参照する
+	clientIPResolver, err := clientip.NewResolver(deps.Config.TrustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client ip resolver: %w", err)
+	}
+	router.Use(logging.WithLoggerMiddleware(deps.Logger), clientIPResolver.Middleware, corsMiddleWare)
 
 	log.Printf("set routes")
 	setHealthRoute(router)
 	setBlogsRoute(router, deps, authMiddleWare)
 	setTagsRoute(router, deps)
+	setAuthorsRoute(router, deps)
 	setFilesRoute(router, deps, authMiddleWare)
-	setAuthRoute(router, deps)
+	setAuthRoute(router, deps, authMiddleWare)
 	setAdminRoute(router, deps, authMiddleWare)
 	setGitHubRoute(router, deps)
+	setFeedRoute(router, deps)
+	setSitemapRoute(router, deps)
 	return router, nil
 }
 
@@ -80,31 +145,61 @@ func setHealthRoute(r chi.Router) {
 func setBlogsRoute(
 	r chi.Router, deps *MuxDependencies, authMiddleWare *middleware.AuthorizationMiddleware,
 ) {
+	requireBlogsWrite := middleware.NewPermissionMiddleware(policy.PermissionBlogsWrite)
+
 	r.Route("/blogs", func(r chi.Router) {
-		blh := handler.NewBlogListHandler(get_blogs.NewUsecase(deps.DB, deps.BlogRepository))
+		blh := handler.NewBlogListHandler(get_blogs.NewUsecase(deps.DB, deps.BlogRepository, deps.UserRepository, deps.ContentsService))
 		r.Get("/", blh.ServeHTTP)
 
 		bah := handler.NewBlogAddHandler(
-			create_blog.NewUsecase(deps.DB, deps.BlogRepository, deps.BlogService),
+			create_blog.NewUsecase(deps.DB, deps.BlogRepository, deps.MediaRepository, deps.Policy),
 			deps.Validator)
-		r.With(authMiddleWare.Middleware).Post("/", bah.ServeHTTP)
+		r.With(authMiddleWare.RequireScope(models.APIKeyScopeBlogsWrite), requireBlogsWrite).Post("/", bah.ServeHTTP)
 
 		bgh := handler.NewBlogGetHandler(
-			get_blog_detail.NewUsecase(deps.DB, deps.BlogRepository), deps.JWTer)
+			get_blog_detail.NewUsecase(
+				deps.DB, deps.BlogRepository, deps.SeriesRepository, deps.UserRepository, deps.ContentsService, deps.Clocker),
+			deps.JWTer, deps.Clocker)
 		r.Get("/{id}", bgh.ServeHTTP)
 
+		bgsh := handler.NewBlogGetBySlugHandler(
+			get_blog_by_slug.NewUsecase(
+				deps.DB, deps.BlogRepository, deps.SeriesRepository, deps.UserRepository, deps.ContentsService, deps.Clocker),
+			deps.JWTer, deps.Clocker)
+		r.Get("/by-slug/{slug}", bgsh.ServeHTTP)
+
 		bdh := handler.NewBlogDeleteHandler(
-			delete_blog.NewUsecase(deps.DB, deps.BlogRepository), deps.Validator)
-		r.With(authMiddleWare.Middleware).Delete("/{id}", bdh.ServeHTTP)
+			delete_blog.NewUsecase(deps.DB, deps.BlogRepository, deps.Policy), deps.Validator)
+		r.With(authMiddleWare.RequireScope(models.APIKeyScopeBlogsWrite), requireBlogsWrite).Delete("/{id}", bdh.ServeHTTP)
 
 		buh := handler.NewBlogPutHandler(
-			put_blog.NewUsecase(deps.DB, deps.BlogRepository), deps.Validator)
-		r.With(authMiddleWare.Middleware).Put("/{id}", buh.ServeHTTP)
+			put_blog.NewUsecase(deps.DB, deps.BlogRepository, deps.MediaRepository, deps.Policy), deps.Validator)
+		r.With(authMiddleWare.RequireScope(models.APIKeyScopeBlogsWrite), requireBlogsWrite).Put("/{id}", buh.ServeHTTP)
+
+		commentRateLimit := middleware.NewRateLimitMiddleware(
+			deps.KVS,
+			"comments",
+			int64(deps.Config.CommentRateLimit),
+			time.Duration(deps.Config.CommentRateLimitWindowSec)*time.Second,
+		)
+		cah := handler.NewCommentAddHandler(
+			create_comment.NewUsecase(deps.DB, deps.BlogRepository, deps.CommentRepository, deps.Clocker),
+			deps.Validator)
+		r.With(commentRateLimit.Middleware).Post("/{id}/comments", cah.ServeHTTP)
+
+		clh := handler.NewCommentListHandler(
+			get_comments.NewUsecase(deps.DB, deps.BlogRepository, deps.CommentRepository, deps.Clocker))
+		r.Get("/{id}/comments", clh.ServeHTTP)
+
+		brh := handler.NewBlogRelatedHandler(
+			get_related_blogs.NewUsecase(
+				deps.DB, deps.BlogRepository, deps.UserRepository, deps.ContentsService, deps.Clocker))
+		r.Get("/{id}/related", brh.ServeHTTP)
 	})
 
 	r.Route("/v2/blogs", func(r chi.Router) {
 		blh := handler.NewBlogGetOffsetPagingHandler(
-			get_blogs_offset_paging.NewUsecase(deps.DB, deps.BlogRepositoryOffset),
+			get_blogs_offset_paging.NewUsecase(deps.DB, deps.BlogRepositoryOffset, deps.UserRepository, deps.ContentsService),
 		)
 		r.Get("/", blh.ServeHTTP)
 	})
@@ -117,44 +212,221 @@ func setTagsRoute(r chi.Router, deps *MuxDependencies) {
 	})
 }
 
+func setAuthorsRoute(r chi.Router, deps *MuxDependencies) {
+	r.Route("/authors", func(r chi.Router) {
+		agh := handler.NewAuthorGetHandler(
+			get_author.NewUsecase(deps.DB, deps.UserRepository, deps.BlogRepository, deps.ContentsService))
+		r.Get("/{id}", agh.ServeHTTP)
+	})
+}
+
 func setFilesRoute(
 	r chi.Router, deps *MuxDependencies, authMiddleWare *middleware.AuthorizationMiddleware,
 ) {
+	requireFilesWrite := middleware.NewPermissionMiddleware(policy.PermissionFilesWrite)
+
 	r.Route("/files", func(r chi.Router) {
 		gt := handler.NewGenerateThumbnailImageSignedURLHandler(
-			storage_presigned_thumbnail.NewUsecase(deps.ContentsService),
+			storage_presigned_thumbnail.NewUsecase(deps.DB, deps.ContentsService),
 			deps.Validator)
-		r.With(authMiddleWare.Middleware).Post("/thumbnail/new", gt.ServeHTTP)
+		r.With(authMiddleWare.RequireScope(models.APIKeyScopeFilesWrite), requireFilesWrite).Post("/thumbnail/new", gt.ServeHTTP)
+
+		pt := handler.NewProcessThumbnailImageHandler(process_thumbnail.NewUsecase(deps.DB, deps.ContentsService))
+		r.With(authMiddleWare.RequireScope(models.APIKeyScopeFilesWrite), requireFilesWrite).Post("/thumbnail/{key}/process", pt.ServeHTTP)
 
 		gc := handler.NewGenerateContentsImageSignedURLHandler(
-			storage_presigned_content.NewUsecase(deps.ContentsService),
+			storage_presigned_content.NewUsecase(deps.DB, deps.ContentsService),
 			deps.Validator)
-		r.With(authMiddleWare.Middleware).Post("/content/new", gc.ServeHTTP)
+		r.With(authMiddleWare.RequireScope(models.APIKeyScopeFilesWrite), requireFilesWrite).Post("/content/new", gc.ServeHTTP)
+
+		mch := handler.NewMediaCompleteHandler(
+			complete_media.NewUsecase(deps.DB, deps.MediaRepository, deps.ContentsService, deps.Policy))
+		r.With(authMiddleWare.RequireScope(models.APIKeyScopeFilesWrite), requireFilesWrite).Post("/media/{id}/complete", mch.ServeHTTP)
+
+		// ローカルストレージを使用する場合は、署名付きURLのアップロード先と配信を提供する
+		if deps.LocalStorage != nil {
+			lph := handler.NewLocalStoragePutHandler(storage_local_put.NewUsecase(deps.LocalStorage))
+			r.Put("/local/*", lph.ServeHTTP)
+
+			lgh := handler.NewLocalStorageGetHandler(storage_local_get.NewUsecase(deps.LocalStorage))
+			r.Get("/local/*", lgh.ServeHTTP)
+		}
 	})
 }
 
-func setAuthRoute(r chi.Router, deps *MuxDependencies) {
+func setAuthRoute(
+	r chi.Router, deps *MuxDependencies, authMiddleWare *middleware.AuthorizationMiddleware,
+) {
 	r.Route("/auth", func(r chi.Router) {
 		ah := handler.NewAuthLoginHandler(
-			login_user.NewUsecase(deps.AuthService),
+			login_user.NewUsecase(deps.AuthService, deps.LoginGuard),
 			deps.Validator,
 			deps.Cookie)
 		r.Post("/signin", ah.ServeHTTP)
 
+		atfh := handler.NewAuthLoginTwoFactorHandler(
+			login_user_two_factor.NewUsecase(deps.AuthService, deps.LoginGuard),
+			deps.Validator,
+			deps.Cookie)
+		r.Post("/signin/2fa", atfh.ServeHTTP)
+
+		arh := handler.NewAuthRefreshHandler(
+			refresh_token.NewUsecase(deps.AuthService),
+			deps.Validator,
+			deps.Cookie)
+		r.Post("/refresh", arh.ServeHTTP)
+
 		ash := handler.NewAuthSessionLoginHandler(login_user_session.NewUsecase(deps.AuthService))
 		r.Get("/signin/me", ash.ServeHTTP)
 
-		alh := handler.NewAuthLogoutHandler(deps.Cookie)
+		alh := handler.NewAuthLogoutHandler(logout_user.NewUsecase(deps.AuthService), deps.Cookie)
 		r.Post("/signout", alh.ServeHTTP)
+
+		alah := handler.NewAuthLogoutAllHandler(logout_user_all.NewUsecase(deps.AuthService), deps.Cookie)
+		r.With(authMiddleWare.Middleware).Post("/signout-all", alah.ServeHTTP)
+
+		aslh := handler.NewAuthSessionListHandler(get_sessions.NewUsecase(deps.AuthService))
+		r.With(authMiddleWare.Middleware).Get("/sessions", aslh.ServeHTTP)
+
+		asrh := handler.NewAuthSessionRevokeHandler(revoke_session.NewUsecase(deps.AuthService))
+		r.With(authMiddleWare.Middleware).Delete("/sessions/{jti}", asrh.ServeHTTP)
+
+		atsh := handler.NewAuthTOTPSetupHandler(
+			setup_totp.NewUsecase(deps.DB, deps.UserRepository, deps.Config.TOTPIssuer))
+		r.With(authMiddleWare.Middleware).Post("/2fa/setup", atsh.ServeHTTP)
+
+		atah := handler.NewAuthTOTPActivateHandler(
+			activate_totp.NewUsecase(deps.DB, deps.UserRepository, deps.Clocker), deps.Validator)
+		r.With(authMiddleWare.Middleware).Post("/2fa/activate", atah.ServeHTTP)
+
+		apch := handler.NewAuthPasswordChangeHandler(
+			change_password.NewUsecase(deps.DB, deps.UserRepository, deps.AuthService),
+			deps.Validator,
+			deps.Cookie)
+		r.With(authMiddleWare.Middleware).Post("/password/change", apch.ServeHTTP)
+
+		apph := handler.NewAuthProfilePutHandler(
+			put_profile.NewUsecase(deps.DB, deps.UserRepository), deps.Validator)
+		r.With(authMiddleWare.Middleware).Put("/profile", apph.ServeHTTP)
+
+		aklh := handler.NewAPIKeyListHandler(get_api_keys.NewUsecase(deps.DB, deps.APIKeyRepository))
+		r.With(authMiddleWare.Middleware).Get("/api-keys", aklh.ServeHTTP)
+
+		akah := handler.NewAPIKeyAddHandler(
+			create_api_key.NewUsecase(deps.DB, deps.APIKeyRepository), deps.Validator)
+		r.With(authMiddleWare.Middleware).Post("/api-keys", akah.ServeHTTP)
+
+		akrh := handler.NewAPIKeyRevokeHandler(revoke_api_key.NewUsecase(deps.DB, deps.APIKeyRepository))
+		r.With(authMiddleWare.Middleware).Delete("/api-keys/{id}", akrh.ServeHTTP)
+
+		aprh := handler.NewAuthPasswordResetRequestHandler(
+			request_password_reset.NewUsecase(
+				deps.DB,
+				deps.UserRepository,
+				deps.JWTer,
+				deps.Mailer,
+				deps.Config.PasswordResetURL,
+				deps.Config.MailFrom,
+				time.Duration(deps.Config.PasswordResetExpiresInSec)*time.Second,
+			),
+			deps.Validator)
+		passwordResetRateLimit := middleware.NewRateLimitMiddleware(
+			deps.KVS, "password_reset", int64(deps.Config.PasswordResetRateLimit), time.Hour)
+		r.With(passwordResetRateLimit.Middleware).Post("/password/reset", aprh.ServeHTTP)
+
+		aprch := handler.NewAuthPasswordResetConfirmHandler(
+			reset_password.NewUsecase(deps.DB, deps.UserRepository, deps.JWTer, deps.AuthService),
+			deps.Validator)
+		r.Post("/password/reset/confirm", aprch.ServeHTTP)
 	})
 }
 
 func setAdminRoute(
 	r chi.Router, deps *MuxDependencies, authMiddleWare *middleware.AuthorizationMiddleware,
 ) {
+	requireBlogsRead := middleware.NewPermissionMiddleware(policy.PermissionBlogsRead)
+	requireBlogsWrite := middleware.NewPermissionMiddleware(policy.PermissionBlogsWrite)
+	requireCommentsModerate := middleware.NewPermissionMiddleware(policy.PermissionCommentsModerate)
+	requireFilesWrite := middleware.NewPermissionMiddleware(policy.PermissionFilesWrite)
+	requireSeriesRead := middleware.NewPermissionMiddleware(policy.PermissionSeriesRead)
+	requireSeriesWrite := middleware.NewPermissionMiddleware(policy.PermissionSeriesWrite)
+	requireUsersManage := middleware.NewPermissionMiddleware(policy.PermissionUsersManage)
+
 	r.Route("/admin", func(r chi.Router) {
-		bla := handler.NewBlogListAdminHandler(get_blogs.NewUsecase(deps.DB, deps.BlogRepository))
-		r.With(authMiddleWare.Middleware).Get("/blogs", bla.ServeHTTP)
+		bla := handler.NewBlogListAdminHandler(get_blogs.NewUsecase(deps.DB, deps.BlogRepository, deps.UserRepository, deps.ContentsService))
+		r.With(authMiddleWare.RequireScope(models.APIKeyScopeBlogsWrite), requireBlogsRead).Get("/blogs", bla.ServeHTTP)
+
+		brl := handler.NewBlogRevisionListHandler(
+			get_blog_revisions.NewUsecase(deps.DB, deps.BlogRepository))
+		r.With(authMiddleWare.Middleware, requireBlogsRead).Get("/blogs/{id}/revisions", brl.ServeHTTP)
+
+		brd := handler.NewBlogRevisionDiffHandler(
+			get_blog_revision_diff.NewUsecase(deps.DB, deps.BlogRepository))
+		r.With(authMiddleWare.Middleware, requireBlogsRead).Get("/blogs/{id}/revisions/diff", brd.ServeHTTP)
+
+		brr := handler.NewBlogRevisionRestoreHandler(
+			restore_blog_revision.NewUsecase(deps.DB, deps.BlogRepository, deps.MediaRepository, deps.Policy))
+		r.With(authMiddleWare.Middleware, requireBlogsWrite).Post("/blogs/{id}/revisions/{revision}/restore", brr.ServeHTTP)
+
+		cla := handler.NewCommentListAdminHandler(
+			get_admin_comments.NewUsecase(deps.DB, deps.CommentRepository))
+		r.With(authMiddleWare.Middleware, requireCommentsModerate).Get("/comments", cla.ServeHTTP)
+
+		moderateComment := moderate_comment.NewUsecase(deps.DB, deps.CommentRepository)
+		cma := handler.NewCommentModerateHandler(moderateComment, models.CommentStatusApproved)
+		r.With(authMiddleWare.Middleware, requireCommentsModerate).Post("/comments/{id}/approve", cma.ServeHTTP)
+
+		cmr := handler.NewCommentModerateHandler(moderateComment, models.CommentStatusRejected)
+		r.With(authMiddleWare.Middleware, requireCommentsModerate).Post("/comments/{id}/reject", cmr.ServeHTTP)
+
+		cms := handler.NewCommentModerateHandler(moderateComment, models.CommentStatusSpam)
+		r.With(authMiddleWare.Middleware, requireCommentsModerate).Post("/comments/{id}/spam", cms.ServeHTTP)
+
+		mla := handler.NewMediaListAdminHandler(get_admin_media.NewUsecase(deps.DB, deps.MediaRepository))
+		r.With(authMiddleWare.Middleware, requireFilesWrite).Get("/media", mla.ServeHTTP)
+
+		sla := handler.NewSeriesListHandler(get_series_list.NewUsecase(deps.DB, deps.SeriesRepository))
+		r.With(authMiddleWare.Middleware, requireSeriesRead).Get("/series", sla.ServeHTTP)
+
+		sah := handler.NewSeriesAddHandler(
+			create_series.NewUsecase(deps.DB, deps.SeriesRepository), deps.Validator)
+		r.With(authMiddleWare.Middleware, requireSeriesWrite).Post("/series", sah.ServeHTTP)
+
+		sgh := handler.NewSeriesGetHandler(get_series.NewUsecase(deps.DB, deps.SeriesRepository))
+		r.With(authMiddleWare.Middleware, requireSeriesRead).Get("/series/{id}", sgh.ServeHTTP)
+
+		suh := handler.NewSeriesPutHandler(
+			put_series.NewUsecase(deps.DB, deps.SeriesRepository), deps.Validator)
+		r.With(authMiddleWare.Middleware, requireSeriesWrite).Put("/series/{id}", suh.ServeHTTP)
+
+		sdh := handler.NewSeriesDeleteHandler(delete_series.NewUsecase(deps.DB, deps.SeriesRepository))
+		r.With(authMiddleWare.Middleware, requireSeriesWrite).Delete("/series/{id}", sdh.ServeHTTP)
+
+		sph := handler.NewSeriesPartsPutHandler(
+			put_series_parts.NewUsecase(deps.DB, deps.BlogRepository, deps.SeriesRepository), deps.Validator)
+		r.With(authMiddleWare.Middleware, requireSeriesWrite).Put("/series/{id}/parts", sph.ServeHTTP)
+
+		ulh := handler.NewUserListHandler(get_users.NewUsecase(deps.DB, deps.UserRepository))
+		r.With(authMiddleWare.Middleware, requireUsersManage).Get("/users", ulh.ServeHTTP)
+
+		uah := handler.NewUserAddHandler(
+			create_user.NewUsecase(deps.DB, deps.UserRepository), deps.Validator)
+		r.With(authMiddleWare.Middleware, requireUsersManage).Post("/users", uah.ServeHTTP)
+
+		ugh := handler.NewUserGetHandler(get_user.NewUsecase(deps.DB, deps.UserRepository))
+		r.With(authMiddleWare.Middleware, requireUsersManage).Get("/users/{id}", ugh.ServeHTTP)
+
+		uph := handler.NewUserPutHandler(
+			put_user.NewUsecase(deps.DB, deps.UserRepository, deps.Clocker, deps.AuthService), deps.Validator)
+		r.With(authMiddleWare.Middleware, requireUsersManage).Put("/users/{id}", uph.ServeHTTP)
+
+		udh := handler.NewUserDeleteHandler(
+			delete_user.NewUsecase(deps.DB, deps.UserRepository, deps.BlogRepository, deps.AuthService))
+		r.With(authMiddleWare.Middleware, requireUsersManage).Delete("/users/{id}", udh.ServeHTTP)
+
+		uprh := handler.NewUserPasswordResetHandler(
+			reset_user_password.NewUsecase(deps.DB, deps.UserRepository, deps.AuthService), deps.Validator)
+		r.With(authMiddleWare.Middleware, requireUsersManage).Post("/users/{id}/password", uprh.ServeHTTP)
 	})
 }
 
@@ -174,3 +446,25 @@ func setGitHubRoute(
 
 	})
 }
+
+func setFeedRoute(r chi.Router, deps *MuxDependencies) {
+	usecase := get_blogs.NewUsecase(deps.DB, deps.BlogRepository, deps.UserRepository, deps.ContentsService)
+
+	frh := handler.NewFeedHandler(usecase, deps.Config, deps.Clocker, handler.FeedFormatRSS)
+	r.Get("/feed.xml", frh.ServeHTTP)
+
+	fah := handler.NewFeedHandler(usecase, deps.Config, deps.Clocker, handler.FeedFormatAtom)
+	r.Get("/atom.xml", fah.ServeHTTP)
+
+	fjh := handler.NewFeedHandler(usecase, deps.Config, deps.Clocker, handler.FeedFormatJSON)
+	r.Get("/feed.json", fjh.ServeHTTP)
+}
+
+func setSitemapRoute(r chi.Router, deps *MuxDependencies) {
+	sh := handler.NewSitemapHandler(get_sitemap.NewUsecase(deps.DB, deps.BlogRepository), deps.Config)
+	r.Get("/sitemap.xml", sh.ServeHTTP)
+	r.Get("/sitemap-{page:[0-9]+}.xml", sh.ServeHTTP)
+
+	rh := handler.NewRobotsHandler(deps.Config)
+	r.Get("/robots.txt", rh.ServeHTTP)
+}
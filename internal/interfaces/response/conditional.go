@@ -0,0 +1,60 @@
+package response
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RespondConditional は、ETagとLast-Modifiedを付与してレスポンスを返却する
+// リクエストの条件付きヘッダに一致する場合は、ボディを返さず304を返却する
+// lastModifiedがゼロ値の場合はLast-Modifiedを付与しない
+func RespondConditional(
+	w http.ResponseWriter, r *http.Request, contentType string, body []byte, lastModified time.Time,
+) error {
+	hash := sha1.Sum(body)
+	etag := `"` + hex.EncodeToString(hash[:]) + `"`
+	w.Header().Set("ETag", etag)
+	if !lastModified.IsZero() {
+		w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+	if notModified(r, etag, lastModified) {
+		w.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodHead {
+		return nil
+	}
+	if _, err := w.Write(body); err != nil {
+		return fmt.Errorf("failed to write body in RespondConditional(): %w", err)
+	}
+	return nil
+}
+
+// notModified は、条件付きリクエストに対してリソースが変更されていないかを判定する
+// If-None-Matchが指定されている場合はIf-Modified-Sinceより優先する
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, tag := range strings.Split(inm, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == etag || tag == "*" {
+				return true
+			}
+		}
+		return false
+	}
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" && !lastModified.IsZero() {
+		t, err := http.ParseTime(ims)
+		if err != nil {
+			return false
+		}
+		// HTTPの日時は秒単位のため切り捨てて比較する
+		return !lastModified.Truncate(time.Second).After(t)
+	}
+	return false
+}
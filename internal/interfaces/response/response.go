@@ -57,6 +57,24 @@ func RespondUnauthorized(w http.ResponseWriter, r *http.Request, err error) {
 	}
 }
 
+func RespondForbidden(w http.ResponseWriter, r *http.Request, err error) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+	resp := ErrorResponse{Message: ErrMessageForbidden}
+	if err := RespondJSON(w, r, http.StatusForbidden, resp); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond json error: %v", err))
+	}
+}
+
+func RespondTooManyRequests(w http.ResponseWriter, r *http.Request, err error) {
+	ctx := r.Context()
+	logger := logging.GetLogger(ctx)
+	resp := ErrorResponse{Message: ErrMessageTooManyRequests}
+	if err := RespondJSON(w, r, http.StatusTooManyRequests, resp); err != nil {
+		logger.Error(fmt.Sprintf("failed to respond json error: %v", err))
+	}
+}
+
 func JsonToStruct(r *http.Request, v any) error {
 	defer r.Body.Close()
 	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
@@ -74,4 +92,6 @@ const (
 	ErrMessageNotFound            = "NotFound"
 	ErrMessageInternalServerError = "InternalServerError"
 	ErrMessageUnauthorized        = "Unauthorized"
+	ErrMessageTooManyRequests     = "TooManyRequests"
+	ErrMessageForbidden           = "Forbidden"
 )
@@ -17,11 +17,12 @@ import (
 	"github.com/shoet/blog/internal/infrastracture/adapter"
 	"github.com/shoet/blog/internal/infrastracture/repository"
 	"github.com/shoet/blog/internal/infrastracture/services/auth_service"
-	"github.com/shoet/blog/internal/infrastracture/services/blog_service"
 	"github.com/shoet/blog/internal/infrastracture/services/contents_service"
 	"github.com/shoet/blog/internal/infrastracture/services/jwt_service"
+	"github.com/shoet/blog/internal/infrastracture/services/login_guard_service"
 	"github.com/shoet/blog/internal/interfaces/cookie"
 	"github.com/shoet/blog/internal/logging"
+	"github.com/shoet/blog/internal/policy"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -74,48 +75,78 @@ func BuildMuxDependencies(ctx context.Context, cfg *config.Config) (*MuxDependen
 		return nil, fmt.Errorf("failed to create redis kvs: %w", err)
 	}
 	c := clocker.RealClocker{}
-	jwtService := jwt_service.NewJWTService(kvs, &c, []byte(cfg.JWTSecret), cfg.JWTExpiresInSec)
+	jwtService := jwt_service.NewJWTService(
+		kvs, &c, []byte(cfg.JWTSecret), cfg.JWTExpiresInSec, cfg.RefreshTokenExpiresInSec)
 
 	blogRepo := repository.NewBlogRepository(&c)
 	blogOffsetRepo := repository.NewBlogRepositoryOffset(&c)
-	blogService := blog_service.NewBlogService()
+	commentRepo := repository.NewCommentRepository(&c)
+	mediaRepo := repository.NewMediaRepository(&c)
+	seriesRepo := repository.NewSeriesRepository(&c)
 
 	userRepo, err := repository.NewUserRepository(&c)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create user repository: %w", err)
 	}
 
-	authService, err := auth_service.NewAuthService(db, userRepo, jwtService)
+	apiKeyRepo := repository.NewAPIKeyRepository(&c)
+
+	authService, err := auth_service.NewAuthService(db, userRepo, apiKeyRepo, jwtService, &c)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create auth service: %w", err)
 	}
 
-	s3Adapter, err := adapter.NewAWSS3StorageAdapter(cfg)
+	loginGuard := login_guard_service.NewLoginGuardService(
+		db, kvs, repository.NewLoginLockoutRepository(&c), &c, login_guard_service.NewLimits(cfg))
+
+	storageAdapter, err := adapter.NewStorageAdapter(cfg, &c)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create s3 adapter: %w", err)
+		return nil, fmt.Errorf("failed to create storage adapter: %w", err)
 	}
-
-	contentsService, err := contents_service.NewContentsService(s3Adapter, cfg.AWSS3ThumbnailDirectory, cfg.AWSSS3ContentImageDirectory)
+	localStorage, _ := storageAdapter.(*adapter.LocalStorageAdapter)
+
+	contentsService, err := contents_service.NewContentsService(
+		storageAdapter,
+		mediaRepo,
+		cfg.AWSS3ThumbnailDirectory,
+		cfg.AWSSS3ContentImageDirectory,
+		cfg.CdnDomain,
+		cfg.UploadMaxSize,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create contents service: %w", err)
 	}
 
 	gitHubAPIAdapter := adapter.NewGitHubV4APIClient(cfg.GitHubPersonalAccessToken)
 
+	mailer, err := adapter.NewMailer(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create mailer: %w", err)
+	}
+
 	return &MuxDependencies{
 		Config:               cfg,
 		DB:                   db,
 		BlogRepository:       blogRepo,
 		BlogRepositoryOffset: blogOffsetRepo,
-		BlogService:          blogService,
+		Policy:               policy.NewPolicy(),
+		CommentRepository:    commentRepo,
+		MediaRepository:      mediaRepo,
+		SeriesRepository:     seriesRepo,
+		UserRepository:       userRepo,
+		APIKeyRepository:     apiKeyRepo,
 		AuthService:          authService,
+		LoginGuard:           loginGuard,
 		ContentsService:      contentsService,
+		LocalStorage:         localStorage,
 		JWTer:                jwtService,
 		Logger:               logger,
 		Validator:            validator,
 		Cookie:               cookie,
 		GitHubAPIAdapter:     gitHubAPIAdapter,
+		Mailer:               mailer,
 		Clocker:              &c,
+		KVS:                  kvs,
 	}, nil
 }
 
@@ -0,0 +1,113 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// Result は、Markdownのレンダリング結果
+type Result struct {
+	HTML        string
+	Toc         models.TableOfContents
+	WordCount   int
+	CharCount   int
+	ReadingTime int
+}
+
+var converter = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+	goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+)
+
+var policy = newPolicy()
+
+// newPolicy は、レンダリング結果のHTMLをサニタイズするポリシーを生成する
+// 目次のアンカーとして見出しのidを、シンタックスハイライト用にコードの言語クラスを許可する
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("id").OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+-]+$`)).OnElements("code")
+	return p
+}
+
+// Render は、MarkdownをサニタイズされたHTMLに変換し、目次と文字数、読了時間を算出する
+func Render(source string) (*Result, error) {
+	src := []byte(source)
+	ctx := parser.NewContext(parser.WithIDs(newHeadingIDs()))
+	doc := converter.Parser().Parse(text.NewReader(src), parser.WithContext(ctx))
+
+	var buf bytes.Buffer
+	if err := converter.Renderer().Render(&buf, src, doc); err != nil {
+		return nil, fmt.Errorf("failed to render markdown: %w", err)
+	}
+
+	var plain strings.Builder
+	toc := models.TableOfContents{}
+	err := ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := n.(type) {
+		case *ast.Heading:
+			id, _ := node.AttributeString("id")
+			idBytes, _ := id.([]byte)
+			toc = append(toc, &models.TocItem{
+				Level: node.Level,
+				Text:  string(node.Text(src)),
+				Id:    string(idBytes),
+			})
+		case *ast.Text:
+			plain.Write(node.Segment.Value(src))
+			if node.SoftLineBreak() || node.HardLineBreak() {
+				plain.WriteString(" ")
+			}
+		case *ast.CodeBlock, *ast.FencedCodeBlock:
+			lines := n.Lines()
+			for i := 0; i < lines.Len(); i++ {
+				line := lines.At(i)
+				plain.Write(line.Value(src))
+			}
+		}
+		if n.Type() == ast.TypeBlock {
+			plain.WriteString(" ")
+		}
+		return ast.WalkContinue, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk markdown: %w", err)
+	}
+
+	stats := Count(plain.String())
+	return &Result{
+		HTML:        policy.Sanitize(buf.String()),
+		Toc:         toc,
+		WordCount:   stats.Words,
+		CharCount:   stats.Chars,
+		ReadingTime: stats.ReadingTime(),
+	}, nil
+}
+
+// RenderBlog は、ブログ本文をレンダリングし、結果をブログに設定する
+// ブログの保存前に呼び出し、レンダリング結果をDBにキャッシュするために使用する
+func RenderBlog(blog *models.Blog) error {
+	result, err := Render(blog.Content)
+	if err != nil {
+		return fmt.Errorf("failed to render blog content: %w", err)
+	}
+	blog.ContentHTML = result.HTML
+	blog.Toc = result.Toc
+	blog.WordCount = result.WordCount
+	blog.CharCount = result.CharCount
+	blog.ReadingTime = result.ReadingTime
+	return nil
+}
@@ -0,0 +1,115 @@
+package markdown_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/markdown"
+)
+
+func Test_Render(t *testing.T) {
+	source := strings.Join([]string{
+		"# はじめに",
+		"",
+		"Go で **ブログ** を書く。",
+		"",
+		"## Getting Started",
+		"",
+		"<script>alert('xss')</script>",
+		"",
+		"```go",
+		"fmt.Println(\"hello\")",
+		"```",
+		"",
+		"## Getting Started",
+	}, "\n")
+
+	got, err := markdown.Render(source)
+	if err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+
+	wantToc := models.TableOfContents{
+		{Level: 1, Text: "はじめに", Id: "はじめに"},
+		{Level: 2, Text: "Getting Started", Id: "getting-started"},
+		{Level: 2, Text: "Getting Started", Id: "getting-started-1"},
+	}
+	if diff := cmp.Diff(wantToc, got.Toc); diff != "" {
+		t.Errorf("toc differs: (-want +got)\n%s", diff)
+	}
+
+	for _, want := range []string{
+		`<h1 id="はじめに">はじめに</h1>`,
+		`<h2 id="getting-started-1">Getting Started</h2>`,
+		`<strong>ブログ</strong>`,
+		`<code class="language-go">`,
+	} {
+		if !strings.Contains(got.HTML, want) {
+			t.Errorf("html does not contain %q: %s", want, got.HTML)
+		}
+	}
+	if strings.Contains(got.HTML, "<script>") {
+		t.Errorf("html is not sanitized: %s", got.HTML)
+	}
+	if got.ReadingTime != 1 {
+		t.Errorf("reading time: want 1, got %d", got.ReadingTime)
+	}
+}
+
+func Test_Render_Empty(t *testing.T) {
+	got, err := markdown.Render("")
+	if err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+	want := &markdown.Result{Toc: models.TableOfContents{}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
+}
+
+func Test_Count(t *testing.T) {
+	tests := []struct {
+		name            string
+		text            string
+		wantStats       markdown.Stats
+		wantReadingTime int
+	}{
+		{
+			name:            "英語",
+			text:            "It's a well-known fact.",
+			wantStats:       markdown.Stats{Words: 4, Chars: 20},
+			wantReadingTime: 1,
+		},
+		{
+			name:            "日本語",
+			text:            "ブログを書く。",
+			wantStats:       markdown.Stats{CJKChars: 6, Chars: 7},
+			wantReadingTime: 1,
+		},
+		{
+			name:            "日本語と英語の混在",
+			text:            strings.Repeat("あ", 500) + " " + strings.Repeat("word ", 201),
+			wantStats:       markdown.Stats{Words: 201, CJKChars: 500, Chars: 500 + 201*4},
+			wantReadingTime: 3,
+		},
+		{
+			name:            "空",
+			text:            " \n",
+			wantStats:       markdown.Stats{},
+			wantReadingTime: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := markdown.Count(tt.text)
+			if diff := cmp.Diff(tt.wantStats, got); diff != "" {
+				t.Errorf("differs: (-want +got)\n%s", diff)
+			}
+			if got.ReadingTime() != tt.wantReadingTime {
+				t.Errorf("reading time: want %d, got %d", tt.wantReadingTime, got.ReadingTime())
+			}
+		})
+	}
+}
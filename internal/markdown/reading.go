@@ -0,0 +1,72 @@
+package markdown
+
+import (
+	"unicode"
+)
+
+const (
+	// wordsPerMinute は、英語など単語で区切られる文章の1分あたりの読書量(単語数)
+	wordsPerMinute = 200
+	// cjkCharsPerMinute は、日本語など単語の区切りがない文章の1分あたりの読書量(文字数)
+	cjkCharsPerMinute = 500
+)
+
+// Stats は、文章の語数と文字数
+// Wordsは単語で区切られる文章の単語数、CJKCharsは日本語などの文字数、
+// Charsは空白を除いた全体の文字数
+type Stats struct {
+	Words    int
+	CJKChars int
+	Chars    int
+}
+
+// Count は、プレーンテキストの語数と文字数を数える
+func Count(s string) Stats {
+	var stats Stats
+	inWord := false
+	for _, r := range s {
+		if unicode.IsSpace(r) {
+			inWord = false
+			continue
+		}
+		stats.Chars++
+		if isCJK(r) {
+			stats.CJKChars++
+			inWord = false
+			continue
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if !inWord {
+				stats.Words++
+			}
+			inWord = true
+			continue
+		}
+		// 記号は単語の区切りとして扱うが、単語内のアポストロフィやハイフンは区切らない
+		if r != '\'' && r != '-' {
+			inWord = false
+		}
+	}
+	return stats
+}
+
+// ReadingTime は、読了までの目安時間(分)を算出する
+// 単語数と日本語などの文字数それぞれの読書速度から算出し、1分未満は切り上げる
+func (s Stats) ReadingTime() int {
+	if s.Chars == 0 {
+		return 0
+	}
+	// 整数で計算するため、それぞれの読書速度の積を分母とする
+	units := s.Words*cjkCharsPerMinute + s.CJKChars*wordsPerMinute
+	per := wordsPerMinute * cjkCharsPerMinute
+	minutes := (units + per - 1) / per
+	if minutes < 1 {
+		minutes = 1
+	}
+	return minutes
+}
+
+func isCJK(r rune) bool {
+	// 長音記号はカタカナではなく共通の文字種に分類されるため個別に判定する
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) || r == 'ー'
+}
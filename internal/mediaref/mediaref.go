@@ -0,0 +1,54 @@
+// Package mediaref は、ブログの本文・サムネイルからアップロードしたメディアへの参照を抽出する
+// メディアはサーバーで生成したUUIDのファイル名で保存されるため、URLの形式によらずファイル名で判定する
+package mediaref
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/shoet/blog/internal/infrastracture/models"
+)
+
+// mediaFileNamePattern は、メディアのファイル名に一致する
+// サムネイルの派生画像(例: <uuid>_w640.webp)も元のメディアへの参照として扱う
+var mediaFileNamePattern = regexp.MustCompile(
+	`(?i)([0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12})(?:_w[0-9]+)?\.(?:jpe?g|png|gif|webp)`)
+
+var exactMediaFileNamePattern = regexp.MustCompile(`^` + mediaFileNamePattern.String() + `$`)
+
+// Key は、メディアのファイル名から拡張子を除いたキーを返す
+func Key(fileName string) string {
+	return strings.ToLower(strings.TrimSuffix(fileName, path.Ext(fileName)))
+}
+
+// KeyOf は、ファイル名がメディアまたはその派生画像のものであれば、元のメディアのキーを返す
+func KeyOf(fileName string) (string, bool) {
+	m := exactMediaFileNamePattern.FindStringSubmatch(fileName)
+	if m == nil {
+		return "", false
+	}
+	return strings.ToLower(m[1]), true
+}
+
+// Extract は、テキストに含まれるメディアのキーを出現順に重複なく返す
+func Extract(texts ...string) []string {
+	keys := []string{}
+	seen := map[string]bool{}
+	for _, text := range texts {
+		for _, m := range mediaFileNamePattern.FindAllStringSubmatch(text, -1) {
+			key := strings.ToLower(m[1])
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// FromBlog は、ブログの本文とサムネイルが参照するメディアのキーを返す
+func FromBlog(blog *models.Blog) []string {
+	return Extract(blog.ThumbnailImageFileName, blog.Content)
+}
@@ -0,0 +1,58 @@
+package mediaref_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/mediaref"
+)
+
+func Test_Key(t *testing.T) {
+	got := mediaref.Key("0B7E1D2C-1A2B-4C3D-8E9F-0A1B2C3D4E5F.JPG")
+	if want := "0b7e1d2c-1a2b-4c3d-8e9f-0a1b2c3d4e5f"; got != want {
+		t.Errorf("want %s, but got %s", want, got)
+	}
+}
+
+func Test_FromBlog(t *testing.T) {
+	blog := &models.Blog{
+		ThumbnailImageFileName: "https://cdn.example.com/thumbnail/0b7e1d2c-1a2b-4c3d-8e9f-0a1b2c3d4e5f.jpg",
+		Content: "# title\n" +
+			"![image](https://cdn.example.com/content/1c8f2e3d-2b3c-4d4e-9f0a-1b2c3d4e5f60.png)\n" +
+			"<img src=\"https://cdn.example.com/thumbnail/0b7e1d2c-1a2b-4c3d-8e9f-0a1b2c3d4e5f_w640.webp\">\n" +
+			"![legacy](https://cdn.example.com/content/sample.png)\n" +
+			"1c8f2e3d-2b3c-4d4e-9f0a-1b2c3d4e5f60 without extension\n",
+	}
+	want := []string{
+		"0b7e1d2c-1a2b-4c3d-8e9f-0a1b2c3d4e5f",
+		"1c8f2e3d-2b3c-4d4e-9f0a-1b2c3d4e5f60",
+	}
+	if diff := cmp.Diff(mediaref.FromBlog(blog), want); diff != "" {
+		t.Errorf("(-got +want)\n%s", diff)
+	}
+	if got := mediaref.FromBlog(&models.Blog{}); len(got) != 0 {
+		t.Errorf("want empty, but got %v", got)
+	}
+}
+
+func Test_KeyOf(t *testing.T) {
+	tests := []struct {
+		fileName string
+		want     string
+		wantOk   bool
+	}{
+		{fileName: "0b7e1d2c-1a2b-4c3d-8e9f-0a1b2c3d4e5f.jpg", want: "0b7e1d2c-1a2b-4c3d-8e9f-0a1b2c3d4e5f", wantOk: true},
+		{fileName: "0b7e1d2c-1a2b-4c3d-8e9f-0a1b2c3d4e5f_w320.webp", want: "0b7e1d2c-1a2b-4c3d-8e9f-0a1b2c3d4e5f", wantOk: true},
+		{fileName: "sample.png", wantOk: false},
+		{fileName: "prefix-0b7e1d2c-1a2b-4c3d-8e9f-0a1b2c3d4e5f.jpg", wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fileName, func(t *testing.T) {
+			got, ok := mediaref.KeyOf(tt.fileName)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("want (%s, %v), but got (%s, %v)", tt.want, tt.wantOk, got, ok)
+			}
+		})
+	}
+}
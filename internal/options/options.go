@@ -16,6 +16,8 @@ type ListBlogOptions struct {
 	PageDirection string
 	// Pageはオフセット方式のページネーションで使用するページ番号
 	Page int64
+	// AuthorIdが指定された場合は、著者のブログのみに絞り込む
+	AuthorId *models.UserId
 }
 
 const DefaultLimit int64 = 10
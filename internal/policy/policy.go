@@ -0,0 +1,122 @@
+// Package policy は、ユーザーの権限に応じた操作の可否を判定する
+// ルートごとの判定はミドルウェアから、リソースの所有者に依存する判定はユースケースから利用する
+package policy
+
+import (
+	"context"
+	"errors"
+
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/session"
+)
+
+var ErrForbidden = errors.New("forbidden")
+
+type Permission string
+
+const (
+	// PermissionBlogsRead は、下書きを含むブログと編集履歴の閲覧を許可する
+	PermissionBlogsRead Permission = "blogs:read"
+	// PermissionBlogsWrite は、自分のブログの作成・更新・削除を許可する
+	PermissionBlogsWrite Permission = "blogs:write"
+	// PermissionBlogsEditAny は、他のユーザーのブログの更新・削除を許可する
+	PermissionBlogsEditAny Permission = "blogs:edit_any"
+	// PermissionFilesWrite は、画像のアップロードを許可する
+	PermissionFilesWrite Permission = "files:write"
+	// PermissionFilesManage は、他のユーザーがアップロードした画像の操作を許可する
+	PermissionFilesManage Permission = "files:manage"
+	// PermissionCommentsModerate は、コメントの閲覧とモデレーションを許可する
+	PermissionCommentsModerate Permission = "comments:moderate"
+	// PermissionSeriesRead は、シリーズの閲覧を許可する
+	PermissionSeriesRead Permission = "series:read"
+	// PermissionSeriesWrite は、シリーズの作成・更新・削除を許可する
+	PermissionSeriesWrite Permission = "series:write"
+	// PermissionUsersManage は、ユーザーの管理を許可する
+	PermissionUsersManage Permission = "users:manage"
+)
+
+var viewerPermissions = []Permission{
+	PermissionBlogsRead,
+	PermissionSeriesRead,
+}
+
+var authorPermissions = append([]Permission{
+	PermissionBlogsWrite,
+	PermissionFilesWrite,
+}, viewerPermissions...)
+
+var editorPermissions = append([]Permission{
+	PermissionBlogsEditAny,
+	PermissionCommentsModerate,
+	PermissionSeriesWrite,
+}, authorPermissions...)
+
+var adminPermissions = append([]Permission{
+	PermissionUsersManage,
+	PermissionFilesManage,
+}, editorPermissions...)
+
+var rolePermissions = map[models.Role][]Permission{
+	models.RoleViewer: viewerPermissions,
+	models.RoleAuthor: authorPermissions,
+	models.RoleEditor: editorPermissions,
+	models.RoleAdmin:  adminPermissions,
+}
+
+// Can は、権限roleが操作permissionを行えるかを返す
+func Can(role models.Role, permission Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// CanEditBlog は、ユーザーがブログを作成・更新・削除できるかを返す
+// 他のユーザーのブログはPermissionBlogsEditAnyを持つ場合のみ編集できる
+func CanEditBlog(userId models.UserId, role models.Role, blog *models.Blog) bool {
+	if Can(role, PermissionBlogsEditAny) {
+		return true
+	}
+	return Can(role, PermissionBlogsWrite) && blog.AuthorId == userId
+}
+
+// CanCompleteMedia は、ユーザーがメディアのアップロードを完了できるかを返す
+// 他のユーザーがアップロードしたメディアはPermissionFilesManageを持つ場合のみ完了できる
+func CanCompleteMedia(userId models.UserId, role models.Role, media *models.Media) bool {
+	if Can(role, PermissionFilesManage) {
+		return true
+	}
+	return Can(role, PermissionFilesWrite) && media.UploadedBy != nil && *media.UploadedBy == userId
+}
+
+type Policy struct{}
+
+func NewPolicy() *Policy {
+	return &Policy{}
+}
+
+// AuthorizeBlogEdit は、コンテキストのユーザーがブログを編集できない場合にErrForbiddenを返す
+func (p *Policy) AuthorizeBlogEdit(ctx context.Context, blog *models.Blog) error {
+	userId, err := session.GetUserId(ctx)
+	if err != nil {
+		return ErrForbidden
+	}
+	if !CanEditBlog(userId, session.GetRole(ctx), blog) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// AuthorizeMediaComplete は、コンテキストのユーザーがメディアのアップロードを完了できない場合にErrForbiddenを返す
+func (p *Policy) AuthorizeMediaComplete(ctx context.Context, media *models.Media) error {
+	userId, err := session.GetUserId(ctx)
+	if err != nil {
+		return ErrForbidden
+	}
+	if !CanCompleteMedia(userId, session.GetRole(ctx), media) {
+		return ErrForbidden
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package policy_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/policy"
+	"github.com/shoet/blog/internal/session"
+)
+
+func Test_Can(t *testing.T) {
+	tests := []struct {
+		name       string
+		role       models.Role
+		permission policy.Permission
+		want       bool
+	}{
+		{name: "viewer can read blogs", role: models.RoleViewer, permission: policy.PermissionBlogsRead, want: true},
+		{name: "viewer can't write blogs", role: models.RoleViewer, permission: policy.PermissionBlogsWrite, want: false},
+		{name: "author can write blogs", role: models.RoleAuthor, permission: policy.PermissionBlogsWrite, want: true},
+		{name: "author can't edit any blogs", role: models.RoleAuthor, permission: policy.PermissionBlogsEditAny, want: false},
+		{name: "editor can edit any blogs", role: models.RoleEditor, permission: policy.PermissionBlogsEditAny, want: true},
+		{name: "editor can moderate comments", role: models.RoleEditor, permission: policy.PermissionCommentsModerate, want: true},
+		{name: "editor can't manage users", role: models.RoleEditor, permission: policy.PermissionUsersManage, want: false},
+		{name: "admin can manage users", role: models.RoleAdmin, permission: policy.PermissionUsersManage, want: true},
+		{name: "admin can read blogs", role: models.RoleAdmin, permission: policy.PermissionBlogsRead, want: true},
+		{name: "editor can't manage files", role: models.RoleEditor, permission: policy.PermissionFilesManage, want: false},
+		{name: "admin can manage files", role: models.RoleAdmin, permission: policy.PermissionFilesManage, want: true},
+		{name: "unknown role", role: "", permission: policy.PermissionBlogsRead, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.Can(tt.role, tt.permission); got != tt.want {
+				t.Errorf("want %v, but got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_Policy_AuthorizeBlogEdit(t *testing.T) {
+	blog := &models.Blog{AuthorId: 1}
+
+	tests := []struct {
+		name    string
+		userId  models.UserId
+		role    models.Role
+		wantErr error
+	}{
+		{name: "author edits own blog", userId: 1, role: models.RoleAuthor, wantErr: nil},
+		{name: "author edits other's blog", userId: 2, role: models.RoleAuthor, wantErr: policy.ErrForbidden},
+		{name: "editor edits other's blog", userId: 2, role: models.RoleEditor, wantErr: nil},
+		{name: "admin edits other's blog", userId: 2, role: models.RoleAdmin, wantErr: nil},
+		{name: "viewer edits own blog", userId: 1, role: models.RoleViewer, wantErr: policy.ErrForbidden},
+	}
+
+	sut := policy.NewPolicy()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := session.SetUserId(context.Background(), tt.userId)
+			ctx = session.SetRole(ctx, tt.role)
+			if err := sut.AuthorizeBlogEdit(ctx, blog); !errors.Is(err, tt.wantErr) {
+				t.Errorf("want %v, but got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	t.Run("no session", func(t *testing.T) {
+		if err := sut.AuthorizeBlogEdit(context.Background(), blog); !errors.Is(err, policy.ErrForbidden) {
+			t.Errorf("want %v, but got %v", policy.ErrForbidden, err)
+		}
+	})
+}
+
+func Test_Policy_AuthorizeMediaComplete(t *testing.T) {
+	uploadedBy := models.UserId(1)
+	media := &models.Media{UploadedBy: &uploadedBy}
+
+	tests := []struct {
+		name    string
+		userId  models.UserId
+		role    models.Role
+		media   *models.Media
+		wantErr error
+	}{
+		{name: "author completes own media", userId: 1, role: models.RoleAuthor, media: media, wantErr: nil},
+		{name: "author completes other's media", userId: 2, role: models.RoleAuthor, media: media, wantErr: policy.ErrForbidden},
+		{name: "editor completes other's media", userId: 2, role: models.RoleEditor, media: media, wantErr: policy.ErrForbidden},
+		{name: "admin completes other's media", userId: 2, role: models.RoleAdmin, media: media, wantErr: nil},
+		{name: "viewer completes own media", userId: 1, role: models.RoleViewer, media: media, wantErr: policy.ErrForbidden},
+		{name: "author completes media without uploader", userId: 1, role: models.RoleAuthor, media: &models.Media{}, wantErr: policy.ErrForbidden},
+	}
+
+	sut := policy.NewPolicy()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := session.SetUserId(context.Background(), tt.userId)
+			ctx = session.SetRole(ctx, tt.role)
+			if err := sut.AuthorizeMediaComplete(ctx, tt.media); !errors.Is(err, tt.wantErr) {
+				t.Errorf("want %v, but got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	t.Run("no session", func(t *testing.T) {
+		if err := sut.AuthorizeMediaComplete(context.Background(), media); !errors.Is(err, policy.ErrForbidden) {
+			t.Errorf("want %v, but got %v", policy.ErrForbidden, err)
+		}
+	})
+}
@@ -21,3 +21,41 @@ func GetUserId(ctx context.Context) (models.UserId, error) {
 	}
 	return userId, nil
 }
+
+// ClientInfo は、セッションを作成したクライアントの情報
+type ClientInfo struct {
+	UserAgent string
+	IpAddress string
+}
+
+type clientInfoContextKey struct{}
+
+func SetClientInfo(ctx context.Context, info *ClientInfo) context.Context {
+	return context.WithValue(ctx, clientInfoContextKey{}, info)
+}
+
+// GetClientInfo は、コンテキストのクライアントの情報を返す
+// 設定されていない場合は空の情報を返す
+func GetClientInfo(ctx context.Context) *ClientInfo {
+	info, ok := ctx.Value(clientInfoContextKey{}).(*ClientInfo)
+	if !ok {
+		return &ClientInfo{}
+	}
+	return info
+}
+
+type roleContextKey struct{}
+
+func SetRole(ctx context.Context, role models.Role) context.Context {
+	return context.WithValue(ctx, roleContextKey{}, role)
+}
+
+// GetRole は、コンテキストのユーザーの権限を返す
+// 設定されていない場合は空文字を返す
+func GetRole(ctx context.Context) models.Role {
+	role, ok := ctx.Value(roleContextKey{}).(models.Role)
+	if !ok {
+		return ""
+	}
+	return role
+}
@@ -0,0 +1,95 @@
+package sitemap
+
+import (
+	"encoding/xml"
+	"fmt"
+	"time"
+)
+
+// MaxURLs は、1つのサイトマップに含められるURLの上限
+const MaxURLs = 50000
+
+const xmlns = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// URL は、サイトマップに含めるページ
+type URL struct {
+	Loc     string
+	LastMod time.Time
+}
+
+// Sitemap は、サイトマップインデックスに含めるサイトマップ
+type Sitemap struct {
+	Loc     string
+	LastMod time.Time
+}
+
+type urlSet struct {
+	XMLName xml.Name  `xml:"urlset"`
+	Xmlns   string    `xml:"xmlns,attr"`
+	URLs    []xmlItem `xml:"url"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name  `xml:"sitemapindex"`
+	Xmlns    string    `xml:"xmlns,attr"`
+	Sitemaps []xmlItem `xml:"sitemap"`
+}
+
+type xmlItem struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// URLSet は、URLの一覧からサイトマップを生成する
+func URLSet(urls []*URL) ([]byte, error) {
+	set := &urlSet{Xmlns: xmlns, URLs: []xmlItem{}}
+	for _, u := range urls {
+		set.URLs = append(set.URLs, xmlItem{Loc: u.Loc, LastMod: formatLastMod(u.LastMod)})
+	}
+	return marshal(set)
+}
+
+// Index は、サイトマップの一覧からサイトマップインデックスを生成する
+func Index(sitemaps []*Sitemap) ([]byte, error) {
+	index := &sitemapIndex{Xmlns: xmlns, Sitemaps: []xmlItem{}}
+	for _, s := range sitemaps {
+		index.Sitemaps = append(index.Sitemaps, xmlItem{Loc: s.Loc, LastMod: formatLastMod(s.LastMod)})
+	}
+	return marshal(index)
+}
+
+// Split は、URLの一覧を1つのサイトマップに含められる件数ごとに分割する
+func Split(urls []*URL, size int) [][]*URL {
+	var chunks [][]*URL
+	for size < len(urls) {
+		chunks = append(chunks, urls[:size])
+		urls = urls[size:]
+	}
+	return append(chunks, urls)
+}
+
+// LastMod は、URLの一覧の最終更新日時の最大値を返す
+func LastMod(urls []*URL) time.Time {
+	var lastMod time.Time
+	for _, u := range urls {
+		if u.LastMod.After(lastMod) {
+			lastMod = u.LastMod
+		}
+	}
+	return lastMod
+}
+
+func formatLastMod(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func marshal(v interface{}) ([]byte, error) {
+	b, err := xml.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal sitemap: %w", err)
+	}
+	return append([]byte(xml.Header), b...), nil
+}
@@ -0,0 +1,81 @@
+package sitemap_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shoet/blog/internal/sitemap"
+)
+
+func Test_URLSet(t *testing.T) {
+	got, err := sitemap.URLSet([]*sitemap.URL{
+		{Loc: "https://example.com/blogs/1", LastMod: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{Loc: "https://example.com/tags/a&b"},
+	})
+	if err != nil {
+		t.Fatalf("failed to build sitemap: %v", err)
+	}
+	want := `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+		`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">` +
+		`<url><loc>https://example.com/blogs/1</loc><lastmod>2024-01-01T00:00:00Z</lastmod></url>` +
+		`<url><loc>https://example.com/tags/a&amp;b</loc></url>` +
+		`</urlset>`
+	if string(got) != want {
+		t.Errorf("URLSet() = %s, want %s", got, want)
+	}
+}
+
+func Test_Index(t *testing.T) {
+	got, err := sitemap.Index([]*sitemap.Sitemap{
+		{Loc: "https://api.example.com/sitemap-1.xml", LastMod: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+	})
+	if err != nil {
+		t.Fatalf("failed to build sitemap index: %v", err)
+	}
+	want := `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+		`<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">` +
+		`<sitemap><loc>https://api.example.com/sitemap-1.xml</loc><lastmod>2024-01-01T00:00:00Z</lastmod></sitemap>` +
+		`</sitemapindex>`
+	if string(got) != want {
+		t.Errorf("Index() = %s, want %s", got, want)
+	}
+}
+
+func Test_Split(t *testing.T) {
+	urls := make([]*sitemap.URL, 5)
+	for i := range urls {
+		urls[i] = &sitemap.URL{}
+	}
+	tests := []struct {
+		name string
+		size int
+		want []int
+	}{
+		{name: "分割しない", size: 5, want: []int{5}},
+		{name: "端数あり", size: 2, want: []int{2, 2, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sitemap.Split(urls, tt.size)
+			if len(got) != len(tt.want) {
+				t.Fatalf("len = %d, want %d", len(got), len(tt.want))
+			}
+			for i, chunk := range got {
+				if len(chunk) != tt.want[i] {
+					t.Errorf("len(chunk[%d]) = %d, want %d", i, len(chunk), tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func Test_LastMod(t *testing.T) {
+	want := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	got := sitemap.LastMod([]*sitemap.URL{
+		{LastMod: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{LastMod: want},
+	})
+	if !got.Equal(want) {
+		t.Errorf("LastMod() = %v, want %v", got, want)
+	}
+}
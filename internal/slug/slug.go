@@ -0,0 +1,81 @@
+package slug
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxLength はスラッグの最大文字数
+const MaxLength = 80
+
+// Generate は、タイトルからURLに使用できるスラッグを生成する
+// ひらがな・カタカナはローマ字に変換し、変換できない文字(漢字など)は区切りとして扱う
+// 変換結果が空になる場合は、タイトルのハッシュ値からスラッグを生成する
+func Generate(title string) string {
+	s := Normalize(transliterate(title))
+	if s == "" {
+		sum := sha1.Sum([]byte(title))
+		return "post-" + hex.EncodeToString(sum[:])[:8]
+	}
+	return s
+}
+
+// Normalize は、文字列を小文字英数字とハイフンのみのスラッグ形式に整形する
+// 指定されたスラッグの整形にも使用する
+func Normalize(s string) string {
+	// 全角英数字を半角に、アクセント記号付きの文字を基底文字と結合文字に分解する
+	s = norm.NFKD.String(norm.NFKC.String(s))
+
+	var sb strings.Builder
+	lastHyphen := true
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// 分解された結合文字(アクセント記号)は除去する
+			continue
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			sb.WriteRune(r)
+			lastHyphen = false
+		default:
+			if !lastHyphen {
+				sb.WriteRune('-')
+				lastHyphen = true
+			}
+		}
+	}
+	result := strings.Trim(sb.String(), "-")
+	if len(result) > MaxLength {
+		result = result[:MaxLength]
+		// 単語の途中で切れないように、最後のハイフンで切り詰める
+		if i := strings.LastIndex(result, "-"); i > MaxLength/2 {
+			result = result[:i]
+		}
+		result = strings.Trim(result, "-")
+	}
+	return result
+}
+
+// Unique は、exists が false を返すまでスラッグに連番を付与して重複しないスラッグを返す
+func Unique(base string, exists func(slug string) (bool, error)) (string, error) {
+	candidate := base
+	for i := 2; ; i++ {
+		ok, err := exists(candidate)
+		if err != nil {
+			return "", fmt.Errorf("failed to check slug exists: %w", err)
+		}
+		if !ok {
+			return candidate, nil
+		}
+		suffix := fmt.Sprintf("-%d", i)
+		trimmed := base
+		if len(trimmed)+len(suffix) > MaxLength {
+			trimmed = strings.TrimRight(trimmed[:MaxLength-len(suffix)], "-")
+		}
+		candidate = trimmed + suffix
+	}
+}
@@ -0,0 +1,80 @@
+package slug_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/shoet/blog/internal/slug"
+)
+
+func Test_Generate(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		want  string
+	}{
+		{name: "英語", title: "Hello, World!", want: "hello-world"},
+		{name: "全角英数字", title: "Ｇｏ　１．２２", want: "go-1-22"},
+		{name: "アクセント記号", title: "Café Crème", want: "cafe-creme"},
+		{name: "ひらがな", title: "はじめてのごー", want: "hajimetenogo"},
+		{name: "カタカナ", title: "コンテナ入門", want: "kontena"},
+		{name: "拗音と促音", title: "きょうはちょっとキャッシュ", want: "kyouhachottokyasshu"},
+		{name: "撥音の区切り", title: "かんい", want: "kan-i"},
+		{name: "英語と日本語の混在", title: "Goでつくるブログ API", want: "godetsukuruburogu-api"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := slug.Generate(tt.title)
+			if got != tt.want {
+				t.Errorf("got: %q, want: %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Generate_Fallback(t *testing.T) {
+	// かなを含まない日本語のタイトルはハッシュ値から生成する
+	got := slug.Generate("漢字")
+	if !strings.HasPrefix(got, "post-") || len(got) != len("post-")+8 {
+		t.Errorf("got: %q, want hash fallback", got)
+	}
+	if got != slug.Generate("漢字") {
+		t.Errorf("fallback slug must be deterministic")
+	}
+}
+
+func Test_Normalize(t *testing.T) {
+	long := strings.Repeat("word-", 30)
+	got := slug.Normalize(long)
+	if len(got) > slug.MaxLength {
+		t.Errorf("length %d exceeds MaxLength", len(got))
+	}
+	if strings.HasSuffix(got, "-") {
+		t.Errorf("got %q, must not end with hyphen", got)
+	}
+	if got := slug.Normalize("--My  Slug--"); got != "my-slug" {
+		t.Errorf("got %q, want %q", got, "my-slug")
+	}
+}
+
+func Test_Unique(t *testing.T) {
+	used := map[string]bool{"hello": true, "hello-2": true}
+	got, err := slug.Unique("hello", func(s string) (bool, error) {
+		return used[s], nil
+	})
+	if err != nil {
+		t.Fatalf("failed to Unique: %v", err)
+	}
+	if got != "hello-3" {
+		t.Errorf("got %q, want %q", got, "hello-3")
+	}
+
+	_, err = slug.Unique("hello", func(s string) (bool, error) {
+		return false, fmt.Errorf("db error")
+	})
+	if err == nil {
+		t.Errorf("want error, got nil")
+	}
+}
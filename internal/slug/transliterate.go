@@ -0,0 +1,104 @@
+package slug
+
+import (
+	"strings"
+)
+
+// kanaRomaji は、ひらがなからヘボン式ローマ字への変換表
+var kanaRomaji = map[string]string{
+	"あ": "a", "い": "i", "う": "u", "え": "e", "お": "o",
+	"か": "ka", "き": "ki", "く": "ku", "け": "ke", "こ": "ko",
+	"さ": "sa", "し": "shi", "す": "su", "せ": "se", "そ": "so",
+	"た": "ta", "ち": "chi", "つ": "tsu", "て": "te", "と": "to",
+	"な": "na", "に": "ni", "ぬ": "nu", "ね": "ne", "の": "no",
+	"は": "ha", "ひ": "hi", "ふ": "fu", "へ": "he", "ほ": "ho",
+	"ま": "ma", "み": "mi", "む": "mu", "め": "me", "も": "mo",
+	"や": "ya", "ゆ": "yu", "よ": "yo",
+	"ら": "ra", "り": "ri", "る": "ru", "れ": "re", "ろ": "ro",
+	"わ": "wa", "ゐ": "i", "ゑ": "e", "を": "o", "ん": "n",
+	"が": "ga", "ぎ": "gi", "ぐ": "gu", "げ": "ge", "ご": "go",
+	"ざ": "za", "じ": "ji", "ず": "zu", "ぜ": "ze", "ぞ": "zo",
+	"だ": "da", "ぢ": "ji", "づ": "zu", "で": "de", "ど": "do",
+	"ば": "ba", "び": "bi", "ぶ": "bu", "べ": "be", "ぼ": "bo",
+	"ぱ": "pa", "ぴ": "pi", "ぷ": "pu", "ぺ": "pe", "ぽ": "po",
+	"ぁ": "a", "ぃ": "i", "ぅ": "u", "ぇ": "e", "ぉ": "o",
+	"ゃ": "ya", "ゅ": "yu", "ょ": "yo", "ゎ": "wa", "ゔ": "vu",
+	"きゃ": "kya", "きゅ": "kyu", "きょ": "kyo",
+	"しゃ": "sha", "しゅ": "shu", "しょ": "sho", "しぇ": "she",
+	"ちゃ": "cha", "ちゅ": "chu", "ちょ": "cho", "ちぇ": "che",
+	"にゃ": "nya", "にゅ": "nyu", "にょ": "nyo",
+	"ひゃ": "hya", "ひゅ": "hyu", "ひょ": "hyo",
+	"みゃ": "mya", "みゅ": "myu", "みょ": "myo",
+	"りゃ": "rya", "りゅ": "ryu", "りょ": "ryo",
+	"ぎゃ": "gya", "ぎゅ": "gyu", "ぎょ": "gyo",
+	"じゃ": "ja", "じゅ": "ju", "じょ": "jo", "じぇ": "je",
+	"びゃ": "bya", "びゅ": "byu", "びょ": "byo",
+	"ぴゃ": "pya", "ぴゅ": "pyu", "ぴょ": "pyo",
+	"ふぁ": "fa", "ふぃ": "fi", "ふぇ": "fe", "ふぉ": "fo",
+	"てぃ": "ti", "でぃ": "di", "とぅ": "tu", "どぅ": "du",
+	"うぃ": "wi", "うぇ": "we", "うぉ": "wo",
+	"ゔぁ": "va", "ゔぃ": "vi", "ゔぇ": "ve", "ゔぉ": "vo",
+}
+
+// transliterate は、文字列中のひらがな・カタカナをローマ字に変換する
+// かな以外の文字はそのまま残す
+func transliterate(s string) string {
+	runes := []rune(toHiragana(s))
+	var sb strings.Builder
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		// 促音は次の音の子音を重ねる
+		if r == 'っ' {
+			if i+1 < len(runes) {
+				if next, _ := lookupKana(runes, i+1); next != "" {
+					sb.WriteByte(next[0])
+				}
+			}
+			continue
+		}
+
+		// 長音記号は直前の母音を重ねずに省略する
+		if r == 'ー' {
+			continue
+		}
+
+		if romaji, size := lookupKana(runes, i); romaji != "" {
+			// 「ん」の後に母音・や行が続く場合は区切りを入れる
+			if romaji == "n" && i+1 < len(runes) {
+				if next, _ := lookupKana(runes, i+1); next != "" && strings.ContainsAny(next[:1], "aiueoy") {
+					romaji = "n-"
+				}
+			}
+			sb.WriteString(romaji)
+			i += size - 1
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// lookupKana は、位置iから始まるかなのローマ字と、変換に使用した文字数を返す
+// 拗音を優先して2文字での変換を試みる
+func lookupKana(runes []rune, i int) (string, int) {
+	if i+1 < len(runes) {
+		if v, ok := kanaRomaji[string(runes[i:i+2])]; ok {
+			return v, 2
+		}
+	}
+	if v, ok := kanaRomaji[string(runes[i])]; ok {
+		return v, 1
+	}
+	return "", 0
+}
+
+// toHiragana は、カタカナをひらがなに変換する
+func toHiragana(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'ァ' && r <= 'ヴ' {
+			return r - ('ァ' - 'ぁ')
+		}
+		return r
+	}, s)
+}
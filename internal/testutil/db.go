@@ -13,6 +13,7 @@ import (
 	_ "github.com/jackc/pgx/stdlib"
 	"github.com/jmoiron/sqlx"
 	migrate "github.com/rubenv/sql-migrate"
+	"github.com/rubenv/sql-migrate/sqlparse"
 	"github.com/shoet/blog/internal/util"
 )
 
@@ -83,7 +84,7 @@ func NewDBPostgreSQLForTest(t *testing.T, ctx context.Context) (*sqlx.DB, error)
 	return sqlx.NewDb(db, "pgx"), nil
 }
 
-func RepositoryTestPrepare(t *testing.T, ctx context.Context, db *sqlx.DB) {
+func migrationDir(t *testing.T) string {
 	t.Helper()
 
 	curDir, err := os.Getwd()
@@ -94,13 +95,38 @@ func RepositoryTestPrepare(t *testing.T, ctx context.Context, db *sqlx.DB) {
 	if err != nil {
 		t.Fatalf("failed to get project root: %v", err)
 	}
+	return filepath.Join(cwd, "_tools/migrations/postgres")
+}
+
+// ExecMigrationUp は、マイグレーションのUpをトランザクション内で再度実行する
+// 既存のデータに対するマイグレーションの動作の確認に使用する
+func ExecMigrationUp(t *testing.T, ctx context.Context, tx *sqlx.Tx, fileName string) {
+	t.Helper()
+
+	f, err := os.Open(filepath.Join(migrationDir(t), fileName))
+	if err != nil {
+		t.Fatalf("failed to open migration: %v", err)
+	}
+	defer f.Close()
+	parsed, err := sqlparse.ParseMigration(f)
+	if err != nil {
+		t.Fatalf("failed to parse migration: %v", err)
+	}
+	for _, stmt := range parsed.UpStatements {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("failed to exec migration: %v", err)
+		}
+	}
+}
+
+func RepositoryTestPrepare(t *testing.T, ctx context.Context, db *sqlx.DB) {
+	t.Helper()
 
-	sqlDir := filepath.Join(cwd, "_tools/migrations/postgres")
 	migrations := &migrate.FileMigrationSource{
-		Dir: sqlDir,
+		Dir: migrationDir(t),
 	}
 
-	_, err = migrate.ExecContext(ctx, db.DB, "postgres", migrations, migrate.Up)
+	_, err := migrate.ExecContext(ctx, db.DB, "postgres", migrations, migrate.Up)
 	if err != nil {
 		t.Fatalf("failed to migrate: %v", err)
 	}
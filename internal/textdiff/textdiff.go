@@ -0,0 +1,167 @@
+package textdiff
+
+import (
+	"strings"
+)
+
+type Op string
+
+const (
+	OpEqual  Op = "equal"
+	OpInsert Op = "insert"
+	OpDelete Op = "delete"
+)
+
+// Line は、差分の1行を表す
+// OldLine, NewLineは1始まりの行番号で、該当しない側は0となる
+type Line struct {
+	Op      Op     `json:"op"`
+	Text    string `json:"text"`
+	OldLine int    `json:"oldLine,omitempty"`
+	NewLine int    `json:"newLine,omitempty"`
+}
+
+// Lines は、2つのテキストの行単位の差分を取得する
+// 差分の算出にはMyersのアルゴリズムを使用する
+func Lines(oldText string, newText string) []*Line {
+	a := splitLines(oldText)
+	b := splitLines(newText)
+
+	ops := myers(a, b)
+
+	result := make([]*Line, 0, len(ops))
+	oldLine, newLine := 0, 0
+	for _, op := range ops {
+		line := &Line{Op: op.op}
+		switch op.op {
+		case OpEqual:
+			oldLine++
+			newLine++
+			line.Text = a[oldLine-1]
+			line.OldLine = oldLine
+			line.NewLine = newLine
+		case OpDelete:
+			oldLine++
+			line.Text = a[oldLine-1]
+			line.OldLine = oldLine
+		case OpInsert:
+			newLine++
+			line.Text = b[newLine-1]
+			line.NewLine = newLine
+		}
+		result = append(result, line)
+	}
+	return result
+}
+
+// HasChanges は、差分に追加・削除が含まれているかを判定する
+func HasChanges(lines []*Line) bool {
+	for _, l := range lines {
+		if l.Op != OpEqual {
+			return true
+		}
+	}
+	return false
+}
+
+// Unified は、差分を "+", "-", " " を行頭に付与したテキストに変換する
+func Unified(lines []*Line) string {
+	var sb strings.Builder
+	for _, l := range lines {
+		switch l.Op {
+		case OpInsert:
+			sb.WriteString("+")
+		case OpDelete:
+			sb.WriteString("-")
+		default:
+			sb.WriteString(" ")
+		}
+		sb.WriteString(l.Text)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return []string{}
+	}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.Split(text, "\n")
+}
+
+type edit struct {
+	op Op
+}
+
+// myers は、aをbに変換する最短の編集手順を求める
+func myers(a []string, b []string) []edit {
+	n, m := len(a), len(b)
+	max := n + m
+	offset := max + 1
+	v := make([]int, 2*max+3)
+
+	var trace [][]int
+	found := false
+	for d := 0; d <= max && !found; d++ {
+		snapshot := make([]int, len(v))
+		copy(snapshot, v)
+		trace = append(trace, snapshot)
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				found = true
+				break
+			}
+		}
+	}
+
+	// 終点から始点に向かって編集手順を復元する
+	edits := make([]edit, 0, max)
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		v := trace[d]
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			edits = append(edits, edit{op: OpEqual})
+			x--
+			y--
+		}
+		if x == prevX {
+			edits = append(edits, edit{op: OpInsert})
+			y--
+		} else {
+			edits = append(edits, edit{op: OpDelete})
+			x--
+		}
+	}
+	for x > 0 && y > 0 {
+		edits = append(edits, edit{op: OpEqual})
+		x--
+		y--
+	}
+
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits
+}
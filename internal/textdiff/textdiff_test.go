@@ -0,0 +1,109 @@
+package textdiff_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/shoet/blog/internal/textdiff"
+)
+
+func Test_Lines(t *testing.T) {
+	type args struct {
+		oldText string
+		newText string
+	}
+	type wants struct {
+		lines []*textdiff.Line
+	}
+
+	tests := []struct {
+		name  string
+		args  args
+		wants wants
+	}{
+		{
+			name: "変更なし",
+			args: args{oldText: "a\nb", newText: "a\nb"},
+			wants: wants{
+				lines: []*textdiff.Line{
+					{Op: textdiff.OpEqual, Text: "a", OldLine: 1, NewLine: 1},
+					{Op: textdiff.OpEqual, Text: "b", OldLine: 2, NewLine: 2},
+				},
+			},
+		},
+		{
+			name: "行の追加",
+			args: args{oldText: "a\nc", newText: "a\nb\nc"},
+			wants: wants{
+				lines: []*textdiff.Line{
+					{Op: textdiff.OpEqual, Text: "a", OldLine: 1, NewLine: 1},
+					{Op: textdiff.OpInsert, Text: "b", NewLine: 2},
+					{Op: textdiff.OpEqual, Text: "c", OldLine: 2, NewLine: 3},
+				},
+			},
+		},
+		{
+			name: "行の削除",
+			args: args{oldText: "a\nb\nc", newText: "a\nc"},
+			wants: wants{
+				lines: []*textdiff.Line{
+					{Op: textdiff.OpEqual, Text: "a", OldLine: 1, NewLine: 1},
+					{Op: textdiff.OpDelete, Text: "b", OldLine: 2},
+					{Op: textdiff.OpEqual, Text: "c", OldLine: 3, NewLine: 2},
+				},
+			},
+		},
+		{
+			name: "行の置換",
+			args: args{oldText: "a\nb\nc", newText: "a\nx\nc"},
+			wants: wants{
+				lines: []*textdiff.Line{
+					{Op: textdiff.OpEqual, Text: "a", OldLine: 1, NewLine: 1},
+					{Op: textdiff.OpDelete, Text: "b", OldLine: 2},
+					{Op: textdiff.OpInsert, Text: "x", NewLine: 2},
+					{Op: textdiff.OpEqual, Text: "c", OldLine: 3, NewLine: 3},
+				},
+			},
+		},
+		{
+			name: "空文字からの追加",
+			args: args{oldText: "", newText: "a\r\nb"},
+			wants: wants{
+				lines: []*textdiff.Line{
+					{Op: textdiff.OpInsert, Text: "a", NewLine: 1},
+					{Op: textdiff.OpInsert, Text: "b", NewLine: 2},
+				},
+			},
+		},
+		{
+			name: "全削除",
+			args: args{oldText: "a\nb", newText: ""},
+			wants: wants{
+				lines: []*textdiff.Line{
+					{Op: textdiff.OpDelete, Text: "a", OldLine: 1},
+					{Op: textdiff.OpDelete, Text: "b", OldLine: 2},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := textdiff.Lines(tt.args.oldText, tt.args.newText)
+			if diff := cmp.Diff(got, tt.wants.lines); diff != "" {
+				t.Errorf("differs: (-got +want)\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_Unified(t *testing.T) {
+	lines := textdiff.Lines("a\nb\nc", "a\nx\nc")
+	want := " a\n-b\n+x\n c\n"
+	if got := textdiff.Unified(lines); got != want {
+		t.Errorf("got: %q, want: %q", got, want)
+	}
+	if !textdiff.HasChanges(lines) {
+		t.Errorf("HasChanges() = false, want true")
+	}
+}
@@ -0,0 +1,36 @@
+package totp
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strings"
+)
+
+// recoveryCodeAlphabet は、読み間違えやすい文字(0, 1, i, l, o)を除いた文字
+const recoveryCodeAlphabet = "23456789abcdefghjkmnpqrstuvwxyz"
+
+// GenerateRecoveryCodes は、認証アプリを使えない場合のための使い捨てのリカバリーコードを生成する
+// コードは xxxxx-xxxxx の形式
+func GenerateRecoveryCodes(n int) ([]string, error) {
+	codes := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		b := make([]byte, 10)
+		if _, err := rand.Read(b); err != nil {
+			return nil, fmt.Errorf("failed to read random: %w", err)
+		}
+		var sb strings.Builder
+		for j, c := range b {
+			if j == 5 {
+				sb.WriteByte('-')
+			}
+			sb.WriteByte(recoveryCodeAlphabet[int(c)%len(recoveryCodeAlphabet)])
+		}
+		codes = append(codes, sb.String())
+	}
+	return codes, nil
+}
+
+// NormalizeRecoveryCode は、入力されたリカバリーコードの大文字・空白を正規化する
+func NormalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), " ", ""))
+}
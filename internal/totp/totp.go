@@ -0,0 +1,114 @@
+// Package totp は、RFC 6238のTOTP(時間ベースのワンタイムパスワード)を実装する
+// Google Authenticator等の認証アプリと互換のSHA1・6桁・30秒の設定のみを扱う
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	Digits = 6
+	Period = 30 * time.Second
+	// Skew は、時刻のずれを許容する前後のステップ数
+	Skew = 1
+)
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret は、160bitのランダムな共有鍵をBase32で返す
+func GenerateSecret() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to read random: %w", err)
+	}
+	return encoding.EncodeToString(b), nil
+}
+
+// URI は、認証アプリに登録するためのotpauth URIを返す
+func URI(issuer string, account string, secret string) string {
+	v := url.Values{}
+	v.Set("secret", secret)
+	v.Set("issuer", issuer)
+	v.Set("algorithm", "SHA1")
+	v.Set("digits", fmt.Sprint(Digits))
+	v.Set("period", fmt.Sprint(int(Period.Seconds())))
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + account,
+		RawQuery: v.Encode(),
+	}
+	return u.String()
+}
+
+// Code は、指定した時刻のワンタイムパスワードを返す
+func Code(secret string, t time.Time) (string, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return hotp(key, uint64(t.Unix()/int64(Period.Seconds()))), nil
+}
+
+// Verify は、ワンタイムパスワードが指定した時刻の前後Skewステップの範囲で一致するかを判定する
+func Verify(secret string, code string, t time.Time) bool {
+	_, ok := VerifyStep(secret, code, t)
+	return ok
+}
+
+// VerifyStep は、Verifyと同様に判定し、一致したタイムステップを返す
+// 同じコードの再利用を防ぐため、呼び出し側は受け付けたステップ以前のコードを拒否する
+func VerifyStep(secret string, code string, t time.Time) (int64, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != Digits {
+		return 0, false
+	}
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return 0, false
+	}
+	counter := t.Unix() / int64(Period.Seconds())
+	for i := -Skew; i <= Skew; i++ {
+		step := counter + int64(i)
+		want := hotp(key, uint64(step))
+		if subtle.ConstantTimeCompare([]byte(want), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+func decodeSecret(secret string) ([]byte, error) {
+	s := strings.ToUpper(strings.ReplaceAll(secret, " ", ""))
+	key, err := encoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode secret: %w", err)
+	}
+	return key, nil
+}
+
+// hotp は、RFC 4226のHOTPを計算する
+func hotp(key []byte, counter uint64) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, counter)
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	mod := uint32(1)
+	for i := 0; i < Digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", Digits, value%mod)
+}
@@ -0,0 +1,128 @@
+package totp_test
+
+import (
+	"encoding/base32"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shoet/blog/internal/totp"
+)
+
+// RFC 6238 Appendix B のSHA1のテストベクタ(下6桁)
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func Test_Code(t *testing.T) {
+	tests := []struct {
+		name string
+		unix int64
+		want string
+	}{
+		{name: "59", unix: 59, want: "287082"},
+		{name: "1111111109", unix: 1111111109, want: "081804"},
+		{name: "1111111111", unix: 1111111111, want: "050471"},
+		{name: "1234567890", unix: 1234567890, want: "005924"},
+		{name: "2000000000", unix: 2000000000, want: "279037"},
+		{name: "20000000000", unix: 20000000000, want: "353130"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := totp.Code(rfcSecret, time.Unix(tt.unix, 0))
+			if err != nil {
+				t.Fatalf("failed to generate code: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("want %s, but got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_Verify(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+
+	tests := []struct {
+		name string
+		code string
+		at   time.Time
+		want bool
+	}{
+		{name: "同じステップ", code: "050471", at: now, want: true},
+		{name: "1ステップ前のコード", code: "050471", at: now.Add(totp.Period), want: true},
+		{name: "2ステップ前のコードは不一致", code: "050471", at: now.Add(2 * totp.Period), want: false},
+		{name: "桁数が異なる", code: "50471", at: now, want: false},
+		{name: "不一致", code: "000000", at: now, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := totp.Verify(rfcSecret, tt.code, tt.at); got != tt.want {
+				t.Errorf("want %v, but got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_VerifyStep(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+
+	step, ok := totp.VerifyStep(rfcSecret, "050471", now.Add(totp.Period))
+	if !ok {
+		t.Fatalf("want verified")
+	}
+	if want := int64(1111111111 / 30); step != want {
+		t.Errorf("want step %d, but got %d", want, step)
+	}
+	if _, ok := totp.VerifyStep(rfcSecret, "000000", now); ok {
+		t.Errorf("want not verified")
+	}
+}
+
+func Test_GenerateSecret(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatalf("failed to generate secret: %v", err)
+	}
+	now := time.Now()
+	code, err := totp.Code(secret, now)
+	if err != nil {
+		t.Fatalf("failed to generate code: %v", err)
+	}
+	if !totp.Verify(secret, code, now) {
+		t.Errorf("failed to verify generated code")
+	}
+}
+
+func Test_URI(t *testing.T) {
+	got := totp.URI("blog", "admin@example.com", "SECRET")
+	want := "otpauth://totp/blog:admin@example.com?algorithm=SHA1&digits=6&issuer=blog&period=30&secret=SECRET"
+	if got != want {
+		t.Errorf("want %s, but got %s", want, got)
+	}
+}
+
+func Test_GenerateRecoveryCodes(t *testing.T) {
+	codes, err := totp.GenerateRecoveryCodes(10)
+	if err != nil {
+		t.Fatalf("failed to generate recovery codes: %v", err)
+	}
+	if len(codes) != 10 {
+		t.Fatalf("want 10 codes, but got %d", len(codes))
+	}
+	pattern := regexp.MustCompile(`^[2-9a-z]{5}-[2-9a-z]{5}$`)
+	seen := map[string]struct{}{}
+	for _, c := range codes {
+		if !pattern.MatchString(c) {
+			t.Errorf("invalid format: %s", c)
+		}
+		if _, ok := seen[c]; ok {
+			t.Errorf("duplicated code: %s", c)
+		}
+		seen[c] = struct{}{}
+		if totp.NormalizeRecoveryCode(" "+strings.ToUpper(c)+" ") != c {
+			t.Errorf("failed to normalize: %s", c)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package activate_totp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shoet/blog/internal/clocker"
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/totp"
+	"github.com/shoet/blog/internal/util"
+)
+
+type UserRepository interface {
+	Get(ctx context.Context, tx infrastracture.TX, id models.UserId) (*models.User, error)
+	PutTOTP(ctx context.Context, tx infrastracture.TX, id models.UserId, secret string, enabled bool) error
+	ReplaceRecoveryCodes(ctx context.Context, tx infrastracture.TX, userId models.UserId, codeHashes []string) error
+	UseTOTPStep(ctx context.Context, tx infrastracture.TX, id models.UserId, step int64) (bool, error)
+}
+
+var (
+	ErrAlreadyEnabled = errors.New("two factor authentication is already enabled")
+	ErrNotSetup       = errors.New("two factor authentication is not setup")
+	ErrCodeInvalid    = errors.New("two factor code is invalid")
+)
+
+// RecoveryCodeCount は、有効化時に発行するリカバリーコードの数
+const RecoveryCodeCount = 10
+
+// activate_totp.Usecaseは認証コードを検証して2段階認証を有効化し、リカバリーコードを発行するユースケースです。
+// リカバリーコードはハッシュ化して保存するため、平文を返すのはこの時のみです。
+type Usecase struct {
+	DB             infrastracture.DB
+	UserRepository UserRepository
+	Clocker        clocker.Clocker
+}
+
+func NewUsecase(db infrastracture.DB, userRepository UserRepository, clocker clocker.Clocker) *Usecase {
+	return &Usecase{
+		DB:             db,
+		UserRepository: userRepository,
+		Clocker:        clocker,
+	}
+}
+
+func (u *Usecase) Run(ctx context.Context, userId models.UserId, code string) ([]string, error) {
+	transactor := infrastracture.NewTransactionProvider(u.DB)
+	result, err := transactor.DoInTx(ctx, func(tx infrastracture.TX) (interface{}, error) {
+		user, err := u.UserRepository.Get(ctx, tx, userId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user: %w", err)
+		}
+		if user.TOTPEnabled {
+			return nil, ErrAlreadyEnabled
+		}
+		if user.TOTPSecret == "" {
+			return nil, ErrNotSetup
+		}
+		step, ok := totp.VerifyStep(user.TOTPSecret, code, u.Clocker.Now())
+		if !ok {
+			return nil, ErrCodeInvalid
+		}
+		// 有効化に使用した認証コードはログインに再利用できないようにする
+		if _, err := u.UserRepository.UseTOTPStep(ctx, tx, userId, step); err != nil {
+			return nil, fmt.Errorf("failed to use totp step: %w", err)
+		}
+
+		codes, err := totp.GenerateRecoveryCodes(RecoveryCodeCount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate recovery codes: %w", err)
+		}
+		hashes := make([]string, 0, len(codes))
+		for _, c := range codes {
+			h, err := util.HashPassword(c)
+			if err != nil {
+				return nil, fmt.Errorf("failed to hash recovery code: %w", err)
+			}
+			hashes = append(hashes, h)
+		}
+		if err := u.UserRepository.ReplaceRecoveryCodes(ctx, tx, userId, hashes); err != nil {
+			return nil, fmt.Errorf("failed to replace recovery codes: %w", err)
+		}
+		if err := u.UserRepository.PutTOTP(ctx, tx, userId, user.TOTPSecret, true); err != nil {
+			return nil, fmt.Errorf("failed to put totp: %w", err)
+		}
+		return codes, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to activate totp: %w", err)
+	}
+	codes, ok := result.([]string)
+	if !ok {
+		return nil, fmt.Errorf("failed to type assertion")
+	}
+	return codes, nil
+}
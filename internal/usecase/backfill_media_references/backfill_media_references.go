@@ -0,0 +1,71 @@
+package backfill_media_references
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/mediaref"
+)
+
+type BlogRepository interface {
+	ListIds(ctx context.Context, tx infrastracture.TX) ([]models.BlogId, error)
+	Get(ctx context.Context, tx infrastracture.TX, id models.BlogId) (*models.Blog, error)
+}
+
+type MediaRepository interface {
+	ReplaceBlogReferences(ctx context.Context, tx infrastracture.TX, blogId models.BlogId, keys []string) error
+}
+
+// backfill_media_references.Usecaseはすべてのブログのメディアへの参照を作り直すユースケースです。
+// メディアの参照の記録を導入する前に保存したブログの参照の生成に使用します。
+type Usecase struct {
+	DB              infrastracture.DB
+	BlogRepository  BlogRepository
+	MediaRepository MediaRepository
+}
+
+func NewUsecase(
+	db infrastracture.DB, blogRepository BlogRepository, mediaRepository MediaRepository,
+) *Usecase {
+	return &Usecase{
+		DB:              db,
+		BlogRepository:  blogRepository,
+		MediaRepository: mediaRepository,
+	}
+}
+
+// Run は、参照を作り直したブログの件数を返す
+func (u *Usecase) Run(ctx context.Context) (int, error) {
+	transactor := infrastracture.NewTransactionProvider(u.DB)
+	result, err := transactor.DoInTx(ctx, func(tx infrastracture.TX) (interface{}, error) {
+		ids, err := u.BlogRepository.ListIds(ctx, tx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list blog ids: %w", err)
+		}
+		count := 0
+		for _, id := range ids {
+			blog, err := u.BlogRepository.Get(ctx, tx, id)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get blog: %w", err)
+			}
+			if blog == nil {
+				continue
+			}
+			if err := u.MediaRepository.ReplaceBlogReferences(ctx, tx, blog.Id, mediaref.FromBlog(blog)); err != nil {
+				return nil, fmt.Errorf("failed to replace media references of blog %d: %w", id, err)
+			}
+			count++
+		}
+		return count, nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to backfill media references: %w", err)
+	}
+	count, ok := result.(int)
+	if !ok {
+		return 0, fmt.Errorf("failed to type assertion")
+	}
+	return count, nil
+}
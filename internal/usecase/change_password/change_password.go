@@ -0,0 +1,66 @@
+package change_password
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/util"
+)
+
+type UserRepository interface {
+	GetPasswordHash(ctx context.Context, tx infrastracture.TX, id models.UserId) (string, error)
+	PutPassword(ctx context.Context, tx infrastracture.TX, id models.UserId, hashedPassword string) error
+}
+
+type AuthService interface {
+	LogoutAll(ctx context.Context, userId models.UserId) error
+}
+
+var ErrPasswordMismatch = errors.New("current password is incorrect")
+
+// change_password.Usecaseはログイン中のユーザーのパスワードを変更するユースケースです。
+// 変更後は既存のセッションをすべて失効させます。
+type Usecase struct {
+	DB             infrastracture.DB
+	UserRepository UserRepository
+	authService    AuthService
+}
+
+func NewUsecase(db infrastracture.DB, userRepository UserRepository, authService AuthService) *Usecase {
+	return &Usecase{
+		DB:             db,
+		UserRepository: userRepository,
+		authService:    authService,
+	}
+}
+
+func (u *Usecase) Run(ctx context.Context, userId models.UserId, currentPassword string, newPassword string) error {
+	transactor := infrastracture.NewTransactionProvider(u.DB)
+	_, err := transactor.DoInTx(ctx, func(tx infrastracture.TX) (interface{}, error) {
+		hashed, err := u.UserRepository.GetPasswordHash(ctx, tx, userId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get password: %w", err)
+		}
+		if !util.ComparePassword(hashed, currentPassword) {
+			return nil, ErrPasswordMismatch
+		}
+		newHashed, err := util.HashPassword(newPassword)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash password: %w", err)
+		}
+		if err := u.UserRepository.PutPassword(ctx, tx, userId, newHashed); err != nil {
+			return nil, fmt.Errorf("failed to put password: %w", err)
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to change password: %w", err)
+	}
+	if err := u.authService.LogoutAll(ctx, userId); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	return nil
+}
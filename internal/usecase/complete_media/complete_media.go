@@ -0,0 +1,89 @@
+package complete_media
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/adapter"
+	"github.com/shoet/blog/internal/infrastracture/models"
+)
+
+type MediaRepository interface {
+	Get(ctx context.Context, tx infrastracture.TX, id models.MediaId) (*models.Media, error)
+	MarkUploaded(ctx context.Context, tx infrastracture.TX, id models.MediaId, size int64) error
+}
+
+type ContentsService interface {
+	UploadedSize(ctx context.Context, kind models.MediaKind, fileName string) (int64, error)
+}
+
+type Policy interface {
+	AuthorizeMediaComplete(ctx context.Context, media *models.Media) error
+}
+
+var (
+	ErrMediaNotFound    = errors.New("media is not found")
+	ErrMediaNotUploaded = errors.New("media is not uploaded")
+)
+
+// complete_media.Usecaseは署名付きURLでのアップロードの完了を受け、メディアをアップロード済みとするユースケースです。
+// ストレージにオブジェクトが存在することを確認し、実際のサイズを記録します。
+// 完了できるのはメディアをアップロードしたユーザーと、他のユーザーの画像を操作する権限を持つユーザーのみです。
+type Usecase struct {
+	DB              infrastracture.DB
+	MediaRepository MediaRepository
+	ContentsService ContentsService
+	Policy          Policy
+}
+
+func NewUsecase(
+	db infrastracture.DB, mediaRepository MediaRepository, contentsService ContentsService, policy Policy,
+) *Usecase {
+	return &Usecase{
+		DB:              db,
+		MediaRepository: mediaRepository,
+		ContentsService: contentsService,
+		Policy:          policy,
+	}
+}
+
+func (u *Usecase) Run(ctx context.Context, id models.MediaId) (*models.Media, error) {
+	transactor := infrastracture.NewTransactionProvider(u.DB)
+	result, err := transactor.DoInTx(ctx, func(tx infrastracture.TX) (interface{}, error) {
+		media, err := u.MediaRepository.Get(ctx, tx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get media: %w", err)
+		}
+		if media == nil {
+			return nil, ErrMediaNotFound
+		}
+		if err := u.Policy.AuthorizeMediaComplete(ctx, media); err != nil {
+			return nil, fmt.Errorf("failed to authorize: %w", err)
+		}
+		size, err := u.ContentsService.UploadedSize(ctx, media.Kind, media.FileName)
+		if err != nil {
+			if errors.Is(err, adapter.ErrObjectNotFound) {
+				return nil, ErrMediaNotUploaded
+			}
+			return nil, fmt.Errorf("failed to get uploaded size: %w", err)
+		}
+		if err := u.MediaRepository.MarkUploaded(ctx, tx, id, size); err != nil {
+			return nil, fmt.Errorf("failed to mark uploaded: %w", err)
+		}
+		uploaded, err := u.MediaRepository.Get(ctx, tx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get media: %w", err)
+		}
+		return uploaded, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to complete media: %w", err)
+	}
+	media, ok := result.(*models.Media)
+	if !ok {
+		return nil, fmt.Errorf("failed to type assertion")
+	}
+	return media, nil
+}
@@ -0,0 +1,81 @@
+package create_api_key
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shoet/blog/internal/apikey"
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+)
+
+type APIKeyRepository interface {
+	Add(ctx context.Context, tx infrastracture.TX, key *models.APIKey) (models.APIKeyId, error)
+	GetByPrefix(ctx context.Context, tx infrastracture.TX, prefix string) (*models.APIKey, error)
+}
+
+var ErrInvalidScope = errors.New("invalid api key scope")
+
+// Result は、発行したAPIキーとその平文
+// 平文のキーは発行時にのみ返す
+type Result struct {
+	Key    string         `json:"key"`
+	APIKey *models.APIKey `json:"apiKey"`
+}
+
+// create_api_key.UsecaseはユーザーのスコープつきAPIキーを発行するユースケースです。
+type Usecase struct {
+	DB               infrastracture.DB
+	APIKeyRepository APIKeyRepository
+}
+
+func NewUsecase(db infrastracture.DB, apiKeyRepository APIKeyRepository) *Usecase {
+	return &Usecase{
+		DB:               db,
+		APIKeyRepository: apiKeyRepository,
+	}
+}
+
+func (u *Usecase) Run(
+	ctx context.Context, userId models.UserId, name string, scopes models.APIKeyScopes,
+) (*Result, error) {
+	if len(scopes) == 0 {
+		return nil, ErrInvalidScope
+	}
+	for _, s := range scopes {
+		if !s.IsValid() {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidScope, s)
+		}
+	}
+	key, prefix, hash, err := apikey.Generate()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate api key: %w", err)
+	}
+
+	transactor := infrastracture.NewTransactionProvider(u.DB)
+	result, err := transactor.DoInTx(ctx, func(tx infrastracture.TX) (interface{}, error) {
+		if _, err := u.APIKeyRepository.Add(ctx, tx, &models.APIKey{
+			UserId:  userId,
+			Name:    name,
+			Prefix:  prefix,
+			KeyHash: hash,
+			Scopes:  scopes,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to add api key: %w", err)
+		}
+		newKey, err := u.APIKeyRepository.GetByPrefix(ctx, tx, prefix)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get api key: %w", err)
+		}
+		return newKey, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create api key: %w", err)
+	}
+	newKey, ok := result.(*models.APIKey)
+	if !ok {
+		return nil, fmt.Errorf("failed to type assertion")
+	}
+	return &Result{Key: key, APIKey: newKey}, nil
+}
@@ -6,7 +6,10 @@ import (
 
 	"github.com/shoet/blog/internal/infrastracture"
 	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/markdown"
+	"github.com/shoet/blog/internal/mediaref"
 	"github.com/shoet/blog/internal/session"
+	"github.com/shoet/blog/internal/slug"
 )
 
 type BlogRepository interface {
@@ -15,27 +18,36 @@ type BlogRepository interface {
 	AddBlogTag(ctx context.Context, tx infrastracture.TX, blogId models.BlogId, tagId models.TagId) (int64, error)
 	SelectTags(ctx context.Context, tx infrastracture.TX, tag string) ([]*models.Tag, error)
 	AddTag(ctx context.Context, tx infrastracture.TX, tag string) (models.TagId, error)
+	AddRevision(ctx context.Context, tx infrastracture.TX, revision *models.BlogRevision) (*models.BlogRevision, error)
+	ExistsSlug(ctx context.Context, tx infrastracture.TX, slug string, excludeBlogId models.BlogId) (bool, error)
 }
 
-type BlogService interface {
-	Validate(ctx context.Context, userId models.UserId, blog *models.Blog) error
+type MediaRepository interface {
+	ReplaceBlogReferences(ctx context.Context, tx infrastracture.TX, blogId models.BlogId, keys []string) error
+}
+
+type Policy interface {
+	AuthorizeBlogEdit(ctx context.Context, blog *models.Blog) error
 }
 
 type Usecase struct {
-	DB             infrastracture.DB
-	BlogRepository BlogRepository
-	BlogService    BlogService
+	DB              infrastracture.DB
+	BlogRepository  BlogRepository
+	MediaRepository MediaRepository
+	Policy          Policy
 }
 
 func NewUsecase(
 	db infrastracture.DB,
 	blogRepository BlogRepository,
-	blogService BlogService,
+	mediaRepository MediaRepository,
+	policy Policy,
 ) *Usecase {
 	return &Usecase{
-		DB:             db,
-		BlogRepository: blogRepository,
-		BlogService:    blogService,
+		DB:              db,
+		BlogRepository:  blogRepository,
+		MediaRepository: mediaRepository,
+		Policy:          policy,
 	}
 }
 
@@ -44,8 +56,9 @@ func (u *Usecase) Run(ctx context.Context, blog *models.Blog) (*models.Blog, err
 	if err != nil {
 		return nil, fmt.Errorf("failed to session.GetUserId: %w", err)
 	}
-	if err := u.BlogService.Validate(ctx, sessionUserId, blog); err != nil {
-		return nil, fmt.Errorf("failed to BlogService.Validate: %w", err)
+	// 他のユーザーを著者とするブログは、他のユーザーのブログを編集できる場合のみ作成できる
+	if err := u.Policy.AuthorizeBlogEdit(ctx, blog); err != nil {
+		return nil, fmt.Errorf("failed to authorize: %w", err)
 	}
 
 	transactor := infrastracture.NewTransactionProvider(u.DB)
@@ -69,6 +82,24 @@ func (u *Usecase) Run(ctx context.Context, blog *models.Blog) (*models.Blog, err
 			}
 		}
 
+		// スラッグが指定されていない場合はタイトルから生成する
+		baseSlug := slug.Normalize(blog.Slug)
+		if baseSlug == "" {
+			baseSlug = slug.Generate(blog.Title)
+		}
+		blogSlug, err := slug.Unique(baseSlug, func(s string) (bool, error) {
+			return u.BlogRepository.ExistsSlug(ctx, tx, s, 0)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve slug: %w", err)
+		}
+		blog.Slug = blogSlug
+
+		// 本文のレンダリング結果をキャッシュする
+		if err := markdown.RenderBlog(blog); err != nil {
+			return nil, fmt.Errorf("failed to render blog: %w", err)
+		}
+
 		// add blog
 		id, err := u.BlogRepository.Add(ctx, tx, blog)
 		if err != nil {
@@ -87,6 +118,17 @@ func (u *Usecase) Run(ctx context.Context, blog *models.Blog) (*models.Blog, err
 		if err != nil {
 			return nil, fmt.Errorf("failed to get blog: %w", err)
 		}
+
+		// 本文・サムネイルから参照しているメディアを記録する
+		if err := u.MediaRepository.ReplaceBlogReferences(ctx, tx, newBlog.Id, mediaref.FromBlog(newBlog)); err != nil {
+			return nil, fmt.Errorf("failed to replace media references: %w", err)
+		}
+
+		// add blog_revisions
+		revision := models.NewBlogRevision(newBlog, sessionUserId)
+		if _, err := u.BlogRepository.AddRevision(ctx, tx, revision); err != nil {
+			return nil, fmt.Errorf("failed to add blog revision: %w", err)
+		}
 		return newBlog, nil
 	})
 
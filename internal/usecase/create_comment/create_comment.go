@@ -0,0 +1,113 @@
+package create_comment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shoet/blog/internal/clocker"
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+)
+
+type BlogRepository interface {
+	Get(ctx context.Context, tx infrastracture.TX, id models.BlogId) (*models.Blog, error)
+}
+
+type CommentRepository interface {
+	Add(ctx context.Context, tx infrastracture.TX, comment *models.Comment) (models.CommentId, error)
+	Get(ctx context.Context, tx infrastracture.TX, id models.CommentId) (*models.Comment, error)
+}
+
+var ErrBlogNotFound = errors.New("blog is not found")
+var ErrParentCommentNotFound = errors.New("parent comment is not found")
+var ErrNestedReply = errors.New("can't reply to a reply")
+
+// create_comment.Usecaseは読者のコメントを投稿するユースケースです。
+// 投稿されたコメントは承認待ちとなり、管理者が承認するまで公開されません。
+type Usecase struct {
+	DB                infrastracture.DB
+	BlogRepository    BlogRepository
+	CommentRepository CommentRepository
+	Clocker           clocker.Clocker
+}
+
+func NewUsecase(
+	db infrastracture.DB,
+	blogRepository BlogRepository,
+	commentRepository CommentRepository,
+	clocker clocker.Clocker,
+) *Usecase {
+	return &Usecase{
+		DB:                db,
+		BlogRepository:    blogRepository,
+		CommentRepository: commentRepository,
+		Clocker:           clocker,
+	}
+}
+
+type Input struct {
+	BlogId      models.BlogId
+	ParentId    *models.CommentId
+	AuthorName  string
+	AuthorEmail string
+	Body        string
+	IpAddress   string
+	UserAgent   string
+}
+
+func (u *Usecase) Run(ctx context.Context, input *Input) (*models.Comment, error) {
+	transactor := infrastracture.NewTransactionProvider(u.DB)
+	result, err := transactor.DoInTx(ctx, func(tx infrastracture.TX) (interface{}, error) {
+		blog, err := u.BlogRepository.Get(ctx, tx, input.BlogId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get blog: %w", err)
+		}
+		// 公開中のブログにのみコメントできる
+		if blog == nil || !blog.IsPublished(u.Clocker.Now()) {
+			return nil, ErrBlogNotFound
+		}
+
+		if input.ParentId != nil {
+			parent, err := u.CommentRepository.Get(ctx, tx, *input.ParentId)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get parent comment: %w", err)
+			}
+			if parent == nil || parent.BlogId != input.BlogId || parent.Status != models.CommentStatusApproved {
+				return nil, ErrParentCommentNotFound
+			}
+			// 返信は1階層のみ
+			if parent.IsReply() {
+				return nil, ErrNestedReply
+			}
+		}
+
+		comment := &models.Comment{
+			BlogId:      input.BlogId,
+			ParentId:    input.ParentId,
+			AuthorName:  input.AuthorName,
+			AuthorEmail: input.AuthorEmail,
+			Body:        input.Body,
+			Status:      models.CommentStatusPending,
+			IpAddress:   input.IpAddress,
+			UserAgent:   input.UserAgent,
+		}
+		id, err := u.CommentRepository.Add(ctx, tx, comment)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add comment: %w", err)
+		}
+		newComment, err := u.CommentRepository.Get(ctx, tx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get comment: %w", err)
+		}
+		return newComment, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create comment: %w", err)
+	}
+	comment, ok := result.(*models.Comment)
+	if !ok {
+		return nil, fmt.Errorf("failed to type assertion")
+	}
+	return comment, nil
+}
@@ -0,0 +1,49 @@
+package create_series
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+)
+
+type SeriesRepository interface {
+	Add(ctx context.Context, tx infrastracture.TX, series *models.Series) (models.SeriesId, error)
+	Get(ctx context.Context, tx infrastracture.TX, id models.SeriesId) (*models.Series, error)
+}
+
+type Usecase struct {
+	DB               infrastracture.DB
+	SeriesRepository SeriesRepository
+}
+
+func NewUsecase(db infrastracture.DB, seriesRepository SeriesRepository) *Usecase {
+	return &Usecase{
+		DB:               db,
+		SeriesRepository: seriesRepository,
+	}
+}
+
+func (u *Usecase) Run(ctx context.Context, series *models.Series) (*models.Series, error) {
+	transactor := infrastracture.NewTransactionProvider(u.DB)
+	result, err := transactor.DoInTx(ctx, func(tx infrastracture.TX) (interface{}, error) {
+		id, err := u.SeriesRepository.Add(ctx, tx, series)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add series: %w", err)
+		}
+		newSeries, err := u.SeriesRepository.Get(ctx, tx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get series: %w", err)
+		}
+		return newSeries, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create series: %w", err)
+	}
+	newSeries, ok := result.(*models.Series)
+	if !ok {
+		return nil, fmt.Errorf("failed to type assertion")
+	}
+	return newSeries, nil
+}
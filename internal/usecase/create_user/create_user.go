@@ -0,0 +1,83 @@
+package create_user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/infrastracture/repository"
+	"github.com/shoet/blog/internal/util"
+)
+
+type UserRepository interface {
+	Add(ctx context.Context, tx infrastracture.TX, user *models.User) (*models.User, error)
+	Get(ctx context.Context, tx infrastracture.TX, id models.UserId) (*models.User, error)
+	GetByEmail(ctx context.Context, tx infrastracture.TX, email string) (*models.User, error)
+}
+
+var ErrInvalidRole = errors.New("role is invalid")
+var ErrEmailAlreadyExists = errors.New("email already exists")
+
+type Input struct {
+	Name     string
+	Email    string
+	Password string
+	Role     models.Role
+}
+
+// create_user.Usecaseは管理者がユーザーを追加するユースケースです。
+type Usecase struct {
+	DB             infrastracture.DB
+	UserRepository UserRepository
+}
+
+func NewUsecase(db infrastracture.DB, userRepository UserRepository) *Usecase {
+	return &Usecase{
+		DB:             db,
+		UserRepository: userRepository,
+	}
+}
+
+func (u *Usecase) Run(ctx context.Context, input *Input) (*models.User, error) {
+	if !input.Role.IsValid() {
+		return nil, ErrInvalidRole
+	}
+	hashed, err := util.HashPassword(input.Password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	transactor := infrastracture.NewTransactionProvider(u.DB)
+	result, err := transactor.DoInTx(ctx, func(tx infrastracture.TX) (interface{}, error) {
+		if _, err := u.UserRepository.GetByEmail(ctx, tx, input.Email); err == nil {
+			return nil, ErrEmailAlreadyExists
+		} else if !errors.Is(err, repository.ErrUserNotFound) {
+			return nil, fmt.Errorf("failed to get user by email: %w", err)
+		}
+		user, err := u.UserRepository.Add(ctx, tx, &models.User{
+			Name:     input.Name,
+			Email:    input.Email,
+			Password: hashed,
+			Role:     input.Role,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to add user: %w", err)
+		}
+		newUser, err := u.UserRepository.Get(ctx, tx, user.Id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user: %w", err)
+		}
+		return newUser, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	user, ok := result.(*models.User)
+	if !ok {
+		return nil, fmt.Errorf("failed to type assertion: %w", err)
+	}
+	return user, nil
+}
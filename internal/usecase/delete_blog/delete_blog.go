@@ -6,7 +6,6 @@ import (
 
 	"github.com/shoet/blog/internal/infrastracture"
 	"github.com/shoet/blog/internal/infrastracture/models"
-	"github.com/shoet/blog/internal/session"
 	"golang.org/x/exp/slices"
 )
 
@@ -19,27 +18,29 @@ type BlogRepository interface {
 	DeleteBlogsTags(ctx context.Context, tx infrastracture.TX, blogId models.BlogId, tagId models.TagId) error
 }
 
+type Policy interface {
+	AuthorizeBlogEdit(ctx context.Context, blog *models.Blog) error
+}
+
 type Usecase struct {
 	DB             infrastracture.DB
 	BlogRepository BlogRepository
+	Policy         Policy
 }
 
 func NewUsecase(
 	db infrastracture.DB,
 	blogRepository BlogRepository,
+	policy Policy,
 ) *Usecase {
 	return &Usecase{
 		DB:             db,
 		BlogRepository: blogRepository,
+		Policy:         policy,
 	}
 }
 
 func (u *Usecase) Run(ctx context.Context, blogId models.BlogId) (models.BlogId, error) {
-	sessionUserId, err := session.GetUserId(ctx)
-	if err != nil {
-		return 0, fmt.Errorf("failed to session.GetUserId: %w", err)
-	}
-
 	transactor := infrastracture.NewTransactionProvider(u.DB)
 
 	result, err := transactor.DoInTx(ctx, func(tx infrastracture.TX) (interface{}, error) {
@@ -48,8 +49,12 @@ func (u *Usecase) Run(ctx context.Context, blogId models.BlogId) (models.BlogId,
 			return 0, fmt.Errorf("failed to BlogRepository.Get: %w", err)
 		}
 
-		if blog.AuthorId != sessionUserId {
-			return 0, fmt.Errorf("can't delete other user's blog")
+		if blog == nil {
+			return 0, fmt.Errorf("blog is not found")
+		}
+
+		if err := u.Policy.AuthorizeBlogEdit(ctx, blog); err != nil {
+			return 0, fmt.Errorf("failed to authorize: %w", err)
 		}
 
 		// delete blogs_tags -----------------
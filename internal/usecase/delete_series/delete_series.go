@@ -0,0 +1,52 @@
+package delete_series
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+)
+
+type SeriesRepository interface {
+	Get(ctx context.Context, tx infrastracture.TX, id models.SeriesId) (*models.Series, error)
+	Delete(ctx context.Context, tx infrastracture.TX, id models.SeriesId) error
+}
+
+var ErrSeriesNotFound = errors.New("series is not found")
+
+// delete_series.Usecaseはシリーズを削除するユースケースです。
+// 所属していたブログは削除せず、シリーズから外れるのみです。
+type Usecase struct {
+	DB               infrastracture.DB
+	SeriesRepository SeriesRepository
+}
+
+func NewUsecase(db infrastracture.DB, seriesRepository SeriesRepository) *Usecase {
+	return &Usecase{
+		DB:               db,
+		SeriesRepository: seriesRepository,
+	}
+}
+
+func (u *Usecase) Run(ctx context.Context, id models.SeriesId) error {
+	transactor := infrastracture.NewTransactionProvider(u.DB)
+	_, err := transactor.DoInTx(ctx, func(tx infrastracture.TX) (interface{}, error) {
+		series, err := u.SeriesRepository.Get(ctx, tx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get series: %w", err)
+		}
+		if series == nil {
+			return nil, ErrSeriesNotFound
+		}
+		if err := u.SeriesRepository.Delete(ctx, tx, id); err != nil {
+			return nil, fmt.Errorf("failed to delete series: %w", err)
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete series: %w", err)
+	}
+	return nil
+}
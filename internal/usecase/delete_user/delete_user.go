@@ -0,0 +1,92 @@
+package delete_user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/infrastracture/repository"
+)
+
+type UserRepository interface {
+	Get(ctx context.Context, tx infrastracture.TX, id models.UserId) (*models.User, error)
+	Delete(ctx context.Context, tx infrastracture.TX, id models.UserId) error
+	CountActiveAdmins(ctx context.Context, tx infrastracture.TX) (int64, error)
+}
+
+type BlogRepository interface {
+	ExistsByAuthorId(ctx context.Context, tx infrastracture.TX, authorId models.UserId) (bool, error)
+}
+
+type AuthService interface {
+	LogoutAll(ctx context.Context, userId models.UserId) error
+}
+
+var ErrUserNotFound = errors.New("user is not found")
+var ErrLastAdmin = errors.New("can't delete the last admin")
+var ErrUserHasBlogs = errors.New("can't delete the user who has blogs")
+
+// delete_user.Usecaseは管理者がユーザーを削除するユースケースです。
+// ブログの著者となっているユーザーは削除せず、無効化で対応します。
+type Usecase struct {
+	DB             infrastracture.DB
+	UserRepository UserRepository
+	BlogRepository BlogRepository
+	authService    AuthService
+}
+
+func NewUsecase(
+	db infrastracture.DB, userRepository UserRepository, blogRepository BlogRepository, authService AuthService,
+) *Usecase {
+	return &Usecase{
+		DB:             db,
+		UserRepository: userRepository,
+		BlogRepository: blogRepository,
+		authService:    authService,
+	}
+}
+
+func (u *Usecase) Run(ctx context.Context, id models.UserId) error {
+	transactor := infrastracture.NewTransactionProvider(u.DB)
+	_, err := transactor.DoInTx(ctx, func(tx infrastracture.TX) (interface{}, error) {
+		user, err := u.UserRepository.Get(ctx, tx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrUserNotFound) {
+				return nil, ErrUserNotFound
+			}
+			return nil, fmt.Errorf("failed to get user: %w", err)
+		}
+
+		if user.Role == models.RoleAdmin && !user.IsDisabled() {
+			count, err := u.UserRepository.CountActiveAdmins(ctx, tx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to count admins: %w", err)
+			}
+			if count <= 1 {
+				return nil, ErrLastAdmin
+			}
+		}
+
+		exists, err := u.BlogRepository.ExistsByAuthorId(ctx, tx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check blogs: %w", err)
+		}
+		if exists {
+			return nil, ErrUserHasBlogs
+		}
+
+		if err := u.UserRepository.Delete(ctx, tx, id); err != nil {
+			return nil, fmt.Errorf("failed to delete user: %w", err)
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	if err := u.authService.LogoutAll(ctx, id); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	return nil
+}
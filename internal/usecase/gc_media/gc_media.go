@@ -0,0 +1,123 @@
+package gc_media
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/adapter"
+	"github.com/shoet/blog/internal/infrastracture/models"
+)
+
+type BlogRepository interface {
+	ListImageSources(ctx context.Context, tx infrastracture.TX) (models.Blogs, error)
+	ListRevisionImageSources(ctx context.Context, tx infrastracture.TX) ([]*models.BlogRevision, error)
+}
+
+type UserRepository interface {
+	ListAvatarURLs(ctx context.Context, tx infrastracture.TX) ([]string, error)
+}
+
+type MediaRepository interface {
+	DeleteByFileNames(ctx context.Context, tx infrastracture.TX, fileNames []string) error
+}
+
+type MediaGCService interface {
+	FindOrphans(ctx context.Context, references []string, gracePeriod time.Duration) ([]*adapter.ObjectInfo, error)
+	Delete(ctx context.Context, objects []*adapter.ObjectInfo) ([]*adapter.ObjectInfo, error)
+}
+
+// gc_media.Usecaseはどのブログ・編集履歴の本文・サムネイル、ユーザーのアバターからも参照されていない画像を
+// ストレージから削除するユースケースです。
+// dryRunの場合は削除の対象を返すのみで削除しません。
+// 削除した場合は削除できたオブジェクトを返し、そのメディアの登録も削除します。
+// 一部のオブジェクトの削除に失敗した場合も、削除できたオブジェクトをエラーとともに返します。
+type Usecase struct {
+	DB              infrastracture.DB
+	BlogRepository  BlogRepository
+	UserRepository  UserRepository
+	MediaRepository MediaRepository
+	MediaGCService  MediaGCService
+}
+
+func NewUsecase(
+	db infrastracture.DB,
+	blogRepository BlogRepository,
+	userRepository UserRepository,
+	mediaRepository MediaRepository,
+	mediaGCService MediaGCService,
+) *Usecase {
+	return &Usecase{
+		DB:              db,
+		BlogRepository:  blogRepository,
+		UserRepository:  userRepository,
+		MediaRepository: mediaRepository,
+		MediaGCService:  mediaGCService,
+	}
+}
+
+func (u *Usecase) Run(
+	ctx context.Context, gracePeriod time.Duration, dryRun bool,
+) ([]*adapter.ObjectInfo, error) {
+	transactor := infrastracture.NewTransactionProvider(u.DB)
+	result, err := transactor.DoInTx(ctx, func(tx infrastracture.TX) (interface{}, error) {
+		blogs, err := u.BlogRepository.ListImageSources(ctx, tx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list blogs: %w", err)
+		}
+		revisions, err := u.BlogRepository.ListRevisionImageSources(ctx, tx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list blog revisions: %w", err)
+		}
+		avatarURLs, err := u.UserRepository.ListAvatarURLs(ctx, tx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list avatar urls: %w", err)
+		}
+		references := make([]string, 0, len(blogs)*2+len(revisions)*2+len(avatarURLs))
+		for _, b := range blogs {
+			references = append(references, b.ThumbnailImageFileName, b.Content)
+		}
+		for _, r := range revisions {
+			references = append(references, r.ThumbnailImageFileName, r.Content)
+		}
+		references = append(references, avatarURLs...)
+		return references, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get references: %w", err)
+	}
+	references, ok := result.([]string)
+	if !ok {
+		return nil, fmt.Errorf("failed to type assertion")
+	}
+
+	orphans, err := u.MediaGCService.FindOrphans(ctx, references, gracePeriod)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find orphans: %w", err)
+	}
+	if dryRun || len(orphans) == 0 {
+		return orphans, nil
+	}
+
+	deleted, deleteErr := u.MediaGCService.Delete(ctx, orphans)
+	if len(deleted) > 0 {
+		fileNames := make([]string, 0, len(deleted))
+		for _, o := range deleted {
+			fileNames = append(fileNames, path.Base(o.Key))
+		}
+		if _, err := transactor.DoInTx(ctx, func(tx infrastracture.TX) (interface{}, error) {
+			if err := u.MediaRepository.DeleteByFileNames(ctx, tx, fileNames); err != nil {
+				return nil, fmt.Errorf("failed to delete media: %w", err)
+			}
+			return nil, nil
+		}); err != nil {
+			return deleted, fmt.Errorf("failed to delete media: %w", err)
+		}
+	}
+	if deleteErr != nil {
+		return deleted, fmt.Errorf("failed to delete orphans: %w", deleteErr)
+	}
+	return deleted, nil
+}
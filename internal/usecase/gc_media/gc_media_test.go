@@ -0,0 +1,126 @@
+package gc_media_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/shoet/blog/internal/clocker"
+	"github.com/shoet/blog/internal/config"
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/adapter"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/infrastracture/services/media_gc_service"
+	"github.com/shoet/blog/internal/testutil"
+	"github.com/shoet/blog/internal/usecase/gc_media"
+)
+
+type blogRepository struct {
+	blogs     models.Blogs
+	revisions []*models.BlogRevision
+}
+
+func (r *blogRepository) ListImageSources(ctx context.Context, tx infrastracture.TX) (models.Blogs, error) {
+	return r.blogs, nil
+}
+
+func (r *blogRepository) ListRevisionImageSources(
+	ctx context.Context, tx infrastracture.TX,
+) ([]*models.BlogRevision, error) {
+	return r.revisions, nil
+}
+
+type userRepository struct {
+	avatarURLs []string
+}
+
+func (r *userRepository) ListAvatarURLs(ctx context.Context, tx infrastracture.TX) ([]string, error) {
+	return r.avatarURLs, nil
+}
+
+type mediaRepository struct {
+	deleted []string
+}
+
+func (r *mediaRepository) DeleteByFileNames(ctx context.Context, tx infrastracture.TX, fileNames []string) error {
+	r.deleted = append(r.deleted, fileNames...)
+	return nil
+}
+
+func Test_Usecase_Run(t *testing.T) {
+	ctx := context.Background()
+	// リポジトリはフェイクのため、トランザクションの開始のみに使用する
+	db, err := testutil.NewDBSQLite3ForTest(t, ctx)
+	if err != nil {
+		t.Fatalf("failed to create db: %v", err)
+	}
+	dir := t.TempDir()
+	storage, err := adapter.NewLocalStorageAdapter(&config.Config{StorageLocalDir: dir}, clocker.NewFixedClocker())
+	if err != nil {
+		t.Fatalf("failed to create local storage adapter: %v", err)
+	}
+
+	const (
+		blogKey     = "0b7e1d2c-1a2b-4c3d-8e9f-0a1b2c3d4e5f"
+		revisionKey = "1c8f2e3d-2b3c-4d4e-9f0a-1b2c3d4e5f60"
+		avatarKey   = "2d9a3f4e-3c4d-4e5f-8a1b-2c3d4e5f6071"
+		orphanKey   = "3e0b4a5f-4d5e-4f60-9b2c-3d4e5f607182"
+	)
+	// FixedClockerの現在時刻は2020-01-01のため、猶予期間を過ぎたオブジェクトとする
+	old := time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC)
+	for _, key := range []string{
+		"thumbnail/" + blogKey + ".jpg",
+		"thumbnail/" + revisionKey + ".jpg",
+		"content/" + avatarKey + ".png",
+		"content/" + orphanKey + ".png",
+	} {
+		if err := storage.PutObject(ctx, key, "image/png", strings.NewReader("image")); err != nil {
+			t.Fatalf("failed to put object: %v", err)
+		}
+		if err := os.Chtimes(filepath.Join(dir, filepath.FromSlash(key)), old, old); err != nil {
+			t.Fatalf("failed to change times: %v", err)
+		}
+	}
+
+	blogRepo := &blogRepository{
+		blogs: models.Blogs{
+			{Id: 1, ThumbnailImageFileName: "https://cdn.example.com/thumbnail/" + blogKey + ".jpg", Content: "content"},
+		},
+		// 現在のブログからは参照されていないが、履歴の復元で参照が戻る
+		revisions: []*models.BlogRevision{
+			{BlogId: 1, ThumbnailImageFileName: "https://cdn.example.com/thumbnail/" + revisionKey + ".jpg"},
+		},
+	}
+	userRepo := &userRepository{avatarURLs: []string{"https://cdn.example.com/content/" + avatarKey + ".png"}}
+	mediaRepo := &mediaRepository{}
+	gcService := media_gc_service.NewMediaGCService(storage, clocker.NewFixedClocker(), "thumbnail", "content")
+	sut := gc_media.NewUsecase(db, blogRepo, userRepo, mediaRepo, gcService)
+
+	orphans, err := sut.Run(ctx, 24*time.Hour, false)
+	if err != nil {
+		t.Fatalf("failed to run: %v", err)
+	}
+	if len(orphans) != 1 || orphans[0].Key != "content/"+orphanKey+".png" {
+		t.Errorf("want only the orphan, but got %v", orphans)
+	}
+	for _, key := range []string{
+		"thumbnail/" + blogKey + ".jpg",
+		"thumbnail/" + revisionKey + ".jpg",
+		"content/" + avatarKey + ".png",
+	} {
+		if _, err := storage.HeadObject(ctx, key); err != nil {
+			t.Errorf("want %s kept, but got %v", key, err)
+		}
+	}
+	if _, err := storage.HeadObject(ctx, "content/"+orphanKey+".png"); !errors.Is(err, adapter.ErrObjectNotFound) {
+		t.Errorf("want orphan deleted, but got %v", err)
+	}
+	if len(mediaRepo.deleted) != 1 || mediaRepo.deleted[0] != orphanKey+".png" {
+		t.Errorf("want media of the orphan deleted, but got %v", mediaRepo.deleted)
+	}
+}
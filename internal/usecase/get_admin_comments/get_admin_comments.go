@@ -0,0 +1,69 @@
+package get_admin_comments
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+)
+
+type CommentRepository interface {
+	ListByStatus(
+		ctx context.Context, tx infrastracture.TX, status models.CommentStatus, limit int64, offset int64,
+	) ([]*models.Comment, error)
+}
+
+const DefaultLimit int64 = 20
+
+// get_admin_comments.Usecaseは管理者向けに状態ごとのコメントを取得するユースケースです。
+// 状態を指定しない場合は承認待ちのコメントを取得します。
+type Usecase struct {
+	DB                infrastracture.DB
+	CommentRepository CommentRepository
+}
+
+func NewUsecase(db infrastracture.DB, commentRepository CommentRepository) *Usecase {
+	return &Usecase{
+		DB:                db,
+		CommentRepository: commentRepository,
+	}
+}
+
+type Input struct {
+	Status *models.CommentStatus
+	Limit  *int64
+	Page   *int64
+}
+
+func (u *Usecase) Run(ctx context.Context, input *Input) ([]*models.Comment, error) {
+	status := models.CommentStatusPending
+	if input.Status != nil {
+		status = *input.Status
+	}
+	limit := DefaultLimit
+	if input.Limit != nil {
+		limit = *input.Limit
+	}
+	var offset int64
+	if input.Page != nil && *input.Page > 1 {
+		offset = (*input.Page - 1) * limit
+	}
+
+	transactor := infrastracture.NewTransactionProvider(u.DB)
+	result, err := transactor.DoInTx(ctx, func(tx infrastracture.TX) (interface{}, error) {
+		comments, err := u.CommentRepository.ListByStatus(ctx, tx, status, limit, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list comments: %w", err)
+		}
+		return comments, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comments: %w", err)
+	}
+	comments, ok := result.([]*models.Comment)
+	if !ok {
+		return nil, fmt.Errorf("failed to type assertion")
+	}
+	return comments, nil
+}
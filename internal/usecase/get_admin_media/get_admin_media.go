@@ -0,0 +1,91 @@
+package get_admin_media
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+)
+
+type MediaRepository interface {
+	List(
+		ctx context.Context, tx infrastracture.TX, filter *models.MediaFilter, limit int64, offset int64,
+	) ([]*models.Media, error)
+	Count(ctx context.Context, tx infrastracture.TX, filter *models.MediaFilter) (int64, error)
+	ListBlogIds(
+		ctx context.Context, tx infrastracture.TX, mediaIds []models.MediaId,
+	) (map[models.MediaId][]models.BlogId, error)
+}
+
+const DefaultLimit int64 = 20
+
+// get_admin_media.Usecaseは管理者向けにメディアを参照しているブログとともに取得するユースケースです。
+type Usecase struct {
+	DB              infrastracture.DB
+	MediaRepository MediaRepository
+}
+
+func NewUsecase(db infrastracture.DB, mediaRepository MediaRepository) *Usecase {
+	return &Usecase{
+		DB:              db,
+		MediaRepository: mediaRepository,
+	}
+}
+
+type Input struct {
+	Filter models.MediaFilter
+	Limit  *int64
+	Page   *int64
+}
+
+type Output struct {
+	Media      []*models.Media `json:"media"`
+	TotalCount int64           `json:"totalCount"`
+}
+
+func (u *Usecase) Run(ctx context.Context, input *Input) (*Output, error) {
+	limit := DefaultLimit
+	if input.Limit != nil {
+		limit = *input.Limit
+	}
+	var offset int64
+	if input.Page != nil && *input.Page > 1 {
+		offset = (*input.Page - 1) * limit
+	}
+
+	transactor := infrastracture.NewTransactionProvider(u.DB)
+	result, err := transactor.DoInTx(ctx, func(tx infrastracture.TX) (interface{}, error) {
+		media, err := u.MediaRepository.List(ctx, tx, &input.Filter, limit, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list media: %w", err)
+		}
+		totalCount, err := u.MediaRepository.Count(ctx, tx, &input.Filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count media: %w", err)
+		}
+		mediaIds := make([]models.MediaId, 0, len(media))
+		for _, m := range media {
+			mediaIds = append(mediaIds, m.Id)
+		}
+		blogIds, err := u.MediaRepository.ListBlogIds(ctx, tx, mediaIds)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list blog ids: %w", err)
+		}
+		for _, m := range media {
+			m.BlogIds = blogIds[m.Id]
+			if m.BlogIds == nil {
+				m.BlogIds = []models.BlogId{}
+			}
+		}
+		return &Output{Media: media, TotalCount: totalCount}, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get media: %w", err)
+	}
+	output, ok := result.(*Output)
+	if !ok {
+		return nil, fmt.Errorf("failed to type assertion")
+	}
+	return output, nil
+}
@@ -0,0 +1,33 @@
+package get_api_keys
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+)
+
+type APIKeyRepository interface {
+	ListByUserId(ctx context.Context, tx infrastracture.TX, userId models.UserId) ([]*models.APIKey, error)
+}
+
+type Usecase struct {
+	DB               infrastracture.DB
+	APIKeyRepository APIKeyRepository
+}
+
+func NewUsecase(db infrastracture.DB, apiKeyRepository APIKeyRepository) *Usecase {
+	return &Usecase{
+		DB:               db,
+		APIKeyRepository: apiKeyRepository,
+	}
+}
+
+func (u *Usecase) Run(ctx context.Context, userId models.UserId) ([]*models.APIKey, error) {
+	keys, err := u.APIKeyRepository.ListByUserId(ctx, u.DB, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+	return keys, nil
+}
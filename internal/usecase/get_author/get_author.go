@@ -0,0 +1,114 @@
+package get_author
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/infrastracture/repository"
+	"github.com/shoet/blog/internal/options"
+)
+
+type UserRepository interface {
+	Get(ctx context.Context, tx infrastracture.TX, id models.UserId) (*models.User, error)
+	ListAuthors(ctx context.Context, tx infrastracture.TX, ids []models.UserId) ([]*models.Author, error)
+}
+
+type BlogRepository interface {
+	List(ctx context.Context, tx infrastracture.TX, option *options.ListBlogOptions) ([]*models.Blog, error)
+	ExistsPublicByAuthorId(ctx context.Context, tx infrastracture.TX, authorId models.UserId) (bool, error)
+}
+
+type ContentsService interface {
+	SetThumbnailVariants(ctx context.Context, tx infrastracture.TX, blogs models.Blogs) error
+}
+
+var ErrAuthorNotFound = errors.New("author is not found")
+
+type Output struct {
+	Author *models.Author `json:"author"`
+	Blogs  []*models.Blog `json:"blogs"`
+}
+
+// get_author.Usecaseは著者のプロフィールと公開中のブログを新しい順に取得するユースケースです。
+// 続きのブログは/blogsのauthorIdで取得します。
+// 無効化されたユーザーや公開中のブログが無いユーザーは著者として扱いません。
+type Usecase struct {
+	DB              infrastracture.DB
+	UserRepository  UserRepository
+	BlogRepository  BlogRepository
+	ContentsService ContentsService
+}
+
+func NewUsecase(
+	db infrastracture.DB,
+	userRepository UserRepository,
+	blogRepository BlogRepository,
+	contentsService ContentsService,
+) *Usecase {
+	return &Usecase{
+		DB:              db,
+		UserRepository:  userRepository,
+		BlogRepository:  blogRepository,
+		ContentsService: contentsService,
+	}
+}
+
+func (u *Usecase) Run(ctx context.Context, id models.UserId, limit *int64) (*Output, error) {
+	isPublic := true
+	option, err := options.NewListBlogOptions(&isPublic, nil, limit, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create list option: %w", err)
+	}
+	option.AuthorId = &id
+
+	transactor := infrastracture.NewTransactionProvider(u.DB)
+	result, err := transactor.DoInTx(ctx, func(tx infrastracture.TX) (interface{}, error) {
+		user, err := u.UserRepository.Get(ctx, tx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrUserNotFound) {
+				return nil, ErrAuthorNotFound
+			}
+			return nil, fmt.Errorf("failed to get user: %w", err)
+		}
+		if user.IsDisabled() {
+			return nil, ErrAuthorNotFound
+		}
+		exists, err := u.BlogRepository.ExistsPublicByAuthorId(ctx, tx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check public blogs: %w", err)
+		}
+		if !exists {
+			return nil, ErrAuthorNotFound
+		}
+		authors, err := u.UserRepository.ListAuthors(ctx, tx, []models.UserId{id})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list authors: %w", err)
+		}
+		if len(authors) == 0 {
+			return nil, ErrAuthorNotFound
+		}
+		blogs, err := u.BlogRepository.List(ctx, tx, option)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list blogs: %w", err)
+		}
+		if blogs == nil {
+			blogs = []*models.Blog{}
+		}
+		if err := u.ContentsService.SetThumbnailVariants(ctx, tx, blogs); err != nil {
+			return nil, fmt.Errorf("failed to set thumbnail variants: %w", err)
+		}
+		return &Output{Author: authors[0], Blogs: blogs}, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get author: %w", err)
+	}
+
+	output, ok := result.(*Output)
+	if !ok {
+		return nil, fmt.Errorf("failed to type assertion")
+	}
+	return output, nil
+}
@@ -0,0 +1,128 @@
+package get_blog_by_slug
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shoet/blog/internal/clocker"
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+)
+
+type BlogRepository interface {
+	Get(ctx context.Context, tx infrastracture.TX, id models.BlogId) (*models.Blog, error)
+	GetBySlug(ctx context.Context, tx infrastracture.TX, slug string) (*models.Blog, error)
+	GetBlogIdBySlugHistory(ctx context.Context, tx infrastracture.TX, slug string) (models.BlogId, error)
+}
+
+type SeriesRepository interface {
+	GetByBlogId(ctx context.Context, tx infrastracture.TX, blogId models.BlogId) (*models.Series, error)
+}
+
+type UserRepository interface {
+	ListAuthors(ctx context.Context, tx infrastracture.TX, ids []models.UserId) ([]*models.Author, error)
+}
+
+type ContentsService interface {
+	SetThumbnailVariants(ctx context.Context, tx infrastracture.TX, blogs models.Blogs) error
+}
+
+// get_blog_by_slug.Usecaseはスラッグからブログを取得するユースケースです。
+// 変更前のスラッグが指定された場合は、現在のスラッグをRedirectSlugに設定します。
+type Usecase struct {
+	DB               infrastracture.DB
+	BlogRepository   BlogRepository
+	SeriesRepository SeriesRepository
+	UserRepository   UserRepository
+	ContentsService  ContentsService
+	Clocker          clocker.Clocker
+}
+
+func NewUsecase(
+	db infrastracture.DB,
+	blogRepository BlogRepository,
+	seriesRepository SeriesRepository,
+	userRepository UserRepository,
+	contentsService ContentsService,
+	clocker clocker.Clocker,
+) *Usecase {
+	return &Usecase{
+		DB:               db,
+		BlogRepository:   blogRepository,
+		SeriesRepository: seriesRepository,
+		UserRepository:   userRepository,
+		ContentsService:  contentsService,
+		Clocker:          clocker,
+	}
+}
+
+type Output struct {
+	Blog         *models.Blog
+	RedirectSlug *string
+}
+
+func (u *Usecase) Run(ctx context.Context, slug string) (*Output, error) {
+	transactor := infrastracture.NewTransactionProvider(u.DB)
+
+	result, err := transactor.DoInTx(ctx, func(tx infrastracture.TX) (interface{}, error) {
+		blog, err := u.BlogRepository.GetBySlug(ctx, tx, slug)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get blog by slug: %w", err)
+		}
+		if blog != nil {
+			if err := u.setDetail(ctx, tx, blog); err != nil {
+				return nil, err
+			}
+			return &Output{Blog: blog}, nil
+		}
+
+		// 変更前のスラッグから検索する
+		blogId, err := u.BlogRepository.GetBlogIdBySlugHistory(ctx, tx, slug)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get blog id by slug history: %w", err)
+		}
+		if blogId == 0 {
+			return &Output{}, nil
+		}
+		blog, err = u.BlogRepository.Get(ctx, tx, blogId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get blog: %w", err)
+		}
+		if blog == nil {
+			return &Output{}, nil
+		}
+		if err := u.setDetail(ctx, tx, blog); err != nil {
+			return nil, err
+		}
+		return &Output{Blog: blog, RedirectSlug: &blog.Slug}, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blog: %w", err)
+	}
+
+	output, ok := result.(*Output)
+	if !ok {
+		return nil, fmt.Errorf("failed to cast *Output")
+	}
+	return output, nil
+}
+
+// setDetail は、ブログが所属するシリーズ内での位置と前後のブログ、著者のプロフィールを設定する
+func (u *Usecase) setDetail(ctx context.Context, tx infrastracture.TX, blog *models.Blog) error {
+	series, err := u.SeriesRepository.GetByBlogId(ctx, tx, blog.Id)
+	if err != nil {
+		return fmt.Errorf("failed to get series: %w", err)
+	}
+	if series != nil {
+		blog.Series = series.Navigation(blog.Id, u.Clocker.Now())
+	}
+	authors, err := u.UserRepository.ListAuthors(ctx, tx, []models.UserId{blog.AuthorId})
+	if err != nil {
+		return fmt.Errorf("failed to list authors: %w", err)
+	}
+	models.Blogs{blog}.SetAuthors(authors)
+	if err := u.ContentsService.SetThumbnailVariants(ctx, tx, models.Blogs{blog}); err != nil {
+		return fmt.Errorf("failed to set thumbnail variants: %w", err)
+	}
+	return nil
+}
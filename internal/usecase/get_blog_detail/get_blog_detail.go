@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/shoet/blog/internal/clocker"
 	"github.com/shoet/blog/internal/infrastracture"
 	"github.com/shoet/blog/internal/infrastracture/models"
 )
@@ -12,15 +13,42 @@ type BlogRepository interface {
 	Get(ctx context.Context, tx infrastracture.TX, id models.BlogId) (*models.Blog, error)
 }
 
+type SeriesRepository interface {
+	GetByBlogId(ctx context.Context, tx infrastracture.TX, blogId models.BlogId) (*models.Series, error)
+}
+
+type UserRepository interface {
+	ListAuthors(ctx context.Context, tx infrastracture.TX, ids []models.UserId) ([]*models.Author, error)
+}
+
+type ContentsService interface {
+	SetThumbnailVariants(ctx context.Context, tx infrastracture.TX, blogs models.Blogs) error
+}
+
 type Usecase struct {
-	DB             infrastracture.DB
-	BlogRepository BlogRepository
+	DB               infrastracture.DB
+	BlogRepository   BlogRepository
+	SeriesRepository SeriesRepository
+	UserRepository   UserRepository
+	ContentsService  ContentsService
+	Clocker          clocker.Clocker
 }
 
-func NewUsecase(db infrastracture.DB, blogRepository BlogRepository) *Usecase {
+func NewUsecase(
+	db infrastracture.DB,
+	blogRepository BlogRepository,
+	seriesRepository SeriesRepository,
+	userRepository UserRepository,
+	contentsService ContentsService,
+	clocker clocker.Clocker,
+) *Usecase {
 	return &Usecase{
-		DB:             db,
-		BlogRepository: blogRepository,
+		DB:               db,
+		BlogRepository:   blogRepository,
+		SeriesRepository: seriesRepository,
+		UserRepository:   userRepository,
+		ContentsService:  contentsService,
+		Clocker:          clocker,
 	}
 }
 
@@ -32,6 +60,24 @@ func (u *Usecase) Run(ctx context.Context, blogId models.BlogId) (*models.Blog,
 		if err != nil {
 			return nil, fmt.Errorf("failed to get blog: %v", err)
 		}
+		if blog == nil {
+			return blog, nil
+		}
+		series, err := u.SeriesRepository.GetByBlogId(ctx, tx, blogId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get series: %v", err)
+		}
+		if series != nil {
+			blog.Series = series.Navigation(blogId, u.Clocker.Now())
+		}
+		authors, err := u.UserRepository.ListAuthors(ctx, tx, []models.UserId{blog.AuthorId})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list authors: %v", err)
+		}
+		models.Blogs{blog}.SetAuthors(authors)
+		if err := u.ContentsService.SetThumbnailVariants(ctx, tx, models.Blogs{blog}); err != nil {
+			return nil, fmt.Errorf("failed to set thumbnail variants: %v", err)
+		}
 		return blog, nil
 	})
 	if err != nil {
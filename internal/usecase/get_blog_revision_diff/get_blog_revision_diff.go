@@ -0,0 +1,95 @@
+package get_blog_revision_diff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/textdiff"
+)
+
+type BlogRepository interface {
+	GetRevision(ctx context.Context, tx infrastracture.TX, blogId models.BlogId, revision int64) (*models.BlogRevision, error)
+}
+
+var ErrRevisionNotFound = errors.New("blog revision is not found")
+
+// get_blog_revision_diff.Usecaseはブログの2つの履歴の差分を取得するユースケースです。
+type Usecase struct {
+	DB             infrastracture.DB
+	BlogRepository BlogRepository
+}
+
+func NewUsecase(db infrastracture.DB, blogRepository BlogRepository) *Usecase {
+	return &Usecase{
+		DB:             db,
+		BlogRepository: blogRepository,
+	}
+}
+
+type FieldDiff struct {
+	Changed bool             `json:"changed"`
+	Lines   []*textdiff.Line `json:"lines"`
+}
+
+type Output struct {
+	From                   *models.BlogRevision `json:"from"`
+	To                     *models.BlogRevision `json:"to"`
+	Title                  *FieldDiff           `json:"title"`
+	Description            *FieldDiff           `json:"description"`
+	ThumbnailImageFileName *FieldDiff           `json:"thumbnailImageFileName"`
+	Content                *FieldDiff           `json:"content"`
+}
+
+func (u *Usecase) Run(
+	ctx context.Context, blogId models.BlogId, from int64, to int64,
+) (*Output, error) {
+	transactor := infrastracture.NewTransactionProvider(u.DB)
+
+	result, err := transactor.DoInTx(ctx, func(tx infrastracture.TX) (interface{}, error) {
+		fromRevision, err := u.BlogRepository.GetRevision(ctx, tx, blogId, from)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get from revision: %w", err)
+		}
+		if fromRevision == nil {
+			return nil, ErrRevisionNotFound
+		}
+		toRevision, err := u.BlogRepository.GetRevision(ctx, tx, blogId, to)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get to revision: %w", err)
+		}
+		if toRevision == nil {
+			return nil, ErrRevisionNotFound
+		}
+		return &Output{
+			From:                   fromRevision,
+			To:                     toRevision,
+			Title:                  newFieldDiff(fromRevision.Title, toRevision.Title),
+			Description:            newFieldDiff(fromRevision.Description, toRevision.Description),
+			ThumbnailImageFileName: newFieldDiff(fromRevision.ThumbnailImageFileName, toRevision.ThumbnailImageFileName),
+			Content:                newFieldDiff(fromRevision.Content, toRevision.Content),
+		}, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blog revision diff: %w", err)
+	}
+
+	output, ok := result.(*Output)
+	if !ok {
+		return nil, fmt.Errorf("failed to cast *Output")
+	}
+	// 差分として返却するため、本文は重複して返さない
+	output.From.Content = ""
+	output.To.Content = ""
+	return output, nil
+}
+
+func newFieldDiff(oldText string, newText string) *FieldDiff {
+	lines := textdiff.Lines(oldText, newText)
+	return &FieldDiff{
+		Changed: textdiff.HasChanges(lines),
+		Lines:   lines,
+	}
+}
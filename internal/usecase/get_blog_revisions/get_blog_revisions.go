@@ -0,0 +1,58 @@
+package get_blog_revisions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+)
+
+type BlogRepository interface {
+	Get(ctx context.Context, tx infrastracture.TX, id models.BlogId) (*models.Blog, error)
+	ListRevisions(ctx context.Context, tx infrastracture.TX, blogId models.BlogId) ([]*models.BlogRevision, error)
+}
+
+var ErrBlogNotFound = errors.New("blog is not found")
+
+// get_blog_revisions.Usecaseはブログの履歴一覧を取得するユースケースです。
+type Usecase struct {
+	DB             infrastracture.DB
+	BlogRepository BlogRepository
+}
+
+func NewUsecase(db infrastracture.DB, blogRepository BlogRepository) *Usecase {
+	return &Usecase{
+		DB:             db,
+		BlogRepository: blogRepository,
+	}
+}
+
+func (u *Usecase) Run(ctx context.Context, blogId models.BlogId) ([]*models.BlogRevision, error) {
+	transactor := infrastracture.NewTransactionProvider(u.DB)
+
+	result, err := transactor.DoInTx(ctx, func(tx infrastracture.TX) (interface{}, error) {
+		blog, err := u.BlogRepository.Get(ctx, tx, blogId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get blog: %w", err)
+		}
+		if blog == nil {
+			return nil, ErrBlogNotFound
+		}
+		revisions, err := u.BlogRepository.ListRevisions(ctx, tx, blogId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list blog revisions: %w", err)
+		}
+		return revisions, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blog revisions: %w", err)
+	}
+
+	revisions, ok := result.([]*models.BlogRevision)
+	if !ok {
+		return nil, fmt.Errorf("failed to cast []*models.BlogRevision")
+	}
+	return revisions, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/shoet/blog/internal/highlight"
 	"github.com/shoet/blog/internal/infrastracture"
 	"github.com/shoet/blog/internal/infrastracture/models"
 	"github.com/shoet/blog/internal/options"
@@ -21,22 +22,40 @@ type BlogRepository interface {
 	ListByKeyword(
 		ctx context.Context, tx infrastracture.TX, keyword string, option *options.ListBlogOptions,
 	) (models.Blogs, error)
+
+	SelectBlogsContents(
+		ctx context.Context, tx infrastracture.TX, ids []models.BlogId,
+	) (models.Blogs, error)
+}
+
+type UserRepository interface {
+	ListAuthors(ctx context.Context, tx infrastracture.TX, ids []models.UserId) ([]*models.Author, error)
+}
+
+type ContentsService interface {
+	SetThumbnailVariants(ctx context.Context, tx infrastracture.TX, blogs models.Blogs) error
 }
 
 // get_blogs.Usecaseはブログ一覧を取得するユースケースです。
 // ページングはカーソル方式で実装しています。
 type Usecase struct {
-	DB             infrastracture.DB
-	BlogRepository BlogRepository
+	DB              infrastracture.DB
+	BlogRepository  BlogRepository
+	UserRepository  UserRepository
+	ContentsService ContentsService
 }
 
 func NewUsecase(
 	DB infrastracture.DB,
 	blogRepository BlogRepository,
+	userRepository UserRepository,
+	contentsService ContentsService,
 ) *Usecase {
 	return &Usecase{
-		DB:             DB,
-		BlogRepository: blogRepository,
+		DB:              DB,
+		BlogRepository:  blogRepository,
+		UserRepository:  userRepository,
+		ContentsService: contentsService,
 	}
 }
 
@@ -47,6 +66,8 @@ type GetBlogsInput struct {
 	CursorId      *models.BlogId
 	PageDirection *string
 	Limit         *int64
+	// AuthorIdが指定された場合は、タグ・キーワードの検索でも著者のブログのみに絞り込む
+	AuthorId *models.UserId
 }
 
 func (u *Usecase) Run(
@@ -59,6 +80,7 @@ func (u *Usecase) Run(
 	if err != nil {
 		return nil, false, false, fmt.Errorf("failed to create list option: %v", err)
 	}
+	option.AuthorId = input.AuthorId
 	// 次のページが存在するか判定するためにLimit+1で取得する
 	option.Limit++
 
@@ -78,6 +100,9 @@ func (u *Usecase) Run(
 			if err != nil {
 				return nil, fmt.Errorf("failed to list blogs by keyword: %v", err)
 			}
+			if err := u.setSnippets(ctx, tx, *input.KeyWord, b); err != nil {
+				return nil, fmt.Errorf("failed to set snippets: %v", err)
+			}
 			blogs = b
 		} else {
 			// 通常の検索
@@ -88,6 +113,15 @@ func (u *Usecase) Run(
 			blogs = b
 		}
 
+		authors, err := u.UserRepository.ListAuthors(ctx, tx, blogs.AuthorIds())
+		if err != nil {
+			return nil, fmt.Errorf("failed to list authors: %v", err)
+		}
+		blogs.SetAuthors(authors)
+		if err := u.ContentsService.SetThumbnailVariants(ctx, tx, blogs); err != nil {
+			return nil, fmt.Errorf("failed to set thumbnail variants: %v", err)
+		}
+
 		return blogs.ToSlice(), nil
 	})
 
@@ -117,3 +151,29 @@ func (u *Usecase) Run(
 		option.PageDirection == "next" && isEOF,
 		nil
 }
+
+// setSnippets は、検索結果のブログにキーワードの一致箇所をハイライトした抜粋を設定する
+func (u *Usecase) setSnippets(
+	ctx context.Context, tx infrastracture.TX, keyword string, blogs models.Blogs,
+) error {
+	ids := make([]models.BlogId, 0, len(blogs))
+	for _, b := range blogs {
+		ids = append(ids, b.Id)
+	}
+	contents, err := u.BlogRepository.SelectBlogsContents(ctx, tx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to select blogs contents: %v", err)
+	}
+	contentsMap := make(map[models.BlogId]*models.Blog, len(contents))
+	for _, c := range contents {
+		contentsMap[c.Id] = c
+	}
+	for _, b := range blogs {
+		c, ok := contentsMap[b.Id]
+		if !ok {
+			continue
+		}
+		b.Snippet = highlight.Snippet(keyword, highlight.DefaultLength, c.Content, c.Description)
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/shoet/blog/internal/highlight"
 	"github.com/shoet/blog/internal/infrastracture"
 	"github.com/shoet/blog/internal/infrastracture/models"
 	"github.com/shoet/blog/internal/options"
@@ -33,6 +34,18 @@ type BlogRepositoryOffset interface {
 	CountBlogsByKeyword(
 		ctx context.Context, tx infrastracture.TX, keyword string, option *options.ListBlogOptions,
 	) (int64, error)
+
+	SelectBlogsContents(
+		ctx context.Context, tx infrastracture.TX, ids []models.BlogId,
+	) (models.Blogs, error)
+}
+
+type UserRepository interface {
+	ListAuthors(ctx context.Context, tx infrastracture.TX, ids []models.UserId) ([]*models.Author, error)
+}
+
+type ContentsService interface {
+	SetThumbnailVariants(ctx context.Context, tx infrastracture.TX, blogs models.Blogs) error
 }
 
 // get_blogs_offset_paging.Usecaseはブログ一覧を取得するユースケースです。
@@ -40,15 +53,21 @@ type BlogRepositoryOffset interface {
 type Usecase struct {
 	DB                   infrastracture.DB
 	BlogRepositoryOffset BlogRepositoryOffset
+	UserRepository       UserRepository
+	ContentsService      ContentsService
 }
 
 func NewUsecase(
 	DB infrastracture.DB,
 	blogRepositoryOffset BlogRepositoryOffset,
+	userRepository UserRepository,
+	contentsService ContentsService,
 ) *Usecase {
 	return &Usecase{
 		DB:                   DB,
 		BlogRepositoryOffset: blogRepositoryOffset,
+		UserRepository:       userRepository,
+		ContentsService:      contentsService,
 	}
 }
 
@@ -94,6 +113,9 @@ func (u *Usecase) Run(ctx context.Context, input *Input) ([]*models.Blog, int64,
 			if err != nil {
 				return nil, fmt.Errorf("failed to list blogs by keyword: %v", err)
 			}
+			if err := u.setSnippets(ctx, tx, *input.KeyWord, b); err != nil {
+				return nil, fmt.Errorf("failed to set snippets: %v", err)
+			}
 			count, err := u.BlogRepositoryOffset.CountBlogsByKeyword(ctx, tx, *input.KeyWord, option)
 			if err != nil {
 				return nil, fmt.Errorf("failed to count blogs by keyword: %v", err)
@@ -113,6 +135,16 @@ func (u *Usecase) Run(ctx context.Context, input *Input) ([]*models.Blog, int64,
 			blogs = b
 			blogsCount = count
 		}
+
+		authors, err := u.UserRepository.ListAuthors(ctx, tx, blogs.AuthorIds())
+		if err != nil {
+			return nil, fmt.Errorf("failed to list authors: %v", err)
+		}
+		blogs.SetAuthors(authors)
+		if err := u.ContentsService.SetThumbnailVariants(ctx, tx, blogs); err != nil {
+			return nil, fmt.Errorf("failed to set thumbnail variants: %v", err)
+		}
+
 		txResult := TransactionResult{
 			blogs:      blogs.ToSlice(),
 			blogsCount: blogsCount,
@@ -132,3 +164,29 @@ func (u *Usecase) Run(ctx context.Context, input *Input) ([]*models.Blog, int64,
 
 	return txResult.blogs, txResult.blogsCount, nil
 }
+
+// setSnippets は、検索結果のブログにキーワードの一致箇所をハイライトした抜粋を設定する
+func (u *Usecase) setSnippets(
+	ctx context.Context, tx infrastracture.TX, keyword string, blogs models.Blogs,
+) error {
+	ids := make([]models.BlogId, 0, len(blogs))
+	for _, b := range blogs {
+		ids = append(ids, b.Id)
+	}
+	contents, err := u.BlogRepositoryOffset.SelectBlogsContents(ctx, tx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to select blogs contents: %v", err)
+	}
+	contentsMap := make(map[models.BlogId]*models.Blog, len(contents))
+	for _, c := range contents {
+		contentsMap[c.Id] = c
+	}
+	for _, b := range blogs {
+		c, ok := contentsMap[b.Id]
+		if !ok {
+			continue
+		}
+		b.Snippet = highlight.Snippet(keyword, highlight.DefaultLength, c.Content, c.Description)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package get_comments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shoet/blog/internal/clocker"
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+)
+
+type BlogRepository interface {
+	Get(ctx context.Context, tx infrastracture.TX, id models.BlogId) (*models.Blog, error)
+}
+
+type CommentRepository interface {
+	ListByBlogId(
+		ctx context.Context, tx infrastracture.TX, blogId models.BlogId, status models.CommentStatus,
+	) ([]*models.Comment, error)
+}
+
+var ErrBlogNotFound = errors.New("blog is not found")
+
+// get_comments.Usecaseは公開中のブログの承認済みのコメントを取得するユースケースです。
+// 返信は返信先のコメントのRepliesにまとめて返却します。
+type Usecase struct {
+	DB                infrastracture.DB
+	BlogRepository    BlogRepository
+	CommentRepository CommentRepository
+	Clocker           clocker.Clocker
+}
+
+func NewUsecase(
+	db infrastracture.DB,
+	blogRepository BlogRepository,
+	commentRepository CommentRepository,
+	clocker clocker.Clocker,
+) *Usecase {
+	return &Usecase{
+		DB:                db,
+		BlogRepository:    blogRepository,
+		CommentRepository: commentRepository,
+		Clocker:           clocker,
+	}
+}
+
+func (u *Usecase) Run(ctx context.Context, blogId models.BlogId) ([]*models.Comment, error) {
+	transactor := infrastracture.NewTransactionProvider(u.DB)
+	result, err := transactor.DoInTx(ctx, func(tx infrastracture.TX) (interface{}, error) {
+		blog, err := u.BlogRepository.Get(ctx, tx, blogId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get blog: %w", err)
+		}
+		if blog == nil || !blog.IsPublished(u.Clocker.Now()) {
+			return nil, ErrBlogNotFound
+		}
+		comments, err := u.CommentRepository.ListByBlogId(ctx, tx, blogId, models.CommentStatusApproved)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list comments: %w", err)
+		}
+		return buildThreads(comments), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comments: %w", err)
+	}
+	comments, ok := result.([]*models.Comment)
+	if !ok {
+		return nil, fmt.Errorf("failed to type assertion")
+	}
+	return comments, nil
+}
+
+// buildThreads は、投稿順のコメントを返信先ごとにまとめる
+// 返信先が一覧に含まれない返信は除外する
+func buildThreads(comments []*models.Comment) []*models.Comment {
+	threads := []*models.Comment{}
+	parents := map[models.CommentId]*models.Comment{}
+	for _, c := range comments {
+		if !c.IsReply() {
+			threads = append(threads, c)
+			parents[c.Id] = c
+		}
+	}
+	for _, c := range comments {
+		if !c.IsReply() {
+			continue
+		}
+		if parent, ok := parents[*c.ParentId]; ok {
+			parent.Replies = append(parent.Replies, c)
+		}
+	}
+	return threads
+}
@@ -0,0 +1,86 @@
+package get_related_blogs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shoet/blog/internal/clocker"
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+)
+
+type BlogRepository interface {
+	Get(ctx context.Context, tx infrastracture.TX, id models.BlogId) (*models.Blog, error)
+	ListRelated(ctx context.Context, tx infrastracture.TX, blogId models.BlogId, limit int64) (models.Blogs, error)
+}
+
+type UserRepository interface {
+	ListAuthors(ctx context.Context, tx infrastracture.TX, ids []models.UserId) ([]*models.Author, error)
+}
+
+type ContentsService interface {
+	SetThumbnailVariants(ctx context.Context, tx infrastracture.TX, blogs models.Blogs) error
+}
+
+var ErrBlogNotFound = errors.New("blog is not found")
+
+// get_related_blogs.Usecaseはタグを共有する関連ブログを取得するユースケースです。
+// 下書きや公開日時前のブログは対象外です。
+type Usecase struct {
+	DB              infrastracture.DB
+	BlogRepository  BlogRepository
+	UserRepository  UserRepository
+	ContentsService ContentsService
+	Clocker         clocker.Clocker
+}
+
+func NewUsecase(
+	db infrastracture.DB,
+	blogRepository BlogRepository,
+	userRepository UserRepository,
+	contentsService ContentsService,
+	clocker clocker.Clocker,
+) *Usecase {
+	return &Usecase{
+		DB:              db,
+		BlogRepository:  blogRepository,
+		UserRepository:  userRepository,
+		ContentsService: contentsService,
+		Clocker:         clocker,
+	}
+}
+
+func (u *Usecase) Run(ctx context.Context, blogId models.BlogId, limit int64) (models.Blogs, error) {
+	transactor := infrastracture.NewTransactionProvider(u.DB)
+	result, err := transactor.DoInTx(ctx, func(tx infrastracture.TX) (interface{}, error) {
+		blog, err := u.BlogRepository.Get(ctx, tx, blogId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get blog: %w", err)
+		}
+		if blog == nil || !blog.IsPublished(u.Clocker.Now()) {
+			return nil, ErrBlogNotFound
+		}
+		blogs, err := u.BlogRepository.ListRelated(ctx, tx, blogId, limit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list related blogs: %w", err)
+		}
+		authors, err := u.UserRepository.ListAuthors(ctx, tx, blogs.AuthorIds())
+		if err != nil {
+			return nil, fmt.Errorf("failed to list authors: %w", err)
+		}
+		blogs.SetAuthors(authors)
+		if err := u.ContentsService.SetThumbnailVariants(ctx, tx, blogs); err != nil {
+			return nil, fmt.Errorf("failed to set thumbnail variants: %w", err)
+		}
+		return blogs, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get related blogs: %w", err)
+	}
+	blogs, ok := result.(models.Blogs)
+	if !ok {
+		return nil, fmt.Errorf("failed to type assertion")
+	}
+	return blogs, nil
+}
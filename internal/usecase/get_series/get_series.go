@@ -0,0 +1,50 @@
+package get_series
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+)
+
+type SeriesRepository interface {
+	Get(ctx context.Context, tx infrastracture.TX, id models.SeriesId) (*models.Series, error)
+}
+
+var ErrSeriesNotFound = errors.New("series is not found")
+
+type Usecase struct {
+	DB               infrastracture.DB
+	SeriesRepository SeriesRepository
+}
+
+func NewUsecase(db infrastracture.DB, seriesRepository SeriesRepository) *Usecase {
+	return &Usecase{
+		DB:               db,
+		SeriesRepository: seriesRepository,
+	}
+}
+
+func (u *Usecase) Run(ctx context.Context, id models.SeriesId) (*models.Series, error) {
+	transactor := infrastracture.NewTransactionProvider(u.DB)
+	result, err := transactor.DoInTx(ctx, func(tx infrastracture.TX) (interface{}, error) {
+		series, err := u.SeriesRepository.Get(ctx, tx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get series: %w", err)
+		}
+		if series == nil {
+			return nil, ErrSeriesNotFound
+		}
+		return series, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get series: %w", err)
+	}
+	series, ok := result.(*models.Series)
+	if !ok {
+		return nil, fmt.Errorf("failed to type assertion")
+	}
+	return series, nil
+}
@@ -0,0 +1,44 @@
+package get_series_list
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+)
+
+type SeriesRepository interface {
+	List(ctx context.Context, tx infrastracture.TX) ([]*models.Series, error)
+}
+
+type Usecase struct {
+	DB               infrastracture.DB
+	SeriesRepository SeriesRepository
+}
+
+func NewUsecase(db infrastracture.DB, seriesRepository SeriesRepository) *Usecase {
+	return &Usecase{
+		DB:               db,
+		SeriesRepository: seriesRepository,
+	}
+}
+
+func (u *Usecase) Run(ctx context.Context) ([]*models.Series, error) {
+	transactor := infrastracture.NewTransactionProvider(u.DB)
+	result, err := transactor.DoInTx(ctx, func(tx infrastracture.TX) (interface{}, error) {
+		series, err := u.SeriesRepository.List(ctx, tx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list series: %w", err)
+		}
+		return series, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get series list: %w", err)
+	}
+	series, ok := result.([]*models.Series)
+	if !ok {
+		return nil, fmt.Errorf("failed to type assertion")
+	}
+	return series, nil
+}
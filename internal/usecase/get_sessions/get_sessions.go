@@ -0,0 +1,25 @@
+package get_sessions
+
+import (
+	"context"
+
+	"github.com/shoet/blog/internal/infrastracture/models"
+)
+
+type AuthService interface {
+	ListSessions(ctx context.Context, userId models.UserId, token string) ([]*models.Session, error)
+}
+
+type Usecase struct {
+	authService AuthService
+}
+
+func NewUsecase(authService AuthService) *Usecase {
+	return &Usecase{
+		authService: authService,
+	}
+}
+
+func (u *Usecase) Run(ctx context.Context, userId models.UserId, token string) ([]*models.Session, error) {
+	return u.authService.ListSessions(ctx, userId, token)
+}
@@ -0,0 +1,55 @@
+package get_sitemap
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+)
+
+type BlogRepository interface {
+	ListPublishedModified(ctx context.Context, tx infrastracture.TX) (models.Blogs, error)
+	ListTagsModified(ctx context.Context, tx infrastracture.TX) ([]*models.TagModified, error)
+}
+
+// get_sitemap.Usecaseはサイトマップに掲載する公開中のブログとタグを取得するユースケースです。
+type Usecase struct {
+	DB             infrastracture.DB
+	BlogRepository BlogRepository
+}
+
+func NewUsecase(db infrastracture.DB, blogRepository BlogRepository) *Usecase {
+	return &Usecase{
+		DB:             db,
+		BlogRepository: blogRepository,
+	}
+}
+
+type Output struct {
+	Blogs models.Blogs
+	Tags  []*models.TagModified
+}
+
+func (u *Usecase) Run(ctx context.Context) (*Output, error) {
+	transactor := infrastracture.NewTransactionProvider(u.DB)
+	result, err := transactor.DoInTx(ctx, func(tx infrastracture.TX) (interface{}, error) {
+		blogs, err := u.BlogRepository.ListPublishedModified(ctx, tx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list blogs: %w", err)
+		}
+		tags, err := u.BlogRepository.ListTagsModified(ctx, tx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tags: %w", err)
+		}
+		return &Output{Blogs: blogs, Tags: tags}, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sitemap: %w", err)
+	}
+	output, ok := result.(*Output)
+	if !ok {
+		return nil, fmt.Errorf("failed to type assertion")
+	}
+	return output, nil
+}
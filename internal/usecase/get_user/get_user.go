@@ -0,0 +1,56 @@
+package get_user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/infrastracture/repository"
+)
+
+type UserRepository interface {
+	Get(ctx context.Context, tx infrastracture.TX, id models.UserId) (*models.User, error)
+	GetByEmail(ctx context.Context, tx infrastracture.TX, email string) (*models.User, error)
+}
+
+var ErrUserNotFound = errors.New("user is not found")
+
+// get_user.Usecaseは管理用にユーザーを取得するユースケースです。
+type Usecase struct {
+	DB             infrastracture.DB
+	UserRepository UserRepository
+}
+
+func NewUsecase(db infrastracture.DB, userRepository UserRepository) *Usecase {
+	return &Usecase{
+		DB:             db,
+		UserRepository: userRepository,
+	}
+}
+
+func (u *Usecase) Run(ctx context.Context, id models.UserId) (*models.User, error) {
+	user, err := u.UserRepository.Get(ctx, u.DB, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return user, nil
+}
+
+// RunByEmail は、メールアドレスでユーザーを取得する
+func (u *Usecase) RunByEmail(ctx context.Context, email string) (*models.User, error) {
+	user, err := u.UserRepository.GetByEmail(ctx, u.DB, email)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	// 管理用の取得ではパスワードのハッシュを返さない
+	user.Password = ""
+	return user, nil
+}
@@ -0,0 +1,34 @@
+package get_users
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+)
+
+type UserRepository interface {
+	List(ctx context.Context, tx infrastracture.TX) ([]*models.User, error)
+}
+
+// get_users.Usecaseは管理用にユーザーの一覧を取得するユースケースです。
+type Usecase struct {
+	DB             infrastracture.DB
+	UserRepository UserRepository
+}
+
+func NewUsecase(db infrastracture.DB, userRepository UserRepository) *Usecase {
+	return &Usecase{
+		DB:             db,
+		UserRepository: userRepository,
+	}
+}
+
+func (u *Usecase) Run(ctx context.Context) ([]*models.User, error) {
+	users, err := u.UserRepository.List(ctx, u.DB)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	return users, nil
+}
@@ -2,26 +2,59 @@ package login_user
 
 import (
 	"context"
+	"fmt"
+	"time"
+
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/session"
 )
 
 type AuthService interface {
-	Login(ctx context.Context, email string, password string) (string, error)
+	Login(ctx context.Context, email string, password string) (*models.AuthToken, error)
+}
+
+type LoginGuard interface {
+	Check(ctx context.Context, email string, ipAddress string) (time.Duration, error)
+	RecordFailure(ctx context.Context, email string, ipAddress string, userAgent string) (time.Duration, error)
+	Reset(ctx context.Context, email string, ipAddress string) error
 }
 
 type Usecase struct {
 	authService AuthService
+	loginGuard  LoginGuard
 }
 
-func NewUsecase(authService AuthService) *Usecase {
+func NewUsecase(authService AuthService, loginGuard LoginGuard) *Usecase {
 	return &Usecase{
 		authService: authService,
+		loginGuard:  loginGuard,
 	}
 }
 
-func (a *Usecase) Run(ctx context.Context, email string, password string) (string, error) {
+// Run は、メールアドレスとIPアドレスがロックされていなければログインする
+// ロック中の場合は*models.LoginLockedErrorを返す
+func (a *Usecase) Run(ctx context.Context, email string, password string) (*models.AuthToken, error) {
+	client := session.GetClientInfo(ctx)
+	retryAfter, err := a.loginGuard.Check(ctx, email, client.IpAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check login lock: %w", err)
+	}
+	if retryAfter > 0 {
+		return nil, &models.LoginLockedError{RetryAfter: retryAfter}
+	}
+
 	token, err := a.authService.Login(ctx, email, password)
 	if err != nil {
-		return "", err
+		if _, gerr := a.loginGuard.RecordFailure(ctx, email, client.IpAddress, client.UserAgent); gerr != nil {
+			return nil, fmt.Errorf("failed to record login failure: %w", gerr)
+		}
+		return nil, err
+	}
+	// 2段階認証が必要な場合は、認証コードの検証まで失敗回数を残す
+	if !token.TwoFactorRequired {
+		if err := a.loginGuard.Reset(ctx, email, client.IpAddress); err != nil {
+			return nil, fmt.Errorf("failed to reset login failures: %w", err)
+		}
 	}
 	return token, nil
 }
@@ -0,0 +1,62 @@
+package login_user_two_factor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/session"
+)
+
+type AuthService interface {
+	ChallengeEmail(ctx context.Context, challengeToken string) (string, error)
+	LoginTwoFactor(ctx context.Context, challengeToken string, code string) (*models.AuthToken, error)
+}
+
+type LoginGuard interface {
+	Check(ctx context.Context, email string, ipAddress string) (time.Duration, error)
+	RecordFailure(ctx context.Context, email string, ipAddress string, userAgent string) (time.Duration, error)
+	Reset(ctx context.Context, email string, ipAddress string) error
+}
+
+// login_user_two_factor.Usecaseはパスワード認証後のチャレンジトークンと認証コードをトークンの組と交換するユースケースです。
+// 失敗回数はパスワード認証と同様に、チャレンジトークンを発行したログインのメールアドレスとIPアドレスごとに数えます。
+type Usecase struct {
+	authService AuthService
+	loginGuard  LoginGuard
+}
+
+func NewUsecase(authService AuthService, loginGuard LoginGuard) *Usecase {
+	return &Usecase{
+		authService: authService,
+		loginGuard:  loginGuard,
+	}
+}
+
+func (u *Usecase) Run(ctx context.Context, challengeToken string, code string) (*models.AuthToken, error) {
+	client := session.GetClientInfo(ctx)
+	email, err := u.authService.ChallengeEmail(ctx, challengeToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get challenge email: %w", err)
+	}
+	retryAfter, err := u.loginGuard.Check(ctx, email, client.IpAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check login lock: %w", err)
+	}
+	if retryAfter > 0 {
+		return nil, &models.LoginLockedError{RetryAfter: retryAfter}
+	}
+
+	token, err := u.authService.LoginTwoFactor(ctx, challengeToken, code)
+	if err != nil {
+		if _, gerr := u.loginGuard.RecordFailure(ctx, email, client.IpAddress, client.UserAgent); gerr != nil {
+			return nil, fmt.Errorf("failed to record login failure: %w", gerr)
+		}
+		return nil, err
+	}
+	if err := u.loginGuard.Reset(ctx, email, client.IpAddress); err != nil {
+		return nil, fmt.Errorf("failed to reset login failures: %w", err)
+	}
+	return token, nil
+}
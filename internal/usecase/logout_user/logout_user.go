@@ -0,0 +1,23 @@
+package logout_user
+
+import (
+	"context"
+)
+
+type AuthService interface {
+	Logout(ctx context.Context, token string) error
+}
+
+type Usecase struct {
+	authService AuthService
+}
+
+func NewUsecase(authService AuthService) *Usecase {
+	return &Usecase{
+		authService: authService,
+	}
+}
+
+func (u *Usecase) Run(ctx context.Context, token string) error {
+	return u.authService.Logout(ctx, token)
+}
@@ -0,0 +1,25 @@
+package logout_user_all
+
+import (
+	"context"
+
+	"github.com/shoet/blog/internal/infrastracture/models"
+)
+
+type AuthService interface {
+	LogoutAll(ctx context.Context, userId models.UserId) error
+}
+
+type Usecase struct {
+	authService AuthService
+}
+
+func NewUsecase(authService AuthService) *Usecase {
+	return &Usecase{
+		authService: authService,
+	}
+}
+
+func (u *Usecase) Run(ctx context.Context, userId models.UserId) error {
+	return u.authService.LogoutAll(ctx, userId)
+}
@@ -0,0 +1,65 @@
+package moderate_comment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+)
+
+type CommentRepository interface {
+	Get(ctx context.Context, tx infrastracture.TX, id models.CommentId) (*models.Comment, error)
+	UpdateStatus(ctx context.Context, tx infrastracture.TX, id models.CommentId, status models.CommentStatus) error
+}
+
+var ErrCommentNotFound = errors.New("comment is not found")
+var ErrInvalidStatus = errors.New("comment status is invalid")
+
+// moderate_comment.Usecaseはコメントを承認・却下・スパム判定するユースケースです。
+type Usecase struct {
+	DB                infrastracture.DB
+	CommentRepository CommentRepository
+}
+
+func NewUsecase(db infrastracture.DB, commentRepository CommentRepository) *Usecase {
+	return &Usecase{
+		DB:                db,
+		CommentRepository: commentRepository,
+	}
+}
+
+func (u *Usecase) Run(
+	ctx context.Context, id models.CommentId, status models.CommentStatus,
+) (*models.Comment, error) {
+	if !status.Valid() {
+		return nil, ErrInvalidStatus
+	}
+	transactor := infrastracture.NewTransactionProvider(u.DB)
+	result, err := transactor.DoInTx(ctx, func(tx infrastracture.TX) (interface{}, error) {
+		comment, err := u.CommentRepository.Get(ctx, tx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get comment: %w", err)
+		}
+		if comment == nil {
+			return nil, ErrCommentNotFound
+		}
+		if err := u.CommentRepository.UpdateStatus(ctx, tx, id, status); err != nil {
+			return nil, fmt.Errorf("failed to update comment status: %w", err)
+		}
+		newComment, err := u.CommentRepository.Get(ctx, tx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get comment: %w", err)
+		}
+		return newComment, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to moderate comment: %w", err)
+	}
+	comment, ok := result.(*models.Comment)
+	if !ok {
+		return nil, fmt.Errorf("failed to type assertion")
+	}
+	return comment, nil
+}
@@ -0,0 +1,51 @@
+package process_thumbnail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shoet/blog/internal/imaging"
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/adapter"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/infrastracture/services/contents_service"
+)
+
+type ContentsService interface {
+	ProcessThumbnail(ctx context.Context, tx infrastracture.TX, fileName string) ([]*models.ImageVariant, error)
+}
+
+var (
+	ErrImageNotFound = errors.New("image is not found")
+	ErrInvalidImage  = errors.New("invalid image")
+)
+
+// process_thumbnail.Usecaseはアップロード済みのサムネイル画像からメタデータを除去し、派生画像を生成するユースケースです。
+type Usecase struct {
+	db              infrastracture.DB
+	contentsService ContentsService
+}
+
+func NewUsecase(db infrastracture.DB, contentsService ContentsService) *Usecase {
+	return &Usecase{
+		db:              db,
+		contentsService: contentsService,
+	}
+}
+
+func (u *Usecase) Run(ctx context.Context, fileName string) ([]*models.ImageVariant, error) {
+	variants, err := u.contentsService.ProcessThumbnail(ctx, u.db, fileName)
+	if err != nil {
+		if errors.Is(err, adapter.ErrObjectNotFound) || errors.Is(err, contents_service.ErrMediaNotFound) {
+			return nil, ErrImageNotFound
+		}
+		if errors.Is(err, contents_service.ErrInvalidFileName) ||
+			errors.Is(err, imaging.ErrUnsupportedImage) ||
+			errors.Is(err, imaging.ErrImageTooLarge) {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+		}
+		return nil, fmt.Errorf("failed to process thumbnail: %w", err)
+	}
+	return variants, nil
+}
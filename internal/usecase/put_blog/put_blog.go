@@ -6,7 +6,10 @@ import (
 
 	"github.com/shoet/blog/internal/infrastracture"
 	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/markdown"
+	"github.com/shoet/blog/internal/mediaref"
 	"github.com/shoet/blog/internal/session"
+	"github.com/shoet/blog/internal/slug"
 	"golang.org/x/exp/slices"
 )
 
@@ -20,20 +23,38 @@ type BlogRepository interface {
 	DeleteBlogsTags(ctx context.Context, tx infrastracture.TX, blogId models.BlogId, tagId models.TagId) error
 	Put(ctx context.Context, tx infrastracture.TX, blog *models.Blog) (models.BlogId, error)
 	Get(ctx context.Context, tx infrastracture.TX, id models.BlogId) (*models.Blog, error)
+	AddRevision(ctx context.Context, tx infrastracture.TX, revision *models.BlogRevision) (*models.BlogRevision, error)
+	ExistsSlug(ctx context.Context, tx infrastracture.TX, slug string, excludeBlogId models.BlogId) (bool, error)
+	AddSlugHistory(ctx context.Context, tx infrastracture.TX, blogId models.BlogId, slug string) error
+	DeleteSlugHistory(ctx context.Context, tx infrastracture.TX, slug string) error
+}
+
+type MediaRepository interface {
+	ReplaceBlogReferences(ctx context.Context, tx infrastracture.TX, blogId models.BlogId, keys []string) error
+}
+
+type Policy interface {
+	AuthorizeBlogEdit(ctx context.Context, blog *models.Blog) error
 }
 
 type Usecase struct {
-	DB             infrastracture.DB
-	BlogRepository BlogRepository
+	DB              infrastracture.DB
+	BlogRepository  BlogRepository
+	MediaRepository MediaRepository
+	Policy          Policy
 }
 
 func NewUsecase(
 	db infrastracture.DB,
 	blogRepository BlogRepository,
+	mediaRepository MediaRepository,
+	policy Policy,
 ) *Usecase {
 	return &Usecase{
-		DB:             db,
-		BlogRepository: blogRepository,
+		DB:              db,
+		BlogRepository:  blogRepository,
+		MediaRepository: mediaRepository,
+		Policy:          policy,
 	}
 }
 
@@ -42,12 +63,24 @@ func (u *Usecase) Run(ctx context.Context, blog *models.Blog) (*models.Blog, err
 	if err != nil {
 		return nil, fmt.Errorf("failed to session.GetUserId: %w", err)
 	}
-	if sessionUserId != blog.AuthorId {
-		return nil, fmt.Errorf("can't update other user's blog")
-	}
 
 	transactor := infrastracture.NewTransactionProvider(u.DB)
 	result, err := transactor.DoInTx(ctx, func(tx infrastracture.TX) (interface{}, error) {
+		// 更新前のブログと、著者の変更後のブログの両方を編集できることを確認する
+		current, err := u.BlogRepository.Get(ctx, tx, blog.Id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get blog: %w", err)
+		}
+		if current == nil {
+			return nil, fmt.Errorf("blog is not found")
+		}
+		if err := u.Policy.AuthorizeBlogEdit(ctx, current); err != nil {
+			return nil, fmt.Errorf("failed to authorize: %w", err)
+		}
+		if err := u.Policy.AuthorizeBlogEdit(ctx, blog); err != nil {
+			return nil, fmt.Errorf("failed to authorize: %w", err)
+		}
+
 		// このブログに紐づいているタグで、他のブログで使用されているタグを取得する
 		var usingTagsByOtherBlog models.BlogsTagsArray
 		usingTagsByOtherBlog, err = u.BlogRepository.SelectBlogsTagsByOtherUsingBlog(ctx, tx, blog.Id)
@@ -57,7 +90,7 @@ func (u *Usecase) Run(ctx context.Context, blog *models.Blog) (*models.Blog, err
 
 		// このブログに紐づいているタグを取得する
 		var currentTags models.BlogsTagsArray
-		currentTags, err := u.BlogRepository.SelectBlogsTags(ctx, tx, blog.Id)
+		currentTags, err = u.BlogRepository.SelectBlogsTags(ctx, tx, blog.Id)
 		if err != nil {
 			return nil, fmt.Errorf("failed to select current tags: %w", err)
 		}
@@ -103,6 +136,16 @@ func (u *Usecase) Run(ctx context.Context, blog *models.Blog) (*models.Blog, err
 			}
 		}
 
+		// スラッグの更新
+		if err := u.resolveSlug(ctx, tx, blog); err != nil {
+			return nil, fmt.Errorf("failed to resolve slug: %w", err)
+		}
+
+		// 本文のレンダリング結果をキャッシュする
+		if err := markdown.RenderBlog(blog); err != nil {
+			return nil, fmt.Errorf("failed to render blog: %w", err)
+		}
+
 		// ブログの更新
 		id, err := u.BlogRepository.Put(ctx, tx, blog)
 		if err != nil {
@@ -115,6 +158,17 @@ func (u *Usecase) Run(ctx context.Context, blog *models.Blog) (*models.Blog, err
 			return nil, fmt.Errorf("failed to get blog: %w", err)
 		}
 
+		// 本文・サムネイルから参照しているメディアを記録する
+		if err := u.MediaRepository.ReplaceBlogReferences(ctx, tx, newBlog.Id, mediaref.FromBlog(newBlog)); err != nil {
+			return nil, fmt.Errorf("failed to replace media references: %w", err)
+		}
+
+		// 更新後の内容を履歴として保存
+		revision := models.NewBlogRevision(newBlog, sessionUserId)
+		if _, err := u.BlogRepository.AddRevision(ctx, tx, revision); err != nil {
+			return nil, fmt.Errorf("failed to add blog revision: %w", err)
+		}
+
 		return newBlog, nil
 	})
 
@@ -128,3 +182,46 @@ func (u *Usecase) Run(ctx context.Context, blog *models.Blog) (*models.Blog, err
 	}
 	return blog, nil
 }
+
+// resolveSlug は、更新後のスラッグを決定する
+// スラッグが指定されていない場合は現在のスラッグを維持し、
+// 変更された場合は変更前のスラッグをリダイレクト用の履歴に保存する
+func (u *Usecase) resolveSlug(ctx context.Context, tx infrastracture.TX, blog *models.Blog) error {
+	current, err := u.BlogRepository.Get(ctx, tx, blog.Id)
+	if err != nil {
+		return fmt.Errorf("failed to get blog: %w", err)
+	}
+	if current == nil {
+		return fmt.Errorf("blog is not found")
+	}
+
+	baseSlug := slug.Normalize(blog.Slug)
+	if baseSlug == "" {
+		baseSlug = current.Slug
+	}
+	if baseSlug == "" {
+		baseSlug = slug.Generate(blog.Title)
+	}
+	if baseSlug == current.Slug {
+		blog.Slug = current.Slug
+		return nil
+	}
+
+	newSlug, err := slug.Unique(baseSlug, func(s string) (bool, error) {
+		return u.BlogRepository.ExistsSlug(ctx, tx, s, blog.Id)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to unique slug: %w", err)
+	}
+	// 過去に使用していたスラッグに戻す場合は履歴から削除する
+	if err := u.BlogRepository.DeleteSlugHistory(ctx, tx, newSlug); err != nil {
+		return fmt.Errorf("failed to delete slug history: %w", err)
+	}
+	if current.Slug != "" {
+		if err := u.BlogRepository.AddSlugHistory(ctx, tx, blog.Id, current.Slug); err != nil {
+			return fmt.Errorf("failed to add slug history: %w", err)
+		}
+	}
+	blog.Slug = newSlug
+	return nil
+}
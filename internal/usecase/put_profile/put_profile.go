@@ -0,0 +1,50 @@
+package put_profile
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+)
+
+type UserRepository interface {
+	Get(ctx context.Context, tx infrastracture.TX, id models.UserId) (*models.User, error)
+	PutProfile(ctx context.Context, tx infrastracture.TX, id models.UserId, profile *models.Profile) error
+}
+
+// put_profile.Usecaseはログイン中のユーザーの公開用のプロフィールを更新するユースケースです。
+type Usecase struct {
+	DB             infrastracture.DB
+	UserRepository UserRepository
+}
+
+func NewUsecase(db infrastracture.DB, userRepository UserRepository) *Usecase {
+	return &Usecase{
+		DB:             db,
+		UserRepository: userRepository,
+	}
+}
+
+func (u *Usecase) Run(ctx context.Context, userId models.UserId, profile *models.Profile) (*models.User, error) {
+	transactor := infrastracture.NewTransactionProvider(u.DB)
+	result, err := transactor.DoInTx(ctx, func(tx infrastracture.TX) (interface{}, error) {
+		if err := u.UserRepository.PutProfile(ctx, tx, userId, profile); err != nil {
+			return nil, fmt.Errorf("failed to put profile: %w", err)
+		}
+		user, err := u.UserRepository.Get(ctx, tx, userId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user: %w", err)
+		}
+		return user, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update profile: %w", err)
+	}
+
+	user, ok := result.(*models.User)
+	if !ok {
+		return nil, fmt.Errorf("failed to type assertion: %w", err)
+	}
+	return user, nil
+}
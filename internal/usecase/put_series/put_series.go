@@ -0,0 +1,58 @@
+package put_series
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+)
+
+type SeriesRepository interface {
+	Get(ctx context.Context, tx infrastracture.TX, id models.SeriesId) (*models.Series, error)
+	Put(ctx context.Context, tx infrastracture.TX, series *models.Series) error
+}
+
+var ErrSeriesNotFound = errors.New("series is not found")
+
+type Usecase struct {
+	DB               infrastracture.DB
+	SeriesRepository SeriesRepository
+}
+
+func NewUsecase(db infrastracture.DB, seriesRepository SeriesRepository) *Usecase {
+	return &Usecase{
+		DB:               db,
+		SeriesRepository: seriesRepository,
+	}
+}
+
+func (u *Usecase) Run(ctx context.Context, series *models.Series) (*models.Series, error) {
+	transactor := infrastracture.NewTransactionProvider(u.DB)
+	result, err := transactor.DoInTx(ctx, func(tx infrastracture.TX) (interface{}, error) {
+		current, err := u.SeriesRepository.Get(ctx, tx, series.Id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get series: %w", err)
+		}
+		if current == nil {
+			return nil, ErrSeriesNotFound
+		}
+		if err := u.SeriesRepository.Put(ctx, tx, series); err != nil {
+			return nil, fmt.Errorf("failed to put series: %w", err)
+		}
+		newSeries, err := u.SeriesRepository.Get(ctx, tx, series.Id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get series: %w", err)
+		}
+		return newSeries, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to put series: %w", err)
+	}
+	newSeries, ok := result.(*models.Series)
+	if !ok {
+		return nil, fmt.Errorf("failed to type assertion")
+	}
+	return newSeries, nil
+}
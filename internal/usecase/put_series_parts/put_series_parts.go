@@ -0,0 +1,99 @@
+package put_series_parts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+)
+
+type BlogRepository interface {
+	Get(ctx context.Context, tx infrastracture.TX, id models.BlogId) (*models.Blog, error)
+}
+
+type SeriesRepository interface {
+	Get(ctx context.Context, tx infrastracture.TX, id models.SeriesId) (*models.Series, error)
+	GetSeriesIdByBlogId(ctx context.Context, tx infrastracture.TX, blogId models.BlogId) (models.SeriesId, error)
+	SetParts(ctx context.Context, tx infrastracture.TX, seriesId models.SeriesId, blogIds []models.BlogId) error
+}
+
+var ErrSeriesNotFound = errors.New("series is not found")
+var ErrBlogNotFound = errors.New("blog is not found")
+var ErrBlogDuplicated = errors.New("blog is duplicated in series")
+var ErrBlogInOtherSeries = errors.New("blog already belongs to other series")
+
+// put_series_parts.Usecaseはシリーズに所属するブログとその順番を更新するユースケースです。
+// 指定したブログの並びでシリーズの所属を置き換えるため、追加・削除・並べ替えを兼ねます。
+type Usecase struct {
+	DB               infrastracture.DB
+	BlogRepository   BlogRepository
+	SeriesRepository SeriesRepository
+}
+
+func NewUsecase(
+	db infrastracture.DB, blogRepository BlogRepository, seriesRepository SeriesRepository,
+) *Usecase {
+	return &Usecase{
+		DB:               db,
+		BlogRepository:   blogRepository,
+		SeriesRepository: seriesRepository,
+	}
+}
+
+func (u *Usecase) Run(
+	ctx context.Context, seriesId models.SeriesId, blogIds []models.BlogId,
+) (*models.Series, error) {
+	transactor := infrastracture.NewTransactionProvider(u.DB)
+	result, err := transactor.DoInTx(ctx, func(tx infrastracture.TX) (interface{}, error) {
+		series, err := u.SeriesRepository.Get(ctx, tx, seriesId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get series: %w", err)
+		}
+		if series == nil {
+			return nil, ErrSeriesNotFound
+		}
+
+		seen := map[models.BlogId]struct{}{}
+		for _, blogId := range blogIds {
+			if _, ok := seen[blogId]; ok {
+				return nil, ErrBlogDuplicated
+			}
+			seen[blogId] = struct{}{}
+
+			blog, err := u.BlogRepository.Get(ctx, tx, blogId)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get blog: %w", err)
+			}
+			if blog == nil {
+				return nil, ErrBlogNotFound
+			}
+			// ブログは1つのシリーズにのみ所属できる
+			belongs, err := u.SeriesRepository.GetSeriesIdByBlogId(ctx, tx, blogId)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get series id: %w", err)
+			}
+			if belongs != 0 && belongs != seriesId {
+				return nil, ErrBlogInOtherSeries
+			}
+		}
+
+		if err := u.SeriesRepository.SetParts(ctx, tx, seriesId, blogIds); err != nil {
+			return nil, fmt.Errorf("failed to set series parts: %w", err)
+		}
+		newSeries, err := u.SeriesRepository.Get(ctx, tx, seriesId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get series: %w", err)
+		}
+		return newSeries, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to put series parts: %w", err)
+	}
+	series, ok := result.(*models.Series)
+	if !ok {
+		return nil, fmt.Errorf("failed to type assertion")
+	}
+	return series, nil
+}
@@ -0,0 +1,125 @@
+package put_user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shoet/blog/internal/clocker"
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/infrastracture/repository"
+)
+
+type UserRepository interface {
+	Get(ctx context.Context, tx infrastracture.TX, id models.UserId) (*models.User, error)
+	GetByEmail(ctx context.Context, tx infrastracture.TX, email string) (*models.User, error)
+	Put(ctx context.Context, tx infrastracture.TX, user *models.User) error
+	CountActiveAdmins(ctx context.Context, tx infrastracture.TX) (int64, error)
+}
+
+type AuthService interface {
+	LogoutAll(ctx context.Context, userId models.UserId) error
+}
+
+var ErrUserNotFound = errors.New("user is not found")
+var ErrInvalidRole = errors.New("role is invalid")
+var ErrEmailAlreadyExists = errors.New("email already exists")
+var ErrLastAdmin = errors.New("can't demote or disable the last admin")
+
+type Input struct {
+	Name     string
+	Email    string
+	Role     models.Role
+	Disabled bool
+}
+
+// put_user.Usecaseは管理者がユーザーの情報・権限・有効状態を更新するユースケースです。
+// 無効化したユーザーのセッションはすべて失効させます。
+type Usecase struct {
+	DB             infrastracture.DB
+	UserRepository UserRepository
+	Clocker        clocker.Clocker
+	authService    AuthService
+}
+
+func NewUsecase(
+	db infrastracture.DB, userRepository UserRepository, clocker clocker.Clocker, authService AuthService,
+) *Usecase {
+	return &Usecase{
+		DB:             db,
+		UserRepository: userRepository,
+		Clocker:        clocker,
+		authService:    authService,
+	}
+}
+
+func (u *Usecase) Run(ctx context.Context, id models.UserId, input *Input) (*models.User, error) {
+	if !input.Role.IsValid() {
+		return nil, ErrInvalidRole
+	}
+
+	transactor := infrastracture.NewTransactionProvider(u.DB)
+	result, err := transactor.DoInTx(ctx, func(tx infrastracture.TX) (interface{}, error) {
+		user, err := u.UserRepository.Get(ctx, tx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrUserNotFound) {
+				return nil, ErrUserNotFound
+			}
+			return nil, fmt.Errorf("failed to get user: %w", err)
+		}
+
+		if input.Email != user.Email {
+			if _, err := u.UserRepository.GetByEmail(ctx, tx, input.Email); err == nil {
+				return nil, ErrEmailAlreadyExists
+			} else if !errors.Is(err, repository.ErrUserNotFound) {
+				return nil, fmt.Errorf("failed to get user by email: %w", err)
+			}
+		}
+
+		// 有効な管理者がいなくなる変更は受け付けない
+		if user.Role == models.RoleAdmin && !user.IsDisabled() &&
+			(input.Role != models.RoleAdmin || input.Disabled) {
+			count, err := u.UserRepository.CountActiveAdmins(ctx, tx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to count admins: %w", err)
+			}
+			if count <= 1 {
+				return nil, ErrLastAdmin
+			}
+		}
+
+		user.Name = input.Name
+		user.Email = input.Email
+		user.Role = input.Role
+		if !input.Disabled {
+			user.DisabledAt = nil
+		} else if user.DisabledAt == nil {
+			now := uint(u.Clocker.Now().Unix())
+			user.DisabledAt = &now
+		}
+		if err := u.UserRepository.Put(ctx, tx, user); err != nil {
+			return nil, fmt.Errorf("failed to put user: %w", err)
+		}
+
+		newUser, err := u.UserRepository.Get(ctx, tx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user: %w", err)
+		}
+		return newUser, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	user, ok := result.(*models.User)
+	if !ok {
+		return nil, fmt.Errorf("failed to type assertion: %w", err)
+	}
+	if user.IsDisabled() {
+		if err := u.authService.LogoutAll(ctx, user.Id); err != nil {
+			return nil, fmt.Errorf("failed to revoke sessions: %w", err)
+		}
+	}
+	return user, nil
+}
@@ -0,0 +1,25 @@
+package refresh_token
+
+import (
+	"context"
+
+	"github.com/shoet/blog/internal/infrastracture/models"
+)
+
+type AuthService interface {
+	Refresh(ctx context.Context, refreshToken string) (*models.AuthToken, error)
+}
+
+type Usecase struct {
+	authService AuthService
+}
+
+func NewUsecase(authService AuthService) *Usecase {
+	return &Usecase{
+		authService: authService,
+	}
+}
+
+func (u *Usecase) Run(ctx context.Context, refreshToken string) (*models.AuthToken, error) {
+	return u.authService.Refresh(ctx, refreshToken)
+}
@@ -0,0 +1,67 @@
+package render_blogs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/markdown"
+)
+
+type BlogRepository interface {
+	ListIds(ctx context.Context, tx infrastracture.TX) ([]models.BlogId, error)
+	Get(ctx context.Context, tx infrastracture.TX, id models.BlogId) (*models.Blog, error)
+	PutRendered(ctx context.Context, tx infrastracture.TX, blog *models.Blog) error
+}
+
+// render_blogs.Usecaseはすべてのブログの本文を再レンダリングし、キャッシュを更新するユースケースです。
+// キャッシュ導入前の記事のキャッシュ生成や、レンダリング方法を変更した際に使用します。
+type Usecase struct {
+	DB             infrastracture.DB
+	BlogRepository BlogRepository
+}
+
+func NewUsecase(db infrastracture.DB, blogRepository BlogRepository) *Usecase {
+	return &Usecase{
+		DB:             db,
+		BlogRepository: blogRepository,
+	}
+}
+
+// Run は、再レンダリングしたブログの件数を返す
+func (u *Usecase) Run(ctx context.Context) (int, error) {
+	transactor := infrastracture.NewTransactionProvider(u.DB)
+	result, err := transactor.DoInTx(ctx, func(tx infrastracture.TX) (interface{}, error) {
+		ids, err := u.BlogRepository.ListIds(ctx, tx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list blog ids: %w", err)
+		}
+		count := 0
+		for _, id := range ids {
+			blog, err := u.BlogRepository.Get(ctx, tx, id)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get blog: %w", err)
+			}
+			if blog == nil {
+				continue
+			}
+			if err := markdown.RenderBlog(blog); err != nil {
+				return nil, fmt.Errorf("failed to render blog %d: %w", id, err)
+			}
+			if err := u.BlogRepository.PutRendered(ctx, tx, blog); err != nil {
+				return nil, fmt.Errorf("failed to put rendered blog: %w", err)
+			}
+			count++
+		}
+		return count, nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to render blogs: %w", err)
+	}
+	count, ok := result.(int)
+	if !ok {
+		return 0, fmt.Errorf("failed to type assertion")
+	}
+	return count, nil
+}
@@ -0,0 +1,94 @@
+package request_password_reset
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/infrastracture/repository"
+)
+
+type UserRepository interface {
+	GetByEmail(ctx context.Context, tx infrastracture.TX, email string) (*models.User, error)
+}
+
+type TokenIssuer interface {
+	GeneratePasswordResetToken(ctx context.Context, userId models.UserId, ttl time.Duration) (string, error)
+}
+
+type Mailer interface {
+	Send(ctx context.Context, mail *models.Mail) error
+}
+
+// request_password_reset.Usecaseはパスワードリセットトークンを発行し、メールで送付するユースケースです。
+// メールアドレスの登録有無が分からないよう、ユーザーが存在しない場合もエラーにしません。
+type Usecase struct {
+	DB             infrastracture.DB
+	UserRepository UserRepository
+	tokenIssuer    TokenIssuer
+	mailer         Mailer
+	// ResetURL はメールに記載するリセット画面のURL。空の場合はトークンのみを記載する
+	ResetURL string
+	MailFrom string
+	TokenTTL time.Duration
+}
+
+func NewUsecase(
+	db infrastracture.DB,
+	userRepository UserRepository,
+	tokenIssuer TokenIssuer,
+	mailer Mailer,
+	resetURL string,
+	mailFrom string,
+	tokenTTL time.Duration,
+) *Usecase {
+	return &Usecase{
+		DB:             db,
+		UserRepository: userRepository,
+		tokenIssuer:    tokenIssuer,
+		mailer:         mailer,
+		ResetURL:       resetURL,
+		MailFrom:       mailFrom,
+		TokenTTL:       tokenTTL,
+	}
+}
+
+func (u *Usecase) Run(ctx context.Context, email string) error {
+	user, err := u.UserRepository.GetByEmail(ctx, u.DB, email)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to get user by email: %w", err)
+	}
+	token, err := u.tokenIssuer.GeneratePasswordResetToken(ctx, user.Id, u.TokenTTL)
+	if err != nil {
+		return fmt.Errorf("failed to generate password reset token: %w", err)
+	}
+	mail := &models.Mail{
+		From:    u.MailFrom,
+		To:      user.Email,
+		Subject: "パスワードの再設定",
+		Body:    u.mailBody(token),
+	}
+	if err := u.mailer.Send(ctx, mail); err != nil {
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+	return nil
+}
+
+func (u *Usecase) mailBody(token string) string {
+	link := token
+	if u.ResetURL != "" {
+		link = fmt.Sprintf("%s?token=%s", u.ResetURL, url.QueryEscape(token))
+	}
+	return fmt.Sprintf(
+		"パスワードの再設定を受け付けました。\n"+
+			"以下から%d分以内に新しいパスワードを設定してください。\n\n%s\n\n"+
+			"心当たりがない場合はこのメールを破棄してください。\n",
+		int(u.TokenTTL.Minutes()), link)
+}
@@ -0,0 +1,66 @@
+package reset_password
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/util"
+)
+
+type UserRepository interface {
+	PutPassword(ctx context.Context, tx infrastracture.TX, id models.UserId, hashedPassword string) error
+}
+
+type TokenIssuer interface {
+	UsePasswordResetToken(ctx context.Context, token string) (models.UserId, error)
+}
+
+type AuthService interface {
+	LogoutAll(ctx context.Context, userId models.UserId) error
+}
+
+var ErrTokenInvalid = errors.New("password reset token is invalid")
+
+// reset_password.Usecaseはパスワードリセットトークンを使用してパスワードを再設定するユースケースです。
+// トークンは一度のみ使用でき、再設定後は既存のセッションをすべて失効させます。
+type Usecase struct {
+	DB             infrastracture.DB
+	UserRepository UserRepository
+	tokenIssuer    TokenIssuer
+	authService    AuthService
+}
+
+func NewUsecase(
+	db infrastracture.DB, userRepository UserRepository, tokenIssuer TokenIssuer, authService AuthService,
+) *Usecase {
+	return &Usecase{
+		DB:             db,
+		UserRepository: userRepository,
+		tokenIssuer:    tokenIssuer,
+		authService:    authService,
+	}
+}
+
+func (u *Usecase) Run(ctx context.Context, token string, newPassword string) error {
+	hashed, err := util.HashPassword(newPassword)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+	userId, err := u.tokenIssuer.UsePasswordResetToken(ctx, token)
+	if err != nil {
+		return fmt.Errorf("failed to use password reset token: %w", err)
+	}
+	if userId == 0 {
+		return ErrTokenInvalid
+	}
+	if err := u.UserRepository.PutPassword(ctx, u.DB, userId, hashed); err != nil {
+		return fmt.Errorf("failed to put password: %w", err)
+	}
+	if err := u.authService.LogoutAll(ctx, userId); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	return nil
+}
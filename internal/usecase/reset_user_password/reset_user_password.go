@@ -0,0 +1,66 @@
+package reset_user_password
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/infrastracture/repository"
+	"github.com/shoet/blog/internal/util"
+)
+
+type UserRepository interface {
+	GetPasswordHash(ctx context.Context, tx infrastracture.TX, id models.UserId) (string, error)
+	PutPassword(ctx context.Context, tx infrastracture.TX, id models.UserId, hashedPassword string) error
+}
+
+type AuthService interface {
+	LogoutAll(ctx context.Context, userId models.UserId) error
+}
+
+var ErrUserNotFound = errors.New("user is not found")
+
+// reset_user_password.Usecaseは管理者がユーザーのパスワードを再設定するユースケースです。
+// 現在のパスワードの確認は行わず、既存のセッションをすべて失効させます。
+type Usecase struct {
+	DB             infrastracture.DB
+	UserRepository UserRepository
+	authService    AuthService
+}
+
+func NewUsecase(db infrastracture.DB, userRepository UserRepository, authService AuthService) *Usecase {
+	return &Usecase{
+		DB:             db,
+		UserRepository: userRepository,
+		authService:    authService,
+	}
+}
+
+func (u *Usecase) Run(ctx context.Context, id models.UserId, newPassword string) error {
+	hashed, err := util.HashPassword(newPassword)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+	transactor := infrastracture.NewTransactionProvider(u.DB)
+	_, err = transactor.DoInTx(ctx, func(tx infrastracture.TX) (interface{}, error) {
+		if _, err := u.UserRepository.GetPasswordHash(ctx, tx, id); err != nil {
+			if errors.Is(err, repository.ErrUserNotFound) {
+				return nil, ErrUserNotFound
+			}
+			return nil, fmt.Errorf("failed to get user: %w", err)
+		}
+		if err := u.UserRepository.PutPassword(ctx, tx, id, hashed); err != nil {
+			return nil, fmt.Errorf("failed to put password: %w", err)
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reset password: %w", err)
+	}
+	if err := u.authService.LogoutAll(ctx, id); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	return nil
+}
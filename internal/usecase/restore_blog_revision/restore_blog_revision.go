@@ -0,0 +1,120 @@
+package restore_blog_revision
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/markdown"
+	"github.com/shoet/blog/internal/mediaref"
+	"github.com/shoet/blog/internal/session"
+)
+
+type BlogRepository interface {
+	Get(ctx context.Context, tx infrastracture.TX, id models.BlogId) (*models.Blog, error)
+	Put(ctx context.Context, tx infrastracture.TX, blog *models.Blog) (models.BlogId, error)
+	GetRevision(ctx context.Context, tx infrastracture.TX, blogId models.BlogId, revision int64) (*models.BlogRevision, error)
+	AddRevision(ctx context.Context, tx infrastracture.TX, revision *models.BlogRevision) (*models.BlogRevision, error)
+}
+
+type MediaRepository interface {
+	ReplaceBlogReferences(ctx context.Context, tx infrastracture.TX, blogId models.BlogId, keys []string) error
+}
+
+type Policy interface {
+	AuthorizeBlogEdit(ctx context.Context, blog *models.Blog) error
+}
+
+var ErrBlogNotFound = errors.New("blog is not found")
+var ErrRevisionNotFound = errors.New("blog revision is not found")
+
+// restore_blog_revision.Usecaseはブログを過去の履歴の内容に戻すユースケースです。
+// 公開状態とタグは履歴の対象外のため、現在の値を維持します。
+type Usecase struct {
+	DB              infrastracture.DB
+	BlogRepository  BlogRepository
+	MediaRepository MediaRepository
+	Policy          Policy
+}
+
+func NewUsecase(
+	db infrastracture.DB, blogRepository BlogRepository, mediaRepository MediaRepository, policy Policy,
+) *Usecase {
+	return &Usecase{
+		DB:              db,
+		BlogRepository:  blogRepository,
+		MediaRepository: mediaRepository,
+		Policy:          policy,
+	}
+}
+
+func (u *Usecase) Run(
+	ctx context.Context, blogId models.BlogId, revision int64,
+) (*models.Blog, error) {
+	sessionUserId, err := session.GetUserId(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to session.GetUserId: %w", err)
+	}
+
+	transactor := infrastracture.NewTransactionProvider(u.DB)
+	result, err := transactor.DoInTx(ctx, func(tx infrastracture.TX) (interface{}, error) {
+		blog, err := u.BlogRepository.Get(ctx, tx, blogId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get blog: %w", err)
+		}
+		if blog == nil {
+			return nil, ErrBlogNotFound
+		}
+		if err := u.Policy.AuthorizeBlogEdit(ctx, blog); err != nil {
+			return nil, fmt.Errorf("failed to authorize: %w", err)
+		}
+
+		target, err := u.BlogRepository.GetRevision(ctx, tx, blogId, revision)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get revision: %w", err)
+		}
+		if target == nil {
+			return nil, ErrRevisionNotFound
+		}
+
+		blog.Title = target.Title
+		blog.Content = target.Content
+		blog.Description = target.Description
+		blog.ThumbnailImageFileName = target.ThumbnailImageFileName
+		if err := markdown.RenderBlog(blog); err != nil {
+			return nil, fmt.Errorf("failed to render blog: %w", err)
+		}
+		if _, err := u.BlogRepository.Put(ctx, tx, blog); err != nil {
+			return nil, fmt.Errorf("failed to put blog: %w", err)
+		}
+
+		newBlog, err := u.BlogRepository.Get(ctx, tx, blogId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get blog: %w", err)
+		}
+
+		// 本文・サムネイルから参照しているメディアを記録する
+		if err := u.MediaRepository.ReplaceBlogReferences(ctx, tx, newBlog.Id, mediaref.FromBlog(newBlog)); err != nil {
+			return nil, fmt.Errorf("failed to replace media references: %w", err)
+		}
+
+		// 復元も1つの更新として履歴に残す
+		if _, err := u.BlogRepository.AddRevision(
+			ctx, tx, models.NewBlogRevision(newBlog, sessionUserId),
+		); err != nil {
+			return nil, fmt.Errorf("failed to add blog revision: %w", err)
+		}
+		return newBlog, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore blog revision: %w", err)
+	}
+
+	blog, ok := result.(*models.Blog)
+	if !ok {
+		return nil, fmt.Errorf("failed to cast *models.Blog")
+	}
+	return blog, nil
+}
@@ -0,0 +1,40 @@
+package revoke_api_key
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+)
+
+type APIKeyRepository interface {
+	Revoke(ctx context.Context, tx infrastracture.TX, userId models.UserId, id models.APIKeyId) (bool, error)
+}
+
+var ErrAPIKeyNotFound = errors.New("api key is not found")
+
+// revoke_api_key.Usecaseはユーザー自身のAPIキーを失効させるユースケースです。
+type Usecase struct {
+	DB               infrastracture.DB
+	APIKeyRepository APIKeyRepository
+}
+
+func NewUsecase(db infrastracture.DB, apiKeyRepository APIKeyRepository) *Usecase {
+	return &Usecase{
+		DB:               db,
+		APIKeyRepository: apiKeyRepository,
+	}
+}
+
+func (u *Usecase) Run(ctx context.Context, userId models.UserId, id models.APIKeyId) error {
+	revoked, err := u.APIKeyRepository.Revoke(ctx, u.DB, userId, id)
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	if !revoked {
+		return ErrAPIKeyNotFound
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package revoke_session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shoet/blog/internal/infrastracture/models"
+)
+
+type AuthService interface {
+	ListSessions(ctx context.Context, userId models.UserId, token string) ([]*models.Session, error)
+	RevokeSession(ctx context.Context, userId models.UserId, sessionId string) error
+}
+
+var ErrSessionNotFound = errors.New("session is not found")
+
+// revoke_session.Usecaseはログイン中のユーザー自身のセッションを失効させるユースケースです。
+type Usecase struct {
+	authService AuthService
+}
+
+func NewUsecase(authService AuthService) *Usecase {
+	return &Usecase{
+		authService: authService,
+	}
+}
+
+func (u *Usecase) Run(ctx context.Context, userId models.UserId, token string, sessionId string) error {
+	sessions, err := u.authService.ListSessions(ctx, userId, token)
+	if err != nil {
+		return fmt.Errorf("failed to list sessions: %w", err)
+	}
+	found := false
+	for _, s := range sessions {
+		if s.Id == sessionId {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return ErrSessionNotFound
+	}
+	if err := u.authService.RevokeSession(ctx, userId, sessionId); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return nil
+}
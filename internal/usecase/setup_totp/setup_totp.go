@@ -0,0 +1,66 @@
+package setup_totp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/totp"
+)
+
+type UserRepository interface {
+	Get(ctx context.Context, tx infrastracture.TX, id models.UserId) (*models.User, error)
+	PutTOTP(ctx context.Context, tx infrastracture.TX, id models.UserId, secret string, enabled bool) error
+}
+
+var ErrAlreadyEnabled = errors.New("two factor authentication is already enabled")
+
+// setup_totp.Usecaseは2段階認証の共有鍵を生成し、認証アプリに登録するURIを返すユースケースです。
+// 認証コードで有効化するまでは、ログインに2段階認証は要求されません。
+type Usecase struct {
+	DB             infrastracture.DB
+	UserRepository UserRepository
+	Issuer         string
+}
+
+func NewUsecase(db infrastracture.DB, userRepository UserRepository, issuer string) *Usecase {
+	return &Usecase{
+		DB:             db,
+		UserRepository: userRepository,
+		Issuer:         issuer,
+	}
+}
+
+func (u *Usecase) Run(ctx context.Context, userId models.UserId) (*models.TOTPSetup, error) {
+	transactor := infrastracture.NewTransactionProvider(u.DB)
+	result, err := transactor.DoInTx(ctx, func(tx infrastracture.TX) (interface{}, error) {
+		user, err := u.UserRepository.Get(ctx, tx, userId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user: %w", err)
+		}
+		if user.TOTPEnabled {
+			return nil, ErrAlreadyEnabled
+		}
+		secret, err := totp.GenerateSecret()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate secret: %w", err)
+		}
+		if err := u.UserRepository.PutTOTP(ctx, tx, userId, secret, false); err != nil {
+			return nil, fmt.Errorf("failed to put totp: %w", err)
+		}
+		return &models.TOTPSetup{
+			Secret: secret,
+			URI:    totp.URI(u.Issuer, user.Name, secret),
+		}, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup totp: %w", err)
+	}
+	setup, ok := result.(*models.TOTPSetup)
+	if !ok {
+		return nil, fmt.Errorf("failed to type assertion")
+	}
+	return setup, nil
+}
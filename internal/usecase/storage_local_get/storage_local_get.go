@@ -0,0 +1,31 @@
+package storage_local_get
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+type LocalStorage interface {
+	Open(key string) (*os.File, error)
+}
+
+// storage_local_get.Usecaseはローカルストレージに保存したオブジェクトを取得するユースケースです。
+// 呼び出し元でファイルを閉じる必要があります。
+type Usecase struct {
+	localStorage LocalStorage
+}
+
+func NewUsecase(localStorage LocalStorage) *Usecase {
+	return &Usecase{
+		localStorage: localStorage,
+	}
+}
+
+func (u *Usecase) Run(ctx context.Context, key string) (*os.File, error) {
+	f, err := u.localStorage.Open(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open object: %w", err)
+	}
+	return f, nil
+}
@@ -0,0 +1,42 @@
+package storage_local_put
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+type LocalStorage interface {
+	Verify(key string, contentType string, contentLength int64, expires string, signature string) error
+	Save(key string, body io.Reader) error
+}
+
+// storage_local_put.Usecaseは署名付きURLで受け付けたオブジェクトをローカルストレージに保存するユースケースです。
+type Usecase struct {
+	localStorage LocalStorage
+}
+
+func NewUsecase(localStorage LocalStorage) *Usecase {
+	return &Usecase{
+		localStorage: localStorage,
+	}
+}
+
+// Runは、署名に含まれるContent-TypeとContent-Lengthがリクエストと一致する場合のみ保存します。
+func (u *Usecase) Run(
+	ctx context.Context,
+	key string,
+	contentType string,
+	contentLength int64,
+	expires string,
+	signature string,
+	body io.Reader,
+) error {
+	if err := u.localStorage.Verify(key, contentType, contentLength, expires, signature); err != nil {
+		return fmt.Errorf("failed to verify signature: %w", err)
+	}
+	if err := u.localStorage.Save(key, io.LimitReader(body, contentLength)); err != nil {
+		return fmt.Errorf("failed to save object: %w", err)
+	}
+	return nil
+}
@@ -1,21 +1,72 @@
 package storage_presigned_content
 
-import "context"
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shoet/blog/internal/infrastracture"
+	"github.com/shoet/blog/internal/infrastracture/models"
+	"github.com/shoet/blog/internal/infrastracture/services/contents_service"
+	"github.com/shoet/blog/internal/session"
+)
 
 type ContentsService interface {
-	GenerateContentImagePutURL(fileName string) (presignedUrl, objectUrl string, err error)
+	GenerateContentImagePutURL(fileName string, contentType string, contentLength int64) (*models.UploadURL, error)
+	RegisterUpload(
+		ctx context.Context, tx infrastracture.TX, kind models.MediaKind, uploadURL *models.UploadURL,
+		contentType string, contentLength int64, uploadedBy *models.UserId,
+	) (*models.Media, error)
 }
 
+var ErrInvalidUpload = errors.New("invalid upload")
+
 type Usecase struct {
+	db              infrastracture.DB
 	contentsService ContentsService
 }
 
-func NewUsecase(contentsService ContentsService) *Usecase {
+func NewUsecase(db infrastracture.DB, contentsService ContentsService) *Usecase {
 	return &Usecase{
+		db:              db,
 		contentsService: contentsService,
 	}
 }
 
-func (u *Usecase) Run(ctx context.Context, fileName string) (presignedUrl, objectUrl string, err error) {
-	return u.contentsService.GenerateContentImagePutURL(fileName)
+type Output struct {
+	UploadURL *models.UploadURL
+	Media     *models.Media
+}
+
+// Run は、署名付きURLを発行し、アップロード待ちのメディアとして登録する
+func (u *Usecase) Run(
+	ctx context.Context, fileName string, contentType string, contentLength int64,
+) (*Output, error) {
+	uploadURL, err := u.contentsService.GenerateContentImagePutURL(fileName, contentType, contentLength)
+	if err != nil {
+		if errors.Is(err, contents_service.ErrUnsupportedContentType) ||
+			errors.Is(err, contents_service.ErrFileTooLarge) {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidUpload, err)
+		}
+		return nil, fmt.Errorf("failed to generate put url: %w", err)
+	}
+
+	var uploadedBy *models.UserId
+	if userId, err := session.GetUserId(ctx); err == nil {
+		uploadedBy = &userId
+	}
+
+	transactor := infrastracture.NewTransactionProvider(u.db)
+	result, err := transactor.DoInTx(ctx, func(tx infrastracture.TX) (interface{}, error) {
+		return u.contentsService.RegisterUpload(
+			ctx, tx, models.MediaKindContent, uploadURL, contentType, contentLength, uploadedBy)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to register media: %w", err)
+	}
+	registered, ok := result.(*models.Media)
+	if !ok {
+		return nil, fmt.Errorf("failed to type assertion")
+	}
+	return &Output{UploadURL: uploadURL, Media: registered}, nil
 }
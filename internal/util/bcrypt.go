@@ -18,3 +18,8 @@ func HashPassword(password string) (string, error) {
 	}
 	return string(bytes), nil
 }
+
+// ComparePassword は、HashPasswordで生成したハッシュと平文が一致するかを判定する
+func ComparePassword(hashed string, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hashed), []byte(password)) == nil
+}
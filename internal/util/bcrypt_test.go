@@ -48,3 +48,28 @@ func Test_HashPassword(t *testing.T) {
 		})
 	}
 }
+
+func Test_ComparePassword(t *testing.T) {
+	hashed, err := HashPassword("password")
+	if err != nil {
+		t.Fatalf("failed to hash password: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		password string
+		want     bool
+	}{
+		{name: "match", password: "password", want: true},
+		{name: "mismatch", password: "passw0rd", want: false},
+		{name: "empty", password: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ComparePassword(hashed, tt.password); got != tt.want {
+				t.Errorf("ComparePassword() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}